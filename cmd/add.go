@@ -13,6 +13,8 @@ import (
 var (
 	messages            []string
 	noExpandSuggestions bool
+	addTemplate         string
+	addTemplateVars     []string
 
 	// Client for dependency injection (tests can override)
 	addClient github.GitHubAPI
@@ -37,6 +39,9 @@ var addCmd = &cobra.Command{
 		The comment message supports GitHub markdown formatting and can include
 		code suggestions using the [SUGGEST: code] syntax. Use offset syntax
 		[SUGGEST:+N: code] for lines below or [SUGGEST:-N: code] for lines above.
+
+		Use --template to post a snippet from the library instead of typing the
+		message (see 'gh comment templates').
 	`),
 	Example: heredoc.Doc(`
 		# General PR discussion comments
@@ -60,6 +65,9 @@ var addCmd = &cobra.Command{
 		$ gh comment add 123 "Consider using async/await: [SUGGEST: const result = await fetchData();]"
 		$ gh comment add 123 "Add error handling above: [SUGGEST:-1: try {]"
 		$ gh comment add 123 "Add timeout below: [SUGGEST:+2: const timeout = 5000;]"
+
+		# Reusable snippets with variables
+		$ gh comment add 123 --template missing-test --var func=Parse
 	`),
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runAdd,
@@ -69,6 +77,9 @@ func init() {
 	rootCmd.AddCommand(addCmd)
 	addCmd.Flags().StringArrayVarP(&messages, "message", "m", []string{}, "Add message (can be used multiple times for multi-line comments) (default: empty)")
 	addCmd.Flags().BoolVar(&noExpandSuggestions, "no-expand-suggestions", false, "Disable automatic expansion of [SUGGEST:] and <<<SUGGEST>>> syntax (default: false)")
+	addCmd.Flags().StringVar(&addTemplate, "template", "", "Use a snippet from the library as the comment (see 'gh comment templates')")
+	addCmd.Flags().StringArrayVar(&addTemplateVars, "var", nil, "Set a snippet variable (key=value, repeatable)")
+	addPolicyFlag(addCmd)
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -86,8 +97,24 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	var comment string
 	var err error
 
+	// A snippet stands in for the message flags
+	messageLines := messages
+	if addTemplate != "" {
+		if len(messages) > 0 {
+			return fmt.Errorf("--template cannot be combined with --message")
+		}
+		if len(args) == 2 {
+			return fmt.Errorf("invalid arguments when using --template: expected [pr] or no args")
+		}
+		body, err := renderSnippetByName(addTemplate, addTemplateVars)
+		if err != nil {
+			return err
+		}
+		messageLines = []string{body}
+	}
+
 	// Parse arguments for general PR comments
-	if len(messages) > 0 {
+	if len(messageLines) > 0 {
 		// Using --message flags
 		if len(args) == 1 {
 			// PR provided + --message flags
@@ -119,7 +146,7 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		} else {
 			return fmt.Errorf("invalid arguments when using --message flags: expected [pr] or no args")
 		}
-		comment = strings.Join(messages, "\n")
+		comment = strings.Join(messageLines, "\n")
 	} else if len(args) == 2 {
 		// PR number provided + comment
 		pr, err = parsePositiveInt(args[0], "PR number")
@@ -163,6 +190,17 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Check for secrets and personal data before anything is sent
+	comment, err = scanCommentBody("comment body", comment)
+	if err != nil {
+		return err
+	}
+
+	// Check the repository policy before anything is sent
+	if err := enforcePolicy(policySubject{Body: comment}); err != nil {
+		return err
+	}
+
 	// Expand suggestion syntax to GitHub markdown (unless disabled)
 	var transformedComment string
 	if noExpandSuggestions {
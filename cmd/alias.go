@@ -0,0 +1,554 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	aliasGlobal  bool
+	aliasClobber bool
+
+	aliasNamePattern        = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+	aliasPlaceholderPattern = regexp.MustCompile(`\$(\d+)`)
+)
+
+// aliasCmd represents the alias command
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Create shortcuts for gh-comment commands",
+	Long: heredoc.Doc(`
+		Manage command aliases stored under 'aliases:' in the config file.
+
+		An alias expands to a gh-comment command line before the command runs.
+		Arguments given after the alias are appended to the expansion, unless the
+		expansion uses positional placeholders ($1, $2, ...), in which case those
+		arguments are substituted and any remaining ones appended.
+
+		An expansion starting with '!' is run by the shell (sh -c) instead. Shell
+		aliases receive their arguments as $1, $2, ... and "$@", so they can pipe
+		gh-comment into other tools. They are only run from the user config file
+		(--global), never from a project's .gh-comment.yaml.
+
+		Aliases can refer to other aliases; cycles are rejected. Built-in commands
+		always take precedence over aliases with the same name. Global flags such
+		as --repo and --profile may come before the alias.
+
+		Aliases are written to the active config file (see 'gh comment config'),
+		or to the user config file with --global.
+	`),
+	Example: heredoc.Doc(`
+		# Team shortcuts
+		$ gh comment alias set nit 'review --event COMMENT --comment'
+		$ gh comment alias set sec "list --author 'security*' --type review"
+		$ gh comment nit 123 "src/api.go:42:nit: prefer errors.Is"
+		$ gh comment sec 123
+		$ gh comment -R acme/api sec 123
+
+		# Positional placeholders
+		$ gh comment alias set approve-with 'review $1 "$2" --event APPROVE'
+		$ gh comment approve-with 123 "Ship it"
+
+		# Shell aliases
+		$ gh comment alias set --global todo '!gh comment list "$1" --grep TODO --ids-only | wc -l'
+
+		# Manage aliases
+		$ gh comment alias list
+		$ gh comment alias delete nit
+	`),
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <expansion>",
+	Short: "Create or update an alias",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runAliasSet,
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured aliases",
+	Args:  cobra.NoArgs,
+	RunE:  runAliasList,
+}
+
+var aliasDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an alias",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAliasDelete,
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasDeleteCmd)
+
+	aliasCmd.PersistentFlags().BoolVar(&aliasGlobal, "global", false, "Use the user config file instead of the active config file")
+	aliasSetCmd.Flags().BoolVar(&aliasClobber, "clobber", false, "Overwrite an existing alias with the same name")
+
+	rootCmd.AddCommand(aliasCmd)
+}
+
+func runAliasSet(cmd *cobra.Command, args []string) error {
+	name, expansion := args[0], strings.TrimSpace(args[1])
+
+	if isBuiltinCommand(name) {
+		return formatValidationError("alias name", name, "already a gh-comment command")
+	}
+	if !strings.HasPrefix(expansion, "!") {
+		words, err := splitAliasWords(expansion)
+		if err != nil {
+			return err
+		}
+		if len(words) == 0 || (!isBuiltinCommand(words[0]) && GetConfig().Aliases[words[0]] == "") {
+			return formatValidationError("alias expansion", expansion,
+				"must start with a gh-comment command or another alias (prefix with ! for a shell command)")
+		}
+	}
+
+	path, err := aliasConfigPath()
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(expansion, "!") && !isUserConfigFile(path) {
+		return formatValidationError("alias expansion", expansion,
+			"shell aliases can only be stored in the user config file (use --global)")
+	}
+
+	aliases, err := configFileAliases(path)
+	if err != nil {
+		return err
+	}
+	_, existed := aliases[name]
+	if existed && !aliasClobber {
+		return fmt.Errorf("alias %s already exists (use --clobber to overwrite)", name)
+	}
+
+	err = editConfigFile(path, func(doc configDocument) error {
+		return doc.Set([]string{"aliases", name}, expansion)
+	})
+	if err != nil {
+		return err
+	}
+
+	action := "Added"
+	if existed {
+		action = "Updated"
+	}
+	fmt.Printf("✅ %s alias %s: %s (%s)\n", action, name, expansion, path)
+	return nil
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	aliases := GetConfig().Aliases
+	if aliasGlobal {
+		path, err := userConfigFile()
+		if err != nil {
+			return err
+		}
+		aliases = nil
+		if _, err := os.Stat(path); err == nil {
+			config, err := LoadConfig(path)
+			if err != nil {
+				return err
+			}
+			aliases = config.Aliases
+		}
+	}
+
+	if len(aliases) == 0 {
+		fmt.Println("No aliases configured")
+		return nil
+	}
+
+	names := make([]string, 0, len(aliases))
+	width := 0
+	for name := range aliases {
+		names = append(names, name)
+		if len(name)+1 > width {
+			width = len(name) + 1
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("%-*s  %s\n", width, name+":", aliases[name])
+	}
+	return nil
+}
+
+func runAliasDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	path, err := aliasConfigPath()
+	if err != nil {
+		return err
+	}
+
+	err = editConfigFile(path, func(doc configDocument) error {
+		if !doc.Unset([]string{"aliases", name}) {
+			return fmt.Errorf("no such alias: %s", name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Deleted alias %s (%s)\n", name, path)
+	return nil
+}
+
+// expandAliases expands a leading alias in args, following aliases of aliases.
+// For a shell alias it returns the shell command and the arguments to pass to it.
+// Shell aliases only run when trusted, the user config file's aliases, has the same expansion.
+func expandAliases(aliases, trusted map[string]string, args []string) (expanded []string, shellCommand string, err error) {
+	var chain []string
+	for len(args) > 0 {
+		name := args[0]
+		expansion, ok := aliases[name]
+		if !ok || isBuiltinCommand(name) {
+			break
+		}
+
+		for _, seen := range chain {
+			if seen == name {
+				return nil, "", fmt.Errorf("alias cycle detected: %s -> %s", strings.Join(chain, " -> "), name)
+			}
+		}
+		chain = append(chain, name)
+
+		if strings.HasPrefix(expansion, "!") {
+			if trusted[name] != expansion {
+				return nil, "", fmt.Errorf("alias %s runs a shell command but is not defined in the user config file; "+
+					"shell aliases from project config files are not run", name)
+			}
+			return args[1:], strings.TrimPrefix(expansion, "!"), nil
+		}
+		if args, err = substituteAliasArgs(name, expansion, args[1:]); err != nil {
+			return nil, "", err
+		}
+	}
+	return args, "", nil
+}
+
+// substituteAliasArgs fills $N placeholders in an expansion and appends the unused arguments
+func substituteAliasArgs(name, expansion string, args []string) ([]string, error) {
+	words, err := splitAliasWords(expansion)
+	if err != nil {
+		return nil, fmt.Errorf("alias %s: %w", name, err)
+	}
+
+	highest := 0
+	for i, word := range words {
+		var missing int
+		words[i] = aliasPlaceholderPattern.ReplaceAllStringFunc(word, func(placeholder string) string {
+			n, _ := strconv.Atoi(placeholder[1:])
+			if n == 0 {
+				return placeholder
+			}
+			if n > highest {
+				highest = n
+			}
+			if n > len(args) {
+				missing = n
+				return placeholder
+			}
+			return args[n-1]
+		})
+		if missing > 0 {
+			return nil, fmt.Errorf("alias %s expects at least %d argument(s), got %d", name, missing, len(args))
+		}
+	}
+
+	return append(words, args[highest:]...), nil
+}
+
+// splitAliasWords splits an expansion into words the way a POSIX shell would,
+// honoring single quotes, double quotes and backslash escapes
+func splitAliasWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`, runes[i+1]):
+				i++
+				word.WriteRune(runes[i])
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// validateAliases checks alias names and rejects aliases that expand into themselves
+func validateAliases(aliases map[string]string) error {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !aliasNamePattern.MatchString(name) {
+			return formatValidationError("alias name", name, "must start with a letter and contain only letters, digits, '-' or '_'")
+		}
+		if strings.TrimSpace(strings.TrimPrefix(aliases[name], "!")) == "" {
+			return formatValidationError("alias", name, "expansion must not be empty")
+		}
+
+		chain := []string{name}
+		for next := aliasTarget(aliases[name]); next != ""; next = aliasTarget(aliases[next]) {
+			if _, ok := aliases[next]; !ok {
+				break
+			}
+			for _, seen := range chain {
+				if seen == next {
+					return fmt.Errorf("alias cycle detected: %s -> %s", strings.Join(chain, " -> "), next)
+				}
+			}
+			chain = append(chain, next)
+		}
+	}
+	return nil
+}
+
+// aliasTarget returns the command an expansion starts with, or "" for shell aliases
+func aliasTarget(expansion string) string {
+	if strings.HasPrefix(expansion, "!") {
+		return ""
+	}
+	words, err := splitAliasWords(expansion)
+	if err != nil || len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
+
+// isBuiltinCommand reports whether name is a gh-comment command or one of its aliases
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, command := range rootCmd.Commands() {
+		if command.Name() == name || command.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandCommandLine expands an alias at the start of the command line, after any global
+// flags, using the aliases from the config file and the selected profile. It returns nil
+// when the command line does not use an alias, and the shell command to run for a '!' alias.
+func expandCommandLine(args []string) (expanded []string, shellCommand string, err error) {
+	i := commandIndex(args)
+	if i < 0 || isBuiltinCommand(args[i]) {
+		return nil, "", nil
+	}
+
+	config, _, err := loadConfigLayers(flagValueFromArgs(args, "config"), flagValueFromArgs(args, "profile"))
+	if err != nil {
+		// Configuration errors are reported once the command runs
+		return nil, "", nil
+	}
+	if _, ok := config.Aliases[args[i]]; !ok {
+		return nil, "", nil
+	}
+
+	expanded, shellCommand, err = expandAliases(config.Aliases, userConfigAliases(), args[i:])
+	if err != nil {
+		return nil, "", err
+	}
+	if shellCommand != "" {
+		if i > 0 {
+			return nil, "", fmt.Errorf("global flags cannot be used with shell alias %s", args[i])
+		}
+		return expanded, shellCommand, nil
+	}
+	return append(append([]string{}, args[:i]...), expanded...), "", nil
+}
+
+// commandIndex returns the index of the first word on the command line that is not a
+// global flag or its value, or -1 when there is none or an unknown flag comes first
+func commandIndex(args []string) int {
+	flags := rootCmd.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return -1
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return i
+		}
+
+		var flag *pflag.Flag
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			flag = flags.Lookup(name)
+		case len(name) > 0:
+			// -R owner/repo or -Rowner/repo
+			flag = flags.ShorthandLookup(name[:1])
+			hasValue = hasValue || len(name) > 1
+		}
+		if flag == nil {
+			return -1
+		}
+		if !hasValue && flag.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return -1
+}
+
+// flagValueFromArgs finds the value of a --name flag on the raw command line
+func flagValueFromArgs(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--"+name && i+1 < len(args) {
+			return args[i+1]
+		}
+		if value, ok := strings.CutPrefix(arg, "--"+name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// userConfigAliases returns the aliases in the user config file, the only file shell aliases run from
+func userConfigAliases() map[string]string {
+	if config := loadUserConfig(); config != nil {
+		return config.Aliases
+	}
+	return nil
+}
+
+// configFileAliases returns the aliases defined in one config file, which may not exist yet
+func configFileAliases(path string) (map[string]string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	config := NewDefaultConfig()
+	if err := loadConfigFile(config, path); err != nil {
+		return nil, fmt.Errorf("failed to load config file %s: %w", path, err)
+	}
+	return config.Aliases, nil
+}
+
+// runShellAlias runs a '!' alias with sh -c, passing args as positional parameters
+func runShellAlias(name, command string, args []string) error {
+	shell := exec.Command("sh", append([]string{"-c", command, name}, args...)...)
+	shell.Stdin = os.Stdin
+	shell.Stdout = os.Stdout
+	shell.Stderr = os.Stderr
+
+	if err := shell.Run(); err != nil {
+		return fmt.Errorf("shell alias %s failed: %w", name, err)
+	}
+	return nil
+}
+
+// aliasConfigPath returns the config file alias changes are written to
+func aliasConfigPath() (string, error) {
+	if aliasGlobal {
+		return userConfigFile()
+	}
+	if configPath != "" {
+		return configPath, nil
+	}
+	if path, err := findConfigFile(); err == nil {
+		return path, nil
+	}
+	return userConfigFile()
+}
+
+// userConfigFile returns the existing user config file, or the default path for a new one
+func userConfigFile() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.json"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// isUserConfigFile reports whether path is one of the user config file locations,
+// which need not exist yet
+func isUserConfigFile(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, candidate := range userConfigPaths() {
+		if abs == candidate || sameFile(abs, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlMappingValue returns the value node for key in a YAML mapping, or nil
+func yamlMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
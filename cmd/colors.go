@@ -226,3 +226,11 @@ func ColorizeWarning(text string) string {
 	}
 	return ColorWarning.Sprintf("⚠️ %s", text)
 }
+
+// ColorizeHeader returns bold magenta section header text
+func ColorizeHeader(text string) string {
+	if ColorHeader == nil {
+		return text
+	}
+	return ColorHeader.Sprint(text)
+}
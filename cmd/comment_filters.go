@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// suggestionBlockPattern matches a GitHub suggestion fence at the start of a line
+var suggestionBlockPattern = regexp.MustCompile("(?m)^[ \t]*```suggestion\\b")
+
+// CommentQuery holds the content-level filters shared by commands that select comments.
+// All filters that are set must match; --grep and --regex patterns match if any of them do.
+type CommentQuery struct {
+	Grep          []string
+	Regex         []string
+	Files         []string
+	LineRange     string
+	HasSuggestion bool
+	Reaction      string
+	MinReactions  int
+	MinReplies    int
+	Invert        bool
+
+	// Compiled state, populated by Compile
+	bodyPatterns []*regexp.Regexp
+	fileMatchers []*regexp.Regexp
+	lineStart    int
+	lineEnd      int
+	reaction     string
+}
+
+// addCommentQueryFlags registers the content filter flags on a command
+func addCommentQueryFlags(flags *pflag.FlagSet, q *CommentQuery) {
+	flags.StringArrayVar(&q.Grep, "grep", nil, "Match comments whose body contains text (case-insensitive, repeatable)")
+	flags.StringArrayVar(&q.Regex, "regex", nil, "Match comments whose body matches a regular expression (repeatable)")
+	flags.StringArrayVar(&q.Files, "file", nil, "Match review comments on files matching a glob (supports **, repeatable)")
+	flags.StringVar(&q.LineRange, "line-range", "", "Match review comments on a line or range (42 or 10-20)")
+	flags.BoolVar(&q.HasSuggestion, "has-suggestion", false, "Match comments containing a suggestion block")
+	flags.StringVar(&q.Reaction, "reaction", "", "Match comments with at least one reaction of this type (+1, heart, rocket, ...)")
+	flags.IntVar(&q.MinReactions, "min-reactions", 0, "Match comments with at least N reactions (of the --reaction type, if given)")
+	flags.IntVar(&q.MinReplies, "min-replies", 0, "Match review comments with at least N replies")
+	flags.BoolVar(&q.Invert, "invert", false, "Select comments that do NOT match the content filters above")
+}
+
+// IsEmpty reports whether no content filters are set
+func (q *CommentQuery) IsEmpty() bool {
+	return len(q.Grep) == 0 && len(q.Regex) == 0 && len(q.Files) == 0 && q.LineRange == "" &&
+		!q.HasSuggestion && q.Reaction == "" && q.MinReactions == 0 && q.MinReplies == 0
+}
+
+// Compile validates the filters and prepares them for matching
+func (q *CommentQuery) Compile() error {
+	q.bodyPatterns = nil
+	q.fileMatchers = nil
+	q.lineStart, q.lineEnd = 0, 0
+	q.reaction = ""
+
+	for _, text := range q.Grep {
+		if text == "" {
+			return fmt.Errorf("--grep text cannot be empty")
+		}
+		q.bodyPatterns = append(q.bodyPatterns, regexp.MustCompile("(?i)"+regexp.QuoteMeta(text)))
+	}
+
+	for _, expr := range q.Regex {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return formatValidationError("regex", expr, err.Error())
+		}
+		q.bodyPatterns = append(q.bodyPatterns, pattern)
+	}
+
+	for _, glob := range q.Files {
+		matcher, err := compilePathGlob(glob)
+		if err != nil {
+			return err
+		}
+		q.fileMatchers = append(q.fileMatchers, matcher)
+	}
+
+	if q.LineRange != "" {
+		start, end, err := parseLineRangeFilter(q.LineRange)
+		if err != nil {
+			return err
+		}
+		q.lineStart, q.lineEnd = start, end
+	}
+
+	if q.Reaction != "" {
+		reaction := normalizeReaction(q.Reaction)
+		if !validateReaction(reaction) {
+			return formatValidationError("reaction", q.Reaction, "must be one of: +1, -1, laugh, confused, heart, hooray, rocket, eyes")
+		}
+		q.reaction = reaction
+	}
+
+	if q.MinReactions < 0 {
+		return formatValidationError("min-reactions", strconv.Itoa(q.MinReactions), "must be non-negative")
+	}
+
+	if q.MinReplies < 0 {
+		return formatValidationError("min-replies", strconv.Itoa(q.MinReplies), "must be non-negative")
+	}
+
+	if q.Invert && q.IsEmpty() {
+		return fmt.Errorf("--invert requires at least one content filter (--grep, --regex, --file, --line-range, --has-suggestion, --reaction, --min-reactions, --min-replies)")
+	}
+
+	return nil
+}
+
+// Matches reports whether a comment satisfies the query, honoring --invert
+func (q *CommentQuery) Matches(comment Comment) bool {
+	if q.IsEmpty() {
+		return true
+	}
+	return q.matchesAll(comment) != q.Invert
+}
+
+// matchesAll reports whether every configured filter matches the comment
+func (q *CommentQuery) matchesAll(comment Comment) bool {
+	if len(q.bodyPatterns) > 0 && !matchesAnyPattern(q.bodyPatterns, comment.Body) {
+		return false
+	}
+
+	if len(q.fileMatchers) > 0 && (comment.Path == "" || !matchesAnyPattern(q.fileMatchers, comment.Path)) {
+		return false
+	}
+
+	if q.lineStart > 0 && (comment.Line < q.lineStart || comment.Line > q.lineEnd) {
+		return false
+	}
+
+	if q.HasSuggestion && !hasSuggestionBlock(comment.Body) {
+		return false
+	}
+
+	if q.reaction != "" && comment.Reactions.Count(q.reaction) < max(q.MinReactions, 1) {
+		return false
+	}
+
+	if q.reaction == "" && q.MinReactions > 0 && comment.Reactions.TotalCount < q.MinReactions {
+		return false
+	}
+
+	if q.MinReplies > 0 && comment.Replies < q.MinReplies {
+		return false
+	}
+
+	return true
+}
+
+// matchesAnyPattern reports whether any of the patterns match s
+func matchesAnyPattern(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSuggestionBlock reports whether a comment body contains a ```suggestion fence
+func hasSuggestionBlock(body string) bool {
+	return suggestionBlockPattern.MatchString(body)
+}
+
+// parseLineRangeFilter parses "42" or "10-20" into an inclusive range
+func parseLineRangeFilter(value string) (int, int, error) {
+	if strings.Contains(value, "-") {
+		start, end, err := parseRange(value)
+		if err != nil {
+			return 0, 0, formatValidationError("line range", value, err.Error())
+		}
+		return start, end, nil
+	}
+
+	line, err := parsePositiveInt(strings.TrimSpace(value), "line range")
+	if err != nil {
+		return 0, 0, err
+	}
+	return line, line, nil
+}
+
+// compilePathGlob converts a path glob into a regular expression.
+// '*' and '?' never cross a '/', while '**' matches any number of directories.
+func compilePathGlob(glob string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(glob) == "" {
+		return nil, fmt.Errorf("file glob cannot be empty")
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i++
+			if i+1 < len(glob) && glob[i+1] == '/' {
+				// "**/" matches zero or more leading directories
+				i++
+				pattern.WriteString("(?:.*/)?")
+			} else {
+				pattern.WriteString(".*")
+			}
+		case c == '*':
+			pattern.WriteString("[^/]*")
+		case c == '?':
+			pattern.WriteString("[^/]")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	pattern.WriteString("$")
+
+	matcher, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, formatValidationError("file glob", glob, err.Error())
+	}
+	return matcher, nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func queryTestComments() []Comment {
+	return []Comment{
+		{ID: 1, Type: "issue", Author: "alice", Body: "Overall LGTM"},
+		{ID: 2, Type: "review", Author: "bob", Body: "TODO: handle the error", Path: "pkg/auth/token.go", Line: 12},
+		{ID: 3, Type: "review", Author: "carol", Body: "//nolint is hiding a real bug", Path: "pkg/auth/internal/session.go", Line: 40, Replies: 3},
+		{ID: 4, Type: "review", Author: "dave", Body: "Try this:\n```suggestion\nreturn nil\n```", Path: "cmd/main.go", Line: 7,
+			Reactions: github.ReactionSummary{TotalCount: 2, PlusOne: 2}},
+		{ID: 5, Type: "review", Author: "erin", Body: "Nice test", Path: "cmd/main_test.go", Line: 15, Replies: 1,
+			Reactions: github.ReactionSummary{TotalCount: 1, Heart: 1}},
+	}
+}
+
+func matchingIDs(t *testing.T, q CommentQuery) []int {
+	t.Helper()
+	require.NoError(t, q.Compile())
+
+	var ids []int
+	for _, comment := range queryTestComments() {
+		if q.Matches(comment) {
+			ids = append(ids, comment.ID)
+		}
+	}
+	return ids
+}
+
+func TestCommentQueryMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    CommentQuery
+		expected []int
+	}{
+		{name: "empty query matches everything", query: CommentQuery{}, expected: []int{1, 2, 3, 4, 5}},
+		{name: "grep is case-insensitive", query: CommentQuery{Grep: []string{"todo"}}, expected: []int{2}},
+		{name: "multiple grep terms match any", query: CommentQuery{Grep: []string{"TODO", "nolint"}}, expected: []int{2, 3}},
+		{name: "regex", query: CommentQuery{Regex: []string{`^Nice\b`}}, expected: []int{5}},
+		{name: "grep and regex combine as alternatives", query: CommentQuery{Grep: []string{"lgtm"}, Regex: []string{`suggestion`}}, expected: []int{1, 4}},
+		{name: "double star glob", query: CommentQuery{Files: []string{"pkg/auth/**"}}, expected: []int{2, 3}},
+		{name: "single star stays in directory", query: CommentQuery{Files: []string{"pkg/auth/*"}}, expected: []int{2}},
+		{name: "leading double star", query: CommentQuery{Files: []string{"**/*_test.go"}}, expected: []int{5}},
+		{name: "line range", query: CommentQuery{LineRange: "10-20"}, expected: []int{2, 5}},
+		{name: "single line", query: CommentQuery{LineRange: "40"}, expected: []int{3}},
+		{name: "has suggestion", query: CommentQuery{HasSuggestion: true}, expected: []int{4}},
+		{name: "reaction", query: CommentQuery{Reaction: "+1"}, expected: []int{4}},
+		{name: "reaction emoji alias", query: CommentQuery{Reaction: "❤️"}, expected: []int{5}},
+		{name: "min reactions of a type", query: CommentQuery{Reaction: "+1", MinReactions: 2}, expected: []int{4}},
+		{name: "min reactions above count", query: CommentQuery{Reaction: "heart", MinReactions: 2}, expected: nil},
+		{name: "min reactions of any type", query: CommentQuery{MinReactions: 1}, expected: []int{4, 5}},
+		{name: "min replies", query: CommentQuery{MinReplies: 1}, expected: []int{3, 5}},
+		{name: "filters combine", query: CommentQuery{Files: []string{"pkg/**"}, Grep: []string{"bug"}, MinReplies: 2}, expected: []int{3}},
+		{name: "invert", query: CommentQuery{Files: []string{"pkg/auth/**"}, Invert: true}, expected: []int{1, 4, 5}},
+		{name: "invert combined query", query: CommentQuery{Files: []string{"cmd/**"}, Reaction: "heart", Invert: true}, expected: []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchingIDs(t, tt.query))
+		})
+	}
+}
+
+func TestCommentQueryCompileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   CommentQuery
+		wantErr string
+	}{
+		{name: "bad regex", query: CommentQuery{Regex: []string{"("}}, wantErr: "invalid regex"},
+		{name: "empty grep", query: CommentQuery{Grep: []string{""}}, wantErr: "--grep text cannot be empty"},
+		{name: "empty glob", query: CommentQuery{Files: []string{" "}}, wantErr: "file glob cannot be empty"},
+		{name: "bad line range", query: CommentQuery{LineRange: "20-10"}, wantErr: "invalid line range"},
+		{name: "non-numeric line", query: CommentQuery{LineRange: "abc"}, wantErr: "invalid line range"},
+		{name: "unknown reaction", query: CommentQuery{Reaction: "thumbs"}, wantErr: "invalid reaction"},
+		{name: "negative replies", query: CommentQuery{MinReplies: -1}, wantErr: "invalid min-replies"},
+		{name: "negative reactions", query: CommentQuery{MinReactions: -1}, wantErr: "invalid min-reactions"},
+		{name: "invert without filters", query: CommentQuery{Invert: true}, wantErr: "--invert requires"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Compile()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCompilePathGlob(t *testing.T) {
+	tests := []struct {
+		glob    string
+		path    string
+		matches bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/deep/main.go", true},
+		{"cmd/**", "cmd/a/b.go", true},
+		{"cmd/**", "internal/cmd/a.go", false},
+		{"src/?.js", "src/a.js", true},
+		{"src/?.js", "src/ab.js", false},
+		{"docs/[draft].md", "docs/[draft].md", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			matcher, err := compilePathGlob(tt.glob)
+			require.NoError(t, err)
+			assert.Equal(t, tt.matches, matcher.MatchString(tt.path))
+		})
+	}
+}
+
+func TestFetchAllCommentsCountsReplies(t *testing.T) {
+	mockClient := &github.MockClient{
+		ReviewComments: []github.Comment{
+			{ID: 10, Body: "root", User: github.User{Login: "a"}, Path: "x.go", Line: 1},
+			{ID: 11, Body: "reply 1", User: github.User{Login: "b"}, Path: "x.go", Line: 1, InReplyToID: 10},
+			{ID: 12, Body: "reply 2", User: github.User{Login: "c"}, Path: "x.go", Line: 1, InReplyToID: 10,
+				Reactions: github.ReactionSummary{TotalCount: 1, Rocket: 1}},
+		},
+	}
+
+	comments, err := fetchAllComments(mockClient, "owner/repo", 1)
+	require.NoError(t, err)
+	require.Len(t, comments, 3)
+
+	byID := make(map[int]Comment)
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+	assert.Equal(t, 2, byID[10].Replies)
+	assert.Equal(t, 10, byID[11].InReplyToID)
+	assert.Equal(t, 1, byID[12].Reactions.Count("rocket"))
+}
+
+func TestFilterCommentsAppliesListQuery(t *testing.T) {
+	originalQuery := listQuery
+	defer func() { listQuery = originalQuery }()
+
+	listQuery = CommentQuery{Grep: []string{"todo"}}
+	require.NoError(t, listQuery.Compile())
+
+	filtered := filterComments(queryTestComments())
+	require.Len(t, filtered, 1)
+	assert.Equal(t, 2, filtered[0].ID)
+}
@@ -22,6 +22,12 @@ type Config struct {
 	Suggestions SuggestionsConfig    `yaml:"suggestions" json:"suggestions"`
 	Aliases     map[string]string    `yaml:"aliases" json:"aliases"`
 	Templates   TemplatesConfig      `yaml:"templates" json:"templates"`
+
+	Notifications NotificationsConfig `yaml:"notifications" json:"notifications"`
+	Scan          ScanConfig          `yaml:"scan" json:"scan"`
+
+	// Named overrides applied on top of the config files, see ProfileConfig
+	Profiles map[string]ProfileConfig `yaml:"profiles,omitempty" json:"profiles,omitempty"`
 }
 
 // DefaultsConfig holds default values for common flags
@@ -77,6 +83,122 @@ type SuggestionsConfig struct {
 type TemplatesConfig struct {
 	DefaultReviewBody      string `yaml:"default_review_body" json:"default_review_body"`
 	DefaultApprovalMessage string `yaml:"default_approval_message" json:"default_approval_message"`
+
+	// Snippet library: reusable comment bodies defined inline or as markdown files in Dir
+	Dir      string                   `yaml:"dir,omitempty" json:"dir,omitempty"`
+	Snippets map[string]SnippetConfig `yaml:"snippets,omitempty" json:"snippets,omitempty"`
+}
+
+// SnippetConfig is a reusable comment body. Body may reference {{variables}};
+// Vars holds their defaults, and a variable without a default must be given with --var.
+type SnippetConfig struct {
+	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Vars        map[string]string `yaml:"vars,omitempty" json:"vars,omitempty"`
+	Body        string            `yaml:"body" json:"body"`
+}
+
+// UnmarshalYAML also accepts a plain string as shorthand for a snippet body
+func (s *SnippetConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Body = node.Value
+		return nil
+	}
+	type plain SnippetConfig
+	return node.Decode((*plain)(s))
+}
+
+// NotificationsConfig holds the rules applied by 'gh comment notify'
+type NotificationsConfig struct {
+	Rules []NotificationRule `yaml:"rules" json:"rules"`
+}
+
+// NotificationRule matches watch events and runs actions for them.
+// Empty match fields match everything; at least one action is required.
+type NotificationRule struct {
+	Name string `yaml:"name" json:"name"`
+
+	// Matchers
+	Events []string `yaml:"events" json:"events"` // event kinds, see notificationEventKinds
+	Author string   `yaml:"author" json:"author"` // author glob ('bot*')
+	Body   string   `yaml:"body" json:"body"`     // body regex
+	File   string   `yaml:"file" json:"file"`     // file glob ('src/**/*.go')
+
+	// Actions
+	Command string `yaml:"command" json:"command"`   // shell command, event JSON on stdin
+	WriteTo string `yaml:"write_to" json:"write_to"` // file to append event JSON lines to
+	Webhook string `yaml:"webhook" json:"webhook"`   // local URL to POST event JSON to
+}
+
+// ScanConfig controls the secret and PII scanner run on every outbound comment body.
+// Action overrides the built-in detectors' own defaults; Detectors sets the action
+// of one detector by name (or turns it off), and Allow lists regexes of matches
+// that are never reported, such as known example values.
+type ScanConfig struct {
+	Enabled   bool              `yaml:"enabled" json:"enabled"`
+	Action    string            `yaml:"action" json:"action"`       // block, redact or warn
+	Detectors map[string]string `yaml:"detectors" json:"detectors"` // detector name -> action or off
+	Custom    []ScanPattern     `yaml:"custom" json:"custom"`
+	Allow     []string          `yaml:"allow" json:"allow"`
+}
+
+// ScanPattern is a custom detector. When Pattern has a group named 'secret',
+// only that part of the match is reported and redacted.
+type ScanPattern struct {
+	Name    string `yaml:"name" json:"name"`
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Action  string `yaml:"action" json:"action"` // defaults to scan.action, or block
+}
+
+// ProfileConfig is a named set of settings layered over the config files.
+// It is selected with --profile or GH_COMMENT_PROFILE, or automatically when
+// Match fits the current repository. Settings uses the same keys as Config.
+type ProfileConfig struct {
+	Match    ProfileMatch           `yaml:"match,omitempty" json:"match,omitempty"`
+	Settings map[string]interface{} `yaml:",inline" json:"-"`
+}
+
+// ProfileMatch selects a profile by repository owner or git remote URL.
+// Patterns are case-insensitive and '*' matches any run of characters.
+type ProfileMatch struct {
+	Owners  []string `yaml:"owners,omitempty" json:"owners,omitempty"`   // owner globs ('acme*')
+	Remotes []string `yaml:"remotes,omitempty" json:"remotes,omitempty"` // remote URL globs ('*github.acme.com*')
+}
+
+// UnmarshalJSON splits the match rules from the profile's settings
+func (p *ProfileConfig) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if match, ok := fields["match"]; ok {
+		if err := json.Unmarshal(match, &p.Match); err != nil {
+			return fmt.Errorf("invalid profile match: %w", err)
+		}
+		delete(fields, "match")
+	}
+
+	p.Settings = make(map[string]interface{}, len(fields))
+	for key, raw := range fields {
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		p.Settings[key] = value
+	}
+	return nil
+}
+
+// MarshalJSON writes the settings next to the match rules, mirroring the YAML layout
+func (p ProfileConfig) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(p.Settings)+1)
+	for key, value := range p.Settings {
+		fields[key] = value
+	}
+	if len(p.Match.Owners) > 0 || len(p.Match.Remotes) > 0 {
+		fields["match"] = p.Match
+	}
+	return json.Marshal(fields)
 }
 
 // NewDefaultConfig returns a configuration with sensible defaults
@@ -94,7 +216,7 @@ func NewDefaultConfig() *Config {
 			NoExpandSuggestions: false,
 		},
 		Display: DisplayConfig{
-			Format: "table",
+			Format: "default",
 			Color:  "auto",
 			Quiet:  false,
 		},
@@ -121,45 +243,127 @@ func NewDefaultConfig() *Config {
 			DefaultReviewBody:      "Code review complete",
 			DefaultApprovalMessage: "LGTM! Ready to merge",
 		},
+		Scan: ScanConfig{
+			Enabled: true,
+		},
 	}
 }
 
 // LoadConfig loads configuration from various sources in priority order
 func LoadConfig(configPath string) (*Config, error) {
+	config, _, err := loadConfigLayers(configPath, profileName)
+	return config, err
+}
+
+// loadConfigLayers builds the configuration from defaults, the global and project
+// config files, the selected profile and GH_COMMENT_* environment variables,
+// recording which layer set each key. Flags are applied later by applyFlagOverrides.
+func loadConfigLayers(configPath, profile string) (*Config, *ConfigSources, error) {
 	config := NewDefaultConfig()
+	sources := newConfigSources()
 
-	// Find config file if not explicitly provided
-	if configPath == "" {
-		var err error
-		configPath, err = findConfigFile()
+	for _, file := range configFileLayers(configPath) {
+		if err := loadConfigFile(config, file.Path); err != nil {
+			return nil, nil, fmt.Errorf("failed to load config file %s: %w", file.Path, err)
+		}
+		keys, err := configFileKeys(file.Path)
 		if err != nil {
-			// No config file found, use defaults
-			return config, nil
+			return nil, nil, fmt.Errorf("failed to load config file %s: %w", file.Path, err)
+		}
+		if abs, err := filepath.Abs(file.Path); err == nil {
+			sources.Files[file.Layer] = abs
+		} else {
+			sources.Files[file.Layer] = file.Path
 		}
+		sources.set(file.Layer, keys)
 	}
 
-	// Load and parse config file
-	if configPath != "" {
-		err := loadConfigFile(config, configPath)
+	name, reason, err := selectProfile(config, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if name != "" {
+		keys, err := applyProfile(config, config.Profiles[name])
 		if err != nil {
-			return nil, fmt.Errorf("failed to load config file %s: %w", configPath, err)
+			return nil, nil, fmt.Errorf("failed to apply profile %s: %w", name, err)
 		}
+		sources.Profile, sources.ProfileReason = name, reason
+		sources.set(SourceProfile, keys)
 	}
 
 	// Apply environment variable overrides
-	applyEnvironmentOverrides(config)
+	keys, err := applyEnvironmentOverrides(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	sources.set(SourceEnv, keys)
 
 	// Validate configuration
-	err := validateConfig(config)
+	err = validateConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	return config, nil
+	return config, sources, nil
+}
+
+// configFile is a config file and the layer it belongs to
+type configFile struct {
+	Layer string
+	Path  string
+}
+
+// configFileLayers returns the config files to load, lowest priority first.
+// An explicit --config file replaces the search.
+func configFileLayers(configPath string) []configFile {
+	if configPath != "" {
+		return []configFile{{Layer: configFileLayer(configPath), Path: configPath}}
+	}
+
+	var files []configFile
+	globalPath, _ := findUserConfigFile()
+	if globalPath != "" {
+		files = append(files, configFile{Layer: SourceGlobal, Path: globalPath})
+	}
+	if projectPath, _ := findProjectConfigFile(); projectPath != "" && !sameFile(projectPath, globalPath) {
+		files = append(files, configFile{Layer: SourceProject, Path: projectPath})
+	}
+	return files
+}
+
+// configFileLayer reports whether a config file is the user's global config or a project config
+func configFileLayer(path string) string {
+	for _, candidate := range userConfigPaths() {
+		if sameFile(path, candidate) {
+			return SourceGlobal
+		}
+	}
+	return SourceProject
+}
+
+// sameFile reports whether two paths name the same existing file
+func sameFile(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
 }
 
 // findConfigFile searches for config files in priority order
 func findConfigFile() (string, error) {
+	if path, err := findProjectConfigFile(); err == nil {
+		return path, nil
+	}
+	if path, err := findUserConfigFile(); err == nil {
+		return path, nil
+	}
+	return "", fmt.Errorf("no config file found")
+}
+
+// findProjectConfigFile finds .gh-comment.* in the current directory or repository root
+func findProjectConfigFile() (string, error) {
 	// Search locations in priority order
 	searchPaths := []string{
 		".gh-comment.yaml",
@@ -174,28 +378,49 @@ func findConfigFile() (string, error) {
 		}
 	}
 
-	// Add user config locations
-	if homeDir, err := os.UserHomeDir(); err == nil {
-		searchPaths = append(searchPaths,
-			filepath.Join(homeDir, ".config", "gh-comment", "config.yaml"),
-			filepath.Join(homeDir, ".config", "gh-comment", "config.yml"),
-			filepath.Join(homeDir, ".config", "gh-comment", "config.json"),
-			filepath.Join(homeDir, ".gh-comment.yaml"),
-			filepath.Join(homeDir, ".gh-comment.yml"),
-			filepath.Join(homeDir, ".gh-comment.json"),
-		)
+	return firstExistingFile(searchPaths)
+}
+
+// findUserConfigFile finds the user-wide config file
+func findUserConfigFile() (string, error) {
+	return firstExistingFile(userConfigPaths())
+}
+
+// userConfigPaths lists the user-wide config file locations in priority order
+func userConfigPaths() []string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		filepath.Join(homeDir, ".config", "gh-comment", "config.yaml"),
+		filepath.Join(homeDir, ".config", "gh-comment", "config.yml"),
+		filepath.Join(homeDir, ".config", "gh-comment", "config.json"),
+		filepath.Join(homeDir, ".gh-comment.yaml"),
+		filepath.Join(homeDir, ".gh-comment.yml"),
+		filepath.Join(homeDir, ".gh-comment.json"),
 	}
+}
 
-	// Find first existing file
-	for _, path := range searchPaths {
+// firstExistingFile returns the first path that exists
+func firstExistingFile(paths []string) (string, error) {
+	for _, path := range paths {
 		if _, err := os.Stat(path); err == nil {
 			return path, nil
 		}
 	}
-
 	return "", fmt.Errorf("no config file found")
 }
 
+// userConfigDir returns the directory holding the user-wide config file
+func userConfigDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "gh-comment"), nil
+}
+
 // findGitRoot finds the root of the current git repository
 func findGitRoot() string {
 	currentDir, err := os.Getwd()
@@ -220,6 +445,21 @@ func findGitRoot() string {
 	return ""
 }
 
+// loadUserConfig loads the user config file on its own, or returns nil when there is
+// none or it can't be read. Settings that run commands (shell aliases, notification
+// commands) are only honoured when they appear here, never from a project's config.
+func loadUserConfig() *Config {
+	path, err := findUserConfigFile()
+	if err != nil {
+		return nil
+	}
+	config := NewDefaultConfig()
+	if err := loadConfigFile(config, path); err != nil {
+		return nil
+	}
+	return config
+}
+
 // loadConfigFile loads and parses a configuration file
 func loadConfigFile(config *Config, path string) error {
 	data, err := os.ReadFile(path)
@@ -249,26 +489,47 @@ func loadConfigFile(config *Config, path string) error {
 	return nil
 }
 
-// applyEnvironmentOverrides applies environment variable overrides
-func applyEnvironmentOverrides(config *Config) {
-	if val := os.Getenv("GH_COMMENT_REPO"); val != "" {
-		config.Defaults.Repository = val
-	}
-	if val := os.Getenv("GH_COMMENT_AUTHOR"); val != "" {
-		config.Defaults.Author = val
-	}
-	if val := os.Getenv("GH_COMMENT_DRY_RUN"); val != "" {
-		config.Behavior.DryRun = parseBool(val)
-	}
-	if val := os.Getenv("GH_COMMENT_VERBOSE"); val != "" {
-		config.Behavior.Verbose = parseBool(val)
+// legacyEnvironmentVariables are the short variable names supported before every
+// setting got a GH_COMMENT_<SECTION>_<KEY> variable. The long names take precedence.
+var legacyEnvironmentVariables = []struct {
+	Name string
+	Key  string
+}{
+	{"GH_COMMENT_REPO", "defaults.repository"},
+	{"GH_COMMENT_AUTHOR", "defaults.author"},
+	{"GH_COMMENT_DRY_RUN", "behavior.dry_run"},
+	{"GH_COMMENT_VERBOSE", "behavior.verbose"},
+	{"GH_COMMENT_FORMAT", "display.format"},
+	{"GH_COMMENT_COLOR", "display.color"},
+}
+
+// applyEnvironmentOverrides applies environment variable overrides and returns the keys they set
+func applyEnvironmentOverrides(config *Config) ([]string, error) {
+	var keys []string
+	apply := func(name, key string) error {
+		val := os.Getenv(name)
+		if val == "" {
+			return nil
+		}
+		if err := setConfigValue(config, key, val); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		keys = append(keys, key)
+		return nil
 	}
-	if val := os.Getenv("GH_COMMENT_FORMAT"); val != "" {
-		config.Display.Format = val
+
+	for _, legacy := range legacyEnvironmentVariables {
+		if err := apply(legacy.Name, legacy.Key); err != nil {
+			return nil, err
+		}
 	}
-	if val := os.Getenv("GH_COMMENT_COLOR"); val != "" {
-		config.Display.Color = val
+	for _, key := range configKeys {
+		if err := apply(key.EnvVar(), key.Name); err != nil {
+			return nil, err
+		}
 	}
+
+	return keys, nil
 }
 
 // parseBool parses a string as a boolean (true/false, yes/no, 1/0)
@@ -287,9 +548,9 @@ func validateConfig(config *Config) error {
 	}
 
 	// Validate enum values
-	validFormats := map[string]bool{"table": true, "json": true, "quiet": true}
+	validFormats := map[string]bool{"default": true, "table": true, "json": true, "csv": true, "tsv": true, "quiet": true}
 	if !validFormats[config.Display.Format] {
-		return fmt.Errorf("invalid display format: %s (must be table, json, or quiet)", config.Display.Format)
+		return fmt.Errorf("invalid display format: %s (must be default, table, json, csv, tsv, or quiet)", config.Display.Format)
 	}
 
 	validColors := map[string]bool{"auto": true, "always": true, "never": true}
@@ -323,6 +584,22 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("max offset must be between 1 and 9999: %d", config.Suggestions.MaxOffset)
 	}
 
+	if _, err := compileNotificationRules(config.Notifications.Rules); err != nil {
+		return err
+	}
+
+	if _, err := compileScanner(config.Scan); err != nil {
+		return err
+	}
+
+	if err := validateAliases(config.Aliases); err != nil {
+		return err
+	}
+
+	if err := validateProfiles(config.Profiles); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -347,10 +624,11 @@ func GetConfig() *Config {
 
 // LoadGlobalConfig loads the global configuration
 func LoadGlobalConfig(configPath string) error {
-	config, err := LoadConfig(configPath)
+	config, sources, err := loadConfigLayers(configPath, profileName)
 	if err != nil {
 		return err
 	}
 	globalConfig = config
+	globalConfigSources = sources
 	return nil
 }
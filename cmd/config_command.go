@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -25,12 +26,41 @@ var configCmd = &cobra.Command{
 	Long: heredoc.Doc(`
 		Manage gh-comment configuration files and settings.
 
-		Configuration files are searched in this order:
-		1. --config flag specified file
-		2. .gh-comment.yaml in current directory
-		3. .gh-comment.yaml in repository root
-		4. ~/.config/gh-comment/config.yaml (user config)
-		5. ~/.gh-comment.yaml (legacy user config)
+		Settings are layered, each layer overriding the ones before it:
+		1. default   built-in defaults
+		2. global    ~/.config/gh-comment/config.yaml (or legacy ~/.gh-comment.yaml)
+		3. project   .gh-comment.yaml in the current directory or repository root
+		4. profile   the selected profile from the config files
+		5. env       GH_COMMENT_<SECTION>_<KEY> environment variables
+		6. flag      command-line flags such as --repo and --dry-run
+
+		--config FILE replaces the global and project files with FILE.
+
+		Profiles are named overrides under 'profiles'. One is chosen with
+		--profile NAME or GH_COMMENT_PROFILE, or else the first profile (by name)
+		whose match rules fit the repository owner or origin remote URL:
+
+		  profiles:
+		    work:
+		      match:
+		        owners: [acme]
+		        remotes: ["*github.acme.com*"]
+		      behavior:
+		        validate: true
+		      review:
+		        event: REQUEST_CHANGES
+
+		Every setting has an environment variable named after its key, for example
+		GH_COMMENT_API_TIMEOUT=60 or GH_COMMENT_DISPLAY_FORMAT=json. Maps and lists
+		take YAML: GH_COMMENT_ALIASES='{nit: review --comment}'. The short forms
+		GH_COMMENT_REPO, _AUTHOR, _DRY_RUN, _VERBOSE, _FORMAT and _COLOR still work.
+
+		The 'scan' section configures the secret and PII scanner applied to every
+		body before it is posted. Built-in detectors: private-key, github-token,
+		aws-access-key, aws-secret-key, slack-token, connection-string (block by
+		default), email and entropy (warn by default). Each can be set to block,
+		redact, warn or off under 'scan.detectors'; 'scan.custom' adds regexes and
+		'scan.allow' lists regexes of values never to report.
 	`),
 	Example: heredoc.Doc(`
 		# Generate a new config file
@@ -42,6 +72,15 @@ var configCmd = &cobra.Command{
 		$ gh comment config show --source
 		$ gh comment config show --effective
 
+		# Change a single setting (comments in the file are kept)
+		$ gh comment config set display.format json
+		$ gh comment config get display.format
+		$ gh comment config unset display.format --global
+
+		# Use a profile for one command
+		$ gh comment list 123 --profile work
+		$ GH_COMMENT_PROFILE=oss gh comment config show --source
+
 		# Validate configuration
 		$ gh comment config validate
 		$ gh comment config validate ~/.gh-comment.yaml
@@ -78,7 +117,8 @@ var configShowCmd = &cobra.Command{
 	Long: heredoc.Doc(`
 		Show the current configuration, including merged values from all sources.
 
-		Use --source to show which file each setting comes from.
+		Use --source to list every setting with the layer it came from
+		(default, global, project, profile, env or flag).
 		Use --effective to show only the final merged configuration.
 	`),
 	Example: heredoc.Doc(`
@@ -126,7 +166,7 @@ func init() {
 	configInitCmd.Flags().BoolVar(&globalFlag, "global", false, "Create global user config file")
 
 	// Show command flags
-	configShowCmd.Flags().BoolVar(&showSource, "source", false, "Show which file each setting comes from")
+	configShowCmd.Flags().BoolVar(&showSource, "source", false, "Show which layer each setting comes from")
 	configShowCmd.Flags().BoolVar(&showEffective, "effective", false, "Show only effective (merged) configuration")
 
 	// Add to root command
@@ -139,12 +179,11 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	// Determine output file path
 	var outputPath string
 	if globalFlag {
-		homeDir, err := os.UserHomeDir()
+		configDir, err := userConfigDir()
 		if err != nil {
-			return fmt.Errorf("failed to get user home directory: %w", err)
+			return err
 		}
 
-		configDir := filepath.Join(homeDir, ".config", "gh-comment")
 		err = os.MkdirAll(configDir, 0755)
 		if err != nil {
 			return fmt.Errorf("failed to create config directory: %w", err)
@@ -205,22 +244,8 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	}
 
 	if showSource {
-		// Show config with source information
-		fmt.Println("# Configuration sources (in priority order):")
-		fmt.Println("# 1. Command-line flags (highest priority)")
-		fmt.Println("# 2. Environment variables")
-		fmt.Println("# 3. Configuration files")
-		fmt.Println("# 4. Built-in defaults (lowest priority)")
-		fmt.Println()
-
-		// Find and show config file sources
-		configPath, _ := findConfigFile()
-		if configPath != "" {
-			fmt.Printf("# Active config file: %s\n", configPath)
-		} else {
-			fmt.Println("# No config file found - using defaults")
-		}
-		fmt.Println()
+		showConfigSources(GetConfig(), GetConfigSources())
+		return nil
 	}
 
 	// Show current effective config
@@ -233,6 +258,30 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// showConfigSources prints the active layers and every setting with the layer that set it
+func showConfigSources(config *Config, sources *ConfigSources) {
+	fmt.Printf("# Layers (lowest to highest priority): %s\n", strings.Join(configLayers, ", "))
+	for _, layer := range []string{SourceGlobal, SourceProject} {
+		if path, ok := sources.Files[layer]; ok {
+			fmt.Printf("# %s: %s\n", layer, path)
+		}
+	}
+	if sources.Profile != "" {
+		fmt.Printf("# profile: %s (%s)\n", sources.Profile, sources.ProfileReason)
+	}
+	fmt.Println()
+
+	width := 0
+	for _, key := range configKeys {
+		if len(key.Name) > width {
+			width = len(key.Name)
+		}
+	}
+	for _, key := range configKeys {
+		fmt.Printf("%-*s  %-8s  %s\n", width, key.Name, sources.Source(key.Name), formatConfigValue(key.Value(config)))
+	}
+}
+
 func runConfigValidate(cmd *cobra.Command, args []string) error {
 	var configPath string
 	if len(args) > 0 {
@@ -261,6 +261,29 @@ display:
 	})
 }
 
+func TestShowConfigSources(t *testing.T) {
+	config := NewDefaultConfig()
+	config.API.Timeout = 60
+	config.Aliases = map[string]string{"nit": "review --comment"}
+
+	sources := newConfigSources()
+	sources.Files[SourceProject] = "/repo/.gh-comment.yaml"
+	sources.Profile, sources.ProfileReason = "work", "owner acme matches 'acme'"
+	sources.set(SourceProject, []string{"aliases"})
+	sources.set(SourceEnv, []string{"api.timeout"})
+
+	output := captureOutput(func() {
+		showConfigSources(config, sources)
+	})
+
+	assert.Contains(t, output, "# Layers (lowest to highest priority): default, global, project, profile, env, flag\n")
+	assert.Contains(t, output, "# project: /repo/.gh-comment.yaml\n# profile: work (owner acme matches 'acme')\n")
+	assert.Regexp(t, `(?m)^api\.timeout +env +60$`, output)
+	assert.Regexp(t, `(?m)^aliases +project +\{"nit":"review --comment"\}$`, output)
+	assert.Regexp(t, `(?m)^defaults\.repository +default +""$`, output)
+	assert.Regexp(t, `(?m)^notifications\.rules +default +\[\]$`, output)
+}
+
 func TestRunConfigValidate(t *testing.T) {
 	t.Run("validates existing config file", func(t *testing.T) {
 		// Create temporary valid config file
@@ -0,0 +1,485 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// configGetCmd prints one setting
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a setting",
+	Long: heredoc.Doc(`
+		Print the effective value of a setting after all layers are applied.
+
+		Keys are dotted paths into the config file, such as display.format or
+		api.timeout; see 'gh comment config show --source' for the full list.
+		Settings inside a profile are addressed as profiles.<name>.<key>.
+	`),
+	Example: heredoc.Doc(`
+		$ gh comment config get display.format
+		$ gh comment config get aliases
+		$ gh comment config get profiles.work.review.event
+	`),
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigGet,
+}
+
+// configSetCmd writes one setting to a config file
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value in the config file",
+	Long: heredoc.Doc(`
+		Set a value in the project config file, or the user config with --global.
+
+		The project file is the one given with --config, otherwise the nearest
+		.gh-comment.yaml, which is created in the repository root if missing.
+		YAML files are edited in place so comments and key order are kept.
+
+		Values are checked against the type of the setting, and the whole file
+		is validated before it is saved. Maps and lists are given as YAML.
+	`),
+	Example: heredoc.Doc(`
+		$ gh comment config set display.format json
+		$ gh comment config set api.timeout 60 --global
+		$ gh comment config set review.event REQUEST_CHANGES
+		$ gh comment config set profiles.work.behavior.validate true
+		$ gh comment config set profiles.work.match.owners '[acme, acme-labs]'
+	`),
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+// configUnsetCmd removes one setting from a config file
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a value from the config file",
+	Long: heredoc.Doc(`
+		Remove a value from the project config file, or the user config with --global,
+		so the setting falls back to the lower layers. Sections left empty are removed.
+	`),
+	Example: heredoc.Doc(`
+		$ gh comment config unset display.format
+		$ gh comment config unset api.timeout --global
+	`),
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigUnset,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+
+	configSetCmd.Flags().BoolVar(&globalFlag, "global", false, "Write to the user config file instead of the project config")
+	configUnsetCmd.Flags().BoolVar(&globalFlag, "global", false, "Edit the user config file instead of the project config")
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if _, err := configSettingType(key); err != nil {
+		return err
+	}
+
+	var value interface{}
+	if profile, rest, ok := splitProfileKey(key); ok {
+		found := false
+		if settings, exists := GetConfig().Profiles[profile]; exists {
+			value, found = profileSetting(settings, rest)
+		}
+		if !found {
+			return fmt.Errorf("%s is not set", key)
+		}
+	} else {
+		setting, _ := lookupConfigKey(key)
+		value = setting.Value(GetConfig()).Interface()
+	}
+
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Map, reflect.Slice:
+		content, err := yaml.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", key, err)
+		}
+		fmt.Print(string(content))
+	default:
+		fmt.Println(value)
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, raw := args[0], args[1]
+
+	t, err := configSettingType(key)
+	if err != nil {
+		return err
+	}
+	if t.Kind() == reflect.Bool && !isBoolString(raw) {
+		return formatValidationError("value for "+key, raw, "must be true or false")
+	}
+	value, err := parseConfigValue(t, key, raw)
+	if err != nil {
+		return err
+	}
+
+	path, err := configEditPath()
+	if err != nil {
+		return err
+	}
+	err = editConfigFile(path, func(doc configDocument) error {
+		return doc.Set(strings.Split(key, "."), value.Interface())
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Set %s = %s in %s\n", key, formatConfigValue(value), path)
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if _, err := configSettingType(key); err != nil {
+		return err
+	}
+
+	path, err := configEditPath()
+	if err != nil {
+		return err
+	}
+	err = editConfigFile(path, func(doc configDocument) error {
+		if !doc.Unset(strings.Split(key, ".")) {
+			return fmt.Errorf("%s is not set in %s", key, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Unset %s in %s\n", key, path)
+	return nil
+}
+
+// configEditPath returns the file 'config set' and 'config unset' change
+func configEditPath() (string, error) {
+	if globalFlag {
+		return userConfigFile()
+	}
+	if configPath != "" {
+		return configPath, nil
+	}
+	if path, err := findProjectConfigFile(); err == nil {
+		return path, nil
+	}
+	root := findGitRoot()
+	if root == "" {
+		root = "."
+	}
+	return filepath.Join(root, ".gh-comment.yaml"), nil
+}
+
+// configSettingType returns the type stored under a dotted key. Besides the keys
+// in Config it accepts profiles.<name>.<key> and profiles.<name>.match.{owners,remotes}.
+func configSettingType(name string) (reflect.Type, error) {
+	if profile, rest, ok := splitProfileKey(name); ok {
+		if profile == "" || rest == "" {
+			return nil, formatValidationError("config key", name, "expected profiles.<name>.<key>")
+		}
+		switch rest {
+		case "match.owners", "match.remotes":
+			return reflect.TypeOf([]string{}), nil
+		}
+		name = rest
+	}
+
+	if key, ok := lookupConfigKey(name); ok {
+		return key.Value(NewDefaultConfig()).Type(), nil
+	}
+	if isConfigSection(name) {
+		return nil, formatValidationError("config key", name, "is a section, not a setting (for example "+firstKeyInSection(name)+")")
+	}
+	return nil, formatValidationError("config key", name, "not a known setting (see 'gh comment config show --source' for all keys)")
+}
+
+// splitProfileKey splits "profiles.work.review.event" into "work" and "review.event"
+func splitProfileKey(name string) (profile, rest string, ok bool) {
+	if !strings.HasPrefix(name, "profiles.") {
+		return "", "", false
+	}
+	profile, rest, _ = strings.Cut(strings.TrimPrefix(name, "profiles."), ".")
+	return profile, rest, true
+}
+
+// profileSetting looks up a dotted key inside a profile
+func profileSetting(profile ProfileConfig, key string) (interface{}, bool) {
+	switch key {
+	case "match.owners":
+		return profile.Match.Owners, len(profile.Match.Owners) > 0
+	case "match.remotes":
+		return profile.Match.Remotes, len(profile.Match.Remotes) > 0
+	}
+
+	var value interface{} = profile.Settings
+	for _, part := range strings.Split(key, ".") {
+		section, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = section[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// firstKeyInSection returns an example key inside a config section
+func firstKeyInSection(section string) string {
+	for _, key := range configKeys {
+		if strings.HasPrefix(key.Name, section+".") {
+			return key.Name
+		}
+	}
+	return section
+}
+
+// isBoolString reports whether s is one of the spellings parseBool understands
+func isBoolString(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "false", "yes", "no", "on", "off", "1", "0":
+		return true
+	}
+	return false
+}
+
+// configDocument is a config file being edited
+type configDocument interface {
+	Set(path []string, value interface{}) error
+	Unset(path []string) bool
+}
+
+// editConfigFile applies edit to a config file, validates the result and saves it.
+// The file is created if it doesn't exist and left untouched if validation fails.
+func editConfigFile(path string, edit func(doc configDocument) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	isJSON := strings.EqualFold(filepath.Ext(path), ".json")
+	var doc interface {
+		configDocument
+		Bytes() ([]byte, error)
+	}
+	if isJSON {
+		doc, err = newJSONConfigDocument(data)
+	} else {
+		doc, err = newYAMLConfigDocument(data)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := edit(doc); err != nil {
+		return err
+	}
+	content, err := doc.Bytes()
+	if err != nil {
+		return err
+	}
+
+	// Validate the file as it would be loaded
+	config := NewDefaultConfig()
+	if isJSON {
+		err = json.Unmarshal(content, config)
+	} else {
+		err = yaml.Unmarshal(content, config)
+	}
+	if err == nil {
+		err = validateConfig(config)
+	}
+	if err != nil {
+		return fmt.Errorf("not saving %s: invalid configuration: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// yamlConfigDocument edits a YAML config in place, keeping comments and key order
+type yamlConfigDocument struct {
+	doc  *yaml.Node
+	root *yaml.Node
+}
+
+func newYAMLConfigDocument(data []byte) (*yamlConfigDocument, error) {
+	doc, root, err := parseYAMLConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	return &yamlConfigDocument{doc: doc, root: root}, nil
+}
+
+func (d *yamlConfigDocument) Set(path []string, value interface{}) error {
+	mapping := d.root
+	for _, part := range path[:len(path)-1] {
+		next := yamlMappingValue(mapping, part)
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, next)
+		}
+		if next.Kind != yaml.MappingNode || len(next.Content) == 0 {
+			// Replace scalars and empty '{}' or null sections with a block mapping
+			*next = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", HeadComment: next.HeadComment, LineComment: next.LineComment}
+		}
+		mapping = next
+	}
+
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+
+	last := path[len(path)-1]
+	if existing := yamlMappingValue(mapping, last); existing != nil {
+		node.HeadComment, node.LineComment, node.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+		*existing = node
+		return nil
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: last}, &node)
+	return nil
+}
+
+func (d *yamlConfigDocument) Unset(path []string) bool {
+	return unsetYAMLPath(d.root, path)
+}
+
+// unsetYAMLPath removes path from a mapping, dropping sections it leaves empty
+func unsetYAMLPath(mapping *yaml.Node, path []string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) > 1 {
+			value := mapping.Content[i+1]
+			if value.Kind != yaml.MappingNode || !unsetYAMLPath(value, path[1:]) {
+				return false
+			}
+			if len(value.Content) > 0 {
+				return true
+			}
+		}
+		mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		return true
+	}
+	return false
+}
+
+func (d *yamlConfigDocument) Bytes() ([]byte, error) {
+	return encodeYAMLConfig(d.doc)
+}
+
+// jsonConfigDocument edits a JSON config; JSON has no comments and keys are written sorted
+type jsonConfigDocument struct {
+	settings map[string]interface{}
+}
+
+func newJSONConfigDocument(data []byte) (*jsonConfigDocument, error) {
+	settings := make(map[string]interface{})
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+	return &jsonConfigDocument{settings: settings}, nil
+}
+
+func (d *jsonConfigDocument) Set(path []string, value interface{}) error {
+	section := d.settings
+	for _, part := range path[:len(path)-1] {
+		next, ok := section[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			section[part] = next
+		}
+		section = next
+	}
+	section[path[len(path)-1]] = value
+	return nil
+}
+
+func (d *jsonConfigDocument) Unset(path []string) bool {
+	return unsetJSONPath(d.settings, path)
+}
+
+// unsetJSONPath removes path from a decoded JSON object, dropping sections it leaves empty
+func unsetJSONPath(section map[string]interface{}, path []string) bool {
+	value, ok := section[path[0]]
+	if !ok {
+		return false
+	}
+	if len(path) > 1 {
+		nested, ok := value.(map[string]interface{})
+		if !ok || !unsetJSONPath(nested, path[1:]) {
+			return false
+		}
+		if len(nested) > 0 {
+			return true
+		}
+	}
+	delete(section, path[0])
+	return true
+}
+
+func (d *jsonConfigDocument) Bytes() ([]byte, error) {
+	content, err := json.MarshalIndent(d.settings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// parseYAMLConfig parses a YAML config file into a document node and its top-level mapping.
+// Empty input yields an empty document.
+func parseYAMLConfig(data []byte) (*yaml.Node, *yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("failed to parse config file: top level must be a mapping")
+	}
+	return &doc, root, nil
+}
+
+// encodeYAMLConfig writes a config document with a two-space indent
+func encodeYAMLConfig(doc *yaml.Node) ([]byte, error) {
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	return []byte(out.String()), nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSettingType(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    reflect.Kind
+		wantErr string
+	}{
+		{"display.format", reflect.String, ""},
+		{"api.timeout", reflect.Int, ""},
+		{"behavior.dry_run", reflect.Bool, ""},
+		{"aliases", reflect.Map, ""},
+		{"notifications.rules", reflect.Slice, ""},
+		{"profiles.work.review.event", reflect.String, ""},
+		{"profiles.work.match.owners", reflect.Slice, ""},
+		{"display", 0, "is a section, not a setting (for example display.format)"},
+		{"display.colour", 0, "not a known setting"},
+		{"profiles.work", 0, "expected profiles.<name>.<key>"},
+		{"profiles.work.profiles.x", 0, "not a known setting"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			typ, err := configSettingType(tt.key)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, typ.Kind())
+		})
+	}
+}
+
+func TestEditConfigFileYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gh-comment.yaml")
+	original := "# Team config\ndefaults:\n  author: me # that's me\ndisplay:\n  format: table # preferred\n  color: auto\nfilters: {}\n"
+	require.NoError(t, os.WriteFile(path, []byte(original), 0644))
+
+	set := func(key string, value interface{}) error {
+		return editConfigFile(path, func(doc configDocument) error {
+			return doc.Set(strings.Split(key, "."), value)
+		})
+	}
+	read := func() string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	require.NoError(t, set("display.format", "json"))
+	require.NoError(t, set("api.timeout", 60))
+	require.NoError(t, set("filters.status", "open"))
+	require.NoError(t, set("profiles.work.match.owners", []string{"acme"}))
+	assert.Equal(t, "# Team config\ndefaults:\n  author: me # that's me\ndisplay:\n  format: json # preferred\n  color: auto\nfilters:\n  status: open\napi:\n  timeout: 60\nprofiles:\n  work:\n    match:\n      owners:\n        - acme\n", read())
+
+	// Invalid results are not written
+	before := read()
+	assert.ErrorContains(t, set("review.event", "MERGE"), "not saving "+path+": invalid configuration: invalid review event: MERGE")
+	assert.Equal(t, before, read())
+
+	// Unset drops sections that become empty
+	unset := func(key string) error {
+		return editConfigFile(path, func(doc configDocument) error {
+			if !doc.Unset(strings.Split(key, ".")) {
+				return assert.AnError
+			}
+			return nil
+		})
+	}
+	require.NoError(t, unset("api.timeout"))
+	require.NoError(t, unset("profiles.work.match.owners"))
+	require.NoError(t, unset("display.color"))
+	assert.Equal(t, assert.AnError, unset("display.color"))
+	assert.Equal(t, assert.AnError, unset("defaults.author.name"))
+	assert.Equal(t, "# Team config\ndefaults:\n  author: me # that's me\ndisplay:\n  format: json # preferred\nfilters:\n  status: open\n", read())
+}
+
+func TestEditConfigFileJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"display": {"format": "table"}, "aliases": {"nit": "review --comment"}}`), 0644))
+
+	require.NoError(t, editConfigFile(path, func(doc configDocument) error {
+		if err := doc.Set([]string{"api", "timeout"}, 45); err != nil {
+			return err
+		}
+		doc.Unset([]string{"display", "format"})
+		return nil
+	}))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, 45, config.API.Timeout)
+	assert.Equal(t, "default", config.Display.Format)
+	assert.Equal(t, "review --comment", config.Aliases["nit"])
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "display")
+}
+
+func TestRunConfigSetGetUnset(t *testing.T) {
+	originalConfigPath, originalGlobal, originalConfig := configPath, globalFlag, globalConfig
+	defer func() {
+		configPath, globalFlag, globalConfig = originalConfigPath, originalGlobal, originalConfig
+	}()
+	t.Setenv("HOME", t.TempDir())
+	configPath, globalFlag = filepath.Join(t.TempDir(), ".gh-comment.yaml"), false
+
+	output := captureOutput(func() {
+		require.NoError(t, runConfigSet(configSetCmd, []string{"display.format", "json"}))
+		require.NoError(t, runConfigSet(configSetCmd, []string{"behavior.validate", "off"}))
+		require.NoError(t, runConfigSet(configSetCmd, []string{"aliases", "{nit: review --comment}"}))
+	})
+	assert.Contains(t, output, "✅ Set display.format = json in "+configPath)
+	assert.Contains(t, output, "✅ Set behavior.validate = false in "+configPath)
+
+	assert.ErrorContains(t, runConfigSet(configSetCmd, []string{"behavior.validate", "maybe"}), "must be true or false")
+	assert.ErrorContains(t, runConfigSet(configSetCmd, []string{"api.timeout", "soon"}), "api.timeout must be an integer")
+	assert.ErrorContains(t, runConfigSet(configSetCmd, []string{"display.fromat", "json"}), "not a known setting")
+
+	require.NoError(t, LoadGlobalConfig(configPath))
+	output = captureOutput(func() {
+		require.NoError(t, runConfigGet(configGetCmd, []string{"display.format"}))
+		require.NoError(t, runConfigGet(configGetCmd, []string{"api.timeout"}))
+		require.NoError(t, runConfigGet(configGetCmd, []string{"aliases"}))
+	})
+	assert.Equal(t, "json\n30\nnit: review --comment\n", output)
+	assert.EqualError(t, runConfigGet(configGetCmd, []string{"profiles.work.review.event"}), "profiles.work.review.event is not set")
+
+	captureOutput(func() {
+		require.NoError(t, runConfigUnset(configUnsetCmd, []string{"display.format"}))
+	})
+	assert.ErrorContains(t, runConfigUnset(configUnsetCmd, []string{"display.format"}), "display.format is not set in "+configPath)
+
+	// --global edits the user config
+	globalFlag = true
+	captureOutput(func() {
+		require.NoError(t, runConfigSet(configSetCmd, []string{"api.timeout", "90"}))
+	})
+	userPath, err := userConfigFile()
+	require.NoError(t, err)
+	config, err := LoadConfig(userPath)
+	require.NoError(t, err)
+	assert.Equal(t, 90, config.API.Timeout)
+}
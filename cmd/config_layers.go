@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration layers, lowest priority first
+const (
+	SourceDefault = "default"
+	SourceGlobal  = "global"
+	SourceProject = "project"
+	SourceProfile = "profile"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// configLayers lists the layers in the order they are applied
+var configLayers = []string{SourceDefault, SourceGlobal, SourceProject, SourceProfile, SourceEnv, SourceFlag}
+
+// ConfigSources records where the loaded configuration came from
+type ConfigSources struct {
+	Files         map[string]string // layer -> config file path
+	Profile       string            // selected profile, if any
+	ProfileReason string            // why the profile was selected
+	Keys          map[string]string // dotted key -> layer that last set it
+}
+
+// globalConfigSources describes how globalConfig was built
+var globalConfigSources *ConfigSources
+
+func newConfigSources() *ConfigSources {
+	return &ConfigSources{
+		Files: make(map[string]string),
+		Keys:  make(map[string]string),
+	}
+}
+
+// set records that layer set the given keys
+func (s *ConfigSources) set(layer string, keys []string) {
+	for _, key := range keys {
+		s.Keys[key] = layer
+	}
+}
+
+// Source returns the layer that set key
+func (s *ConfigSources) Source(key string) string {
+	if layer, ok := s.Keys[key]; ok {
+		return layer
+	}
+	return SourceDefault
+}
+
+// GetConfigSources returns the sources of the global configuration
+func GetConfigSources() *ConfigSources {
+	if globalConfigSources == nil {
+		return newConfigSources()
+	}
+	return globalConfigSources
+}
+
+// configKey is a setting addressable by a dotted key such as "api.timeout"
+type configKey struct {
+	Name  string
+	Index []int // field index path into Config
+}
+
+// EnvVar returns the environment variable that overrides the key
+func (k configKey) EnvVar() string {
+	return "GH_COMMENT_" + strings.ToUpper(strings.ReplaceAll(k.Name, ".", "_"))
+}
+
+// Value returns the field holding the key in config
+func (k configKey) Value(config *Config) reflect.Value {
+	return reflect.ValueOf(config).Elem().FieldByIndex(k.Index)
+}
+
+// configKeys lists every setting in Config, in declaration order.
+// Maps and lists (aliases, snippets, notification rules) are single keys.
+var configKeys = buildConfigKeys()
+
+func buildConfigKeys() []configKey {
+	var keys []configKey
+	var walk func(t reflect.Type, prefix string, index []int)
+	walk = func(t reflect.Type, prefix string, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name == "" || name == "-" || (prefix == "" && name == "profiles") {
+				continue
+			}
+
+			path := append(append([]int{}, index...), i)
+			if field.Type.Kind() == reflect.Struct {
+				walk(field.Type, prefix+name+".", path)
+				continue
+			}
+			keys = append(keys, configKey{Name: prefix + name, Index: path})
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "", nil)
+	return keys
+}
+
+// lookupConfigKey finds a setting by its dotted key
+func lookupConfigKey(name string) (configKey, bool) {
+	for _, key := range configKeys {
+		if key.Name == name {
+			return key, true
+		}
+	}
+	return configKey{}, false
+}
+
+// isConfigSection reports whether name is a section such as "api" holding other keys
+func isConfigSection(name string) bool {
+	for _, key := range configKeys {
+		if strings.HasPrefix(key.Name, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// setConfigValue parses raw for the key's type and stores it in config.
+// Maps and lists are given as YAML, e.g. '{nit: review --comment}'.
+func setConfigValue(config *Config, name, raw string) error {
+	key, ok := lookupConfigKey(name)
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", name)
+	}
+
+	parsed, err := parseConfigValue(key.Value(config).Type(), name, raw)
+	if err != nil {
+		return err
+	}
+	key.Value(config).Set(parsed)
+	return nil
+}
+
+// parseConfigValue parses raw as a value of type t. Maps and lists are given as YAML.
+func parseConfigValue(t reflect.Type, name, raw string) (reflect.Value, error) {
+	value := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		value.SetBool(parseBool(raw))
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return value, fmt.Errorf("%s must be an integer, got %q", name, raw)
+		}
+		value.SetInt(int64(n))
+	default:
+		if err := yaml.Unmarshal([]byte(raw), value.Addr().Interface()); err != nil {
+			return value, fmt.Errorf("%s must be YAML: %w", name, err)
+		}
+	}
+	return value, nil
+}
+
+// formatConfigValue renders a setting on one line
+func formatConfigValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		if value.String() == "" {
+			return `""`
+		}
+		return value.String()
+	case reflect.Map, reflect.Slice:
+		if value.Len() == 0 {
+			if value.Kind() == reflect.Map {
+				return "{}"
+			}
+			return "[]"
+		}
+		data, err := json.Marshal(value.Interface())
+		if err != nil {
+			return fmt.Sprintf("%v", value.Interface())
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", value.Interface())
+	}
+}
+
+// configFileKeys returns the keys a config file sets
+func configFileKeys(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := map[string]interface{}{}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return presentConfigKeys(raw), nil
+}
+
+// presentConfigKeys returns the keys present in a decoded config document
+func presentConfigKeys(raw map[string]interface{}) []string {
+	var keys []string
+	for _, key := range configKeys {
+		section := raw
+		parts := strings.Split(key.Name, ".")
+		for i, part := range parts {
+			value, ok := section[part]
+			if !ok {
+				break
+			}
+			if i == len(parts)-1 {
+				keys = append(keys, key.Name)
+				break
+			}
+			if section, ok = value.(map[string]interface{}); !ok {
+				break
+			}
+		}
+	}
+	return keys
+}
+
+// unknownConfigKeys returns the dotted keys in a decoded config document that Config does not have
+func unknownConfigKeys(raw map[string]interface{}, prefix string) []string {
+	var unknown []string
+	for name, value := range raw {
+		full := prefix + name
+		if _, ok := lookupConfigKey(full); ok {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok && isConfigSection(full) {
+			unknown = append(unknown, unknownConfigKeys(nested, full+".")...)
+			continue
+		}
+		unknown = append(unknown, full)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// applyFlagOverrides copies explicitly set global flags into config, the highest priority layer
+func applyFlagOverrides(config *Config, sources *ConfigSources, flags *pflag.FlagSet) {
+	var keys []string
+	if flags.Changed("repo") {
+		config.Defaults.Repository = repo
+		keys = append(keys, "defaults.repository")
+	}
+	if flags.Changed("pr") {
+		config.Defaults.PR = prNumber
+		keys = append(keys, "defaults.pr")
+	}
+	if flags.Changed("dry-run") {
+		config.Behavior.DryRun = dryRun
+		keys = append(keys, "behavior.dry_run")
+	}
+	if flags.Changed("verbose") {
+		config.Behavior.Verbose = verbose
+		keys = append(keys, "behavior.verbose")
+	}
+	if flags.Changed("validate") {
+		config.Behavior.Validate = validateDiff
+		keys = append(keys, "behavior.validate")
+	}
+	if flags.Changed("no-color") && noColor {
+		config.Display.Color = "never"
+		keys = append(keys, "display.color")
+	}
+	sources.set(SourceFlag, keys)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profileName is the --profile flag
+var profileName string
+
+// gitRemoteURL returns the URL of the origin remote, or "" outside a repository.
+// It is a variable so tests can fake the repository.
+var gitRemoteURL = func() string {
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// validateProfiles checks that profiles only use known settings
+func validateProfiles(profiles map[string]ProfileConfig) error {
+	for _, name := range sortedProfileNames(profiles) {
+		profile := profiles[name]
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("profile names cannot be empty")
+		}
+		if unknown := unknownConfigKeys(profile.Settings, ""); len(unknown) > 0 {
+			return fmt.Errorf("profile %s: unknown setting %s", name, strings.Join(unknown, ", "))
+		}
+		for _, pattern := range append(append([]string{}, profile.Match.Owners...), profile.Match.Remotes...) {
+			if strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("profile %s: match patterns cannot be empty", name)
+			}
+		}
+	}
+	return nil
+}
+
+// selectProfile picks the profile named by --profile or GH_COMMENT_PROFILE, or else
+// the first profile (by name) whose match rules fit the current repository.
+// It returns the profile name and why it was chosen; no match selects no profile.
+func selectProfile(config *Config, requested string) (string, string, error) {
+	reason := "--profile"
+	if requested == "" {
+		requested, reason = os.Getenv("GH_COMMENT_PROFILE"), "GH_COMMENT_PROFILE"
+	}
+	if requested != "" {
+		if _, ok := config.Profiles[requested]; !ok {
+			available := "none defined"
+			if len(config.Profiles) > 0 {
+				available = "available: " + strings.Join(sortedProfileNames(config.Profiles), ", ")
+			}
+			return "", "", fmt.Errorf("unknown profile '%s' (%s)", requested, available)
+		}
+		return requested, reason, nil
+	}
+
+	hasRules := false
+	for _, profile := range config.Profiles {
+		if len(profile.Match.Owners) > 0 || len(profile.Match.Remotes) > 0 {
+			hasRules = true
+			break
+		}
+	}
+	if !hasRules {
+		return "", "", nil
+	}
+
+	remote := gitRemoteURL()
+	owner := currentRepoOwner(config, remote)
+	for _, name := range sortedProfileNames(config.Profiles) {
+		if reason := config.Profiles[name].Match.matches(owner, remote); reason != "" {
+			return name, reason, nil
+		}
+	}
+	return "", "", nil
+}
+
+// matches reports why the rules fit the repository, or "" when they don't
+func (m ProfileMatch) matches(owner, remote string) string {
+	for _, pattern := range m.Owners {
+		if owner != "" && matchesProfileGlob(pattern, owner) {
+			return fmt.Sprintf("owner %s matches '%s'", owner, pattern)
+		}
+	}
+	for _, pattern := range m.Remotes {
+		if remote != "" && matchesProfileGlob(pattern, remote) {
+			return fmt.Sprintf("remote %s matches '%s'", remote, pattern)
+		}
+	}
+	return ""
+}
+
+// currentRepoOwner returns the owner of the repository being worked on: from --repo,
+// GH_REPO or the configured default repository, falling back to the origin remote
+func currentRepoOwner(config *Config, remote string) string {
+	for _, candidate := range []string{repo, os.Getenv("GH_REPO"), config.Defaults.Repository} {
+		if candidate == "" {
+			continue
+		}
+		// GH_REPO may be [HOST/]OWNER/REPO
+		parts := strings.Split(candidate, "/")
+		if len(parts) >= 2 {
+			return parts[len(parts)-2]
+		}
+	}
+	return remoteOwner(remote)
+}
+
+// remoteOwner extracts the owner from a git remote URL such as
+// https://github.com/owner/repo.git or git@github.com:owner/repo.git
+func remoteOwner(remote string) string {
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	parts := strings.FieldsFunc(trimmed, func(r rune) bool { return r == '/' || r == ':' })
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[len(parts)-2]
+}
+
+// matchesProfileGlob matches s against a case-insensitive pattern where '*' matches anything
+func matchesProfileGlob(pattern, s string) bool {
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, `.*`)
+	return regexp.MustCompile("(?i)^" + expr + "$").MatchString(s)
+}
+
+// applyProfile layers the profile's settings over config and returns the keys it set
+func applyProfile(config *Config, profile ProfileConfig) ([]string, error) {
+	if unknown := unknownConfigKeys(profile.Settings, ""); len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown setting %s", strings.Join(unknown, ", "))
+	}
+
+	data, err := yaml.Marshal(profile.Settings)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return presentConfigKeys(profile.Settings), nil
+}
+
+// sortedProfileNames returns profile names in alphabetical order
+func sortedProfileNames(profiles map[string]ProfileConfig) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// withFakeRemote makes profile auto-selection see the given origin remote
+func withFakeRemote(t *testing.T, remote string) {
+	t.Helper()
+	original := gitRemoteURL
+	t.Cleanup(func() { gitRemoteURL = original })
+	gitRemoteURL = func() string { return remote }
+}
+
+func TestProfileConfigDecoding(t *testing.T) {
+	var config Config
+	require.NoError(t, yaml.Unmarshal([]byte(`
+profiles:
+  work:
+    match:
+      owners: [acme]
+    review:
+      event: REQUEST_CHANGES
+`), &config))
+	work := config.Profiles["work"]
+	assert.Equal(t, []string{"acme"}, work.Match.Owners)
+	assert.Equal(t, map[string]interface{}{"review": map[string]interface{}{"event": "REQUEST_CHANGES"}}, work.Settings)
+
+	data, err := json.Marshal(config.Profiles)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"work": {"match": {"owners": ["acme"]}, "review": {"event": "REQUEST_CHANGES"}}}`, string(data))
+
+	var decoded map[string]ProfileConfig
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, config.Profiles, decoded)
+}
+
+func TestSelectProfile(t *testing.T) {
+	originalRepo := repo
+	defer func() { repo = originalRepo }()
+	repo = ""
+	t.Setenv("GH_REPO", "")
+	t.Setenv("GH_COMMENT_PROFILE", "")
+
+	config := NewDefaultConfig()
+	config.Profiles = map[string]ProfileConfig{
+		"oss":  {},
+		"work": {Match: ProfileMatch{Owners: []string{"acme*"}}},
+		"ghe":  {Match: ProfileMatch{Remotes: []string{"*github.example.com*"}}},
+	}
+
+	tests := []struct {
+		name      string
+		requested string
+		env       string
+		repo      string
+		remote    string
+		want      string
+		reason    string
+	}{
+		{"explicit profile", "oss", "", "", "git@github.com:acme/api.git", "oss", "--profile"},
+		{"environment variable", "", "oss", "", "", "oss", "GH_COMMENT_PROFILE"},
+		{"owner from remote", "", "", "", "git@github.com:ACME-corp/api.git", "work", "owner ACME-corp matches 'acme*'"},
+		{"owner from --repo wins over remote", "", "", "someone/else", "https://github.com/acme/api", "", ""},
+		{"remote URL", "", "", "", "https://github.example.com/team/api.git", "ghe", "remote https://github.example.com/team/api.git matches '*github.example.com*'"},
+		{"no match", "", "", "", "https://github.com/other/api", "", ""},
+		{"outside a repository", "", "", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_COMMENT_PROFILE", tt.env)
+			withFakeRemote(t, tt.remote)
+			repo = tt.repo
+
+			name, reason, err := selectProfile(config, tt.requested)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, name)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+
+	repo = ""
+	_, _, err := selectProfile(config, "missing")
+	assert.EqualError(t, err, "unknown profile 'missing' (available: ghe, oss, work)")
+	_, _, err = selectProfile(NewDefaultConfig(), "missing")
+	assert.EqualError(t, err, "unknown profile 'missing' (none defined)")
+}
+
+func TestRemoteOwner(t *testing.T) {
+	assert.Equal(t, "acme", remoteOwner("git@github.com:acme/api.git"))
+	assert.Equal(t, "acme", remoteOwner("https://github.com/acme/api.git"))
+	assert.Equal(t, "acme", remoteOwner("ssh://git@github.example.com/acme/api/"))
+	assert.Equal(t, "", remoteOwner(""))
+	assert.Equal(t, "", remoteOwner("api"))
+}
+
+func TestValidateProfiles(t *testing.T) {
+	assert.NoError(t, validateProfiles(map[string]ProfileConfig{
+		"work": {Settings: map[string]interface{}{"api": map[string]interface{}{"timeout": 60}, "aliases": map[string]interface{}{"x": "list"}}},
+	}))
+	assert.EqualError(t, validateProfiles(map[string]ProfileConfig{
+		"work": {Settings: map[string]interface{}{"api": map[string]interface{}{"timeot": 60}, "colour": "never"}},
+	}), "profile work: unknown setting api.timeot, colour")
+	assert.ErrorContains(t, validateProfiles(map[string]ProfileConfig{
+		"nested": {Settings: map[string]interface{}{"profiles": map[string]interface{}{}}},
+	}), "unknown setting profiles")
+	assert.ErrorContains(t, validateProfiles(map[string]ProfileConfig{
+		"work": {Match: ProfileMatch{Owners: []string{" "}}},
+	}), "match patterns cannot be empty")
+}
+
+func TestLoadConfigLayers(t *testing.T) {
+	originalRepo := repo
+	defer func() { repo = originalRepo }()
+	repo = ""
+	t.Setenv("GH_REPO", "")
+	t.Setenv("GH_COMMENT_PROFILE", "")
+	withFakeRemote(t, "git@github.com:acme/api.git")
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	globalDir := filepath.Join(home, ".config", "gh-comment")
+	require.NoError(t, os.MkdirAll(globalDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(`
+defaults:
+  author: alice
+api:
+  timeout: 45
+aliases:
+  mine: list --author alice
+profiles:
+  work:
+    match:
+      owners: [acme]
+    behavior:
+      validate: true
+    review:
+      event: REQUEST_CHANGES
+`), 0644))
+
+	project := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".gh-comment.yaml"), []byte(`
+api:
+  timeout: 20
+  retry_count: 5
+review:
+  event: APPROVE
+aliases:
+  nit: review --comment
+`), 0644))
+	t.Chdir(project)
+	t.Setenv("GH_COMMENT_API_RETRY_COUNT", "7")
+
+	config, sources, err := loadConfigLayers("", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "alice", config.Defaults.Author)
+	assert.Equal(t, 20, config.API.Timeout)
+	assert.Equal(t, 7, config.API.RetryCount)
+	assert.Equal(t, "REQUEST_CHANGES", config.Review.Event)
+	assert.True(t, config.Behavior.Validate)
+	assert.Equal(t, map[string]string{"mine": "list --author alice", "nit": "review --comment"}, config.Aliases)
+
+	assert.Equal(t, filepath.Join(globalDir, "config.yaml"), sources.Files[SourceGlobal])
+	assert.Equal(t, "work", sources.Profile)
+	assert.Equal(t, "owner acme matches 'acme'", sources.ProfileReason)
+	assert.Equal(t, SourceGlobal, sources.Source("defaults.author"))
+	assert.Equal(t, SourceProject, sources.Source("api.timeout"))
+	assert.Equal(t, SourceEnv, sources.Source("api.retry_count"))
+	assert.Equal(t, SourceProfile, sources.Source("review.event"))
+	assert.Equal(t, SourceProject, sources.Source("aliases"))
+	assert.Equal(t, SourceDefault, sources.Source("display.format"))
+
+	// An explicit profile replaces auto-selection, and an explicit file replaces the search
+	config, sources, err = loadConfigLayers(filepath.Join(project, ".gh-comment.yaml"), "")
+	require.NoError(t, err)
+	assert.Equal(t, SourceProject, sources.Source("api.timeout"))
+	assert.Empty(t, sources.Files[SourceGlobal])
+	assert.Equal(t, "", config.Defaults.Author)
+
+	_, _, err = loadConfigLayers("", "missing")
+	assert.ErrorContains(t, err, "unknown profile 'missing' (available: work)")
+}
+
+func TestApplyFlagOverrides(t *testing.T) {
+	originalRepo, originalDryRun, originalNoColor := repo, dryRun, noColor
+	defer func() {
+		repo, dryRun, noColor = originalRepo, originalDryRun, originalNoColor
+		_ = rootCmd.PersistentFlags().Set("repo", originalRepo)
+		for _, name := range []string{"repo", "dry-run", "no-color"} {
+			rootCmd.PersistentFlags().Lookup(name).Changed = false
+		}
+	}()
+
+	flags := rootCmd.PersistentFlags()
+	require.NoError(t, flags.Set("repo", "owner/flagged"))
+	require.NoError(t, flags.Set("dry-run", "true"))
+	require.NoError(t, flags.Set("no-color", "true"))
+
+	config := NewDefaultConfig()
+	sources := newConfigSources()
+	applyFlagOverrides(config, sources, flags)
+
+	assert.Equal(t, "owner/flagged", config.Defaults.Repository)
+	assert.True(t, config.Behavior.DryRun)
+	assert.Equal(t, "never", config.Display.Color)
+	assert.Equal(t, SourceFlag, sources.Source("defaults.repository"))
+	assert.Equal(t, SourceFlag, sources.Source("display.color"))
+	assert.Equal(t, SourceDefault, sources.Source("behavior.verbose"))
+}
@@ -24,7 +24,7 @@ func TestNewDefaultConfig(t *testing.T) {
 	assert.Equal(t, true, config.Behavior.Validate)
 	assert.Equal(t, false, config.Behavior.NoExpandSuggestions)
 
-	assert.Equal(t, "table", config.Display.Format)
+	assert.Equal(t, "default", config.Display.Format)
 	assert.Equal(t, "auto", config.Display.Color)
 	assert.Equal(t, false, config.Display.Quiet)
 
@@ -370,6 +370,55 @@ func TestApplyEnvironmentOverrides(t *testing.T) {
 	assert.Equal(t, "always", config.Display.Color)
 }
 
+func TestApplyEnvironmentOverridesEveryKey(t *testing.T) {
+	t.Setenv("GH_COMMENT_API_TIMEOUT", "60")
+	t.Setenv("GH_COMMENT_FILTERS_SHOW_ALL", "yes")
+	t.Setenv("GH_COMMENT_TEMPLATES_DEFAULT_REVIEW_BODY", "Reviewed")
+	t.Setenv("GH_COMMENT_ALIASES", "{nit: review --comment}")
+	t.Setenv("GH_COMMENT_FORMAT", "json")
+	t.Setenv("GH_COMMENT_DISPLAY_FORMAT", "table")
+
+	config := NewDefaultConfig()
+	keys, err := applyEnvironmentOverrides(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, 60, config.API.Timeout)
+	assert.True(t, config.Filters.ShowAll)
+	assert.Equal(t, "Reviewed", config.Templates.DefaultReviewBody)
+	assert.Equal(t, map[string]string{"nit": "review --comment"}, config.Aliases)
+	assert.Equal(t, "table", config.Display.Format, "the full variable name wins over the short form")
+	assert.Subset(t, keys, []string{"api.timeout", "filters.show_all", "aliases", "display.format"})
+
+	t.Setenv("GH_COMMENT_API_TIMEOUT", "soon")
+	_, err = applyEnvironmentOverrides(NewDefaultConfig())
+	assert.EqualError(t, err, `GH_COMMENT_API_TIMEOUT: api.timeout must be an integer, got "soon"`)
+}
+
+func TestConfigKeys(t *testing.T) {
+	key, ok := lookupConfigKey("behavior.no_expand_suggestions")
+	require.True(t, ok)
+	assert.Equal(t, "GH_COMMENT_BEHAVIOR_NO_EXPAND_SUGGESTIONS", key.EnvVar())
+	assert.Equal(t, false, key.Value(NewDefaultConfig()).Interface())
+
+	for _, name := range []string{"defaults.repository", "aliases", "templates.snippets", "notifications.rules"} {
+		_, ok := lookupConfigKey(name)
+		assert.True(t, ok, name)
+	}
+	for _, name := range []string{"profiles", "api", "api.nope"} {
+		_, ok := lookupConfigKey(name)
+		assert.False(t, ok, name)
+	}
+	assert.True(t, isConfigSection("api"))
+	assert.False(t, isConfigSection("aliases"))
+
+	assert.Equal(t, []string{"defaults.author", "api.timeout", "aliases"}, presentConfigKeys(map[string]interface{}{
+		"defaults": map[string]interface{}{"author": "me"},
+		"api":      map[string]interface{}{"timeout": 5},
+		"aliases":  nil,
+		"unknown":  "x",
+	}))
+}
+
 func TestParseBool(t *testing.T) {
 	tests := []struct {
 		input string
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 
@@ -13,6 +16,11 @@ import (
 
 var (
 	editMessages []string
+	editYes      bool
+	editAppend   string
+	editPrepend  string
+	editReplace  string
+	editCheck    int
 
 	// Client for dependency injection (tests can override)
 	editClient github.GitHubAPI
@@ -27,6 +35,18 @@ var editCmd = &cobra.Command{
 		You can edit with a new message using either positional argument or --message flags.
 		Use the comment ID from the URL shown in 'gh comment list' output.
 
+		For small changes, edit the current text instead of rewriting it:
+		--append and --prepend add a paragraph, --replace rewrites regex matches
+		(use $1 for capture groups), and --check ticks or unticks the Nth task
+		list item. If the comment changes after it was read, the edit is refused
+		so nobody's update is overwritten.
+
+		Before anything is changed, a word-level diff of the current and new text
+		is shown and you are asked to confirm. Pass --yes to skip the question.
+		Without a terminal, such as in scripts and pipes, nobody can answer, so
+		the diff is shown and the edit is applied without asking.
+		Earlier versions stay available with 'gh comment history'.
+
 		Common use cases:
 		- Fix typos in comments: "Fixed typo in previous comment"
 		- Add more context: "Adding more details about the implementation"
@@ -40,6 +60,18 @@ var editCmd = &cobra.Command{
 		# Edit with multi-line content using --message flags (AI-friendly)
 		$ gh comment edit 2246362251 --message "First paragraph" --message "Second paragraph"
 
+		# Add a status line below the existing text
+		$ gh comment edit 2246362251 --append "UPDATE: fixed in abc123"
+
+		# Fix a word everywhere it appears
+		$ gh comment edit 2246362251 --replace 'recieve=>receive'
+
+		# Tick the second checkbox of a task list
+		$ gh comment edit 2246362251 --check 2
+
+		# Edit from a script without the confirmation prompt
+		$ gh comment edit 2246362251 "Updated comment" --yes
+
 		# Edit with multi-line content (shell native)
 		$ gh comment edit 2246362251 "Line 1
 		Line 2
@@ -52,6 +84,11 @@ var editCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(editCmd)
 	editCmd.Flags().StringArrayVarP(&editMessages, "message", "m", []string{}, "Edit message (can be used multiple times for multi-line comments)")
+	editCmd.Flags().StringVar(&editAppend, "append", "", "Add text as a new paragraph after the current body")
+	editCmd.Flags().StringVar(&editPrepend, "prepend", "", "Add text as a new paragraph before the current body")
+	editCmd.Flags().StringVar(&editReplace, "replace", "", "Rewrite matches of a regex: 'regex=>replacement' ($1 refers to groups)")
+	editCmd.Flags().IntVar(&editCheck, "check", 0, "Check or uncheck the Nth task list item (counting from 1)")
+	editCmd.Flags().BoolVarP(&editYes, "yes", "y", false, "Apply the edit without asking for confirmation")
 }
 
 func runEdit(cmd *cobra.Command, args []string) error {
@@ -70,15 +107,47 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Work out which kind of edit was asked for
+	hasMessage := len(args) == 2 || len(editMessages) > 0
+	modes := 0
+	for _, set := range []bool{hasMessage, editAppend != "", editPrepend != "", editReplace != "", editCheck != 0} {
+		if set {
+			modes++
+		}
+	}
+	if modes == 0 {
+		return fmt.Errorf("must provide either a message argument or --message flags (or --append, --prepend, --replace or --check)")
+	}
+	if modes > 1 {
+		return fmt.Errorf("only one of a new message, --append, --prepend, --replace or --check can be used")
+	}
+	if editCheck < 0 {
+		return formatValidationError("check", strconv.Itoa(editCheck), "must be a positive task list item number")
+	}
+
 	var message string
+	var replacePattern *regexp.Regexp
 
 	// Handle message from positional arg or --message flags
-	if len(args) == 2 {
+	switch {
+	case len(args) == 2:
 		message = args[1]
-	} else if len(editMessages) > 0 {
+	case len(editMessages) > 0:
 		message = strings.Join(editMessages, "\n")
-	} else {
-		return fmt.Errorf("must provide either a message argument or --message flags")
+	case editAppend != "":
+		message = editAppend
+	case editPrepend != "":
+		message = editPrepend
+	case editReplace != "":
+		pattern, replacement, ok := strings.Cut(editReplace, "=>")
+		if !ok || pattern == "" {
+			return formatValidationError("replace", editReplace, "must be in the form 'regex=>replacement'")
+		}
+		replacePattern, err = regexp.Compile(pattern)
+		if err != nil {
+			return formatValidationError("replace", pattern, fmt.Sprintf("must be a valid regular expression: %v", err))
+		}
+		message = replacement
 	}
 
 	// Validate comment body length
@@ -86,6 +155,12 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Check for secrets and personal data before anything is sent
+	message, err = scanCommentBody("comment body", message)
+	if err != nil {
+		return err
+	}
+
 	// Get repository context
 	repository, prNumber, err := getPRContext()
 	if err != nil {
@@ -111,14 +186,77 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// Every edit starts from the current text, which is also what the preview diffs against
+	info, err := github.FindComment(editClient, owner, repoName, commentID, prNumber)
+	if err != nil {
+		return formatActionableError("comment lookup", err)
+	}
+	if !info.Found {
+		return formatNotFoundError("comment", commentID)
+	}
+	current := info.Comment
+
+	newBody := message
+	switch {
+	case editAppend != "":
+		newBody = joinParagraphs(current.Body, message)
+	case editPrepend != "":
+		newBody = joinParagraphs(message, current.Body)
+	case replacePattern != nil:
+		if !replacePattern.MatchString(current.Body) {
+			return fmt.Errorf("--replace pattern %q does not match comment #%d", replacePattern.String(), commentID)
+		}
+		newBody = replacePattern.ReplaceAllString(current.Body, message)
+	case editCheck > 0:
+		newBody, err = toggleTaskItem(current.Body, editCheck)
+		if err != nil {
+			return err
+		}
+	}
+	if newBody != message {
+		if err := validateCommentBody(newBody); err != nil {
+			return err
+		}
+	}
+
+	if newBody == current.Body {
+		fmt.Printf("Comment #%d already has this text; nothing to edit\n", commentID)
+		return nil
+	}
+	fmt.Printf("Changes to comment #%d:\n\n%s\n\n", commentID, renderWordDiff(wordDiff(current.Body, newBody)))
+
 	if dryRun {
-		fmt.Printf("Would edit comment #%d:\n", commentID)
-		fmt.Printf("New message: %s\n", message)
+		fmt.Printf("Would edit comment #%d\n", commentID)
 		return nil
 	}
 
+	// Scripts and pipes cannot answer, so only ask when someone is at a terminal
+	if !editYes && stdinIsTerminal() {
+		confirmed, err := confirmAction("Apply this edit?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Edit cancelled")
+			return nil
+		}
+	}
+
+	// Refuse to overwrite changes made since the body was read, e.g. while confirming
+	latest, err := github.FindComment(editClient, owner, repoName, commentID, prNumber)
+	if err != nil {
+		return formatActionableError("comment lookup", err)
+	}
+	if !latest.Found {
+		return formatNotFoundError("comment", commentID)
+	}
+	if !latest.Comment.UpdatedAt.Equal(current.UpdatedAt) {
+		return fmt.Errorf("comment #%d was changed at %s after it was read; run the edit again to apply it to the latest text",
+			commentID, latest.Comment.UpdatedAt.Format(time.RFC3339))
+	}
+
 	// Edit the comment using the client
-	err = editClient.EditComment(owner, repoName, commentID, prNumber, message)
+	err = editClient.EditComment(owner, repoName, commentID, prNumber, newBody)
 	if err != nil {
 		return formatActionableError("comment editing", err)
 	}
@@ -126,3 +264,55 @@ func runEdit(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s\n", ColorizeSuccess(fmt.Sprintf("Edited comment #%d", commentID)))
 	return nil
 }
+
+// editTaskPattern matches a task list item: the text up to the box, the box's mark, and the rest
+var editTaskPattern = regexp.MustCompile(`^(\s*(?:[-*+]|\d{1,9}[.)])\s+\[)([ xX])(\](?:\s.*)?)$`)
+
+// joinParagraphs puts two pieces of text in separate markdown paragraphs
+func joinParagraphs(first, second string) string {
+	first = strings.TrimRight(first, "\r\n")
+	second = strings.TrimLeft(second, "\r\n")
+	if first == "" || second == "" {
+		return first + second
+	}
+	return first + "\n\n" + second
+}
+
+// toggleTaskItem checks or unchecks the nth task list item, counting from 1 and
+// skipping anything inside code blocks
+func toggleTaskItem(body string, n int) (string, error) {
+	lines := strings.Split(body, "\n")
+	fence := ""
+	count := 0
+	for i, line := range lines {
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				fence = ""
+			}
+			continue
+		}
+		if match := mdFencePattern.FindStringSubmatch(line); match != nil {
+			fence = match[1]
+			continue
+		}
+
+		match := editTaskPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		count++
+		if count == n {
+			mark := "x"
+			if match[2] != " " {
+				mark = " "
+			}
+			lines[i] = match[1] + mark + match[3]
+			return strings.Join(lines, "\n"), nil
+		}
+	}
+
+	if count == 0 {
+		return "", fmt.Errorf("comment has no task list items to check")
+	}
+	return "", formatValidationError("check", strconv.Itoa(n), fmt.Sprintf("must be a task list item between 1 and %d", count))
+}
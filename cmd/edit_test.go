@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/silouanwright/gh-comment/internal/github"
 )
@@ -20,6 +23,8 @@ func TestRunEditWithMockClient(t *testing.T) {
 	// Set up mock client and environment
 	mockClient := github.NewMockClient()
 	editClient = mockClient
+	editYes = true // no terminal to confirm from
+	t.Cleanup(func() { editYes = false })
 	repo = "owner/repo"
 
 	tests := []struct {
@@ -127,6 +132,8 @@ func TestRunEditDryRun(t *testing.T) {
 	// Set up environment
 	mockClient := github.NewMockClient()
 	editClient = mockClient
+	editYes = true // no terminal to confirm from
+	t.Cleanup(func() { editYes = false })
 	repo = "owner/repo"
 	prNumber = 123
 	dryRun = true
@@ -151,6 +158,8 @@ func TestRunEditVerbose(t *testing.T) {
 	// Set up environment
 	mockClient := github.NewMockClient()
 	editClient = mockClient
+	editYes = true // no terminal to confirm from
+	t.Cleanup(func() { editYes = false })
 	repo = "owner/repo"
 	prNumber = 123
 	verbose = true
@@ -172,6 +181,8 @@ func TestEditMessageHandling(t *testing.T) {
 	// Set up mock client and environment
 	mockClient := github.NewMockClient()
 	editClient = mockClient
+	editYes = true // no terminal to confirm from
+	t.Cleanup(func() { editYes = false })
 	repo = "owner/repo"
 
 	tests := []struct {
@@ -253,6 +264,8 @@ func TestEditRepositoryParsing(t *testing.T) {
 
 	mockClient := github.NewMockClient()
 	editClient = mockClient
+	editYes = true // no terminal to confirm from
+	t.Cleanup(func() { editYes = false })
 	prNumber = 123
 
 	tests := []struct {
@@ -303,3 +316,201 @@ func TestEditRepositoryParsing(t *testing.T) {
 		})
 	}
 }
+
+func setupEditConfirmTest(t *testing.T, terminal bool, answer string) *github.MockClient {
+	t.Helper()
+	disableColor(t)
+	originalClient, originalRepo, originalPR, originalYes := editClient, repo, prNumber, editYes
+	originalInput, originalTerminal := confirmInput, stdinIsTerminal
+	t.Cleanup(func() {
+		editClient, repo, prNumber, editYes = originalClient, originalRepo, originalPR, originalYes
+		confirmInput, stdinIsTerminal = originalInput, originalTerminal
+	})
+
+	mockClient := github.NewMockClient()
+	editClient, repo, prNumber, editYes = mockClient, "owner/repo", 123, false
+	confirmInput = strings.NewReader(answer)
+	stdinIsTerminal = func() bool { return terminal }
+	return mockClient
+}
+
+func TestRunEditShowsDiffAndConfirms(t *testing.T) {
+	mockClient := setupEditConfirmTest(t, true, "y\n")
+
+	output := captureOutput(func() {
+		require.NoError(t, runEdit(nil, []string{"123456", "LGTM! Great work on this change."}))
+	})
+	assert.Contains(t, output, "Changes to comment #123456:\n\nLGTM! Great work on this [-PR-]{+change+}.\n")
+	assert.Contains(t, output, "Edited comment #123456")
+	assert.Equal(t, "LGTM! Great work on this change.", mockClient.EditedBodies[123456])
+}
+
+func TestRunEditDeclined(t *testing.T) {
+	mockClient := setupEditConfirmTest(t, true, "n\n")
+
+	output := captureOutput(func() {
+		require.NoError(t, runEdit(nil, []string{"123456", "Changed my mind"}))
+	})
+	assert.Contains(t, output, "Edit cancelled")
+	assert.Empty(t, mockClient.EditedBodies)
+}
+
+func TestRunEditAppliesWithoutTerminal(t *testing.T) {
+	mockClient := setupEditConfirmTest(t, false, "n\n")
+
+	output := captureOutput(func() {
+		require.NoError(t, runEdit(nil, []string{"123456", "Scripted edit"}))
+	})
+	assert.Contains(t, output, "Changes to comment #123456:")
+	assert.Contains(t, output, "Edited comment #123456")
+	assert.Equal(t, "Scripted edit", mockClient.EditedBodies[123456], "stdin is not read for an answer")
+}
+
+func TestRunEditYesSkipsQuestion(t *testing.T) {
+	mockClient := setupEditConfirmTest(t, true, "n\n")
+	editYes = true
+
+	output := captureOutput(func() {
+		require.NoError(t, runEdit(nil, []string{"123456", "Confirmed edit"}))
+	})
+	assert.NotContains(t, output, "Edit cancelled")
+	assert.Equal(t, "Confirmed edit", mockClient.EditedBodies[123456])
+}
+
+func TestRunEditPreviewEdgeCases(t *testing.T) {
+	mockClient := setupEditConfirmTest(t, false, "")
+
+	output := captureOutput(func() {
+		require.NoError(t, runEdit(nil, []string{"123456", "LGTM! Great work on this PR."}))
+	})
+	assert.Equal(t, "Comment #123456 already has this text; nothing to edit\n", output)
+
+	assert.ErrorContains(t, runEdit(nil, []string{"999", "Anything"}), "comment not found: 999")
+
+	originalDryRun := dryRun
+	dryRun = true
+	t.Cleanup(func() { dryRun = originalDryRun })
+	output = captureOutput(func() {
+		require.NoError(t, runEdit(nil, []string{"123456", "Dry run edit"}))
+	})
+	assert.Contains(t, output, "Would edit comment #123456")
+	assert.Empty(t, mockClient.EditedBodies)
+}
+
+// concurrentEditClient simulates someone else editing comments after the first read
+type concurrentEditClient struct {
+	*github.MockClient
+	reads int
+}
+
+func (c *concurrentEditClient) ListIssueComments(owner, repo string, prNumber int) ([]github.Comment, error) {
+	comments, err := c.MockClient.ListIssueComments(owner, repo, prNumber)
+	c.reads++
+	if c.reads == 1 {
+		return comments, err
+	}
+	changed := append([]github.Comment(nil), comments...)
+	for i := range changed {
+		changed[i].UpdatedAt = time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)
+	}
+	return changed, err
+}
+
+func setupEditModesTest(t *testing.T, body string) *github.MockClient {
+	t.Helper()
+	mockClient := setupEditConfirmTest(t, false, "")
+	editYes = true
+	mockClient.IssueComments[0].Body = body
+
+	originalAppend, originalPrepend, originalReplace, originalCheck := editAppend, editPrepend, editReplace, editCheck
+	t.Cleanup(func() {
+		editAppend, editPrepend, editReplace, editCheck = originalAppend, originalPrepend, originalReplace, originalCheck
+		editMessages = []string{}
+	})
+	editAppend, editPrepend, editReplace, editCheck = "", "", "", 0
+	return mockClient
+}
+
+func TestRunEditModes(t *testing.T) {
+	const body = "Plan:\n- [ ] write tests\n- [x] fix the bug\n"
+
+	tests := []struct {
+		name  string
+		setup func()
+		want  string
+	}{
+		{"append", func() { editAppend = "UPDATE: fixed in abc123" }, "Plan:\n- [ ] write tests\n- [x] fix the bug\n\nUPDATE: fixed in abc123"},
+		{"prepend", func() { editPrepend = "**Resolved**" }, "**Resolved**\n\nPlan:\n- [ ] write tests\n- [x] fix the bug\n"},
+		{"replace with groups", func() { editReplace = `(write|fix) the (\w+)=>$1 the ${2}s` }, "Plan:\n- [ ] write tests\n- [x] fix the bugs\n"},
+		{"check an item", func() { editCheck = 1 }, "Plan:\n- [x] write tests\n- [x] fix the bug\n"},
+		{"uncheck an item", func() { editCheck = 2 }, "Plan:\n- [ ] write tests\n- [ ] fix the bug\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := setupEditModesTest(t, body)
+			tt.setup()
+
+			captureOutput(func() {
+				require.NoError(t, runEdit(nil, []string{"123456"}))
+			})
+			assert.Equal(t, tt.want, mockClient.EditedBodies[123456])
+		})
+	}
+}
+
+func TestRunEditModeErrors(t *testing.T) {
+	mockClient := setupEditModesTest(t, "No tasks here")
+
+	editAppend = "more"
+	assert.ErrorContains(t, runEdit(nil, []string{"123456", "message"}), "only one of a new message, --append")
+	editAppend, editPrepend = "more", "less"
+	assert.ErrorContains(t, runEdit(nil, []string{"123456"}), "only one of a new message, --append")
+	editAppend, editPrepend = "", ""
+
+	editReplace = "missing separator"
+	assert.ErrorContains(t, runEdit(nil, []string{"123456"}), "must be in the form 'regex=>replacement'")
+	editReplace = "([=>x"
+	assert.ErrorContains(t, runEdit(nil, []string{"123456"}), "must be a valid regular expression")
+	editReplace = "absent=>present"
+	assert.ErrorContains(t, runEdit(nil, []string{"123456"}), `--replace pattern "absent" does not match comment #123456`)
+	editReplace = ""
+
+	editCheck = 1
+	assert.ErrorContains(t, runEdit(nil, []string{"123456"}), "comment has no task list items")
+	editCheck = -1
+	assert.ErrorContains(t, runEdit(nil, []string{"123456"}), "must be a positive task list item number")
+
+	assert.Empty(t, mockClient.EditedBodies)
+}
+
+func TestRunEditRefusesStaleComment(t *testing.T) {
+	mockClient := setupEditModesTest(t, "Original")
+	editClient = &concurrentEditClient{MockClient: mockClient}
+	editAppend = "UPDATE: done"
+
+	var err error
+	captureOutput(func() {
+		err = runEdit(nil, []string{"123456"})
+	})
+	assert.ErrorContains(t, err, "comment #123456 was changed at 2024-02-01T08:00:00Z after it was read")
+	assert.Empty(t, mockClient.EditedBodies)
+}
+
+func TestToggleTaskItem(t *testing.T) {
+	body := "1. [ ] first\n```\n- [ ] not a task, just code\n```\n  * [X] nested\n- [ ]"
+
+	toggled, err := toggleTaskItem(body, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "1. [ ] first\n```\n- [ ] not a task, just code\n```\n  * [ ] nested\n- [ ]", toggled)
+
+	toggled, err = toggleTaskItem(body, 3)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(toggled, "\n- [x]"))
+
+	_, err = toggleTaskItem(body, 4)
+	assert.ErrorContains(t, err, "must be a task list item between 1 and 3")
+
+	_, err = toggleTaskItem("- [link](http://example.com)", 1)
+	assert.ErrorContains(t, err, "no task list items")
+}
@@ -23,6 +23,9 @@ var (
 	exportInclude   []string
 	includeResolved bool
 
+	// Fetch who reacted to each comment
+	exportReactionUsers bool
+
 	// Client for dependency injection (tests can override)
 	exportClient github.GitHubAPI
 )
@@ -31,7 +34,7 @@ var exportCmd = &cobra.Command{
 	Use:   "export <pr>",
 	Short: "Export PR comments to various formats",
 	Long: heredoc.Doc(`
-		Export PR comments to JSON, CSV, Markdown, or HTML format.
+		Export PR comments to JSON, CSV, Markdown, HTML and tool-friendly formats.
 
 		This command fetches all comments from a PR (both issue and review comments)
 		and exports them in the specified format. You can filter which fields to
@@ -41,7 +44,18 @@ var exportCmd = &cobra.Command{
 		- json: Machine-readable JSON format
 		- csv: Spreadsheet-compatible CSV format
 		- markdown: Documentation-friendly Markdown format
-		- html: Presentation-ready HTML format
+		- html: Self-contained offline report (threads by file, diff hunks, filters)
+		- jsonl: One JSON object per line for log pipelines
+		- sarif: SARIF 2.1.0 so review comments appear in code-scanning tools
+		- junit: JUnit XML with one test case per review thread; unresolved
+		  threads are failures, so a CI step can gate on open feedback
+		- batch-yaml: A 'gh comment batch' file that replays the comments onto
+		  another PR (for example, when a PR is split)
+
+		Resolved comments are skipped unless --include-resolved is given.
+
+		Reaction counts are always exported. --reaction-users also exports who
+		reacted, at the cost of one extra API call per comment with reactions.
 	`),
 	Example: heredoc.Doc(`
 		# Export to JSON (default)
@@ -56,11 +70,24 @@ var exportCmd = &cobra.Command{
 		# Export to Markdown including resolved comments
 		$ gh comment export 123 --format markdown --include-resolved
 
-		# Export to HTML for presentation
+		# Include who reacted to each comment
+		$ gh comment export 123 --reaction-users --include id,body,reactions,reactors
+
+		# Export a self-contained HTML report to share offline
 		$ gh comment export 123 --format html --output pr-123-review.html
 
 		# Export with auto-detected PR
 		$ gh comment export --format csv
+
+		# Fail CI while review threads are unresolved
+		$ gh comment export 123 --format junit --include-resolved --output review-threads.xml
+
+		# Upload review comments to code scanning
+		$ gh comment export 123 --format sarif --output review.sarif
+
+		# Replay comments onto a split-off PR
+		$ gh comment export 123 --format batch-yaml --output replay.yaml
+		$ gh comment batch 130 replay.yaml
 	`),
 	Args: cobra.MaximumNArgs(1),
 	RunE: runExport,
@@ -68,10 +95,11 @@ var exportCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
-	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "json", "Export format (json|csv|markdown|html) (default: json)")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "json", "Export format (json|jsonl|csv|markdown|html|sarif|junit|batch-yaml)")
 	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (default: stdout)")
 	exportCmd.Flags().StringSliceVar(&exportInclude, "include", []string{}, "Fields to include (default: all)")
 	exportCmd.Flags().BoolVar(&includeResolved, "include-resolved", false, "Include resolved comments (default: false)")
+	exportCmd.Flags().BoolVar(&exportReactionUsers, "reaction-users", false, "Include who reacted to each comment (one API call per comment with reactions, a few at a time)")
 }
 
 type ExportComment struct {
@@ -88,6 +116,10 @@ type ExportComment struct {
 	CommitID  string    `json:"commit_id,omitempty"`
 	InReplyTo int       `json:"in_reply_to,omitempty"`
 	Resolved  bool      `json:"resolved,omitempty"`
+	Outdated  bool      `json:"outdated,omitempty"`
+
+	Reactions *github.ReactionSummary `json:"reactions,omitempty"`
+	Reactors  map[string][]string     `json:"reactors,omitempty"` // set by --reaction-users
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
@@ -133,16 +165,9 @@ func runExport(cmd *cobra.Command, args []string) error {
 	owner, repoName := parts[0], parts[1]
 
 	// Validate format
-	validFormats := []string{"json", "csv", "markdown", "html"}
-	isValidFormat := false
-	for _, valid := range validFormats {
-		if exportFormat == valid {
-			isValidFormat = true
-			break
-		}
-	}
-	if !isValidFormat {
-		return fmt.Errorf("invalid format: %s (must be json, csv, markdown, or html)", exportFormat)
+	exporter, ok := exporters[exportFormat]
+	if !ok {
+		return fmt.Errorf("invalid format: %s (must be one of: %s)", exportFormat, strings.Join(exportFormatNames(), ", "))
 	}
 
 	if verbose {
@@ -172,6 +197,23 @@ func runExport(cmd *cobra.Command, args []string) error {
 		comments = filtered
 	}
 
+	// Look up who reacted, only for the comments being exported
+	if exportReactionUsers {
+		var targets []reactorTarget
+		for _, comment := range comments {
+			if comment.Reactions != nil {
+				targets = append(targets, reactorTarget{ID: comment.ID, Type: comment.Type, Reactions: *comment.Reactions})
+			}
+		}
+		reactors, err := fetchReactors(exportClient, owner, repoName, targets)
+		if err != nil {
+			return err
+		}
+		for i := range comments {
+			comments[i].Reactors = reactors[comments[i].ID]
+		}
+	}
+
 	// Setup output writer
 	var writer io.Writer
 	if exportOutput != "" {
@@ -185,19 +227,19 @@ func runExport(cmd *cobra.Command, args []string) error {
 		writer = os.Stdout
 	}
 
-	// Export based on format
-	switch exportFormat {
-	case "json":
-		return exportJSON(writer, comments)
-	case "csv":
-		return exportCSV(writer, comments)
-	case "markdown":
-		return exportMarkdown(writer, comments, repository, pr)
-	case "html":
-		return exportHTML(writer, comments, repository, pr)
+	meta := ExportMeta{Repository: repository, PR: pr, Fields: exportInclude}
+
+	// The HTML report includes PR metadata; it is optional so the export still works without it
+	if exportFormat == "html" {
+		if details, err := exportClient.GetPRDetails(owner, repoName, pr); err == nil {
+			pullRequest := pullRequestFromDetails(pr, details)
+			meta.PullRequest = &pullRequest
+		} else if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch PR details: %v\n", err)
+		}
 	}
 
-	return nil
+	return exporter.Export(writer, comments, meta)
 }
 
 func fetchAllCommentsForExport(client github.GitHubAPI, owner, repo string, pr int) ([]ExportComment, error) {
@@ -218,6 +260,7 @@ func fetchAllCommentsForExport(client github.GitHubAPI, owner, repo string, pr i
 			CreatedAt: comment.CreatedAt,
 			UpdatedAt: comment.UpdatedAt,
 			URL:       fmt.Sprintf("https://github.com/%s/%s/issues/%d#issuecomment-%d", owner, repo, pr, comment.ID),
+			Reactions: exportReactions(comment.Reactions),
 		})
 	}
 
@@ -238,15 +281,54 @@ func fetchAllCommentsForExport(client github.GitHubAPI, owner, repo string, pr i
 			CreatedAt: comment.CreatedAt,
 			UpdatedAt: comment.UpdatedAt,
 			URL:       fmt.Sprintf("https://github.com/%s/%s/pull/%d#discussion_r%d", owner, repo, pr, comment.ID),
+			DiffHunk:  comment.DiffHunk,
 			CommitID:  comment.CommitID,
-			// Note: DiffHunk, InReplyTo, and Resolved are not available in the current Comment struct
-			// These would need to be fetched separately if needed
+			InReplyTo: comment.InReplyToID,
+			Reactions: exportReactions(comment.Reactions),
 		})
 	}
 
+	// Thread resolution state is best-effort: exports still work without it
+	threads, err := client.ListReviewThreads(owner, repo, pr)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch review thread state: %v\n", err)
+		}
+		return allComments, nil
+	}
+
+	state := make(map[int]github.ReviewThread)
+	for _, thread := range threads {
+		for _, id := range thread.CommentIDs {
+			state[id] = thread
+		}
+	}
+	for i := range allComments {
+		if allComments[i].Type == "review" {
+			allComments[i].Resolved = state[allComments[i].ID].IsResolved
+			allComments[i].Outdated = state[allComments[i].ID].IsOutdated
+		}
+	}
+
 	return allComments, nil
 }
 
+// exportReactions returns the reaction counts, or nil when a comment has none
+func exportReactions(reactions github.ReactionSummary) *github.ReactionSummary {
+	if reactions.TotalCount == 0 {
+		return nil
+	}
+	return &reactions
+}
+
+// exportReactionText renders a comment's reactions on one line, empty when it has none
+func exportReactionText(comment ExportComment) string {
+	if comment.Reactions == nil {
+		return ""
+	}
+	return formatReactionSummary(*comment.Reactions, comment.Reactors)
+}
+
 func exportJSON(w io.Writer, comments []ExportComment) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -255,48 +337,7 @@ func exportJSON(w io.Writer, comments []ExportComment) error {
 	if len(exportInclude) > 0 {
 		var filtered []map[string]interface{}
 		for _, comment := range comments {
-			item := make(map[string]interface{})
-			for _, field := range exportInclude {
-				switch field {
-				case "id":
-					item["id"] = comment.ID
-				case "type":
-					item["type"] = comment.Type
-				case "author":
-					item["author"] = comment.Author
-				case "body":
-					item["body"] = comment.Body
-				case "file":
-					if comment.File != "" {
-						item["file"] = comment.File
-					}
-				case "line":
-					if comment.Line != 0 {
-						item["line"] = comment.Line
-					}
-				case "created_at":
-					item["created_at"] = comment.CreatedAt
-				case "updated_at":
-					item["updated_at"] = comment.UpdatedAt
-				case "url":
-					item["url"] = comment.URL
-				case "diff_hunk":
-					if comment.DiffHunk != "" {
-						item["diff_hunk"] = comment.DiffHunk
-					}
-				case "commit_id":
-					if comment.CommitID != "" {
-						item["commit_id"] = comment.CommitID
-					}
-				case "in_reply_to":
-					if comment.InReplyTo != 0 {
-						item["in_reply_to"] = comment.InReplyTo
-					}
-				case "resolved":
-					item["resolved"] = comment.Resolved
-				}
-			}
-			filtered = append(filtered, item)
+			filtered = append(filtered, exportFieldMap(comment, exportInclude))
 		}
 		return encoder.Encode(filtered)
 	}
@@ -304,12 +345,66 @@ func exportJSON(w io.Writer, comments []ExportComment) error {
 	return encoder.Encode(comments)
 }
 
+// exportFieldMap returns only the requested fields of a comment
+func exportFieldMap(comment ExportComment, fields []string) map[string]interface{} {
+	item := make(map[string]interface{})
+	for _, field := range fields {
+		switch field {
+		case "id":
+			item["id"] = comment.ID
+		case "type":
+			item["type"] = comment.Type
+		case "author":
+			item["author"] = comment.Author
+		case "body":
+			item["body"] = comment.Body
+		case "file":
+			if comment.File != "" {
+				item["file"] = comment.File
+			}
+		case "line":
+			if comment.Line != 0 {
+				item["line"] = comment.Line
+			}
+		case "created_at":
+			item["created_at"] = comment.CreatedAt
+		case "updated_at":
+			item["updated_at"] = comment.UpdatedAt
+		case "url":
+			item["url"] = comment.URL
+		case "diff_hunk":
+			if comment.DiffHunk != "" {
+				item["diff_hunk"] = comment.DiffHunk
+			}
+		case "commit_id":
+			if comment.CommitID != "" {
+				item["commit_id"] = comment.CommitID
+			}
+		case "in_reply_to":
+			if comment.InReplyTo != 0 {
+				item["in_reply_to"] = comment.InReplyTo
+			}
+		case "resolved":
+			item["resolved"] = comment.Resolved
+		case "reactions":
+			if comment.Reactions != nil {
+				item["reactions"] = comment.Reactions
+			}
+		case "reactors":
+			if comment.Reactors != nil {
+				item["reactors"] = comment.Reactors
+			}
+		}
+	}
+	return item
+}
+
 func exportCSV(w io.Writer, comments []ExportComment) error {
 	csvWriter := csv.NewWriter(w)
 	defer csvWriter.Flush()
 
 	// Define headers
-	headers := []string{"ID", "Type", "Author", "Body", "File", "Line", "Created At", "Updated At", "URL", "Resolved"}
+	headers := []string{"ID", "Type", "Author", "Body", "File", "Line", "Created At", "Updated At", "URL", "Resolved", "Reactions"}
 	if len(exportInclude) > 0 {
 		headers = exportInclude
 	}
@@ -354,6 +449,8 @@ func exportCSV(w io.Writer, comments []ExportComment) error {
 				row = append(row, comment.URL)
 			case "resolved":
 				row = append(row, strconv.FormatBool(comment.Resolved))
+			case "reactions":
+				row = append(row, exportReactionText(comment))
 			default:
 				row = append(row, "")
 			}
@@ -390,6 +487,9 @@ func exportMarkdown(w io.Writer, comments []ExportComment, repo string, pr int)
 			fmt.Fprintf(w, "### Comment #%d\n", comment.ID)
 			fmt.Fprintf(w, "**Author:** @%s  \n", comment.Author)
 			fmt.Fprintf(w, "**Created:** %s  \n", comment.CreatedAt.Format("2006-01-02 15:04"))
+			if reactions := exportReactionText(comment); reactions != "" {
+				fmt.Fprintf(w, "**Reactions:** %s  \n", reactions)
+			}
 			fmt.Fprintf(w, "\n%s\n\n", comment.Body)
 			_, _ = fmt.Fprintln(w, "---") // Export output
 		}
@@ -406,6 +506,9 @@ func exportMarkdown(w io.Writer, comments []ExportComment, repo string, pr int)
 			if comment.Resolved {
 				_, _ = fmt.Fprintln(w, "**Status:** ✅ Resolved") // Export output
 			}
+			if reactions := exportReactionText(comment); reactions != "" {
+				fmt.Fprintf(w, "**Reactions:** %s  \n", reactions)
+			}
 			fmt.Fprintf(w, "\n%s\n\n", comment.Body)
 			if comment.DiffHunk != "" {
 				_, _ = fmt.Fprintln(w, "```diff")        // Export output
@@ -418,91 +521,3 @@ func exportMarkdown(w io.Writer, comments []ExportComment, repo string, pr int)
 
 	return nil
 }
-
-func exportHTML(w io.Writer, comments []ExportComment, repo string, pr int) error {
-	html := fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-    <title>PR #%d Comments - %s</title>
-    <style>
-        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif; margin: 20px; line-height: 1.6; }
-        h1, h2, h3 { color: #24292e; }
-        .comment { background: #f6f8fa; border: 1px solid #d1d5da; border-radius: 6px; padding: 16px; margin-bottom: 16px; }
-        .comment-header { margin-bottom: 8px; }
-        .author { font-weight: bold; color: #0366d6; }
-        .timestamp { color: #586069; font-size: 14px; }
-        .file-info { background: #f1f8ff; padding: 4px 8px; border-radius: 3px; font-family: monospace; font-size: 12px; }
-        .body { margin-top: 8px; }
-        .diff { background: #fafbfc; border: 1px solid #e1e4e8; border-radius: 3px; padding: 8px; margin-top: 8px; overflow-x: auto; }
-        pre { margin: 0; font-family: 'SFMono-Regular', Consolas, 'Liberation Mono', Menlo, monospace; font-size: 12px; }
-        .resolved { color: #28a745; font-size: 14px; }
-        .stats { background: #f6f8fa; padding: 12px; border-radius: 6px; margin-bottom: 20px; }
-    </style>
-</head>
-<body>
-    <h1>PR #%d Comments Export</h1>
-    <div class="stats">
-        <strong>Repository:</strong> %s<br>
-        <strong>Total Comments:</strong> %d<br>
-        <strong>Exported:</strong> %s
-    </div>
-`, pr, repo, pr, repo, len(comments), time.Now().Format("2006-01-02 15:04:05"))
-
-	// Group by type
-	var issueComments, reviewComments []ExportComment
-	for _, comment := range comments {
-		if comment.Type == "issue" {
-			issueComments = append(issueComments, comment)
-		} else {
-			reviewComments = append(reviewComments, comment)
-		}
-	}
-
-	// Export issue comments
-	if len(issueComments) > 0 {
-		html += fmt.Sprintf("\n    <h2>General PR Comments (%d)</h2>\n", len(issueComments))
-		for _, comment := range issueComments {
-			html += fmt.Sprintf(`    <div class="comment">
-        <div class="comment-header">
-            <span class="author">@%s</span>
-            <span class="timestamp">%s</span>
-        </div>
-        <div class="body">%s</div>
-    </div>
-`, comment.Author, comment.CreatedAt.Format("Jan 2, 2006 15:04"), strings.ReplaceAll(comment.Body, "\n", "<br>"))
-		}
-	}
-
-	// Export review comments
-	if len(reviewComments) > 0 {
-		html += fmt.Sprintf("\n    <h2>Review Comments (%d)</h2>\n", len(reviewComments))
-		for _, comment := range reviewComments {
-			resolvedTag := ""
-			if comment.Resolved {
-				resolvedTag = ` <span class="resolved">✅ Resolved</span>`
-			}
-
-			diffSection := ""
-			if comment.DiffHunk != "" {
-				diffSection = fmt.Sprintf(`        <div class="diff"><pre>%s</pre></div>`, comment.DiffHunk)
-			}
-
-			html += fmt.Sprintf(`    <div class="comment">
-        <div class="comment-header">
-            <span class="author">@%s</span>
-            <span class="file-info">%s:%d</span>
-            <span class="timestamp">%s</span>%s
-        </div>
-        <div class="body">%s</div>
-%s
-    </div>
-`, comment.Author, comment.File, comment.Line, comment.CreatedAt.Format("Jan 2, 2006 15:04"),
-				resolvedTag, strings.ReplaceAll(comment.Body, "\n", "<br>"), diffSection)
-		}
-	}
-
-	html += "\n</body>\n</html>"
-
-	_, err := fmt.Fprint(w, html)
-	return err
-}
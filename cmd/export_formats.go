@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// Exporter writes PR comments in one export format
+type Exporter interface {
+	Export(w io.Writer, comments []ExportComment, meta ExportMeta) error
+}
+
+// ExportMeta describes what is being exported
+type ExportMeta struct {
+	Repository  string
+	PR          int
+	Fields      []string            // --include fields, empty for all
+	PullRequest *github.PullRequest // PR metadata when available
+}
+
+// ExporterFunc adapts a plain function to the Exporter interface
+type ExporterFunc func(w io.Writer, comments []ExportComment, meta ExportMeta) error
+
+// Export calls f(w, comments, meta)
+func (f ExporterFunc) Export(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+	return f(w, comments, meta)
+}
+
+// exporters maps each --format value to its exporter. New formats only need an entry here.
+var exporters = map[string]Exporter{
+	"json": ExporterFunc(func(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+		return exportJSON(w, comments)
+	}),
+	"csv": ExporterFunc(func(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+		return exportCSV(w, comments)
+	}),
+	"markdown": ExporterFunc(func(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+		return exportMarkdown(w, comments, meta.Repository, meta.PR)
+	}),
+	"html":       ExporterFunc(exportHTML),
+	"jsonl":      ExporterFunc(exportJSONLines),
+	"sarif":      ExporterFunc(exportSARIF),
+	"junit":      ExporterFunc(exportJUnit),
+	"batch-yaml": ExporterFunc(exportBatchYAML),
+}
+
+// exportFormatNames returns the supported formats in a stable order
+func exportFormatNames() []string {
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exportThread is a review thread: its first comment and the replies to it
+type exportThread struct {
+	Root    ExportComment
+	Replies []ExportComment
+}
+
+// Resolved reports whether any comment in the thread is marked resolved
+func (t exportThread) Resolved() bool {
+	if t.Root.Resolved {
+		return true
+	}
+	for _, reply := range t.Replies {
+		if reply.Resolved {
+			return true
+		}
+	}
+	return false
+}
+
+// groupExportThreads groups review comments into threads in order of first appearance.
+// Replies whose root is not in the export start their own thread.
+func groupExportThreads(comments []ExportComment) []exportThread {
+	index := make(map[int]int)
+	var threads []exportThread
+
+	for _, comment := range comments {
+		if comment.Type != "review" {
+			continue
+		}
+		if comment.InReplyTo != 0 {
+			if i, ok := index[comment.InReplyTo]; ok {
+				threads[i].Replies = append(threads[i].Replies, comment)
+				continue
+			}
+		}
+		index[comment.ID] = len(threads)
+		threads = append(threads, exportThread{Root: comment})
+	}
+
+	return threads
+}
+
+// exportJSONLines writes one compact JSON object per comment
+func exportJSONLines(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+	encoder := json.NewEncoder(w)
+	for _, comment := range comments {
+		var value interface{} = comment
+		if len(meta.Fields) > 0 {
+			value = exportFieldMap(comment, meta.Fields)
+		}
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SARIF 2.1.0 document structure (only the parts gh-comment emits)
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	Properties          map[string]interface{} `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+const sarifReviewRuleID = "pr-review-comment"
+
+// exportSARIF writes one SARIF result per review thread that has a file and line.
+// Unresolved threads are warnings and resolved threads are notes; general PR comments have no location and are skipped.
+func exportSARIF(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+	results := []sarifResult{}
+	for _, thread := range groupExportThreads(comments) {
+		root := thread.Root
+		if root.File == "" || root.Line == 0 {
+			continue
+		}
+
+		level := "warning"
+		if thread.Resolved() {
+			level = "note"
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  sarifReviewRuleID,
+			Level:   level,
+			Message: sarifMessage{Text: fmt.Sprintf("@%s: %s", root.Author, root.Body)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: root.File},
+					Region:           sarifRegion{StartLine: root.Line},
+				},
+			}},
+			PartialFingerprints: map[string]string{"reviewCommentId/v1": strconv.Itoa(root.ID)},
+			Properties: map[string]interface{}{
+				"author":   root.Author,
+				"url":      root.URL,
+				"resolved": thread.Resolved(),
+				"replies":  len(thread.Replies),
+				"pr":       meta.PR,
+			},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gh-comment",
+				Version:        rootCmd.Version,
+				InformationURI: "https://github.com/silouanwright/gh-comment",
+				Rules: []sarifRule{{
+					ID:               sarifReviewRuleID,
+					ShortDescription: sarifMessage{Text: "Pull request review comment"},
+				}},
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// JUnit XML document structure
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// exportJUnit writes one test case per review thread; unresolved threads are failures
+func exportJUnit(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+	suite := junitTestSuite{
+		Name:      fmt.Sprintf("%s#%d review threads", meta.Repository, meta.PR),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		TestCases: []junitTestCase{},
+	}
+
+	for _, thread := range groupExportThreads(comments) {
+		root := thread.Root
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("%s:%d (#%d)", root.File, root.Line, root.ID),
+			ClassName: root.File,
+		}
+
+		conversation := formatJUnitConversation(thread)
+		if thread.Resolved() {
+			testCase.SystemOut = conversation
+		} else {
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("Unresolved review thread from @%s: %s", root.Author, truncateMessage(firstLine(root.Body), 80)),
+				Type:    "UnresolvedReviewThread",
+				Body:    conversation,
+			}
+			suite.Failures++
+		}
+
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	doc := junitTestSuites{Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// formatJUnitConversation renders a thread as plain text for test output
+func formatJUnitConversation(thread exportThread) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s: %s\n", thread.Root.Author, thread.Root.Body)
+	for _, reply := range thread.Replies {
+		fmt.Fprintf(&b, "@%s: %s\n", reply.Author, reply.Body)
+	}
+	if thread.Root.URL != "" {
+		fmt.Fprintf(&b, "%s\n", thread.Root.URL)
+	}
+	return b.String()
+}
+
+// firstLine returns the text up to the first newline
+func firstLine(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// exportBatchYAML writes a batch configuration that 'gh comment batch <pr> <file>' can replay.
+// Only thread roots are exported because replies cannot be recreated as threads; review
+// comments without a line (outdated) are skipped. PR and repo are left out so the target
+// is chosen on the command line.
+func exportBatchYAML(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+	config := BatchConfig{}
+	skipped := 0
+
+	for _, comment := range comments {
+		if comment.Type == "issue" {
+			config.Comments = append(config.Comments, CommentConfig{Type: "issue", Message: comment.Body})
+		}
+	}
+	for _, thread := range groupExportThreads(comments) {
+		root := thread.Root
+		if root.File == "" || root.Line == 0 {
+			skipped++
+			continue
+		}
+		config.Comments = append(config.Comments, CommentConfig{File: root.File, Line: root.Line, Message: root.Body})
+		skipped += len(thread.Replies)
+	}
+
+	fmt.Fprintf(w, "# Exported from %s#%d by gh comment export\n", meta.Repository, meta.PR)
+	fmt.Fprintf(w, "# Replay with: gh comment batch <pr> <this-file>\n")
+	if skipped > 0 {
+		fmt.Fprintf(w, "# Skipped %d replies or comments without a line\n", skipped)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func exportFormatFixture() []ExportComment {
+	created := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	return []ExportComment{
+		{ID: 1, Type: "issue", Author: "alice", Body: "Overall looks good", CreatedAt: created},
+		{ID: 2, Type: "review", Author: "bob", Body: "Handle the error\nplease", File: "api.go", Line: 10, CreatedAt: created,
+			URL: "https://github.com/owner/repo/pull/5#discussion_r2"},
+		{ID: 3, Type: "review", Author: "alice", Body: "Done", File: "api.go", Line: 10, InReplyTo: 2, CreatedAt: created},
+		{ID: 4, Type: "review", Author: "bob", Body: "Nit: rename", File: "util/strings.go", Line: 3, Resolved: true, CreatedAt: created},
+		{ID: 5, Type: "review", Author: "carol", Body: "Outdated note", File: "old.go", CreatedAt: created},
+	}
+}
+
+var exportFormatMeta = ExportMeta{Repository: "owner/repo", PR: 5}
+
+func TestExportersRegistry(t *testing.T) {
+	assert.Equal(t, []string{"batch-yaml", "csv", "html", "json", "jsonl", "junit", "markdown", "sarif"}, exportFormatNames())
+
+	for _, name := range exportFormatNames() {
+		var buf bytes.Buffer
+		require.NoError(t, exporters[name].Export(&buf, exportFormatFixture(), exportFormatMeta), name)
+		assert.NotEmpty(t, buf.String(), name)
+	}
+}
+
+func TestGroupExportThreads(t *testing.T) {
+	threads := groupExportThreads(exportFormatFixture())
+	require.Len(t, threads, 3)
+
+	assert.Equal(t, 2, threads[0].Root.ID)
+	require.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, 3, threads[0].Replies[0].ID)
+	assert.False(t, threads[0].Resolved())
+	assert.True(t, threads[1].Resolved())
+
+	// A reply whose root was filtered out starts its own thread
+	orphans := groupExportThreads([]ExportComment{{ID: 9, Type: "review", InReplyTo: 8}})
+	require.Len(t, orphans, 1)
+	assert.Equal(t, 9, orphans[0].Root.ID)
+}
+
+func TestExportJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportJSONLines(&buf, exportFormatFixture(), exportFormatMeta))
+
+	scanner := bufio.NewScanner(&buf)
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 5)
+	assert.Equal(t, "Handle the error\nplease", lines[1]["body"])
+
+	buf.Reset()
+	require.NoError(t, exportJSONLines(&buf, exportFormatFixture()[:1], ExportMeta{Fields: []string{"id", "author"}}))
+	assert.Equal(t, `{"author":"alice","id":1}`+"\n", buf.String())
+}
+
+func TestExportSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportSARIF(&buf, exportFormatFixture(), exportFormatMeta))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Equal(t, "gh-comment", log.Runs[0].Tool.Driver.Name)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 2, "issue comments, replies and line-less comments are skipped")
+	assert.Equal(t, "warning", results[0].Level)
+	assert.Equal(t, "api.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 10, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "2", results[0].PartialFingerprints["reviewCommentId/v1"])
+	assert.Equal(t, "note", results[1].Level)
+}
+
+func TestExportJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportJUnit(&buf, exportFormatFixture(), exportFormatMeta))
+	assert.True(t, strings.HasPrefix(buf.String(), "<?xml"))
+
+	var doc junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, 3, doc.Tests)
+	assert.Equal(t, 2, doc.Failures)
+
+	cases := doc.Suites[0].TestCases
+	require.Len(t, cases, 3)
+	assert.Equal(t, "api.go:10 (#2)", cases[0].Name)
+	require.NotNil(t, cases[0].Failure)
+	assert.Equal(t, "Unresolved review thread from @bob: Handle the error", cases[0].Failure.Message)
+	assert.Contains(t, cases[0].Failure.Body, "@alice: Done")
+	assert.Nil(t, cases[1].Failure)
+	assert.Contains(t, cases[1].SystemOut, "Nit: rename")
+}
+
+func TestExportBatchYAMLRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportBatchYAML(&buf, exportFormatFixture(), exportFormatMeta))
+	assert.Contains(t, buf.String(), "# Exported from owner/repo#5")
+	assert.Contains(t, buf.String(), "# Skipped 2 replies or comments without a line")
+
+	file := filepath.Join(t.TempDir(), "replay.yaml")
+	require.NoError(t, os.WriteFile(file, buf.Bytes(), 0644))
+
+	config, err := readBatchConfig(file)
+	require.NoError(t, err)
+	assert.Zero(t, config.PR, "target PR comes from the command line")
+	assert.Empty(t, config.Repo)
+	require.Len(t, config.Comments, 3)
+	assert.Equal(t, CommentConfig{Type: "issue", Message: "Overall looks good"}, config.Comments[0])
+	assert.Equal(t, CommentConfig{File: "api.go", Line: 10, Message: "Handle the error\nplease"}, config.Comments[1])
+	assert.Equal(t, "util/strings.go", config.Comments[2].File)
+}
+
+func TestFetchAllCommentsForExportThreadState(t *testing.T) {
+	mockClient := github.NewMockClient()
+	mockClient.IssueComments = nil
+	mockClient.ReviewComments = []github.Comment{
+		{ID: 3, User: github.User{Login: "charlie"}, Body: "Fix", Path: "test.go", Line: 10},
+		{ID: 4, User: github.User{Login: "dave"}, Body: "Fixed", Path: "test.go", Line: 10, InReplyToID: 3},
+	}
+	mockClient.ReviewThreads = []github.ReviewThread{{ID: "T1", IsResolved: true, CommentIDs: []int{3, 4}}}
+
+	comments, err := fetchAllCommentsForExport(mockClient, "owner", "repo", 123)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.True(t, comments[0].Resolved)
+	assert.Equal(t, 3, comments[1].InReplyTo)
+
+	// Thread state is optional
+	mockClient.ListThreadsError = assert.AnError
+	comments, err = fetchAllCommentsForExport(mockClient, "owner", "repo", 123)
+	require.NoError(t, err)
+	assert.False(t, comments[0].Resolved)
+}
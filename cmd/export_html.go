@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// MaxReportHunkLines limits how much of a diff hunk the HTML report shows above each thread
+const MaxReportHunkLines = 12
+
+// Thread states used for badges and the sidebar filter
+const (
+	reportStateOpen     = "open"
+	reportStateResolved = "resolved"
+	reportStateOutdated = "outdated"
+)
+
+// htmlReport is the data rendered by reportTemplate
+type htmlReport struct {
+	Repository  string
+	PR          int
+	PullRequest *github.PullRequest
+	Generated   string
+
+	Total     int
+	Threads   int
+	Open      int
+	Resolved  int
+	Outdated  int
+	Authors   []string
+	Files     []string
+	General   []htmlComment
+	FileGroup []htmlFileGroup
+}
+
+type htmlFileGroup struct {
+	File    string
+	Threads []htmlThread
+}
+
+type htmlThread struct {
+	ID       int
+	File     string
+	Line     int
+	States   []string
+	Authors  string
+	Hunk     []htmlDiffLine
+	Comments []htmlComment
+}
+
+type htmlDiffLine struct {
+	Class string
+	Text  string
+}
+
+type htmlComment struct {
+	ID        int
+	Author    string
+	Created   string
+	URL       string
+	Body      template.HTML
+	Reactions []htmlReaction
+}
+
+type htmlReaction struct {
+	Emoji string
+	Count int
+	Users string
+}
+
+// exportHTML writes a self-contained HTML report: PR metadata, threads grouped by
+// file with their diff hunks, state badges, reactions, and an inline-JS sidebar filter.
+// Comment bodies are rendered from markdown with all raw HTML escaped.
+func exportHTML(w io.Writer, comments []ExportComment, meta ExportMeta) error {
+	return reportTemplate.Execute(w, buildHTMLReport(comments, meta, time.Now()))
+}
+
+// buildHTMLReport groups comments into the structure rendered by reportTemplate
+func buildHTMLReport(comments []ExportComment, meta ExportMeta, now time.Time) htmlReport {
+	report := htmlReport{
+		Repository:  meta.Repository,
+		PR:          meta.PR,
+		PullRequest: meta.PullRequest,
+		Generated:   now.UTC().Format("2006-01-02 15:04 UTC"),
+		Total:       len(comments),
+	}
+
+	authors := make(map[string]bool)
+	for _, comment := range comments {
+		authors[comment.Author] = true
+		if comment.Type == "issue" {
+			report.General = append(report.General, newHTMLComment(comment))
+		}
+	}
+
+	groups := make(map[string]*htmlFileGroup)
+	var files []string
+	for _, thread := range groupExportThreads(comments) {
+		root := thread.Root
+		group, ok := groups[root.File]
+		if !ok {
+			group = &htmlFileGroup{File: root.File}
+			groups[root.File] = group
+			files = append(files, root.File)
+		}
+
+		item := htmlThread{ID: root.ID, File: root.File, Line: root.Line, Hunk: reportHunk(root.DiffHunk)}
+		threadAuthors := []string{root.Author}
+		item.Comments = append(item.Comments, newHTMLComment(root))
+		outdated := root.Outdated
+		for _, reply := range thread.Replies {
+			item.Comments = append(item.Comments, newHTMLComment(reply))
+			threadAuthors = append(threadAuthors, reply.Author)
+			outdated = outdated || reply.Outdated
+		}
+		item.Authors = strings.Join(threadAuthors, " ")
+
+		report.Threads++
+		if thread.Resolved() {
+			item.States = append(item.States, reportStateResolved)
+			report.Resolved++
+		} else {
+			item.States = append(item.States, reportStateOpen)
+			report.Open++
+		}
+		if outdated {
+			item.States = append(item.States, reportStateOutdated)
+			report.Outdated++
+		}
+
+		group.Threads = append(group.Threads, item)
+	}
+
+	sort.Strings(files)
+	for _, file := range files {
+		report.FileGroup = append(report.FileGroup, *groups[file])
+	}
+	report.Files = files
+
+	for author := range authors {
+		report.Authors = append(report.Authors, author)
+	}
+	sort.Strings(report.Authors)
+
+	return report
+}
+
+func newHTMLComment(comment ExportComment) htmlComment {
+	item := htmlComment{
+		ID:      comment.ID,
+		Author:  comment.Author,
+		Created: comment.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"),
+		URL:     comment.URL,
+		Body:    template.HTML(renderMarkdownHTML(comment.Body)), // renderMarkdownHTML escapes all input
+	}
+
+	if comment.Reactions != nil {
+		for _, reaction := range github.ReactionContents {
+			if count := comment.Reactions.Count(reaction); count > 0 {
+				item.Reactions = append(item.Reactions, htmlReaction{
+					Emoji: reactionEmoji[reaction],
+					Count: count,
+					Users: strings.Join(comment.Reactors[reaction], ", "),
+				})
+			}
+		}
+	}
+
+	return item
+}
+
+// reportHunk keeps the hunk header and the lines closest to the commented line
+func reportHunk(hunk string) []htmlDiffLine {
+	hunk = strings.TrimRight(hunk, "\n")
+	if hunk == "" {
+		return nil
+	}
+
+	lines := strings.Split(hunk, "\n")
+	var result []htmlDiffLine
+	if strings.HasPrefix(lines[0], "@@") {
+		result = append(result, htmlDiffLine{Class: "hunk", Text: lines[0]})
+		lines = lines[1:]
+	}
+	if len(lines) > MaxReportHunkLines {
+		lines = lines[len(lines)-MaxReportHunkLines:]
+	}
+	for _, line := range lines {
+		result = append(result, htmlDiffLine{Class: diffLineClass(line), Text: line})
+	}
+	return result
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>PR #{{.PR}} Comments - {{.Repository}}</title>
+<style>
+:root { --border: #d0d7de; --muted: #57606a; --bg: #f6f8fa; --accent: #0969da; }
+* { box-sizing: border-box; }
+body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif; margin: 0; color: #1f2328; line-height: 1.5; }
+.layout { display: flex; min-height: 100vh; }
+aside { width: 260px; flex-shrink: 0; border-right: 1px solid var(--border); background: var(--bg); padding: 16px; position: sticky; top: 0; height: 100vh; overflow-y: auto; }
+aside label { display: block; font-size: 12px; font-weight: 600; color: var(--muted); margin: 12px 0 4px; }
+aside select, aside input { width: 100%; padding: 4px 6px; border: 1px solid var(--border); border-radius: 6px; font: inherit; }
+aside .count { margin-top: 16px; font-size: 13px; color: var(--muted); }
+main { flex: 1; padding: 24px 32px; max-width: 1100px; }
+h1 { margin: 0 0 4px; font-size: 24px; }
+h2 { font-size: 18px; border-bottom: 1px solid var(--border); padding-bottom: 6px; margin-top: 32px; }
+h2 code { font-size: 16px; }
+.meta { color: var(--muted); font-size: 14px; }
+.meta a { color: var(--accent); }
+.summary { display: flex; gap: 16px; flex-wrap: wrap; margin: 16px 0; }
+.summary div { background: var(--bg); border: 1px solid var(--border); border-radius: 6px; padding: 8px 14px; font-size: 14px; }
+.summary strong { display: block; font-size: 20px; }
+.thread, .comment-card { border: 1px solid var(--border); border-radius: 6px; margin: 16px 0; overflow: hidden; }
+.thread-header { background: var(--bg); padding: 8px 12px; border-bottom: 1px solid var(--border); font-size: 13px; display: flex; gap: 8px; align-items: center; flex-wrap: wrap; }
+.thread-header code { font-weight: 600; }
+.badge { display: inline-block; border-radius: 2em; padding: 0 8px; font-size: 12px; font-weight: 600; border: 1px solid transparent; }
+.badge.open { background: #ddf4ff; color: #0969da; border-color: #54aeff; }
+.badge.resolved { background: #dafbe1; color: #1a7f37; border-color: #4ac26b; }
+.badge.outdated { background: #fff8c5; color: #9a6700; border-color: #d4a72c; }
+pre { margin: 0; padding: 8px 12px; overflow-x: auto; font-family: ui-monospace, SFMono-Regular, Consolas, 'Liberation Mono', Menlo, monospace; font-size: 12px; background: var(--bg); }
+pre.diff span { display: block; white-space: pre; }
+.add { background: #e6ffec; }
+.del { background: #ffebe9; }
+.hunk { color: var(--muted); background: #ddf4ff; }
+.comment { padding: 10px 12px; border-top: 1px solid var(--border); }
+.comment:first-of-type { border-top: none; }
+.comment-meta { font-size: 13px; color: var(--muted); }
+.author { font-weight: 600; color: #1f2328; }
+.body { margin-top: 6px; overflow-wrap: anywhere; }
+.body p { margin: 6px 0; }
+.body pre { border-radius: 6px; }
+.body table { border-collapse: collapse; margin: 6px 0; }
+.body th, .body td { border: 1px solid var(--border); padding: 4px 8px; text-align: left; }
+.body blockquote { margin: 6px 0; padding: 0 12px; border-left: 3px solid var(--border); color: var(--muted); }
+.suggestion { border: 1px solid var(--border); border-radius: 6px; overflow: hidden; margin: 6px 0; }
+.suggestion-title { font-size: 12px; font-weight: 600; padding: 4px 12px; background: var(--bg); border-bottom: 1px solid var(--border); }
+.reactions { margin-top: 6px; display: flex; gap: 6px; }
+.reactions span { border: 1px solid var(--border); border-radius: 2em; padding: 0 8px; font-size: 12px; }
+.hidden { display: none !important; }
+@media print {
+  aside { display: none; }
+  main { max-width: none; padding: 0; }
+  .thread, .comment-card { break-inside: avoid; page-break-inside: avoid; }
+  .hidden { display: block !important; }
+  a { color: inherit; text-decoration: none; }
+  pre { white-space: pre-wrap; }
+  pre.diff span { white-space: pre-wrap; }
+}
+</style>
+</head>
+<body>
+<div class="layout">
+<aside>
+  <strong>Filter</strong>
+  <label for="filter-author">Author</label>
+  <select id="filter-author">
+    <option value="">All authors</option>
+    {{- range .Authors}}
+    <option value="{{.}}">@{{.}}</option>
+    {{- end}}
+  </select>
+  <label for="filter-file">File</label>
+  <select id="filter-file">
+    <option value="">All files</option>
+    {{- range .Files}}
+    <option value="{{.}}">{{.}}</option>
+    {{- end}}
+  </select>
+  <label for="filter-state">State</label>
+  <select id="filter-state">
+    <option value="">All threads</option>
+    <option value="open">Open</option>
+    <option value="resolved">Resolved</option>
+    <option value="outdated">Outdated</option>
+  </select>
+  <label for="filter-text">Text</label>
+  <input id="filter-text" type="search" placeholder="Search comments">
+  <div class="count" id="filter-count"></div>
+</aside>
+<main>
+  <h1>{{if .PullRequest}}{{if .PullRequest.Title}}{{.PullRequest.Title}} {{end}}{{end}}PR #{{.PR}}</h1>
+  <div class="meta">
+    {{.Repository}}
+    {{- with .PullRequest}}
+    {{- if .State}} · {{.State}}{{end}}
+    {{- if .User.Login}} · opened by @{{.User.Login}}{{end}}
+    {{- if not .CreatedAt.IsZero}} on {{.CreatedAt.UTC.Format "2006-01-02"}}{{end}}
+    {{- if .MergedAt}} · merged {{.MergedAt.UTC.Format "2006-01-02"}}{{end}}
+    {{- if .HTMLURL}} · <a href="{{.HTMLURL}}">{{.HTMLURL}}</a>{{end}}
+    {{- end}}
+    · generated {{.Generated}}
+  </div>
+  <div class="summary">
+    <div><strong>{{.Total}}</strong>comments</div>
+    <div><strong>{{.Threads}}</strong>review threads</div>
+    <div><strong>{{.Open}}</strong>open</div>
+    <div><strong>{{.Resolved}}</strong>resolved</div>
+    <div><strong>{{.Outdated}}</strong>outdated</div>
+  </div>
+  {{- if .General}}
+  <h2>General PR Comments ({{len .General}})</h2>
+  {{- range .General}}
+  <div class="comment-card filterable" data-authors="{{.Author}}" data-file="" data-states="">
+    {{template "comment" .}}
+  </div>
+  {{- end}}
+  {{- end}}
+  {{- range .FileGroup}}
+  <h2 class="file-heading" data-file="{{.File}}"><code>{{.File}}</code> ({{len .Threads}})</h2>
+  {{- range .Threads}}
+  <div class="thread filterable" id="thread-{{.ID}}" data-authors="{{.Authors}}" data-file="{{.File}}" data-states="{{join .States " "}}">
+    <div class="thread-header">
+      <code>{{.File}}{{if .Line}}:{{.Line}}{{end}}</code>
+      {{- range .States}} <span class="badge {{.}}">{{.}}</span>{{end}}
+    </div>
+    {{- if .Hunk}}
+    <pre class="diff">{{range .Hunk}}<span class="{{.Class}}">{{.Text}}</span>{{end}}</pre>
+    {{- end}}
+    {{- range .Comments}}
+    {{template "comment" .}}
+    {{- end}}
+  </div>
+  {{- end}}
+  {{- end}}
+</main>
+</div>
+<script>
+(function () {
+  var author = document.getElementById('filter-author');
+  var file = document.getElementById('filter-file');
+  var state = document.getElementById('filter-state');
+  var text = document.getElementById('filter-text');
+  var count = document.getElementById('filter-count');
+  var items = Array.prototype.slice.call(document.querySelectorAll('.filterable'));
+  var headings = Array.prototype.slice.call(document.querySelectorAll('.file-heading'));
+
+  function apply() {
+    var shown = 0;
+    var needle = text.value.toLowerCase();
+    var filesShown = {};
+    items.forEach(function (item) {
+      var visible =
+        (!author.value || item.dataset.authors.split(' ').indexOf(author.value) !== -1) &&
+        (!file.value || item.dataset.file === file.value) &&
+        (!state.value || item.dataset.states.split(' ').indexOf(state.value) !== -1) &&
+        (!needle || item.textContent.toLowerCase().indexOf(needle) !== -1);
+      item.classList.toggle('hidden', !visible);
+      if (visible) {
+        shown++;
+        filesShown[item.dataset.file] = true;
+      }
+    });
+    headings.forEach(function (heading) {
+      heading.classList.toggle('hidden', !filesShown[heading.dataset.file]);
+    });
+    count.textContent = shown + ' of ' + items.length + ' shown';
+  }
+
+  [author, file, state].forEach(function (el) { el.addEventListener('change', apply); });
+  text.addEventListener('input', apply);
+  apply();
+})();
+</script>
+</body>
+</html>
+{{define "comment"}}<div class="comment" id="comment-{{.ID}}">
+      <div class="comment-meta"><span class="author">@{{.Author}}</span> · {{if .URL}}<a href="{{.URL}}">{{.Created}}</a>{{else}}{{.Created}}{{end}}</div>
+      <div class="body">{{.Body}}</div>
+      {{- if .Reactions}}
+      <div class="reactions">{{range .Reactions}}<span{{if .Users}} title="{{.Users}}"{{end}}>{{.Emoji}} {{.Count}}</span>{{end}}</div>
+      {{- end}}
+    </div>{{end}}`))
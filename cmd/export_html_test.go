@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func htmlReportFixture() []ExportComment {
+	created := time.Date(2024, 10, 1, 9, 30, 0, 0, time.UTC)
+	return []ExportComment{
+		{ID: 1, Type: "issue", Author: "alice", Body: "Thanks! <script>alert(1)</script>", CreatedAt: created},
+		{ID: 2, Type: "review", Author: "bob", Body: "Use **errors.Is**", File: "pkg/api.go", Line: 12, CreatedAt: created,
+			DiffHunk:  "@@ -10,3 +10,3 @@\n func a() {\n-\treturn err == io.EOF\n+\treturn err == ErrX",
+			Reactions: &github.ReactionSummary{TotalCount: 3, PlusOne: 2, Rocket: 1}},
+		{ID: 3, Type: "review", Author: "carol", Body: "Agreed", File: "pkg/api.go", Line: 12, InReplyTo: 2, CreatedAt: created},
+		{ID: 4, Type: "review", Author: "bob", Body: "Old note", File: "README.md", Line: 1, Resolved: true, Outdated: true, CreatedAt: created},
+	}
+}
+
+func TestBuildHTMLReport(t *testing.T) {
+	report := buildHTMLReport(htmlReportFixture(), ExportMeta{Repository: "owner/repo", PR: 7}, time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 2, report.Threads)
+	assert.Equal(t, 1, report.Open)
+	assert.Equal(t, 1, report.Resolved)
+	assert.Equal(t, 1, report.Outdated)
+	assert.Equal(t, []string{"alice", "bob", "carol"}, report.Authors)
+	assert.Equal(t, []string{"README.md", "pkg/api.go"}, report.Files)
+	assert.Equal(t, "2024-10-02 00:00 UTC", report.Generated)
+
+	require.Len(t, report.FileGroup, 2)
+	api := report.FileGroup[1].Threads[0]
+	assert.Equal(t, "bob carol", api.Authors)
+	assert.Equal(t, []string{"open"}, api.States)
+	assert.Len(t, api.Comments, 2)
+	assert.Equal(t, []htmlReaction{{Emoji: "👍", Count: 2}, {Emoji: "🚀", Count: 1}}, api.Comments[0].Reactions)
+	assert.Equal(t, []string{"resolved", "outdated"}, report.FileGroup[0].Threads[0].States)
+}
+
+func TestReportHunk(t *testing.T) {
+	assert.Nil(t, reportHunk(""))
+
+	var lines []string
+	lines = append(lines, "@@ -1,20 +1,20 @@")
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf(" line %d", i))
+	}
+	hunk := reportHunk(strings.Join(lines, "\n"))
+	require.Len(t, hunk, MaxReportHunkLines+1)
+	assert.Equal(t, htmlDiffLine{Class: "hunk", Text: "@@ -1,20 +1,20 @@"}, hunk[0])
+	assert.Equal(t, " line 19", hunk[len(hunk)-1].Text, "lines closest to the comment are kept")
+}
+
+func TestExportHTMLReport(t *testing.T) {
+	created := time.Date(2024, 9, 30, 8, 0, 0, 0, time.UTC)
+	meta := ExportMeta{
+		Repository: "owner/repo",
+		PR:         7,
+		PullRequest: &github.PullRequest{
+			Number: 7, Title: "Improve <errors>", State: "open", User: github.User{Login: "alice"},
+			HTMLURL: "https://github.com/owner/repo/pull/7", CreatedAt: created,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exportHTML(&buf, htmlReportFixture(), meta))
+	output := buf.String()
+
+	// Metadata
+	assert.Contains(t, output, "<h1>Improve &lt;errors&gt; PR #7</h1>")
+	assert.Contains(t, output, "opened by @alice on 2024-09-30")
+	assert.Contains(t, output, `<a href="https://github.com/owner/repo/pull/7">`)
+
+	// Threads grouped by file with hunks, badges and reactions
+	assert.Contains(t, output, `<code>pkg/api.go</code> (1)</h2>`)
+	assert.Contains(t, output, `<span class="del">-	return err == io.EOF</span>`)
+	assert.Contains(t, output, `<span class="badge outdated">outdated</span>`)
+	assert.Contains(t, output, `data-authors="bob carol" data-file="pkg/api.go" data-states="open"`)
+	assert.Contains(t, output, "<span>👍 2</span>")
+	assert.Contains(t, output, "<strong>errors.Is</strong>")
+
+	// Sidebar filter, inline script and print styles
+	assert.Contains(t, output, `<option value="carol">@carol</option>`)
+	assert.Contains(t, output, `<select id="filter-state">`)
+	assert.Contains(t, output, "@media print")
+	assert.Contains(t, output, "document.querySelectorAll('.filterable')")
+
+	// Sanitized bodies and no external resources
+	assert.Contains(t, output, "&lt;script&gt;alert(1)&lt;/script&gt;")
+	assert.Equal(t, 1, strings.Count(output, "<script>"), "only the report's own script")
+	assert.NotContains(t, output, "<link")
+	assert.NotContains(t, output, "src=")
+}
@@ -309,18 +309,18 @@ func TestExportFormats(t *testing.T) {
 
 	t.Run("HTML export", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := exportHTML(&buf, comments, "owner/repo", 123)
+		err := exportHTML(&buf, comments, ExportMeta{Repository: "owner/repo", PR: 123})
 		assert.NoError(t, err)
 
 		output := buf.String()
 		assert.Contains(t, output, "<!DOCTYPE html>")
 		assert.Contains(t, output, "<title>PR #123 Comments - owner/repo</title>")
-		assert.Contains(t, output, "<h1>PR #123 Comments Export</h1>")
+		assert.Contains(t, output, "<h1>PR #123</h1>")
 		assert.Contains(t, output, "<h2>General PR Comments (1)</h2>")
-		assert.Contains(t, output, "<h2>Review Comments (1)</h2>")
+		assert.Contains(t, output, "<code>main.go</code> (1)</h2>")
 		assert.Contains(t, output, "class=\"author\">@alice")
 		assert.Contains(t, output, "class=\"author\">@bob")
-		assert.Contains(t, output, "class=\"resolved\">✅ Resolved")
+		assert.Contains(t, output, "class=\"badge resolved\">resolved")
 	})
 }
 
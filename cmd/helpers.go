@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+
+	"github.com/silouanwright/gh-comment/internal/github"
 )
 
 // Constants for API limits and defaults
@@ -32,13 +40,8 @@ const (
 	DefaultBufferSize      = 4096 // Default buffer size for I/O operations
 )
 
-// Security validation patterns
+// Validation patterns
 var (
-	// HTML/Script tag detection patterns
-	htmlTagPattern       = regexp.MustCompile(`(?i)<\s*\/?\s*(script|iframe|object|embed|form|input|meta|link)\b[^>]*>`)
-	scriptPattern        = regexp.MustCompile(`(?i)javascript\s*:|<\s*script\b`)
-	dangerousAttrPattern = regexp.MustCompile(`(?i)\b(on\w+|javascript|vbscript|data|mocha|livescript)\s*=`)
-
 	// Repository validation patterns
 	validRepoPattern  = regexp.MustCompile(`^[a-zA-Z0-9._-]+/[a-zA-Z0-9._-]+$`)
 	validOwnerPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
@@ -63,6 +66,25 @@ func getPRContext() (repo string, pr int, err error) {
 	return repo, pr, nil
 }
 
+// getPRContextFromArgs uses an optional [pr] argument, falling back to getPRContext
+func getPRContextFromArgs(args []string) (repo string, pr int, err error) {
+	if len(args) == 0 {
+		return getPRContext()
+	}
+
+	pr, err = parsePositiveInt(args[0], "PR number")
+	if err != nil {
+		return "", 0, err
+	}
+
+	repo, err = getCurrentRepo()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	return repo, pr, nil
+}
+
 // formatAPIError creates consistent error messages for API failures
 func formatAPIError(operation, endpoint string, err error) error {
 	return fmt.Errorf("GitHub API error during %s: %w", operation, err)
@@ -161,6 +183,28 @@ func formatNotFoundError(resource string, identifier interface{}) error {
 	return fmt.Errorf("%s not found: %v", resource, identifier)
 }
 
+// confirmInput is where confirmation answers are read from (tests can override)
+var confirmInput io.Reader = os.Stdin
+
+// stdinIsTerminal reports whether there is someone to ask for confirmation (tests can override)
+var stdinIsTerminal = func() bool { return term.IsTerminal(os.Stdin) }
+
+// confirmAction asks a yes/no question on stderr, defaulting to no. Scripts and
+// pipes cannot answer, so callers check stdinIsTerminal before asking.
+func confirmAction(question string) (bool, error) {
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("cannot ask for confirmation without a terminal: use --yes to apply without confirming")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(confirmInput).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
 // parsePositiveInt parses a string to a positive integer with consistent validation
 func parsePositiveInt(s, fieldName string) (int, error) {
 	val, err := strconv.Atoi(s)
@@ -188,27 +232,33 @@ func validateCommentBody(body string) error {
 	return nil
 }
 
-// validateCommentSecurity checks for potentially malicious content in comments
+// maxReportedUnsafeContent limits how many problems a security error lists
+const maxReportedUnsafeContent = 10
+
+// validateCommentSecurity rejects raw HTML and links that could run script, with
+// the position of each problem; code spans and fences may contain anything
 func validateCommentSecurity(body string) error {
-	// Check for dangerous HTML tags
-	if htmlTagPattern.MatchString(body) {
-		return formatSecurityValidationError("comment body", "dangerous HTML tags detected",
-			"HTML tags like <script>, <iframe>, <object>, <embed>, <form>, <input>, <meta>, <link> are not allowed")
+	issues := findUnsafeMarkdown(body)
+	if len(issues) == 0 {
+		return nil
 	}
 
-	// Check for JavaScript and event handlers
-	if scriptPattern.MatchString(body) {
-		return formatSecurityValidationError("comment body", "JavaScript content detected",
-			"JavaScript code, inline event handlers, and script tags are not allowed")
+	var kinds, details []string
+	for i, issue := range issues {
+		if !containsString(kinds, issue.Kind) {
+			kinds = append(kinds, issue.Kind)
+		}
+		if i < maxReportedUnsafeContent {
+			details = append(details, fmt.Sprintf("  • line %d, column %d: %s", issue.Line, issue.Column, issue.Detail))
+		}
 	}
-
-	// Check for dangerous attributes
-	if dangerousAttrPattern.MatchString(body) {
-		return formatSecurityValidationError("comment body", "dangerous attributes detected",
-			"Event handlers and script attributes like onclick, onload, href='javascript:' are not allowed")
+	if len(issues) > maxReportedUnsafeContent {
+		details = append(details, fmt.Sprintf("  • ... and %d more", len(issues)-maxReportedUnsafeContent))
 	}
 
-	return nil
+	return formatSecurityValidationError("comment body", strings.Join(kinds, "; ")+"\n"+strings.Join(details, "\n"),
+		"Put code samples in `code spans` or ``` fences, where anything is allowed. Outside code only safe HTML "+
+			"elements (such as <details>, <sub>, <kbd>, <img>) without event handlers are allowed, and links cannot use javascript: or data: URLs")
 }
 
 // validateFilePath validates file path length and format to prevent directory traversal
@@ -373,3 +423,35 @@ func groupConsecutiveLines(lines []int) []lineRange {
 
 	return ranges
 }
+
+// pullRequestFromDetails extracts PR metadata from a GetPRDetails response, tolerating missing fields
+func pullRequestFromDetails(number int, details map[string]interface{}) github.PullRequest {
+	pr := github.PullRequest{Number: number}
+
+	if title, ok := details["title"].(string); ok {
+		pr.Title = title
+	}
+	if state, ok := details["state"].(string); ok {
+		pr.State = state
+	}
+	if user, ok := details["user"].(map[string]interface{}); ok {
+		if login, ok := user["login"].(string); ok {
+			pr.User.Login = login
+		}
+	}
+	if url, ok := details["html_url"].(string); ok {
+		pr.HTMLURL = url
+	}
+	if created, ok := details["created_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, created); err == nil {
+			pr.CreatedAt = t
+		}
+	}
+	if merged, ok := details["merged_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, merged); err == nil {
+			pr.MergedAt = &t
+		}
+	}
+
+	return pr
+}
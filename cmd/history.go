@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+var (
+	historyDiff   bool
+	historyFrom   int
+	historyTo     int
+	historyFormat string
+
+	// Client for dependency injection (tests can override)
+	historyClient github.GitHubAPI
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <comment-id>",
+	Short: "Show the edit history of a comment",
+	Long: heredoc.Doc(`
+		List every revision of a comment, oldest first, with who made each edit
+		and how many words it changed.
+
+		Use --diff to see what each edit changed, or --from and --to to compare
+		any two revisions. Deleted words are shown in red and inserted words in
+		green; without colour they are marked [-like this-] and {+like this+}.
+
+		Revision 1 is the original comment. Comments that were never edited
+		have a single revision.
+	`),
+	Example: heredoc.Doc(`
+		# List the revisions of a comment
+		$ gh comment history 2246362251
+
+		# Show what each edit changed
+		$ gh comment history 2246362251 --diff
+
+		# Compare the original with the current text
+		$ gh comment history 2246362251 --from 1 --to 4
+
+		# Structured output for scripts
+		$ gh comment history 2246362251 --format json | jq '.revisions[].editor'
+	`),
+	Args: cobra.ExactArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().BoolVar(&historyDiff, "diff", false, "Show the changes made by each edit")
+	historyCmd.Flags().IntVar(&historyFrom, "from", 0, "Revision to compare from (requires --to)")
+	historyCmd.Flags().IntVar(&historyTo, "to", 0, "Revision to compare to (requires --from)")
+	historyCmd.Flags().StringVar(&historyFormat, "format", FormatDefault, "Output format (default|json)")
+}
+
+// commentRevision is one version of a comment body, numbered from 1 for the original
+type commentRevision struct {
+	Number   int       `json:"number"`
+	Editor   string    `json:"editor"`
+	EditedAt time.Time `json:"edited_at"`
+	Body     string    `json:"body"`
+	Deleted  bool      `json:"deleted,omitempty"`
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	// Initialize client if not set (production use)
+	if historyClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		historyClient = client
+	}
+
+	commentID, err := parsePositiveInt(args[0], "comment ID")
+	if err != nil {
+		return err
+	}
+
+	if historyFormat != FormatDefault && historyFormat != FormatJSON {
+		return formatValidationError("format", historyFormat, "must be one of: default, json")
+	}
+	if (historyFrom == 0) != (historyTo == 0) {
+		return fmt.Errorf("--from and --to must be used together")
+	}
+	if historyDiff && historyFrom != 0 {
+		return fmt.Errorf("cannot use --diff with --from/--to")
+	}
+
+	// Get repository context
+	repository, pr, err := getPRContext()
+	if err != nil {
+		return err
+	}
+
+	// Parse owner/repo
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid repository format: %s (expected owner/repo)", repository)
+	}
+	owner, repoName := parts[0], parts[1]
+
+	info, err := github.FindComment(historyClient, owner, repoName, commentID, pr)
+	if err != nil {
+		return formatActionableError("comment lookup", err)
+	}
+	if !info.Found {
+		return fmt.Errorf("comment #%d not found on PR #%d", commentID, pr)
+	}
+
+	edits, err := historyClient.ListCommentEdits(owner, repoName, commentID, pr)
+	if err != nil {
+		return formatActionableError("edit history", err)
+	}
+	revisions := buildRevisions(info.Comment, edits)
+
+	if historyFrom != 0 {
+		from, err := findRevision(revisions, historyFrom, "from")
+		if err != nil {
+			return err
+		}
+		to, err := findRevision(revisions, historyTo, "to")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Comment #%d: revision %d → %d\n\n", commentID, from.Number, to.Number)
+		fmt.Println(renderWordDiff(wordDiff(from.Body, to.Body)))
+		return nil
+	}
+
+	if historyFormat == FormatJSON {
+		return writeJSON(os.Stdout, struct {
+			CommentID int               `json:"comment_id"`
+			Author    string            `json:"author"`
+			Revisions []commentRevision `json:"revisions"`
+		}{CommentID: commentID, Author: info.Comment.User.Login, Revisions: revisions})
+	}
+
+	fmt.Printf("Comment #%d by @%s: %d revision(s)\n\n", commentID, info.Comment.User.Login, len(revisions))
+	var previous *commentRevision
+	for i := range revisions {
+		revision := &revisions[i]
+		summary := describeRevision(previous, revision)
+		if i == len(revisions)-1 {
+			summary += " (current)"
+		}
+		fmt.Printf("  #%-3d @%-16s %s  %s\n", revision.Number, revision.Editor, revision.EditedAt.Format("2006-01-02 15:04"), summary)
+
+		if historyDiff && previous != nil && !revision.Deleted {
+			fmt.Printf("\n%s\n\n", indentLines(renderWordDiff(wordDiff(previous.Body, revision.Body)), "      "))
+		}
+		if !revision.Deleted {
+			previous = revision
+		}
+	}
+
+	if len(revisions) > 1 && !historyDiff {
+		fmt.Printf("\n💡 Compare revisions with: gh comment history %d --from 1 --to %d\n", commentID, len(revisions))
+	}
+	return nil
+}
+
+// buildRevisions numbers a comment's revisions, oldest first. GitHub only starts
+// keeping history on the first edit, so an unedited comment is its own single revision.
+func buildRevisions(comment *github.Comment, edits []github.CommentEdit) []commentRevision {
+	if len(edits) == 0 {
+		return []commentRevision{{Number: 1, Editor: comment.User.Login, EditedAt: comment.CreatedAt, Body: comment.Body}}
+	}
+
+	revisions := make([]commentRevision, len(edits))
+	for i, edit := range edits {
+		revisions[i] = commentRevision{Number: i + 1, Editor: edit.Editor, EditedAt: edit.EditedAt, Body: edit.Body, Deleted: edit.Deleted}
+	}
+	return revisions
+}
+
+// findRevision looks up a revision by number, skipping ones removed from the history
+func findRevision(revisions []commentRevision, number int, flag string) (*commentRevision, error) {
+	if number < 1 || number > len(revisions) {
+		return nil, formatValidationError(flag, fmt.Sprintf("%d", number), fmt.Sprintf("must be a revision between 1 and %d", len(revisions)))
+	}
+	revision := &revisions[number-1]
+	if revision.Deleted {
+		return nil, fmt.Errorf("revision %d was deleted from the comment history", number)
+	}
+	return revision, nil
+}
+
+// describeRevision summarises how a revision differs from the one before it
+func describeRevision(previous, revision *commentRevision) string {
+	switch {
+	case revision.Deleted:
+		return "deleted from history"
+	case previous == nil:
+		return "original"
+	}
+	added, removed := wordDiffStats(wordDiff(previous.Body, revision.Body))
+	if added == 0 && removed == 0 {
+		return "whitespace only"
+	}
+	return fmt.Sprintf("+%d −%d words", added, removed)
+}
+
+// indentLines prefixes every line of text with indent
+func indentLines(text, indent string) string {
+	return indent + strings.ReplaceAll(text, "\n", "\n"+indent)
+}
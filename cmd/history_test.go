@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func setupHistoryTest(t *testing.T) *github.MockClient {
+	t.Helper()
+	disableColor(t)
+	originalClient, originalRepo, originalPR := historyClient, repo, prNumber
+	originalDiff, originalFrom, originalTo, originalFormat := historyDiff, historyFrom, historyTo, historyFormat
+	t.Cleanup(func() {
+		historyClient, repo, prNumber = originalClient, originalRepo, originalPR
+		historyDiff, historyFrom, historyTo, historyFormat = originalDiff, originalFrom, originalTo, originalFormat
+	})
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 9, 30, 0, 0, time.UTC) }
+	mock := github.NewMockClient()
+	mock.CommentEdits = map[int][]github.CommentEdit{
+		123456: {
+			{EditedAt: day(1), Editor: "reviewer1", Body: "LGTM"},
+			{EditedAt: day(2), Editor: "reviewer1", Body: "LGTM! Great work"},
+			{EditedAt: day(3), Editor: "ghost", Deleted: true},
+			{EditedAt: day(4), Editor: "maintainer", Body: "LGTM! Great work on this PR."},
+		},
+	}
+
+	historyClient, repo, prNumber = mock, "owner/repo", 7
+	historyDiff, historyFrom, historyTo, historyFormat = false, 0, 0, FormatDefault
+	return mock
+}
+
+func TestHistoryCommand(t *testing.T) {
+	setupHistoryTest(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runHistory(historyCmd, []string{"123456"}))
+	})
+	assert.Contains(t, output, "Comment #123456 by @reviewer1: 4 revision(s)")
+	assert.Contains(t, output, "#1   @reviewer1        2024-01-01 09:30  original\n")
+	assert.Contains(t, output, "#2   @reviewer1        2024-01-02 09:30  +2 −0 words\n")
+	assert.Contains(t, output, "#3   @ghost            2024-01-03 09:30  deleted from history\n")
+	assert.Contains(t, output, "#4   @maintainer       2024-01-04 09:30  +3 −0 words (current)\n")
+	assert.Contains(t, output, "gh comment history 123456 --from 1 --to 4")
+
+	historyDiff = true
+	output = captureOutput(func() {
+		require.NoError(t, runHistory(historyCmd, []string{"123456"}))
+	})
+	assert.Contains(t, output, "      LGTM{+! Great work+}\n")
+	assert.Contains(t, output, "      LGTM! Great work{+ on this PR.+}\n")
+	assert.NotContains(t, output, "--from 1")
+}
+
+func TestHistoryCompareRevisions(t *testing.T) {
+	setupHistoryTest(t)
+	historyFrom, historyTo = 4, 1
+
+	output := captureOutput(func() {
+		require.NoError(t, runHistory(historyCmd, []string{"123456"}))
+	})
+	assert.Equal(t, "Comment #123456: revision 4 → 1\n\nLGTM[-! Great work on this PR.-]\n", output)
+
+	historyTo = 3
+	assert.ErrorContains(t, runHistory(historyCmd, []string{"123456"}), "revision 3 was deleted")
+	historyTo = 9
+	assert.ErrorContains(t, runHistory(historyCmd, []string{"123456"}), "must be a revision between 1 and 4")
+}
+
+func TestHistoryUneditedComment(t *testing.T) {
+	setupHistoryTest(t)
+	historyFormat = FormatJSON
+
+	output := captureOutput(func() {
+		require.NoError(t, runHistory(historyCmd, []string{"654321"}))
+	})
+	assert.JSONEq(t, `{
+		"comment_id": 654321,
+		"author": "reviewer2",
+		"revisions": [
+			{"number": 1, "editor": "reviewer2", "edited_at": "2024-01-01T13:00:00Z", "body": "Consider using a more descriptive variable name here."}
+		]
+	}`, output)
+}
+
+func TestHistoryErrors(t *testing.T) {
+	mock := setupHistoryTest(t)
+
+	assert.ErrorContains(t, runHistory(historyCmd, []string{"abc"}), "invalid comment ID")
+	assert.ErrorContains(t, runHistory(historyCmd, []string{"999"}), "comment #999 not found on PR #7")
+
+	historyFrom = 1
+	assert.ErrorContains(t, runHistory(historyCmd, []string{"123456"}), "--from and --to must be used together")
+	historyTo, historyDiff = 2, true
+	assert.ErrorContains(t, runHistory(historyCmd, []string{"123456"}), "cannot use --diff with --from/--to")
+	historyFrom, historyTo, historyDiff = 0, 0, false
+
+	historyFormat = "table"
+	assert.ErrorContains(t, runHistory(historyCmd, []string{"123456"}), "must be one of: default, json")
+	historyFormat = FormatDefault
+
+	mock.ListCommentEditsError = errors.New("graphql unavailable")
+	assert.ErrorContains(t, runHistory(historyCmd, []string{"123456"}), "graphql unavailable")
+}
@@ -0,0 +1,495 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// Hotspot output formats and node kinds
+const (
+	HotspotFormatTable = "table"
+	HotspotFormatJSON  = "json"
+
+	HotspotNodeDir  = "dir"
+	HotspotNodeFile = "file"
+	HotspotNodeFunc = "func"
+)
+
+var (
+	hotspotsSince       string
+	hotspotsUntil       string
+	hotspotsState       string
+	hotspotsAuthor      string
+	hotspotsLimit       int
+	hotspotsConcurrency int
+	hotspotsTop         int
+	hotspotsFunctions   bool
+	hotspotsRoot        string
+	hotspotsFormat      string
+
+	// Client for dependency injection (tests can override)
+	hotspotsClient github.GitHubAPI
+)
+
+var hotspotsCmd = &cobra.Command{
+	Use:   "hotspots [pr...]",
+	Short: "Rank files and functions by review feedback",
+	Long: heredoc.Doc(`
+		Group review comments by directory and file to find the parts of the
+		codebase that attract the most review feedback.
+
+		Comments are collected from the current PR, from the PRs given as
+		arguments, or from every PR updated since --since. Only line-level
+		review comments are counted; general PR comments have no location.
+
+		With --functions, line numbers in .go files are mapped to their
+		enclosing function or method by parsing the local checkout (--root).
+		Lines refer to the commit each comment was made on, so mapping is
+		most accurate when the checkout is close to the reviewed code.
+
+		The table output ranks directories, files and functions. The JSON
+		output is a tree (directories → files → functions) with comment and
+		PR counts on every node, suitable for dashboards and treemaps.
+	`),
+	Example: heredoc.Doc(`
+		# Hotspots in the current PR
+		$ gh comment hotspots
+
+		# Across specific PRs
+		$ gh comment hotspots 120 121 135
+
+		# Everything reviewed this quarter, mapped to Go functions
+		$ gh comment hotspots --since "3 months ago" --functions
+
+		# JSON tree for a dashboard
+		$ gh comment hotspots --since 2024-01-01 --functions --format json > hotspots.json
+	`),
+	RunE: runHotspots,
+}
+
+func init() {
+	rootCmd.AddCommand(hotspotsCmd)
+
+	hotspotsCmd.Flags().StringVar(&hotspotsSince, "since", "", "Include review comments since this date (flexible formats); enables repository-wide mode")
+	hotspotsCmd.Flags().StringVar(&hotspotsUntil, "until", "", "Include review comments before this date (flexible formats)")
+	hotspotsCmd.Flags().StringVar(&hotspotsState, "state", "all", "PR state in repository-wide mode: open, closed, merged, all")
+	hotspotsCmd.Flags().StringVar(&hotspotsAuthor, "author", "", "Only count comments by this author (supports wildcards, @me for yourself)")
+	hotspotsCmd.Flags().IntVar(&hotspotsLimit, "limit", DefaultSearchLimit, "Maximum number of PRs in repository-wide mode")
+	hotspotsCmd.Flags().IntVar(&hotspotsConcurrency, "concurrency", DefaultSearchConcurrency, "Number of PRs to fetch in parallel")
+	hotspotsCmd.Flags().IntVar(&hotspotsTop, "top", DefaultStatsTop, "Rows to show per ranking")
+	hotspotsCmd.Flags().BoolVar(&hotspotsFunctions, "functions", false, "Map lines in .go files to their enclosing functions using the local checkout")
+	hotspotsCmd.Flags().StringVar(&hotspotsRoot, "root", ".", "Local checkout used by --functions")
+	hotspotsCmd.Flags().StringVar(&hotspotsFormat, "format", HotspotFormatTable, "Output format: table, json")
+}
+
+// HotspotNode is a directory, file or function in the hotspot tree
+type HotspotNode struct {
+	Name     string         `json:"name"`
+	Path     string         `json:"path"`
+	Kind     string         `json:"kind"`
+	Comments int            `json:"comments"`
+	PRs      int            `json:"prs"`
+	Children []*HotspotNode `json:"children,omitempty"`
+
+	prs      map[int]bool
+	children map[string]*HotspotNode
+}
+
+// HotspotReport is the JSON document produced by 'gh comment hotspots --format json'
+type HotspotReport struct {
+	Repository   string       `json:"repository"`
+	PullRequests []int        `json:"pull_requests"`
+	Comments     int          `json:"comments"`
+	Tree         *HotspotNode `json:"tree"`
+}
+
+func runHotspots(cmd *cobra.Command, args []string) error {
+	// Initialize client if not set (production use)
+	if hotspotsClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		hotspotsClient = client
+	}
+
+	if hotspotsFormat != HotspotFormatTable && hotspotsFormat != HotspotFormatJSON {
+		return formatValidationError("format", hotspotsFormat, "must be one of: table, json")
+	}
+	if hotspotsTop <= 0 {
+		return formatValidationError("top", strconv.Itoa(hotspotsTop), "must be a positive integer")
+	}
+
+	repository, comments, err := collectHotspotComments(args)
+	if err != nil {
+		return err
+	}
+
+	var resolver *goFunctionResolver
+	if hotspotsFunctions {
+		resolver = newGoFunctionResolver(hotspotsRoot)
+	}
+
+	report := buildHotspotReport(repository, comments, resolver)
+
+	if hotspotsFormat == HotspotFormatJSON {
+		return writeJSON(os.Stdout, report)
+	}
+	return renderHotspotsTable(os.Stdout, report, hotspotsTop, terminalWidth())
+}
+
+// collectHotspotComments gathers review comments from the selected PRs
+func collectHotspotComments(args []string) (string, []Comment, error) {
+	opts := searchOptions{
+		State:       strings.ToLower(hotspotsState),
+		Author:      hotspotsAuthor,
+		Type:        "review",
+		Status:      "all",
+		Limit:       hotspotsLimit,
+		Concurrency: hotspotsConcurrency,
+	}
+
+	if !containsString([]string{"open", "closed", "merged", "all"}, opts.State) {
+		return "", nil, formatValidationError("state", hotspotsState, "must be one of: open, closed, merged, all")
+	}
+	if opts.Concurrency <= 0 || opts.Concurrency > MaxSearchConcurrency {
+		return "", nil, formatValidationError("concurrency", strconv.Itoa(opts.Concurrency),
+			fmt.Sprintf("must be between 1 and %d", MaxSearchConcurrency))
+	}
+	if hotspotsSince != "" {
+		parsed, err := parseFlexibleDate(hotspotsSince)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid since date '%s': %w", hotspotsSince, err)
+		}
+		opts.Since = &parsed
+	}
+	if hotspotsUntil != "" {
+		parsed, err := parseFlexibleDate(hotspotsUntil)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid until date '%s': %w", hotspotsUntil, err)
+		}
+		opts.Until = &parsed
+	}
+
+	// Repository-wide mode
+	if len(args) == 0 && opts.Since != nil {
+		repository, err := getCurrentRepo()
+		if err != nil {
+			return "", nil, err
+		}
+		if err := validateRepositoryName(repository); err != nil {
+			return "", nil, err
+		}
+
+		result, err := searchComments(hotspotsClient, repository, opts)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, fetchErr := range result.Errors {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", fetchErr)
+		}
+		return repository, result.Comments, nil
+	}
+
+	var repository string
+	var prs []int
+	if len(args) == 0 {
+		var pr int
+		var err error
+		repository, pr, err = getPRContext()
+		if err != nil {
+			return "", nil, err
+		}
+		prs = []int{pr}
+	} else {
+		var err error
+		repository, err = getCurrentRepo()
+		if err != nil {
+			return "", nil, err
+		}
+		for _, arg := range args {
+			pr, err := strconv.Atoi(arg)
+			if err != nil || pr <= 0 {
+				return "", nil, formatValidationError("PR number", arg, "must be a positive integer")
+			}
+			prs = append(prs, pr)
+		}
+	}
+
+	comments, errs := fetchCommentsForPRs(hotspotsClient, repository, prs, opts.Concurrency, false)
+	for _, fetchErr := range errs {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", fetchErr)
+	}
+	if len(errs) == len(prs) {
+		return "", nil, fmt.Errorf("failed to fetch comments for all %d PRs: %w", len(prs), errs[0])
+	}
+
+	var filtered []Comment
+	for _, comment := range comments {
+		if matchesSearchFilters(comment, opts) {
+			filtered = append(filtered, comment)
+		}
+	}
+
+	return repository, filtered, nil
+}
+
+// buildHotspotReport groups located review comments into a directory/file/function tree
+func buildHotspotReport(repository string, comments []Comment, resolver *goFunctionResolver) *HotspotReport {
+	root := newHotspotNode(".", ".", HotspotNodeDir)
+	report := &HotspotReport{Repository: repository, PullRequests: []int{}, Tree: root}
+	seenPRs := make(map[int]bool)
+
+	for _, comment := range comments {
+		if comment.Type != "review" || comment.Path == "" {
+			continue
+		}
+
+		report.Comments++
+		if !seenPRs[comment.PR] {
+			seenPRs[comment.PR] = true
+			report.PullRequests = append(report.PullRequests, comment.PR)
+		}
+
+		node := root
+		node.add(comment.PR)
+
+		filePath := path.Clean(comment.Path)
+		segments := strings.Split(filePath, "/")
+		for i, segment := range segments {
+			kind := HotspotNodeDir
+			if i == len(segments)-1 {
+				kind = HotspotNodeFile
+			}
+			node = node.child(segment, strings.Join(segments[:i+1], "/"), kind)
+			node.add(comment.PR)
+		}
+
+		if resolver != nil {
+			if function := resolver.Enclosing(filePath, comment.Line); function != "" {
+				node = node.child(function, filePath+"#"+function, HotspotNodeFunc)
+				node.add(comment.PR)
+			}
+		}
+	}
+
+	sort.Ints(report.PullRequests)
+	root.finalize()
+	return report
+}
+
+func newHotspotNode(name, nodePath, kind string) *HotspotNode {
+	return &HotspotNode{Name: name, Path: nodePath, Kind: kind, prs: make(map[int]bool), children: make(map[string]*HotspotNode)}
+}
+
+// child returns the named child, creating it if needed
+func (n *HotspotNode) child(name, nodePath, kind string) *HotspotNode {
+	if existing, ok := n.children[name]; ok {
+		return existing
+	}
+	node := newHotspotNode(name, nodePath, kind)
+	n.children[name] = node
+	return node
+}
+
+// add counts one comment from the given PR
+func (n *HotspotNode) add(pr int) {
+	n.Comments++
+	n.prs[pr] = true
+}
+
+// finalize fills PR counts and orders children by comment count, recursively
+func (n *HotspotNode) finalize() {
+	n.PRs = len(n.prs)
+	n.Children = make([]*HotspotNode, 0, len(n.children))
+	for _, child := range n.children {
+		child.finalize()
+		n.Children = append(n.Children, child)
+	}
+	sortHotspotNodes(n.Children)
+}
+
+// walk visits the node and all its descendants
+func (n *HotspotNode) walk(visit func(*HotspotNode)) {
+	visit(n)
+	for _, child := range n.Children {
+		child.walk(visit)
+	}
+}
+
+// rankHotspots returns the nodes of a kind with the most comments
+func rankHotspots(root *HotspotNode, kind string, top int) []*HotspotNode {
+	var nodes []*HotspotNode
+	root.walk(func(n *HotspotNode) {
+		if n.Kind == kind && n != root {
+			nodes = append(nodes, n)
+		}
+	})
+
+	sortHotspotNodes(nodes)
+	if top > 0 && len(nodes) > top {
+		nodes = nodes[:top]
+	}
+	return nodes
+}
+
+// sortHotspotNodes orders by comment count, then PR count, then path
+func sortHotspotNodes(nodes []*HotspotNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Comments != nodes[j].Comments {
+			return nodes[i].Comments > nodes[j].Comments
+		}
+		if nodes[i].PRs != nodes[j].PRs {
+			return nodes[i].PRs > nodes[j].PRs
+		}
+		return nodes[i].Path < nodes[j].Path
+	})
+}
+
+// renderHotspotsTable prints ranked directories, files and functions
+func renderHotspotsTable(w io.Writer, report *HotspotReport, top, width int) error {
+	fmt.Fprintf(w, "🔥 Review hotspots for %s (%d review comments across %d PRs)\n",
+		report.Repository, report.Comments, len(report.PullRequests))
+
+	if report.Comments == 0 {
+		fmt.Fprintln(w, "\nNo line-level review comments found.")
+		return nil
+	}
+
+	sections := []struct {
+		title string
+		kind  string
+	}{
+		{"DIRECTORY", HotspotNodeDir},
+		{"FILE", HotspotNodeFile},
+		{"FUNCTION", HotspotNodeFunc},
+	}
+
+	for _, section := range sections {
+		nodes := rankHotspots(report.Tree, section.kind, top)
+		if len(nodes) == 0 {
+			continue
+		}
+
+		fmt.Fprintln(w)
+		table := tableprinter.New(w, true, width)
+		table.AddHeader([]string{section.title, "COMMENTS", "PRS"})
+		for _, node := range nodes {
+			name := node.Path
+			if section.kind == HotspotNodeDir {
+				name += "/"
+			}
+			if section.kind == HotspotNodeFunc {
+				name = strings.Replace(node.Path, "#", " ", 1)
+			}
+			table.AddField(name)
+			table.AddField(strconv.Itoa(node.Comments))
+			table.AddField(strconv.Itoa(node.PRs))
+			table.EndRow()
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// goFunctionRange is the line span of a function declaration
+type goFunctionRange struct {
+	Name       string
+	Start, End int
+}
+
+// goFunctionResolver maps file lines to enclosing Go functions, parsing each file once
+type goFunctionResolver struct {
+	root  string
+	cache map[string][]goFunctionRange
+}
+
+func newGoFunctionResolver(root string) *goFunctionResolver {
+	return &goFunctionResolver{root: root, cache: make(map[string][]goFunctionRange)}
+}
+
+// Enclosing returns the function containing line in a .go file, or "" when unknown
+func (r *goFunctionResolver) Enclosing(filePath string, line int) string {
+	if line <= 0 || !strings.HasSuffix(filePath, ".go") {
+		return ""
+	}
+
+	ranges, ok := r.cache[filePath]
+	if !ok {
+		ranges = parseGoFunctions(filepath.Join(r.root, filepath.FromSlash(filePath)))
+		r.cache[filePath] = ranges
+	}
+
+	for _, fn := range ranges {
+		if line >= fn.Start && line <= fn.End {
+			return fn.Name
+		}
+	}
+	return ""
+}
+
+// parseGoFunctions lists the top-level functions and methods in a Go file.
+// Files that are missing or fail to parse yield no functions.
+func parseGoFunctions(filename string) []goFunctionRange {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var ranges []goFunctionRange
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		name := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			if receiver := receiverTypeName(fn.Recv.List[0].Type); receiver != "" {
+				name = receiver + "." + name
+			}
+		}
+
+		start := fset.Position(fn.Pos()).Line
+		if fn.Doc != nil {
+			start = fset.Position(fn.Doc.Pos()).Line
+		}
+		ranges = append(ranges, goFunctionRange{Name: name, Start: start, End: fset.Position(fn.End()).Line})
+	}
+
+	return ranges
+}
+
+// receiverTypeName returns the base type name of a method receiver (T for *T, T[K])
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
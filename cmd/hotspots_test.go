@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+const hotspotGoSource = `package api
+
+// Handler serves requests
+func Handler() {
+	doWork()
+}
+
+type server struct{}
+
+func (s *server) Start() error {
+	return nil
+}
+
+type cache[K comparable] struct{}
+
+func (c cache[K]) Get(key K) {}
+`
+
+func writeHotspotFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src", "api"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "api", "handler.go"), []byte(hotspotGoSource), 0644))
+	return root
+}
+
+func hotspotComments() []Comment {
+	return []Comment{
+		{ID: 1, PR: 1, Type: "review", Path: "src/api/handler.go", Line: 5},
+		{ID: 2, PR: 2, Type: "review", Path: "src/api/handler.go", Line: 11},
+		{ID: 3, PR: 2, Type: "review", Path: "src/api/handler.go", Line: 4},
+		{ID: 4, PR: 2, Type: "review", Path: "src/api/routes.go", Line: 1},
+		{ID: 5, PR: 3, Type: "review", Path: "README.md", Line: 2},
+		{ID: 6, PR: 3, Type: "issue", Body: "General comment"},
+	}
+}
+
+func TestParseGoFunctions(t *testing.T) {
+	root := writeHotspotFixture(t)
+
+	ranges := parseGoFunctions(filepath.Join(root, "src", "api", "handler.go"))
+	require.Len(t, ranges, 3)
+	assert.Equal(t, goFunctionRange{Name: "Handler", Start: 3, End: 6}, ranges[0])
+	assert.Equal(t, "server.Start", ranges[1].Name)
+	assert.Equal(t, "cache.Get", ranges[2].Name)
+
+	assert.Nil(t, parseGoFunctions(filepath.Join(root, "missing.go")))
+}
+
+func TestGoFunctionResolverEnclosing(t *testing.T) {
+	resolver := newGoFunctionResolver(writeHotspotFixture(t))
+
+	assert.Equal(t, "Handler", resolver.Enclosing("src/api/handler.go", 5))
+	assert.Equal(t, "Handler", resolver.Enclosing("src/api/handler.go", 3), "doc comments belong to the function")
+	assert.Equal(t, "server.Start", resolver.Enclosing("src/api/handler.go", 11))
+	assert.Equal(t, "", resolver.Enclosing("src/api/handler.go", 8), "type declarations are not functions")
+	assert.Equal(t, "", resolver.Enclosing("src/api/handler.go", 0))
+	assert.Equal(t, "", resolver.Enclosing("README.md", 2))
+	assert.Len(t, resolver.cache, 1)
+}
+
+func TestBuildHotspotReport(t *testing.T) {
+	report := buildHotspotReport("owner/repo", hotspotComments(), nil)
+
+	assert.Equal(t, 5, report.Comments)
+	assert.Equal(t, []int{1, 2, 3}, report.PullRequests)
+	assert.Equal(t, 5, report.Tree.Comments)
+	assert.Equal(t, 3, report.Tree.PRs)
+
+	// Children are ordered by comment count
+	require.Len(t, report.Tree.Children, 2)
+	src := report.Tree.Children[0]
+	assert.Equal(t, "src", src.Name)
+	assert.Equal(t, HotspotNodeDir, src.Kind)
+	assert.Equal(t, 4, src.Comments)
+
+	files := rankHotspots(report.Tree, HotspotNodeFile, 10)
+	require.Len(t, files, 3)
+	assert.Equal(t, "src/api/handler.go", files[0].Path)
+	assert.Equal(t, 3, files[0].Comments)
+	assert.Equal(t, 2, files[0].PRs)
+
+	assert.Empty(t, rankHotspots(report.Tree, HotspotNodeFunc, 10))
+	assert.Len(t, rankHotspots(report.Tree, HotspotNodeFile, 1), 1)
+}
+
+func TestBuildHotspotReportWithFunctions(t *testing.T) {
+	resolver := newGoFunctionResolver(writeHotspotFixture(t))
+	report := buildHotspotReport("owner/repo", hotspotComments(), resolver)
+
+	functions := rankHotspots(report.Tree, HotspotNodeFunc, 10)
+	require.Len(t, functions, 2)
+	assert.Equal(t, "src/api/handler.go#Handler", functions[0].Path)
+	assert.Equal(t, 2, functions[0].Comments)
+	assert.Equal(t, "server.Start", functions[1].Name)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeJSON(&buf, report))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	tree := decoded["tree"].(map[string]interface{})
+	assert.Equal(t, float64(5), tree["comments"])
+	assert.Equal(t, "dir", tree["kind"])
+	assert.NotContains(t, buf.String(), `"children": null`)
+}
+
+func TestRenderHotspotsTable(t *testing.T) {
+	resolver := newGoFunctionResolver(writeHotspotFixture(t))
+	report := buildHotspotReport("owner/repo", hotspotComments(), resolver)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderHotspotsTable(&buf, report, 10, 120))
+	output := buf.String()
+
+	assert.Contains(t, output, "5 review comments across 3 PRs")
+	assert.Contains(t, output, "src/api/")
+	assert.Contains(t, output, "src/api/handler.go Handler")
+
+	buf.Reset()
+	require.NoError(t, renderHotspotsTable(&buf, buildHotspotReport("owner/repo", nil, nil), 10, 120))
+	assert.Contains(t, buf.String(), "No line-level review comments found")
+}
+
+func TestRunHotspotsAcrossPRs(t *testing.T) {
+	originalClient, originalRepo, originalFormat := hotspotsClient, repo, hotspotsFormat
+	defer func() { hotspotsClient, repo, hotspotsFormat = originalClient, originalRepo, originalFormat }()
+
+	hotspotsClient = &github.MockClient{
+		IssueComments: []github.Comment{{ID: 1, Body: "General", User: github.User{Login: "alice"}}},
+		ReviewComments: []github.Comment{
+			{ID: 2, Body: "Fix", User: github.User{Login: "bob"}, Path: "api.go", Line: 5},
+		},
+	}
+	repo = "owner/repo"
+	hotspotsFormat = HotspotFormatJSON
+
+	output := captureOutput(func() {
+		require.NoError(t, runHotspots(hotspotsCmd, []string{"10", "11"}))
+	})
+
+	var report HotspotReport
+	require.NoError(t, json.Unmarshal([]byte(output), &report))
+	assert.Equal(t, []int{10, 11}, report.PullRequests)
+	assert.Equal(t, 2, report.Comments)
+
+	assert.Error(t, runHotspots(hotspotsCmd, []string{"abc"}))
+
+	hotspotsFormat = "xml"
+	assert.Error(t, runHotspots(hotspotsCmd, []string{"10"}))
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
@@ -28,9 +27,18 @@ var (
 	until      string
 	listType   string
 
+	// Content filters (--grep, --regex, --file, ...)
+	listQuery CommentQuery
+
 	// Output format flags
-	outputFormat string
-	idsOnly      bool
+	outputFormat   string
+	outputTemplate string
+	outputJQ       string
+	idsOnly        bool
+	rawBodies      bool
+
+	// Fetch who reacted to each comment
+	listReactionUsers bool
 
 	// Parsed time values
 	sinceTime *time.Time
@@ -52,9 +60,25 @@ var listCmd = &cobra.Command{
 		- Review comments: Line-specific feedback, appear in "Files Changed" tab
 
 		Comments can be filtered by type, author, date range, and more.
+		Content filters (--grep, --regex, --file, --line-range, --has-suggestion,
+		--reaction, --min-reactions, --min-replies) can be combined in one query;
+		every filter that is set must match. Use --invert to select the comments
+		that do not match the content filters.
+
+		Reaction counts are shown under each comment. --reaction-users also shows
+		who reacted, at the cost of one extra API call per comment with reactions.
 		Note: GitHub's REST API does not provide comment resolution status.
 
-		Output can be formatted as tables, JSON, or plain text with color coding.
+		Comment bodies are rendered as markdown in the terminal: headings, lists,
+		tables and highlighted code, with suggestion blocks shown as a diff against
+		the commented lines. Text wraps to the terminal width and colors follow
+		--no-color. Use --raw to print bodies exactly as written.
+
+		Output can be formatted as plain text with color coding, an aligned table,
+		JSON, CSV or TSV. Use --template to render each comment with a Go template
+		(fields: .ID, .Author, .Body, .Type, .Path, .Line, .CreatedAt, .UpdatedAt,
+		.CommitID; helpers: truncate, oneline, timeago, json, upper, lower) or --jq
+		to filter the JSON output with a jq expression, as in 'gh --jq'.
 		Perfect for code review workflows, comment analysis, and automation.
 	`),
 	Example: heredoc.Doc(`
@@ -74,9 +98,25 @@ var listCmd = &cobra.Command{
 
 		# Structured output for automation
 		$ gh comment list 123 --format json | jq '.comments[].id'
+		$ gh comment list 123 --jq '.comments[] | select(.type == "review") | .id'
+		$ gh comment list 123 --format table
+		$ gh comment list 123 --format csv > comments.csv
+		$ gh comment list 123 --template '{{.ID}} {{.Author}} {{truncate 60 (oneline .Body)}}'
 		$ gh comment list 123 --ids-only | xargs -I {} gh comment resolve {}
+		$ gh comment list 123 --raw --no-color > comments.txt
 		$ gh comment list 123 --format json --author "security*" > security-comments.json
 
+		# Search comment bodies and locations
+		$ gh comment list 123 --type review --grep TODO --grep nolint
+		$ gh comment list 123 --regex 'panic\(|log\.Fatal' --file 'pkg/auth/**'
+		$ gh comment list 123 --file '**/*_test.go' --line-range 10-40
+		$ gh comment list 123 --has-suggestion --reaction +1
+		$ gh comment list 123 --min-replies 3
+
+		# Find comments most reviewers agreed with, and who agreed
+		$ gh comment list 123 --reaction +1 --min-reactions 2 --reaction-users
+		$ gh comment list 123 --author "bot*" --grep "addressed" --invert
+
 		# Code review workflow optimization
 		$ gh comment list 123 --since "1 month ago" --author "lead*"
 		$ gh comment list 123 --until "2024-12-31" --type issue
@@ -110,13 +150,18 @@ func init() {
 	listCmd.Flags().StringVar(&since, "since", "", "Show comments after this date/time (flexible formats)")
 	listCmd.Flags().StringVar(&until, "until", "", "Show comments before this date/time (flexible formats)")
 	listCmd.Flags().StringVar(&listType, "type", "", "Filter by type (issue|review)")
+	addCommentQueryFlags(listCmd.Flags(), &listQuery)
 
 	// Display flags
 	listCmd.Flags().BoolVar(&quiet, "quiet", false, "Minimal output (hides URLs and formatting)")
 	listCmd.Flags().BoolVar(&hideAuthors, "hide-authors", false, "Hide comment authors in output")
+	listCmd.Flags().BoolVar(&rawBodies, "raw", false, "Print comment bodies as-is instead of rendering markdown")
+	listCmd.Flags().BoolVar(&listReactionUsers, "reaction-users", false, "Show who reacted to each comment (one API call per comment with reactions, a few at a time)")
 
 	// Output format flags
-	listCmd.Flags().StringVar(&outputFormat, "format", "default", "Output format (default|json)")
+	listCmd.Flags().StringVar(&outputFormat, "format", FormatDefault, "Output format (default|table|json|csv|tsv)")
+	listCmd.Flags().StringVar(&outputTemplate, "template", "", "Format each comment using a Go template")
+	listCmd.Flags().StringVar(&outputJQ, "jq", "", "Filter JSON output using a jq expression")
 	listCmd.Flags().BoolVar(&idsOnly, "ids-only", false, "Output only comment IDs (one per line)")
 
 	// Register command
@@ -150,12 +195,16 @@ func applyListConfigDefaults(cmd *cobra.Command, args []string) {
 		until = config.Filters.Until
 	}
 
-	// Apply display defaults
-	if !cmd.Flags().Changed("format") && config.Display.Format != "table" {
+	// Apply display defaults, unless an explicit output flag picks the output already
+	explicitOutput := false
+	for _, name := range []string{"format", "template", "jq", "ids-only"} {
+		explicitOutput = explicitOutput || cmd.Flags().Changed(name)
+	}
+	if !explicitOutput {
 		// Map config format to list command format
 		switch config.Display.Format {
-		case "json":
-			outputFormat = "json"
+		case FormatJSON, FormatTable, FormatCSV, FormatTSV:
+			outputFormat = config.Display.Format
 		case "quiet":
 			quiet = true
 		}
@@ -187,6 +236,13 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Look up who reacted, only for the comments being shown
+	if listReactionUsers {
+		if err := addReactors(listClient, repository, pr, filteredComments); err != nil {
+			return err
+		}
+	}
+
 	// Format and display the output
 	return formatListOutput(filteredComments, pr)
 }
@@ -261,19 +317,35 @@ func fetchAndFilterComments(client github.GitHubAPI, repository string, pr int)
 // formatListOutput handles different output formats and display
 func formatListOutput(filteredComments []Comment, pr int) error {
 	// Handle different output formats
-	if idsOnly {
+	opts := listOutputOptions()
+	switch {
+	case idsOnly:
 		displayIDsOnly(filteredComments)
-	} else if outputFormat == "json" {
+	case opts.Template != "" || opts.JQ != "":
+		return renderComments(os.Stdout, filteredComments, listJSONOutput(filteredComments, pr), opts)
+	case outputFormat == FormatJSON:
 		if err := displayCommentsJSON(filteredComments, pr); err != nil {
 			return fmt.Errorf("failed to encode JSON output: %w", err)
 		}
-	} else {
+	case outputFormat == FormatTable, outputFormat == FormatCSV, outputFormat == FormatTSV:
+		return renderComments(os.Stdout, filteredComments, listJSONOutput(filteredComments, pr), opts)
+	default:
 		displayComments(filteredComments, pr)
 	}
 
 	return nil
 }
 
+// listOutputOptions collects the list command's output flags
+func listOutputOptions() OutputOptions {
+	return OutputOptions{
+		Format:      outputFormat,
+		Template:    outputTemplate,
+		JQ:          outputJQ,
+		HideAuthors: hideAuthors,
+	}
+}
+
 type Comment struct {
 	ID        int       `json:"id"`
 	Author    string    `json:"author"`
@@ -282,9 +354,23 @@ type Comment struct {
 	UpdatedAt time.Time `json:"updated_at"`
 
 	// For line-specific comments
-	Path     string `json:"path,omitempty"`
-	Line     int    `json:"line,omitempty"`
-	CommitID string `json:"commit_id,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	CommitID  string `json:"commit_id,omitempty"`
+	DiffHunk  string `json:"diff_hunk,omitempty"`
+
+	// Set when comments span several PRs (search)
+	PR int `json:"pr,omitempty"`
+
+	// Thread and reaction information
+	ThreadID    string                 `json:"thread_id,omitempty"`
+	Resolved    bool                   `json:"resolved,omitempty"`
+	Outdated    bool                   `json:"outdated,omitempty"`
+	InReplyToID int                    `json:"in_reply_to_id,omitempty"`
+	Replies     int                    `json:"replies,omitempty"`
+	Reactions   github.ReactionSummary `json:"reactions"`
+	Reactors    map[string][]string    `json:"reactors,omitempty"` // set by --reaction-users
 
 	// Comment type
 	Type string `json:"type"` // "issue" or "review"
@@ -299,55 +385,117 @@ func fetchAllComments(client github.GitHubAPI, repo string, pr int) ([]Comment,
 	owner := parts[0]
 	repoName := parts[1]
 
-	var allComments []Comment
-
 	// Fetch issue comments (general discussion)
 	issueComments, err := client.ListIssueComments(owner, repoName, pr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch issue comments: %w", err)
 	}
 
+	// Fetch review comments (line-specific)
+	reviewComments, err := client.ListReviewComments(owner, repoName, pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review comments: %w", err)
+	}
+
+	allComments := append(convertIssueComments(issueComments), convertReviewComments(reviewComments)...)
+
+	// Sort by creation time
+	for i := 0; i < len(allComments)-1; i++ {
+		for j := i + 1; j < len(allComments); j++ {
+			if allComments[i].CreatedAt.After(allComments[j].CreatedAt) {
+				allComments[i], allComments[j] = allComments[j], allComments[i]
+			}
+		}
+	}
+
+	return allComments, nil
+}
+
+// convertIssueComments converts API issue comments for display
+func convertIssueComments(issueComments []github.Comment) []Comment {
+	comments := make([]Comment, 0, len(issueComments))
 	for _, comment := range issueComments {
-		allComments = append(allComments, Comment{
+		comments = append(comments, Comment{
 			ID:        comment.ID,
 			Author:    comment.User.Login,
 			Body:      comment.Body,
 			CreatedAt: comment.CreatedAt,
 			UpdatedAt: comment.UpdatedAt,
+			Reactions: comment.Reactions,
 			Type:      "issue",
 		})
 	}
+	return comments
+}
 
-	// Fetch review comments (line-specific)
-	reviewComments, err := client.ListReviewComments(owner, repoName, pr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch review comments: %w", err)
+// convertReviewComments converts API review comments for display, counting replies per comment
+func convertReviewComments(reviewComments []github.Comment) []Comment {
+	// Count replies so threads can be filtered by activity
+	replyCounts := make(map[int]int)
+	for _, comment := range reviewComments {
+		if comment.InReplyToID != 0 {
+			replyCounts[comment.InReplyToID]++
+		}
 	}
 
+	comments := make([]Comment, 0, len(reviewComments))
 	for _, comment := range reviewComments {
-		allComments = append(allComments, Comment{
-			ID:        comment.ID,
-			Author:    comment.User.Login,
-			Body:      comment.Body,
-			CreatedAt: comment.CreatedAt,
-			UpdatedAt: comment.UpdatedAt,
-			Path:      comment.Path,
-			Line:      comment.Line,
-			CommitID:  comment.CommitID,
-			Type:      "review",
+		comments = append(comments, Comment{
+			ID:          comment.ID,
+			Author:      comment.User.Login,
+			Body:        comment.Body,
+			CreatedAt:   comment.CreatedAt,
+			UpdatedAt:   comment.UpdatedAt,
+			Path:        comment.Path,
+			Line:        comment.Line,
+			StartLine:   comment.StartLine,
+			CommitID:    comment.CommitID,
+			DiffHunk:    comment.DiffHunk,
+			InReplyToID: comment.InReplyToID,
+			Replies:     replyCounts[comment.ID],
+			Reactions:   comment.Reactions,
+			Type:        "review",
 		})
 	}
+	return comments
+}
 
-	// Sort by creation time
-	for i := 0; i < len(allComments)-1; i++ {
-		for j := i + 1; j < len(allComments); j++ {
-			if allComments[i].CreatedAt.After(allComments[j].CreatedAt) {
-				allComments[i], allComments[j] = allComments[j], allComments[i]
-			}
+// addReactors annotates comments that have reactions with the users who left them
+func addReactors(client github.GitHubAPI, repository string, pr int, comments []Comment) error {
+	owner, repoName, _ := strings.Cut(repository, "/")
+
+	targets := make([]reactorTarget, len(comments))
+	for i, comment := range comments {
+		targets[i] = reactorTarget{ID: comment.ID, Type: comment.Type, Reactions: comment.Reactions}
+	}
+
+	reactors, err := fetchReactors(client, owner, repoName, targets)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].Reactors = reactors[comments[i].ID]
+	}
+	return nil
+}
+
+// applyThreadState annotates review comments with their thread's ID and resolution state
+func applyThreadState(comments []Comment, threads []github.ReviewThread) {
+	byComment := make(map[int]github.ReviewThread)
+	for _, thread := range threads {
+		for _, id := range thread.CommentIDs {
+			byComment[id] = thread
 		}
 	}
 
-	return allComments, nil
+	for i := range comments {
+		if thread, ok := byComment[comments[i].ID]; ok {
+			comments[i].ThreadID = thread.ID
+			comments[i].Resolved = thread.IsResolved
+			comments[i].Outdated = thread.IsOutdated
+		}
+	}
 }
 
 func validateAndParseFilters() error {
@@ -381,16 +529,23 @@ func validateAndParseFilters() error {
 		return fmt.Errorf("invalid type '%s'. Must be one of: issue, review", listType)
 	}
 
-	// Validate output format flag
-	validFormats := []string{"default", "json"}
-	if outputFormat != "" && !containsString(validFormats, outputFormat) {
-		return fmt.Errorf("invalid format '%s'. Must be one of: %s", outputFormat, strings.Join(validFormats, ", "))
+	// Compile content filters
+	if err := listQuery.Compile(); err != nil {
+		return err
+	}
+
+	// Validate output format, template and jq flags
+	if err := validateOutputOptions(listOutputOptions()); err != nil {
+		return err
 	}
 
 	// Handle conflicting output options
-	if idsOnly && outputFormat == "json" {
+	if idsOnly && outputFormat == FormatJSON {
 		return fmt.Errorf("cannot use --ids-only with --format json (use --format json to get structured data including IDs)")
 	}
+	if idsOnly && (outputTemplate != "" || outputJQ != "" || (outputFormat != "" && outputFormat != FormatDefault)) {
+		return fmt.Errorf("cannot use --ids-only with --format, --template or --jq")
+	}
 
 	// Parse explicit since date (overrides filter defaults)
 	if since != "" {
@@ -454,6 +609,11 @@ func filterComments(comments []Comment) []Comment {
 			continue
 		}
 
+		// Filter by body, location, suggestion, reaction and reply criteria
+		if !listQuery.Matches(comment) {
+			continue
+		}
+
 		filtered = append(filtered, comment)
 	}
 
@@ -594,10 +754,20 @@ func displayComment(comment Comment) {
 		body = "(empty comment)"
 	}
 
-	// Indent the comment body
-	lines := strings.Split(body, "\n")
+	// Indent the comment body, rendering markdown unless --raw was given
+	var lines []string
+	if rawBodies {
+		lines = strings.Split(body, "\n")
+	} else {
+		original := suggestionOriginalLines(comment.DiffHunk, comment.StartLine, comment.Line)
+		lines = renderMarkdownTerminal(body, terminalWidth()-CommentBodyIndent, original)
+	}
 	for _, line := range lines {
-		fmt.Printf("   %s\n", line)
+		fmt.Printf("%s%s\n", strings.Repeat(" ", CommentBodyIndent), line)
+	}
+
+	if comment.Reactions.TotalCount > 0 {
+		fmt.Printf("%s%s\n", strings.Repeat(" ", CommentBodyIndent), formatReactionSummary(comment.Reactions, comment.Reactors))
 	}
 
 	// Note: GitHub API doesn't provide HTML URL for comments directly
@@ -672,7 +842,16 @@ func displayIDsOnly(comments []Comment) {
 
 // displayCommentsJSON outputs comments as JSON
 func displayCommentsJSON(comments []Comment, pr int) error {
-	output := struct {
+	return writeJSON(os.Stdout, listJSONOutput(comments, pr))
+}
+
+// listJSONOutput builds the JSON envelope used by 'list --format json' and --jq
+func listJSONOutput(comments []Comment, pr int) interface{} {
+	if comments == nil {
+		comments = []Comment{}
+	}
+
+	return struct {
 		PR       int       `json:"pr"`
 		Total    int       `json:"total"`
 		Comments []Comment `json:"comments"`
@@ -681,8 +860,4 @@ func displayCommentsJSON(comments []Comment, pr int) error {
 		Total:    len(comments),
 		Comments: comments,
 	}
-
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
 }
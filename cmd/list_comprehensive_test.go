@@ -351,6 +351,42 @@ func (m *MockGitHubClientForList) RemoveReaction(owner, repo string, commentID i
 	return nil
 }
 
+func (m *MockGitHubClientForList) ListReactions(owner, repo string, commentID int, prNumber int) ([]github.Reaction, error) {
+	return nil, nil
+}
+
+func (m *MockGitHubClientForList) AddCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	return nil
+}
+
+func (m *MockGitHubClientForList) RemoveCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	return nil
+}
+
+func (m *MockGitHubClientForList) ListCommentReactions(owner, repo string, commentID int, commentType string) ([]github.Reaction, error) {
+	return nil, nil
+}
+
+func (m *MockGitHubClientForList) AddPRReaction(owner, repo string, prNumber int, reaction string) error {
+	return nil
+}
+
+func (m *MockGitHubClientForList) RemovePRReaction(owner, repo string, prNumber int, reaction string) error {
+	return nil
+}
+
+func (m *MockGitHubClientForList) ListPRReactions(owner, repo string, prNumber int) ([]github.Reaction, error) {
+	return nil, nil
+}
+
+func (m *MockGitHubClientForList) GetRateLimit() (*github.RateLimit, error) {
+	return &github.RateLimit{Limit: 5000, Remaining: 5000}, nil
+}
+
+func (m *MockGitHubClientForList) ListCommentEdits(owner, repo string, commentID int, prNumber int) ([]github.CommentEdit, error) {
+	return nil, nil
+}
+
 func (m *MockGitHubClientForList) EditComment(owner, repo string, commentID int, prNumber int, body string) error {
 	return nil
 }
@@ -375,6 +411,34 @@ func (m *MockGitHubClientForList) FindPendingReview(owner, repo string, pr int)
 	return 0, nil
 }
 
+func (m *MockGitHubClientForList) ListReviewCommentsForReview(owner, repo string, pr, reviewID int) ([]github.Comment, error) {
+	return nil, nil
+}
+
 func (m *MockGitHubClientForList) SubmitReview(owner, repo string, pr, reviewID int, body, event string) error {
 	return nil
 }
+
+func (m *MockGitHubClientForList) ListReviewThreads(owner, repo string, prNumber int) ([]github.ReviewThread, error) {
+	return nil, nil
+}
+
+func (m *MockGitHubClientForList) SearchPullRequests(query string, limit int) ([]github.PullRequest, error) {
+	return nil, nil
+}
+
+func (m *MockGitHubClientForList) ListRepoReviewComments(owner, repo string, since time.Time, keep func(github.Comment) bool) ([]github.Comment, error) {
+	return nil, nil
+}
+
+func (m *MockGitHubClientForList) ListIssueCommentsIfChanged(owner, repo string, prNumber int, etag string) (*github.ConditionalComments, error) {
+	return &github.ConditionalComments{}, nil
+}
+
+func (m *MockGitHubClientForList) ListReviewCommentsIfChanged(owner, repo string, prNumber int, etag string) (*github.ConditionalComments, error) {
+	return &github.ConditionalComments{}, nil
+}
+
+func (m *MockGitHubClientForList) ListReviews(owner, repo string, pr int) ([]github.Review, error) {
+	return nil, nil
+}
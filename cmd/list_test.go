@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/silouanwright/gh-comment/internal/github"
 )
@@ -164,6 +165,30 @@ func TestApplyListConfigDefaults(t *testing.T) {
 		// Should set quiet = true when format is "quiet"
 		assert.True(t, quiet)
 	})
+
+	t.Run("explicit output flags win over the config format", func(t *testing.T) {
+		originalIDsOnly, originalTemplate, originalJQ := idsOnly, outputTemplate, outputJQ
+		originalSinceTime, originalUntilTime := sinceTime, untilTime
+		defer func() {
+			idsOnly, outputTemplate, outputJQ = originalIDsOnly, originalTemplate, originalJQ
+			sinceTime, untilTime = originalSinceTime, originalUntilTime
+			for _, name := range []string{"ids-only", "template", "jq"} {
+				cmd.Flags().Lookup(name).Changed = false
+			}
+		}()
+
+		for _, format := range []string{FormatTable, FormatCSV, FormatTSV, FormatJSON} {
+			testConfig.Display.Format = format
+			for flag, value := range map[string]string{"ids-only": "true", "template": "{{.ID}}", "jq": ".comments"} {
+				outputFormat, idsOnly, outputTemplate, outputJQ = "", false, "", ""
+				require.NoError(t, cmd.Flags().Set(flag, value))
+				applyListConfigDefaults(cmd, []string{})
+				assert.Empty(t, outputFormat, "display.format %s with --%s", format, flag)
+				assert.NoError(t, validateAndParseFilters(), "display.format %s with --%s", format, flag)
+				cmd.Flags().Lookup(flag).Changed = false
+			}
+		}
+	})
 }
 
 func TestFetchAllCommentsInvalidRepo(t *testing.T) {
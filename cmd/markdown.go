@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Markdown block kinds produced by parseMarkdownBlocks
+const (
+	MarkdownParagraph = "paragraph"
+	MarkdownHeading   = "heading"
+	MarkdownCode      = "code"
+	MarkdownList      = "list"
+	MarkdownQuote     = "quote"
+	MarkdownRule      = "rule"
+	MarkdownTable     = "table"
+)
+
+// markdownBlock is one block-level element of a comment body.
+// Lines holds paragraph/quote/code lines, one entry per list item, or one
+// entry per table row (header first, delimiter row dropped).
+type markdownBlock struct {
+	Kind    string
+	Level   int    // heading level
+	Lang    string // code fence info string
+	Ordered bool   // ordered list
+	Lines   []string
+
+	// Start and End are the block's source lines, End exclusive
+	Start, End int
+}
+
+var (
+	mdHeadingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRulePattern     = regexp.MustCompile(`^\s{0,3}((\*\s*){3,}|(-\s*){3,}|(_\s*){3,})$`)
+	mdListPattern     = regexp.MustCompile(`^\s{0,3}([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	mdFencePattern    = regexp.MustCompile("^\\s{0,3}(```+|~~~+)\\s*(\\S*)")
+	mdQuotePattern    = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	mdCodeSpanPattern = regexp.MustCompile("`+([^`]+)`+")
+	mdLinkPattern     = regexp.MustCompile(`(!?)\[([^\]]*)\]\(([^)\s]+)\)|(https?://[^\s<>()]+[^\s<>().,;:!?'"])`)
+	mdStrongPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdStrikePattern   = regexp.MustCompile(`~~([^~]+)~~`)
+	mdEmPattern       = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	mdTaskPattern     = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	mdTableDelimiter  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// parseMarkdownBlocks splits a comment body into block-level elements.
+// It covers the GitHub-flavored markdown that appears in review comments;
+// anything it does not recognize is treated as paragraph text.
+func parseMarkdownBlocks(src string) []markdownBlock {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var blocks []markdownBlock
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		start := len(blocks)
+		first := i
+
+		switch fence, lang := markdownFence(line); {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fence != "":
+			block := markdownBlock{Kind: MarkdownCode, Lang: lang}
+			i++
+			for i < len(lines) && !closesMarkdownFence(lines[i], fence) {
+				block.Lines = append(block.Lines, lines[i])
+				i++
+			}
+			i = min(i+1, len(lines)) // closing fence (or end of input for an unterminated block)
+			blocks = append(blocks, block)
+
+		case mdHeadingPattern.MatchString(line):
+			match := mdHeadingPattern.FindStringSubmatch(line)
+			blocks = append(blocks, markdownBlock{Kind: MarkdownHeading, Level: len(match[1]), Lines: []string{match[2]}})
+			i++
+
+		case mdRulePattern.MatchString(line):
+			blocks = append(blocks, markdownBlock{Kind: MarkdownRule})
+			i++
+
+		case mdQuotePattern.MatchString(line):
+			block := markdownBlock{Kind: MarkdownQuote}
+			for i < len(lines) && mdQuotePattern.MatchString(lines[i]) {
+				block.Lines = append(block.Lines, mdQuotePattern.FindStringSubmatch(lines[i])[1])
+				i++
+			}
+			blocks = append(blocks, block)
+
+		case isMarkdownTableStart(lines, i):
+			block := markdownBlock{Kind: MarkdownTable, Lines: []string{line}}
+			i += 2 // header and delimiter rows
+			for i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != "" {
+				block.Lines = append(block.Lines, lines[i])
+				i++
+			}
+			blocks = append(blocks, block)
+
+		case mdListPattern.MatchString(line):
+			marker := mdListPattern.FindStringSubmatch(line)[1]
+			block := markdownBlock{Kind: MarkdownList, Ordered: marker[0] >= '0' && marker[0] <= '9'}
+			for i < len(lines) {
+				if match := mdListPattern.FindStringSubmatch(lines[i]); match != nil {
+					block.Lines = append(block.Lines, match[2])
+				} else if len(block.Lines) > 0 && strings.TrimSpace(lines[i]) != "" && strings.HasPrefix(lines[i], "  ") {
+					// Indented continuation of the previous item
+					block.Lines[len(block.Lines)-1] += " " + strings.TrimSpace(lines[i])
+				} else {
+					break
+				}
+				i++
+			}
+			blocks = append(blocks, block)
+
+		default:
+			block := markdownBlock{Kind: MarkdownParagraph}
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsMarkdownBlock(lines[i]) && !isMarkdownTableStart(lines, i) {
+				block.Lines = append(block.Lines, strings.TrimSpace(lines[i]))
+				i++
+			}
+			blocks = append(blocks, block)
+		}
+
+		if len(blocks) > start {
+			blocks[start].Start, blocks[start].End = first, i
+		}
+	}
+
+	return blocks
+}
+
+// markdownFence returns the fence and info string when line opens a fenced code block
+func markdownFence(line string) (fence, lang string) {
+	loc := mdFencePattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return "", ""
+	}
+	fence = line[loc[2]:loc[3]]
+	// A backtick fence's info string can't contain backticks; the line is inline code instead
+	if fence[0] == '`' && strings.Contains(line[loc[3]:], "`") {
+		return "", ""
+	}
+	return fence, line[loc[4]:loc[5]]
+}
+
+// closesMarkdownFence reports whether line closes a code block opened by fence:
+// the same character at least as many times, indented at most three spaces
+func closesMarkdownFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	return indent <= 3 && len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// startsMarkdownBlock reports whether a line interrupts a paragraph
+func startsMarkdownBlock(line string) bool {
+	fence, _ := markdownFence(line)
+	return fence != "" || mdHeadingPattern.MatchString(line) ||
+		mdQuotePattern.MatchString(line) || mdListPattern.MatchString(line) || mdRulePattern.MatchString(line)
+}
+
+// isMarkdownTableStart reports whether lines[i] is a table header row followed by a delimiter row
+func isMarkdownTableStart(lines []string, i int) bool {
+	return i+1 < len(lines) && strings.Contains(lines[i], "|") &&
+		strings.Contains(lines[i+1], "-") && mdTableDelimiter.MatchString(lines[i+1])
+}
+
+// splitTableRow splits a table row into trimmed cells, honoring escaped pipes
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, "\\|") {
+		row = row[:len(row)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// renderMarkdownHTML renders a comment body as HTML. All text is escaped first,
+// so raw HTML in a comment is displayed rather than interpreted; only links with
+// http(s), mailto or relative targets are emitted.
+func renderMarkdownHTML(src string) string {
+	var b strings.Builder
+
+	for _, block := range parseMarkdownBlocks(src) {
+		switch block.Kind {
+		case MarkdownHeading:
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", block.Level+2, renderInlineHTML(block.Lines[0]), block.Level+2)
+		case MarkdownRule:
+			b.WriteString("<hr>\n")
+		case MarkdownCode:
+			b.WriteString(renderCodeBlockHTML(block))
+		case MarkdownQuote:
+			fmt.Fprintf(&b, "<blockquote>\n%s</blockquote>\n", renderMarkdownHTML(strings.Join(block.Lines, "\n")))
+		case MarkdownList:
+			tag := "ul"
+			if block.Ordered {
+				tag = "ol"
+			}
+			fmt.Fprintf(&b, "<%s>\n", tag)
+			for _, item := range block.Lines {
+				fmt.Fprintf(&b, "<li>%s</li>\n", renderListItemHTML(item))
+			}
+			fmt.Fprintf(&b, "</%s>\n", tag)
+		case MarkdownTable:
+			b.WriteString(renderTableHTML(block))
+		default:
+			fmt.Fprintf(&b, "<p>%s</p>\n", renderInlineHTML(strings.Join(block.Lines, "\n")))
+		}
+	}
+
+	return b.String()
+}
+
+// renderTableHTML renders a table block with its first row as the header
+func renderTableHTML(block markdownBlock) string {
+	var b strings.Builder
+	b.WriteString("<table>\n")
+	for i, row := range block.Lines {
+		cell := "td"
+		if i == 0 {
+			cell = "th"
+		}
+		b.WriteString("<tr>")
+		for _, text := range splitTableRow(row) {
+			fmt.Fprintf(&b, "<%s>%s</%s>", cell, renderInlineHTML(text), cell)
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n")
+	return b.String()
+}
+
+// renderListItemHTML renders a list item, turning task markers into checkboxes
+func renderListItemHTML(item string) string {
+	if match := mdTaskPattern.FindStringSubmatch(item); match != nil {
+		box := "☐"
+		if match[1] != " " {
+			box = "☑"
+		}
+		return box + " " + renderInlineHTML(item[len(match[0]):])
+	}
+	return renderInlineHTML(item)
+}
+
+// renderCodeBlockHTML renders fenced code; suggestion and diff blocks get per-line styling
+func renderCodeBlockHTML(block markdownBlock) string {
+	var b strings.Builder
+
+	switch block.Lang {
+	case "suggestion":
+		b.WriteString("<div class=\"suggestion\"><div class=\"suggestion-title\">Suggested change</div><pre class=\"diff\">")
+		for _, line := range block.Lines {
+			fmt.Fprintf(&b, "<span class=\"add\">+%s</span>", html.EscapeString(line))
+		}
+		b.WriteString("</pre></div>\n")
+	case "diff":
+		b.WriteString("<pre class=\"diff\">")
+		for _, line := range block.Lines {
+			fmt.Fprintf(&b, "<span class=\"%s\">%s</span>", diffLineClass(line), html.EscapeString(line))
+		}
+		b.WriteString("</pre>\n")
+	default:
+		class := ""
+		if block.Lang != "" {
+			class = fmt.Sprintf(" class=\"language-%s\"", html.EscapeString(block.Lang))
+		}
+		fmt.Fprintf(&b, "<pre><code%s>%s</code></pre>\n", class, html.EscapeString(strings.Join(block.Lines, "\n")))
+	}
+
+	return b.String()
+}
+
+// diffLineClass returns the CSS class for a unified diff line
+func diffLineClass(line string) string {
+	switch {
+	case strings.HasPrefix(line, "@@"):
+		return "hunk"
+	case strings.HasPrefix(line, "+"):
+		return "add"
+	case strings.HasPrefix(line, "-"):
+		return "del"
+	default:
+		return "ctx"
+	}
+}
+
+// renderInlineHTML renders code spans, links and emphasis within a line of text
+func renderInlineHTML(text string) string {
+	var b strings.Builder
+
+	last := 0
+	for _, loc := range mdCodeSpanPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(renderInlineTextHTML(text[last:loc[0]]))
+		fmt.Fprintf(&b, "<code>%s</code>", html.EscapeString(strings.TrimSpace(text[loc[2]:loc[3]])))
+		last = loc[1]
+	}
+	b.WriteString(renderInlineTextHTML(text[last:]))
+
+	return strings.ReplaceAll(b.String(), "\n", "<br>\n")
+}
+
+// renderInlineTextHTML renders links and emphasis in text that contains no code spans
+func renderInlineTextHTML(text string) string {
+	var b strings.Builder
+
+	last := 0
+	for _, match := range mdLinkPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(renderEmphasisHTML(html.EscapeString(text[last:match[0]])))
+		last = match[1]
+
+		var label, target string
+		if match[8] >= 0 {
+			// Bare URL
+			label, target = text[match[8]:match[9]], text[match[8]:match[9]]
+		} else {
+			label, target = text[match[4]:match[5]], text[match[6]:match[7]]
+			if label == "" {
+				label = target
+			}
+		}
+
+		if !isSafeLinkTarget(target) {
+			b.WriteString(renderEmphasisHTML(html.EscapeString(label)))
+			continue
+		}
+		fmt.Fprintf(&b, "<a href=\"%s\" rel=\"noopener noreferrer\">%s</a>",
+			html.EscapeString(target), renderEmphasisHTML(html.EscapeString(label)))
+	}
+	b.WriteString(renderEmphasisHTML(html.EscapeString(text[last:])))
+
+	return b.String()
+}
+
+// renderEmphasisHTML applies bold, italic and strikethrough to already-escaped text
+func renderEmphasisHTML(escaped string) string {
+	escaped = mdStrongPattern.ReplaceAllStringFunc(escaped, func(m string) string {
+		return "<strong>" + m[2:len(m)-2] + "</strong>"
+	})
+	escaped = mdStrikePattern.ReplaceAllString(escaped, "<del>$1</del>")
+	return mdEmPattern.ReplaceAllString(escaped, "<em>$1</em>")
+}
+
+// isSafeLinkTarget allows web, mail and relative links only
+func isSafeLinkTarget(target string) bool {
+	lower := strings.ToLower(target)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "mailto:") || strings.HasPrefix(lower, "#") ||
+		(strings.HasPrefix(lower, "/") && !strings.HasPrefix(lower, "//"))
+}
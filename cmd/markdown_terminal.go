@@ -0,0 +1,528 @@
+package cmd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+const (
+	// CommentBodyIndent is the number of spaces comment bodies are indented by
+	CommentBodyIndent = 3
+
+	// MinMarkdownWidth is the narrowest width comment bodies are wrapped to
+	MinMarkdownWidth = 20
+)
+
+// Styles used when rendering comment bodies. fatih/color checks color.NoColor
+// on every call, so --no-color, NO_COLOR and piped output all get plain text.
+var (
+	mdHeadingStyle = color.New(color.Bold, color.Underline)
+	mdStrongStyle  = color.New(color.Bold)
+	mdEmStyle      = color.New(color.Italic)
+	mdStrikeStyle  = color.New(color.CrossedOut)
+	mdCodeStyle    = color.New(color.FgCyan)
+	mdLinkStyle    = color.New(color.FgBlue, color.Underline)
+	mdQuoteStyle   = color.New(color.Faint)
+	mdGutterStyle  = color.New(color.FgHiBlack)
+	mdAddStyle     = color.New(color.FgGreen)
+	mdDelStyle     = color.New(color.FgRed)
+	mdHunkStyle    = color.New(color.FgCyan)
+
+	mdKeywordStyle = color.New(color.FgMagenta)
+	mdStringStyle  = color.New(color.FgGreen)
+	mdCommentStyle = color.New(color.FgHiBlack, color.Italic)
+	mdNumberStyle  = color.New(color.FgYellow)
+)
+
+var ansiEscapePattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// renderMarkdownTerminal renders a comment body for the terminal, wrapping text to width
+// columns. original holds the commented lines so suggestion blocks can be shown as a diff.
+func renderMarkdownTerminal(src string, width int, original []string) []string {
+	if width < MinMarkdownWidth {
+		width = MinMarkdownWidth
+	}
+
+	var out []string
+	for i, block := range parseMarkdownBlocks(src) {
+		if i > 0 {
+			out = append(out, "")
+		}
+
+		switch block.Kind {
+		case MarkdownHeading:
+			out = append(out, renderHeadingTerminal(block, width)...)
+		case MarkdownRule:
+			out = append(out, mdGutterStyle.Sprint(strings.Repeat("─", width)))
+		case MarkdownCode:
+			out = append(out, renderCodeBlockTerminal(block, original)...)
+		case MarkdownQuote:
+			bar := mdGutterStyle.Sprint("│ ")
+			for _, line := range renderMarkdownTerminal(strings.Join(block.Lines, "\n"), width-2, original) {
+				out = append(out, bar+mdQuoteStyle.Sprint(line))
+			}
+		case MarkdownList:
+			out = append(out, renderListTerminal(block, width)...)
+		case MarkdownTable:
+			out = append(out, renderTableTerminal(block, width)...)
+		default:
+			for _, line := range block.Lines {
+				out = append(out, wrapTerminalText(renderInlineTerminal(line), width)...)
+			}
+		}
+	}
+
+	return out
+}
+
+// renderHeadingTerminal styles a heading; without color, top-level headings are underlined with rules
+func renderHeadingTerminal(block markdownBlock, width int) []string {
+	lines := wrapTerminalText(mdHeadingStyle.Sprint(renderInlineTerminal(block.Lines[0])), width)
+	if !color.NoColor || block.Level > 2 {
+		return lines
+	}
+
+	rule := "="
+	if block.Level == 2 {
+		rule = "-"
+	}
+	return append(lines, strings.Repeat(rule, visibleWidth(lines[len(lines)-1])))
+}
+
+// renderListTerminal renders list items with bullets or numbers and a hanging indent
+func renderListTerminal(block markdownBlock, width int) []string {
+	var out []string
+	for i, item := range block.Lines {
+		marker := "• "
+		if block.Ordered {
+			marker = strconv.Itoa(i+1) + ". "
+		}
+		if match := mdTaskPattern.FindStringSubmatch(item); match != nil {
+			box := "☐ "
+			if match[1] != " " {
+				box = "☑ "
+			}
+			marker += box
+			item = item[len(match[0]):]
+		}
+
+		indent := strings.Repeat(" ", visibleWidth(marker))
+		for j, line := range wrapTerminalText(renderInlineTerminal(item), width-len(indent)) {
+			if j == 0 {
+				out = append(out, marker+line)
+			} else {
+				out = append(out, indent+line)
+			}
+		}
+	}
+	return out
+}
+
+// renderTableTerminal aligns table columns, truncating cells when the table is wider than width
+func renderTableTerminal(block markdownBlock, width int) []string {
+	var rows [][]string
+	columns := 0
+	for _, row := range block.Lines {
+		cells := splitTableRow(row)
+		rows = append(rows, cells)
+		if len(cells) > columns {
+			columns = len(cells)
+		}
+	}
+
+	widths := make([]int, columns)
+	for _, cells := range rows {
+		for i, cell := range cells {
+			if w := visibleWidth(renderInlineTerminal(cell)); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	// Shrink the widest columns until the table fits (3 columns of " │ " between cells)
+	available := width - 3*(columns-1)
+	for total := sumInts(widths); total > available; total-- {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= TruncationReserve+1 {
+			break
+		}
+		widths[widest]--
+	}
+
+	separator := mdGutterStyle.Sprint(" │ ")
+	var out []string
+	for r, cells := range rows {
+		parts := make([]string, columns)
+		for i := range parts {
+			text := ""
+			if i < len(cells) {
+				text = renderInlineTerminal(cells[i])
+			}
+			if visibleWidth(text) > widths[i] {
+				text = truncateDisplay(stripANSI(text), widths[i])
+			}
+			if r == 0 {
+				text = mdStrongStyle.Sprint(text)
+			}
+			parts[i] = text + strings.Repeat(" ", widths[i]-visibleWidth(text))
+		}
+		out = append(out, strings.TrimRight(strings.Join(parts, separator), " "))
+
+		if r == 0 {
+			rules := make([]string, columns)
+			for i, w := range widths {
+				rules[i] = strings.Repeat("─", w)
+			}
+			out = append(out, mdGutterStyle.Sprint(strings.Join(rules, "─┼─")))
+		}
+	}
+	return out
+}
+
+// renderCodeBlockTerminal renders fenced code behind a gutter. Suggestion blocks are shown as a
+// diff against the original lines and diff blocks are colored per line; other code is highlighted.
+func renderCodeBlockTerminal(block markdownBlock, original []string) []string {
+	gutter := mdGutterStyle.Sprint("│ ")
+	var out []string
+
+	switch block.Lang {
+	case "suggestion":
+		out = append(out, mdStrongStyle.Sprint("Suggested change:"))
+		for _, line := range original {
+			out = append(out, gutter+mdDelStyle.Sprint("- "+line))
+		}
+		for _, line := range block.Lines {
+			out = append(out, gutter+mdAddStyle.Sprint("+ "+line))
+		}
+	case "diff":
+		for _, line := range block.Lines {
+			out = append(out, gutter+diffLineStyle(line).Sprint(line))
+		}
+	default:
+		for _, line := range block.Lines {
+			out = append(out, gutter+highlightCode(line, block.Lang))
+		}
+	}
+
+	return out
+}
+
+// diffLineStyle returns the style for a unified diff line
+func diffLineStyle(line string) *color.Color {
+	switch diffLineClass(line) {
+	case "hunk":
+		return mdHunkStyle
+	case "add":
+		return mdAddStyle
+	case "del":
+		return mdDelStyle
+	default:
+		return color.New()
+	}
+}
+
+// suggestionOriginalLines returns the lines a suggestion replaces: the last lines of the
+// comment's diff hunk on the new side of the diff, one per commented line.
+func suggestionOriginalLines(diffHunk string, startLine, line int) []string {
+	if diffHunk == "" {
+		return nil
+	}
+
+	var newSide []string
+	for _, hunkLine := range strings.Split(strings.TrimRight(diffHunk, "\n"), "\n") {
+		if strings.HasPrefix(hunkLine, "@@") || strings.HasPrefix(hunkLine, "-") {
+			continue
+		}
+		if hunkLine != "" {
+			hunkLine = hunkLine[1:]
+		}
+		newSide = append(newSide, hunkLine)
+	}
+
+	count := 1
+	if startLine > 0 && startLine < line {
+		count = line - startLine + 1
+	}
+	if count > len(newSide) {
+		count = len(newSide)
+	}
+	return newSide[len(newSide)-count:]
+}
+
+// renderInlineTerminal renders code spans, links and emphasis within a line of text
+func renderInlineTerminal(text string) string {
+	var b strings.Builder
+
+	last := 0
+	for _, loc := range mdCodeSpanPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(renderInlineTextTerminal(text[last:loc[0]]))
+		code := strings.TrimSpace(text[loc[2]:loc[3]])
+		if color.NoColor {
+			// Keep the backticks so code stays recognizable without color
+			code = "`" + code + "`"
+		}
+		b.WriteString(mdCodeStyle.Sprint(code))
+		last = loc[1]
+	}
+	b.WriteString(renderInlineTextTerminal(text[last:]))
+
+	return b.String()
+}
+
+// renderInlineTextTerminal renders links and emphasis in text that contains no code spans
+func renderInlineTextTerminal(text string) string {
+	var b strings.Builder
+
+	last := 0
+	for _, match := range mdLinkPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(renderEmphasisTerminal(text[last:match[0]]))
+		last = match[1]
+
+		if match[8] >= 0 {
+			b.WriteString(mdLinkStyle.Sprint(text[match[8]:match[9]]))
+			continue
+		}
+
+		label, target := text[match[4]:match[5]], text[match[6]:match[7]]
+		if label == "" || label == target {
+			b.WriteString(mdLinkStyle.Sprint(target))
+			continue
+		}
+		b.WriteString(renderEmphasisTerminal(label) + " (" + mdLinkStyle.Sprint(target) + ")")
+	}
+	b.WriteString(renderEmphasisTerminal(text[last:]))
+
+	return b.String()
+}
+
+// renderEmphasisTerminal applies bold, italic and strikethrough, dropping the markers
+func renderEmphasisTerminal(text string) string {
+	text = mdStrongPattern.ReplaceAllStringFunc(text, func(m string) string {
+		return mdStrongStyle.Sprint(m[2 : len(m)-2])
+	})
+	text = mdStrikePattern.ReplaceAllStringFunc(text, func(m string) string {
+		return mdStrikeStyle.Sprint(m[2 : len(m)-2])
+	})
+	return mdEmPattern.ReplaceAllStringFunc(text, func(m string) string {
+		return mdEmStyle.Sprint(m[1 : len(m)-1])
+	})
+}
+
+// wrapTerminalText word-wraps each line of styled text to width visible columns.
+// Words longer than width (such as URLs) are kept whole on their own line.
+func wrapTerminalText(text string, width int) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		current, currentWidth := "", 0
+		for _, word := range strings.Fields(line) {
+			wordWidth := visibleWidth(word)
+			if currentWidth > 0 && currentWidth+1+wordWidth > width {
+				out = append(out, current)
+				current, currentWidth = "", 0
+			}
+			if currentWidth > 0 {
+				current += " "
+				currentWidth++
+			}
+			current += word
+			currentWidth += wordWidth
+		}
+		out = append(out, current)
+	}
+	return out
+}
+
+// visibleWidth counts the runes of s that are not part of ANSI escape sequences
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(stripANSI(s))
+}
+
+// stripANSI removes ANSI color escape sequences
+func stripANSI(s string) string {
+	return ansiEscapePattern.ReplaceAllString(s, "")
+}
+
+func sumInts(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// syntaxRules describes just enough of a language to highlight it line by line
+type syntaxRules struct {
+	keywords     map[string]bool
+	lineComments []string
+	quotes       string
+}
+
+func keywordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+var (
+	cLikeRules = syntaxRules{
+		keywords: keywordSet(`auto break case char class const continue default do double else enum extern
+			float for goto if inline int long namespace new private protected public return short signed
+			sizeof static struct switch template this throw try typedef union unsigned virtual void while
+			bool true false nullptr NULL final abstract extends implements import interface package super
+			synchronized throws boolean byte instanceof null catch finally`),
+		lineComments: []string{"//"},
+		quotes:       `"'`,
+	}
+	scriptRules = syntaxRules{
+		keywords: keywordSet(`break case catch class const continue debugger default delete do else export
+			extends finally for function if import in instanceof let new return super switch this throw try
+			typeof var void while with yield async await of true false null undefined interface type enum
+			implements private protected public readonly as from static`),
+		lineComments: []string{"//"},
+		quotes:       "\"'`",
+	}
+	hashCommentRules = syntaxRules{
+		keywords: keywordSet(`and as assert async await break class continue def del elif else except
+			finally for from global if import in is lambda nonlocal not or pass raise return try while
+			with yield True False None self begin end module require then unless until when do nil`),
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+	shellRules = syntaxRules{
+		keywords: keywordSet(`if then else elif fi case esac for while until do done in function return
+			export local readonly set unset echo exit source`),
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+)
+
+// syntaxLanguages maps code fence languages to their highlighting rules
+var syntaxLanguages = map[string]syntaxRules{
+	"go": {
+		keywords: keywordSet(`break case chan const continue default defer else fallthrough for func go
+			goto if import interface map package range return select struct switch type var true false
+			nil iota`),
+		lineComments: []string{"//"},
+		quotes:       "\"'`",
+	},
+	"rust": {
+		keywords: keywordSet(`as async await break const continue crate dyn else enum extern false fn for
+			if impl in let loop match mod move mut pub ref return self Self static struct super trait
+			true type unsafe use where while Some None Ok Err`),
+		lineComments: []string{"//"},
+		quotes:       `"`,
+	},
+	"sql": {
+		keywords: keywordSet(`select from where and or not insert into values update set delete create
+			table drop alter join left right inner outer on group by order having limit as null is in
+			SELECT FROM WHERE AND OR NOT INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE DROP ALTER
+			JOIN LEFT RIGHT INNER OUTER ON GROUP BY ORDER HAVING LIMIT AS NULL IS IN`),
+		lineComments: []string{"--"},
+		quotes:       `'"`,
+	},
+	"yaml":       {keywords: keywordSet("true false null yes no"), lineComments: []string{"#"}, quotes: `"'`},
+	"json":       {keywords: keywordSet("true false null"), quotes: `"`},
+	"c":          cLikeRules,
+	"javascript": scriptRules,
+	"python":     hashCommentRules,
+	"shell":      shellRules,
+}
+
+// syntaxAliases maps alternative fence names to a language in syntaxLanguages
+var syntaxAliases = map[string]string{
+	"golang": "go", "rs": "rust", "yml": "yaml", "jsonc": "json",
+	"cpp": "c", "c++": "c", "h": "c", "hpp": "c", "java": "c", "kotlin": "c", "kt": "c", "cs": "c", "csharp": "c", "swift": "c",
+	"js": "javascript", "jsx": "javascript", "ts": "javascript", "tsx": "javascript", "typescript": "javascript", "mjs": "javascript",
+	"py": "python", "rb": "python", "ruby": "python",
+	"sh": "shell", "bash": "shell", "zsh": "shell", "console": "shell",
+}
+
+// highlightCode colors keywords, strings, numbers and comments in one line of code.
+// Unknown languages and uncolored output are returned unchanged.
+func highlightCode(line, lang string) string {
+	lang = strings.ToLower(lang)
+	if alias, ok := syntaxAliases[lang]; ok {
+		lang = alias
+	}
+	rules, ok := syntaxLanguages[lang]
+	if !ok || color.NoColor {
+		return line
+	}
+
+	var b strings.Builder
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		rest := string(runes[i:])
+
+		switch {
+		case hasAnyPrefix(rest, rules.lineComments):
+			b.WriteString(mdCommentStyle.Sprint(rest))
+			i = len(runes)
+
+		case strings.ContainsRune(rules.quotes, r):
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				end = len(runes) - 1
+			}
+			b.WriteString(mdStringStyle.Sprint(string(runes[i : end+1])))
+			i = end + 1
+
+		case unicode.IsDigit(r) && (i == 0 || !isIdentifierRune(runes[i-1])):
+			end := i
+			for end < len(runes) && (isIdentifierRune(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			b.WriteString(mdNumberStyle.Sprint(string(runes[i:end])))
+			i = end
+
+		case isIdentifierRune(r):
+			end := i
+			for end < len(runes) && isIdentifierRune(runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			if rules.keywords[word] {
+				word = mdKeywordStyle.Sprint(word)
+			}
+			b.WriteString(word)
+			i = end
+
+		default:
+			b.WriteRune(r)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
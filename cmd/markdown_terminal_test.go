@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withNoColor runs fn with color output forced on or off
+func withNoColor(t *testing.T, disabled bool, fn func()) {
+	t.Helper()
+	original := color.NoColor
+	color.NoColor = disabled
+	defer func() { color.NoColor = original }()
+	fn()
+}
+
+func TestRenderMarkdownTerminalPlain(t *testing.T) {
+	withNoColor(t, true, func() {
+		tests := []struct {
+			name     string
+			input    string
+			expected []string
+		}{
+			{"plain text is unchanged", "Looks good to me", []string{"Looks good to me"}},
+			{"emphasis markers are dropped", "**bold** and *it*", []string{"bold and it"}},
+			{"code spans keep backticks", "call `Close()` here", []string{"call `Close()` here"}},
+			{"links show their target", "see [docs](https://example.com)", []string{"see docs (https://example.com)"}},
+			{"heading is underlined", "# Summary", []string{"Summary", "======="}},
+			{"lists", "- one\n- [x] done\n\n1. first\n2. second", []string{"• one", "• ☑ done", "", "1. first", "2. second"}},
+			{"quote", "> quoted", []string{"│ quoted"}},
+			{"code block", "```go\nreturn nil\n```", []string{"│ return nil"}},
+			{"line breaks are kept", "a\nb", []string{"a", "b"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, renderMarkdownTerminal(tt.input, 80, nil))
+			})
+		}
+	})
+}
+
+func TestRenderMarkdownTerminalWrapsToWidth(t *testing.T) {
+	withNoColor(t, true, func() {
+		body := "- " + strings.Repeat("word ", 12) + "\n\n" + strings.Repeat("text ", 10) + "https://example.com/a/very/long/path/that/cannot/be/split"
+		lines := renderMarkdownTerminal(body, 24, nil)
+
+		for _, line := range lines {
+			if !strings.Contains(line, "https://") {
+				assert.LessOrEqual(t, visibleWidth(line), 24, line)
+			}
+		}
+		assert.Equal(t, "• word word word word", lines[0])
+		assert.Equal(t, "  word word word word", lines[1], "list continuation lines are indented")
+		assert.Equal(t, "https://example.com/a/very/long/path/that/cannot/be/split", lines[len(lines)-1])
+	})
+}
+
+func TestRenderMarkdownTerminalSuggestion(t *testing.T) {
+	withNoColor(t, true, func() {
+		hunk := "@@ -10,4 +10,4 @@ func load() {\n \tdata, err := read()\n-\tif err != nil { panic(err) }\n+\tif err != nil {\n+\t\tpanic(err)"
+		original := suggestionOriginalLines(hunk, 0, 12)
+		assert.Equal(t, []string{"\t\tpanic(err)"}, original)
+
+		lines := renderMarkdownTerminal("Return instead:\n```suggestion\n\t\treturn err\n```", 80, original)
+		assert.Equal(t, []string{
+			"Return instead:",
+			"",
+			"Suggested change:",
+			"│ - \t\tpanic(err)",
+			"│ + \t\treturn err",
+		}, lines)
+	})
+
+	assert.Nil(t, suggestionOriginalLines("", 0, 1))
+	assert.Equal(t, []string{"b", "c"}, suggestionOriginalLines("@@ -1,3 +1,3 @@\n a\n b\n c", 2, 3))
+}
+
+func TestRenderMarkdownTerminalTable(t *testing.T) {
+	withNoColor(t, true, func() {
+		lines := renderMarkdownTerminal("| Name | Status |\n|------|:------:|\n| api | `ok` |\n| worker \\| cron | failing |", 80, nil)
+		assert.Equal(t, []string{
+			"Name          │ Status",
+			"──────────────┼────────",
+			"api           │ `ok`",
+			"worker | cron │ failing",
+		}, lines)
+
+		narrow := renderMarkdownTerminal("| a | b |\n|---|---|\n| "+strings.Repeat("x", 40)+" | "+strings.Repeat("y", 40)+" |", 30, nil)
+		require.Len(t, narrow, 3)
+		assert.LessOrEqual(t, visibleWidth(narrow[2]), 30)
+		assert.Contains(t, narrow[2], TruncationSuffix)
+	})
+}
+
+func TestRenderMarkdownTerminalColors(t *testing.T) {
+	withNoColor(t, false, func() {
+		lines := renderMarkdownTerminal("**bold** `code`\n\n```go\nfunc main() { return \"x\" } // done\n```\n\n```suggestion\nnew\n```", 80, []string{"old"})
+		require.Len(t, lines, 7)
+
+		assert.Equal(t, "bold code", stripANSI(lines[0]), "markers are replaced by styles")
+		assert.Contains(t, lines[0], "\x1b[1mbold")
+		assert.Contains(t, lines[2], mdKeywordStyle.Sprint("func"))
+		assert.Contains(t, lines[2], mdStringStyle.Sprint(`"x"`))
+		assert.Contains(t, lines[2], mdCommentStyle.Sprint("// done"))
+		assert.Contains(t, lines[5], mdDelStyle.Sprint("- old"))
+		assert.Contains(t, lines[6], mdAddStyle.Sprint("+ new"))
+	})
+}
+
+func TestHighlightCode(t *testing.T) {
+	withNoColor(t, false, func() {
+		assert.Equal(t, "plain text", highlightCode("plain text", "unknown-lang"))
+		assert.Equal(t, mdKeywordStyle.Sprint("def")+" f(): "+mdKeywordStyle.Sprint("return")+" "+mdNumberStyle.Sprint("42")+"  "+mdCommentStyle.Sprint("# answer"),
+			highlightCode("def f(): return 42  # answer", "py"))
+		assert.Equal(t, "x1 = "+mdStringStyle.Sprint(`'unterminated`), highlightCode("x1 = 'unterminated", "ts"))
+	})
+
+	withNoColor(t, true, func() {
+		assert.Equal(t, "func main() {}", highlightCode("func main() {}", "go"))
+	})
+}
+
+func TestDisplayCommentRendersMarkdown(t *testing.T) {
+	original := rawBodies
+	defer func() { rawBodies = original }()
+
+	comment := Comment{
+		ID:        1,
+		Author:    "reviewer",
+		Body:      "**Nit:** rename\n```suggestion\nnewName := 1\n```",
+		Type:      "review",
+		Path:      "main.go",
+		Line:      3,
+		DiffHunk:  "@@ -1,3 +1,3 @@\n package main\n \n-x := 1\n+oldName := 1",
+		CreatedAt: testTime(),
+	}
+
+	withNoColor(t, true, func() {
+		rawBodies = false
+		rendered := captureOutput(func() { displayComment(comment) })
+		assert.Contains(t, rendered, "   Nit: rename\n")
+		assert.Contains(t, rendered, "   │ - oldName := 1\n")
+		assert.Contains(t, rendered, "   │ + newName := 1\n")
+
+		rawBodies = true
+		raw := captureOutput(func() { displayComment(comment) })
+		assert.Contains(t, raw, "   **Nit:** rename\n   ```suggestion\n")
+	})
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarkdownBlocks(t *testing.T) {
+	src := strings.Join([]string{
+		"## Summary",
+		"First line",
+		"second line",
+		"",
+		"- one",
+		"  continued",
+		"- two",
+		"",
+		"1. first",
+		"2. second",
+		"> quoted",
+		"> text",
+		"---",
+		"```go",
+		"func main() {}",
+		"```",
+		"```suggestion",
+		"unterminated",
+	}, "\n")
+
+	blocks := parseMarkdownBlocks(src)
+	require.Len(t, blocks, 8)
+
+	assert.Equal(t, markdownBlock{Kind: MarkdownHeading, Level: 2, Lines: []string{"Summary"}, Start: 0, End: 1}, blocks[0])
+	assert.Equal(t, []string{"First line", "second line"}, blocks[1].Lines)
+	assert.Equal(t, [2]int{1, 3}, [2]int{blocks[1].Start, blocks[1].End})
+	assert.Equal(t, markdownBlock{Kind: MarkdownList, Lines: []string{"one continued", "two"}, Start: 4, End: 7}, blocks[2])
+	assert.True(t, blocks[3].Ordered)
+	assert.Equal(t, markdownBlock{Kind: MarkdownQuote, Lines: []string{"quoted", "text"}, Start: 10, End: 12}, blocks[4])
+	assert.Equal(t, MarkdownRule, blocks[5].Kind)
+	assert.Equal(t, markdownBlock{Kind: MarkdownCode, Lang: "go", Lines: []string{"func main() {}"}, Start: 13, End: 16}, blocks[6])
+	assert.Equal(t, markdownBlock{Kind: MarkdownCode, Lang: "suggestion", Lines: []string{"unterminated"}, Start: 16, End: 18}, blocks[7])
+}
+
+func TestParseMarkdownFences(t *testing.T) {
+	// Closing fences use the same character, at least as many times, and nothing else
+	blocks := parseMarkdownBlocks("````\n```\n~~~~\n````` \nafter")
+	require.Len(t, blocks, 2)
+	assert.Equal(t, []string{"```", "~~~~"}, blocks[0].Lines)
+	assert.Equal(t, []string{"after"}, blocks[1].Lines)
+
+	// Backticks after a backtick fence make the line inline code, not a fence
+	blocks = parseMarkdownBlocks("```a`b\n<b>text</b>")
+	require.Len(t, blocks, 1)
+	assert.Equal(t, MarkdownParagraph, blocks[0].Kind)
+
+	blocks = parseMarkdownBlocks("~~~ a`b\ncode")
+	require.Len(t, blocks, 1)
+	assert.Equal(t, MarkdownCode, blocks[0].Kind)
+}
+
+func TestRenderMarkdownHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"paragraph with emphasis", "**bold** and *it* and ~~gone~~", "<p><strong>bold</strong> and <em>it</em> and <del>gone</del></p>\n"},
+		{"code span keeps markup literal", "use `**x** <b>`", "<p>use <code>**x** &lt;b&gt;</code></p>\n"},
+		{"heading is demoted", "# Title", "<h3>Title</h3>\n"},
+		{"link", "[docs](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2" rel="noopener noreferrer">docs</a></p>` + "\n"},
+		{"bare url", "see https://example.com.", `<p>see <a href="https://example.com" rel="noopener noreferrer">https://example.com</a>.</p>` + "\n"},
+		{"task list", "- [x] done\n- [ ] todo", "<ul>\n<li>☑ done</li>\n<li>☐ todo</li>\n</ul>\n"},
+		{"line breaks", "a\nb", "<p>a<br>\nb</p>\n"},
+		{"code block", "```js\nif (a < b) {}\n```", `<pre><code class="language-js">if (a &lt; b) {}</code></pre>` + "\n"},
+		{"suggestion", "```suggestion\nfixed()\n```", `<div class="suggestion"><div class="suggestion-title">Suggested change</div><pre class="diff"><span class="add">+fixed()</span></pre></div>` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, renderMarkdownHTML(tt.input))
+		})
+	}
+}
+
+func TestRenderMarkdownHTMLSanitizes(t *testing.T) {
+	inputs := []string{
+		`<script>alert(1)</script>`,
+		`<img src=x onerror="alert(1)">`,
+		`[click](javascript:alert(1))`,
+		`[x](data:text/html;base64,PHNjcmlwdD4=)`,
+		"```html\n<iframe src=evil></iframe>\n```",
+		`[a](https://ok.example/"onmouseover="alert(1))`,
+	}
+
+	for _, input := range inputs {
+		output := renderMarkdownHTML(input)
+		assert.NotContains(t, output, "<script", input)
+		assert.NotContains(t, output, "<img", input)
+		assert.NotContains(t, output, "<iframe", input)
+		assert.NotContains(t, output, `href="javascript`, input)
+		assert.NotContains(t, output, `href="data`, input)
+		assert.NotContains(t, output, `"onmouseover`, input)
+	}
+}
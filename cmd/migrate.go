@@ -0,0 +1,570 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// How comments whose line no longer exists on the target PR are handled
+const (
+	MigrateMissingFile  = "file"
+	MigrateMissingIssue = "issue"
+	MigrateMissingDrop  = "drop"
+)
+
+// Kinds of migration actions
+const (
+	migrationLine    = "line"
+	migrationFile    = "file"
+	migrationIssue   = "issue"
+	migrationDrop    = "drop"
+	migrationExists  = "exists"
+	migrationInvalid = "invalid"
+)
+
+var (
+	migrateFrom         string
+	migrateTo           string
+	migratePathMap      string
+	migrateMissing      string
+	migrateSkipResolved bool
+
+	// Client for dependency injection (tests can override)
+	migrateClient github.GitHubAPI
+)
+
+var migrateMarkerPattern = regexp.MustCompile(`<!-- gh-comment-migrated-from: (\S+) -->`)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate --from <owner/repo#pr> --to <owner/repo#pr>",
+	Short: "Copy review discussion from one PR to another",
+	Long: heredoc.Doc(`
+		Copy comments and review threads from one pull request to another,
+		for example when a large PR is split or code moves to a new repository.
+
+		Each thread becomes one comment on the target PR. The original author,
+		timestamp and link are quoted at the top, followed by every reply.
+		@mentions in the copy don't notify anyone again, and the copies are
+		scanned for secrets and checked against the repository policy like any
+		other comment before anything is posted.
+
+		Review threads are placed on the same line of the (remapped) file when
+		that line is part of the target PR's diff. Otherwise --missing decides:
+		- file:  comment on the whole file, or a PR comment if the file is not
+		         in the target diff (default)
+		- issue: always fall back to a general PR comment
+		- drop:  skip the thread
+
+		Path mapping (--path-map) is a YAML or JSON file mapping source paths or
+		directory prefixes to target paths; the longest match wins:
+
+		  src/legacy/: pkg/core/
+		  cmd/old_main.go: cmd/main.go
+
+		Migration is idempotent: every migrated comment carries a hidden marker,
+		and threads already present on the target are skipped on later runs.
+		Use --dry-run to preview the plan.
+	`),
+	Example: heredoc.Doc(`
+		# Preview copying the discussion to a split-off PR
+		$ gh comment migrate --from owner/repo#123 --to owner/repo#456 --dry-run
+
+		# Move discussion to another repository with renamed paths
+		$ gh comment migrate --from owner/repo#123 --to owner/repo2#7 --path-map paths.yaml
+
+		# Same repository (current one), dropping threads whose lines are gone
+		$ gh comment migrate --from 123 --to 456 --missing drop --skip-resolved
+	`),
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", "Source PR (owner/repo#123, or #123 / 123 for the current repository)")
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "Target PR (owner/repo#456, or #456 / 456 for the current repository)")
+	migrateCmd.Flags().StringVar(&migratePathMap, "path-map", "", "YAML/JSON file mapping source paths or directories to target paths")
+	migrateCmd.Flags().StringVar(&migrateMissing, "missing", MigrateMissingFile, "Threads whose line is not in the target diff: file, issue, drop")
+	migrateCmd.Flags().BoolVar(&migrateSkipResolved, "skip-resolved", false, "Do not migrate resolved review threads")
+	_ = migrateCmd.MarkFlagRequired("from")
+	_ = migrateCmd.MarkFlagRequired("to")
+}
+
+// prRef identifies a pull request in a repository
+type prRef struct {
+	Owner string
+	Name  string
+	PR    int
+}
+
+// String formats the reference as owner/repo#123
+func (r prRef) String() string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Name, r.PR)
+}
+
+// parsePRRef parses owner/repo#123, #123 or 123 (the latter two use defaultRepo)
+func parsePRRef(s, defaultRepo string) (prRef, error) {
+	repository, number := defaultRepo, strings.TrimPrefix(s, "#")
+	if i := strings.LastIndex(s, "#"); i > 0 {
+		repository, number = s[:i], s[i+1:]
+	}
+
+	pr, err := strconv.Atoi(number)
+	if err != nil || pr <= 0 {
+		return prRef{}, formatValidationError("PR reference", s, "must be owner/repo#123, #123 or 123")
+	}
+	if err := validateRepositoryName(repository); err != nil {
+		return prRef{}, err
+	}
+
+	parts := strings.Split(repository, "/")
+	return prRef{Owner: parts[0], Name: parts[1], PR: pr}, nil
+}
+
+// pathMapRule maps a source path or directory prefix to a target path
+type pathMapRule struct {
+	From string
+	To   string
+}
+
+// pathMapping rewrites source paths for the target PR
+type pathMapping []pathMapRule
+
+// loadPathMap reads a YAML or JSON object of source → target paths
+func loadPathMap(filename string) (pathMapping, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read path map %s: %w", filename, err)
+	}
+
+	var raw map[string]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse path map %s: %w", filename, err)
+	}
+
+	var mapping pathMapping
+	for from, to := range raw {
+		if from == "" {
+			return nil, fmt.Errorf("path map %s: source path cannot be empty", filename)
+		}
+		if to != "" {
+			if err := validateFilePath(to); err != nil {
+				return nil, fmt.Errorf("path map %s: target for %q: %w", filename, from, err)
+			}
+		}
+		mapping = append(mapping, pathMapRule{From: from, To: to})
+	}
+
+	// Longest prefix first
+	sort.Slice(mapping, func(i, j int) bool {
+		if len(mapping[i].From) != len(mapping[j].From) {
+			return len(mapping[i].From) > len(mapping[j].From)
+		}
+		return mapping[i].From < mapping[j].From
+	})
+
+	return mapping, nil
+}
+
+// Map returns the target path for a source path. A rule matches the exact
+// path or, when it ends in "/", any path below that directory.
+func (m pathMapping) Map(path string) string {
+	for _, rule := range m {
+		if path == rule.From {
+			return rule.To
+		}
+		if strings.HasSuffix(rule.From, "/") && strings.HasPrefix(path, rule.From) {
+			return rule.To + strings.TrimPrefix(path, rule.From)
+		}
+	}
+	return path
+}
+
+// migrationItem is one planned action on the target PR
+type migrationItem struct {
+	SourceID   int
+	Kind       string
+	SourcePath string
+	Path       string
+	Line       int
+	Comments   int
+	Body       string
+	Reason     string
+}
+
+// migrationPlanOptions controls how a migration plan is built
+type migrationPlanOptions struct {
+	Source       prRef
+	PathMap      pathMapping
+	Missing      string
+	SkipResolved bool
+	TargetDiff   *github.PullRequestDiff
+	Existing     map[string]bool // markers already on the target PR
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	// Initialize client if not set (production use)
+	if migrateClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		migrateClient = client
+	}
+
+	if !containsString([]string{MigrateMissingFile, MigrateMissingIssue, MigrateMissingDrop}, migrateMissing) {
+		return formatValidationError("missing", migrateMissing, "must be one of: file, issue, drop")
+	}
+
+	defaultRepo := repo
+	if !strings.Contains(migrateFrom, "/") || !strings.Contains(migrateTo, "/") {
+		current, err := getCurrentRepo()
+		if err != nil {
+			return err
+		}
+		defaultRepo = current
+	}
+
+	source, err := parsePRRef(migrateFrom, defaultRepo)
+	if err != nil {
+		return err
+	}
+	target, err := parsePRRef(migrateTo, defaultRepo)
+	if err != nil {
+		return err
+	}
+	if source == target {
+		return fmt.Errorf("source and target are the same PR (%s)", source)
+	}
+
+	opts := migrationPlanOptions{Source: source, Missing: migrateMissing, SkipResolved: migrateSkipResolved}
+	if migratePathMap != "" {
+		if opts.PathMap, err = loadPathMap(migratePathMap); err != nil {
+			return err
+		}
+	}
+
+	if verbose {
+		fmt.Printf("Source: %s\n", source)
+		fmt.Printf("Target: %s\n", target)
+		fmt.Printf("Missing lines: %s\n\n", migrateMissing)
+	}
+
+	// Source discussion
+	sourceRepo := source.Owner + "/" + source.Name
+	comments, err := fetchAllComments(migrateClient, sourceRepo, source.PR)
+	if err != nil {
+		return fmt.Errorf("failed to fetch comments from %s: %w", source, err)
+	}
+	if migrateSkipResolved {
+		threads, err := migrateClient.ListReviewThreads(source.Owner, source.Name, source.PR)
+		if err != nil {
+			return fmt.Errorf("failed to fetch review threads from %s: %w", source, err)
+		}
+		applyThreadState(comments, threads)
+	}
+
+	// Target state: diff lines and previously migrated comments
+	opts.TargetDiff, err = migrateClient.FetchPRDiff(target.Owner, target.Name, target.PR)
+	if err != nil {
+		return formatActionableError("target PR diff fetch", err)
+	}
+	opts.Existing, err = findMigrationMarkers(migrateClient, target)
+	if err != nil {
+		return err
+	}
+
+	plan := planMigration(comments, opts)
+
+	// Check for secrets, personal data and the repository policy before anything is sent
+	if err := checkMigrationBodies(plan); err != nil {
+		return err
+	}
+
+	if dryRun {
+		displayMigrationPlan(plan, source, target)
+		return nil
+	}
+
+	return executeMigration(migrateClient, target, plan)
+}
+
+// findMigrationMarkers collects the markers of comments already migrated to the target PR
+func findMigrationMarkers(client github.GitHubAPI, target prRef) (map[string]bool, error) {
+	issueComments, err := client.ListIssueComments(target.Owner, target.Name, target.PR)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments from %s: %w", target, err)
+	}
+	reviewComments, err := client.ListReviewComments(target.Owner, target.Name, target.PR)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review comments from %s: %w", target, err)
+	}
+
+	markers := make(map[string]bool)
+	for _, comment := range append(issueComments, reviewComments...) {
+		for _, match := range migrateMarkerPattern.FindAllStringSubmatch(comment.Body, -1) {
+			markers[match[1]] = true
+		}
+	}
+	return markers, nil
+}
+
+// migrationKey identifies a source comment across runs
+func migrationKey(source prRef, commentID int) string {
+	return fmt.Sprintf("%s/%d", source, commentID)
+}
+
+// planMigration decides where each source thread and PR comment goes on the target
+func planMigration(comments []Comment, opts migrationPlanOptions) []migrationItem {
+	diffLines := make(map[string]map[int]bool)
+	if opts.TargetDiff != nil {
+		for _, file := range opts.TargetDiff.Files {
+			diffLines[file.Filename] = file.Lines
+		}
+	}
+
+	var plan []migrationItem
+	for _, thread := range groupCommentThreads(comments) {
+		root := thread[0]
+		item := migrationItem{SourceID: root.ID, SourcePath: root.Path, Comments: len(thread)}
+
+		if opts.Existing[migrationKey(opts.Source, root.ID)] {
+			item.Kind = migrationExists
+			plan = append(plan, item)
+			continue
+		}
+
+		if root.Type == "issue" {
+			item.Kind = migrationIssue
+		} else {
+			if opts.SkipResolved && root.Resolved {
+				continue
+			}
+
+			item.Path = opts.PathMap.Map(root.Path)
+			lines, inDiff := diffLines[item.Path]
+			switch {
+			case item.Path == "":
+				item.Kind, item.Reason = migrationDrop, "path mapped to nothing"
+			case inDiff && root.Line > 0 && lines[root.Line]:
+				item.Kind, item.Line = migrationLine, root.Line
+			case opts.Missing == MigrateMissingDrop:
+				item.Kind, item.Reason = migrationDrop, "line not in target diff"
+			case opts.Missing == MigrateMissingFile && inDiff:
+				item.Kind, item.Reason = migrationFile, "line not in target diff"
+			default:
+				item.Kind, item.Reason = migrationIssue, "line not in target diff"
+				if !inDiff {
+					item.Reason = "file not in target diff"
+				}
+			}
+		}
+
+		if item.Kind != migrationDrop {
+			item.Body = formatMigratedBody(opts.Source, thread)
+			if err := validateCommentBody(item.Body); err != nil {
+				item.Kind, item.Reason = migrationInvalid, err.Error()
+			}
+		}
+
+		plan = append(plan, item)
+	}
+
+	return plan
+}
+
+// groupCommentThreads returns PR comments as single-comment threads and review
+// comments grouped with their replies, each thread in creation order
+func groupCommentThreads(comments []Comment) [][]Comment {
+	sorted := append([]Comment(nil), comments...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	index := make(map[int]int)
+	var threads [][]Comment
+	for _, comment := range sorted {
+		if comment.Type == "review" && comment.InReplyToID != 0 {
+			if i, ok := index[comment.InReplyToID]; ok {
+				threads[i] = append(threads[i], comment)
+				continue
+			}
+		}
+		index[comment.ID] = len(threads)
+		threads = append(threads, []Comment{comment})
+	}
+	return threads
+}
+
+// checkMigrationBodies scans the bodies about to be posted, redacting findings in place,
+// and checks them against the repository policy
+func checkMigrationBodies(plan []migrationItem) error {
+	var comments []policyComment
+	for i := range plan {
+		item := &plan[i]
+		if item.Kind != migrationLine && item.Kind != migrationFile && item.Kind != migrationIssue {
+			continue
+		}
+
+		body, err := scanCommentBody(fmt.Sprintf("migrated thread #%d", item.SourceID), item.Body)
+		if err != nil {
+			return err
+		}
+		item.Body = body
+
+		comment := policyComment{Body: item.Body}
+		if item.Kind != migrationIssue {
+			comment.Path, comment.Line = item.Path, item.Line
+		}
+		comments = append(comments, comment)
+	}
+	return enforcePolicy(policySubject{Comments: comments})
+}
+
+// formatMigratedBody quotes a thread with its authors, timestamps and a link to the original.
+// Mentions are neutralized so reposting doesn't notify everyone in the thread again.
+func formatMigratedBody(source prRef, thread []Comment) string {
+	root := thread[0]
+
+	var b strings.Builder
+	location := ""
+	url := fmt.Sprintf("https://github.com/%s/%s/pull/%d#issuecomment-%d", source.Owner, source.Name, source.PR, root.ID)
+	if root.Type == "review" {
+		url = fmt.Sprintf("https://github.com/%s/%s/pull/%d#discussion_r%d", source.Owner, source.Name, source.PR, root.ID)
+		if root.Line > 0 {
+			location = fmt.Sprintf(" on `%s:%d`", root.Path, root.Line)
+		} else {
+			location = fmt.Sprintf(" on `%s`", root.Path)
+		}
+	}
+
+	for i, comment := range thread {
+		if i == 0 {
+			fmt.Fprintf(&b, "> **@%s** commented%s in [%s](%s) · %s\n", comment.Author, location, source, url,
+				comment.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"))
+		} else {
+			fmt.Fprintf(&b, ">\n> **@%s** replied · %s\n", comment.Author, comment.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"))
+		}
+		b.WriteString(">\n")
+		for _, line := range strings.Split(strings.TrimRight(comment.Body, "\n"), "\n") {
+			b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+	}
+
+	quoted := neutralizeMentions(b.String())
+	return quoted + fmt.Sprintf("\n<!-- gh-comment-migrated-from: %s -->", migrationKey(source, root.ID))
+}
+
+// describeMigrationItem renders one plan entry for display
+func describeMigrationItem(item migrationItem) string {
+	var target string
+	switch item.Kind {
+	case migrationLine:
+		target = fmt.Sprintf("line comment on %s:%d", item.Path, item.Line)
+	case migrationFile:
+		target = fmt.Sprintf("file comment on %s", item.Path)
+	case migrationIssue:
+		target = "PR comment"
+	case migrationDrop:
+		target = "dropped"
+	case migrationExists:
+		target = "already migrated"
+	case migrationInvalid:
+		target = "skipped (invalid body)"
+	}
+
+	source := fmt.Sprintf("#%d", item.SourceID)
+	if item.SourcePath != "" {
+		source = fmt.Sprintf("#%d (%s)", item.SourceID, item.SourcePath)
+	}
+	description := fmt.Sprintf("%s, %d comment(s) → %s", source, item.Comments, target)
+	if item.Reason != "" {
+		description += fmt.Sprintf(" [%s]", item.Reason)
+	}
+	return description
+}
+
+// displayMigrationPlan prints what a migration would do
+func displayMigrationPlan(plan []migrationItem, source, target prRef) {
+	fmt.Printf("Would migrate %d thread(s) from %s to %s:\n", countMigrationWrites(plan), source, target)
+	for i, item := range plan {
+		fmt.Printf("  %d. %s\n", i+1, describeMigrationItem(item))
+	}
+}
+
+// countMigrationWrites counts the plan entries that create a comment
+func countMigrationWrites(plan []migrationItem) int {
+	count := 0
+	for _, item := range plan {
+		if item.Kind == migrationLine || item.Kind == migrationFile || item.Kind == migrationIssue {
+			count++
+		}
+	}
+	return count
+}
+
+// executeMigration creates the planned comments, continuing past individual failures
+func executeMigration(client github.GitHubAPI, target prRef, plan []migrationItem) error {
+	var commitID string
+	for _, item := range plan {
+		if item.Kind == migrationLine || item.Kind == migrationFile {
+			details, err := client.GetPRDetails(target.Owner, target.Name, target.PR)
+			if err != nil {
+				return formatActionableError("target PR details fetch", err)
+			}
+			if head, ok := details["head"].(map[string]interface{}); ok {
+				commitID, _ = head["sha"].(string)
+			}
+			if commitID == "" {
+				return fmt.Errorf("could not determine head commit of %s", target)
+			}
+			break
+		}
+	}
+
+	migrated, skipped, failed := 0, 0, 0
+	for _, item := range plan {
+		var err error
+		switch item.Kind {
+		case migrationLine:
+			err = client.AddReviewComment(target.Owner, target.Name, target.PR, github.ReviewCommentInput{
+				Body: item.Body, Path: item.Path, Line: item.Line, Side: "RIGHT", CommitID: commitID,
+			})
+		case migrationFile:
+			err = client.AddReviewComment(target.Owner, target.Name, target.PR, github.ReviewCommentInput{
+				Body: item.Body, Path: item.Path, SubjectType: "file", CommitID: commitID,
+			})
+		case migrationIssue:
+			_, err = client.CreateIssueComment(target.Owner, target.Name, target.PR, item.Body)
+		default:
+			skipped++
+			if verbose || item.Kind == migrationInvalid {
+				fmt.Printf("⏭️  %s\n", describeMigrationItem(item))
+			}
+			continue
+		}
+
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", describeMigrationItem(item), err)
+			continue
+		}
+		migrated++
+		fmt.Printf("✅ %s\n", describeMigrationItem(item))
+	}
+
+	fmt.Printf("%s\n", ColorizeSuccess(fmt.Sprintf("Migrated %d thread(s) to %s (%d skipped, %d failed)", migrated, target, skipped, failed)))
+	if failed > 0 {
+		return fmt.Errorf("%d thread(s) failed to migrate; re-run to retry (already migrated threads are skipped)", failed)
+	}
+	return nil
+}
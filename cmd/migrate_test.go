@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func TestParsePRRef(t *testing.T) {
+	ref, err := parsePRRef("owner/repo#123", "")
+	require.NoError(t, err)
+	assert.Equal(t, prRef{Owner: "owner", Name: "repo", PR: 123}, ref)
+	assert.Equal(t, "owner/repo#123", ref.String())
+
+	ref, err = parsePRRef("#45", "me/current")
+	require.NoError(t, err)
+	assert.Equal(t, prRef{Owner: "me", Name: "current", PR: 45}, ref)
+
+	ref, err = parsePRRef("46", "me/current")
+	require.NoError(t, err)
+	assert.Equal(t, 46, ref.PR)
+
+	for _, invalid := range []string{"owner/repo#abc", "owner/repo#0", "owner#5", "x"} {
+		_, err := parsePRRef(invalid, "me/current")
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLoadPathMap(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "paths.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("src/: lib/\nsrc/legacy/: pkg/core/\ncmd/old.go: cmd/new.go\n"), 0644))
+
+	mapping, err := loadPathMap(file)
+	require.NoError(t, err)
+
+	assert.Equal(t, "pkg/core/db.go", mapping.Map("src/legacy/db.go"), "longest prefix wins")
+	assert.Equal(t, "lib/api.go", mapping.Map("src/api.go"))
+	assert.Equal(t, "cmd/new.go", mapping.Map("cmd/old.go"))
+	assert.Equal(t, "cmd/old.go.bak", mapping.Map("cmd/old.go.bak"), "file rules match exactly")
+	assert.Equal(t, "README.md", mapping.Map("README.md"))
+	assert.Equal(t, "x.go", pathMapping(nil).Map("x.go"))
+
+	require.NoError(t, os.WriteFile(file, []byte("a.go: ../escape.go\n"), 0644))
+	_, err = loadPathMap(file)
+	assert.Error(t, err)
+
+	_, err = loadPathMap(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func migrationSourceComments() []Comment {
+	created := time.Date(2024, 9, 1, 10, 0, 0, 0, time.UTC)
+	return []Comment{
+		{ID: 1, Type: "issue", Author: "alice", Body: "Splitting this PR", CreatedAt: created},
+		{ID: 2, Type: "review", Author: "bob", Body: "Handle errors", Path: "src/api.go", Line: 10, CreatedAt: created.Add(time.Hour)},
+		{ID: 3, Type: "review", Author: "alice", Body: "Done\nin abc123", Path: "src/api.go", Line: 10, InReplyToID: 2, CreatedAt: created.Add(2 * time.Hour)},
+		{ID: 4, Type: "review", Author: "bob", Body: "Moved code", Path: "src/api.go", Line: 99, CreatedAt: created.Add(3 * time.Hour)},
+		{ID: 5, Type: "review", Author: "carol", Body: "Gone file", Path: "docs/old.md", Line: 1, CreatedAt: created.Add(4 * time.Hour), Resolved: true},
+	}
+}
+
+func migrationPlanDefaults() migrationPlanOptions {
+	return migrationPlanOptions{
+		Source:  prRef{Owner: "owner", Name: "repo", PR: 123},
+		PathMap: pathMapping{{From: "src/", To: "lib/"}},
+		Missing: MigrateMissingFile,
+		TargetDiff: &github.PullRequestDiff{Files: []github.DiffFile{
+			{Filename: "lib/api.go", Lines: map[int]bool{10: true, 11: true}},
+		}},
+		Existing: map[string]bool{},
+	}
+}
+
+func planKinds(plan []migrationItem) []string {
+	var kinds []string
+	for _, item := range plan {
+		kinds = append(kinds, item.Kind)
+	}
+	return kinds
+}
+
+func TestPlanMigration(t *testing.T) {
+	opts := migrationPlanDefaults()
+	plan := planMigration(migrationSourceComments(), opts)
+
+	require.Len(t, plan, 4)
+	assert.Equal(t, []string{migrationIssue, migrationLine, migrationFile, migrationIssue}, planKinds(plan))
+
+	assert.Equal(t, "lib/api.go", plan[1].Path)
+	assert.Equal(t, 10, plan[1].Line)
+	assert.Equal(t, 2, plan[1].Comments)
+	assert.Equal(t, "line not in target diff", plan[2].Reason)
+	assert.Equal(t, "file not in target diff", plan[3].Reason)
+
+	t.Run("missing issue", func(t *testing.T) {
+		opts := migrationPlanDefaults()
+		opts.Missing = MigrateMissingIssue
+		assert.Equal(t, []string{migrationIssue, migrationLine, migrationIssue, migrationIssue},
+			planKinds(planMigration(migrationSourceComments(), opts)))
+	})
+
+	t.Run("missing drop and skip resolved", func(t *testing.T) {
+		opts := migrationPlanDefaults()
+		opts.Missing = MigrateMissingDrop
+		opts.SkipResolved = true
+		plan := planMigration(migrationSourceComments(), opts)
+		assert.Equal(t, []string{migrationIssue, migrationLine, migrationDrop}, planKinds(plan))
+		assert.Empty(t, plan[2].Body)
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		opts := migrationPlanDefaults()
+		opts.Existing = map[string]bool{"owner/repo#123/1": true, "owner/repo#123/2": true}
+		plan := planMigration(migrationSourceComments(), opts)
+		assert.Equal(t, []string{migrationExists, migrationExists, migrationFile, migrationIssue}, planKinds(plan))
+		assert.Equal(t, 2, countMigrationWrites(plan))
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		comments := []Comment{{ID: 9, Type: "issue", Author: "eve", Body: "<script>alert(1)</script>"}}
+		plan := planMigration(comments, migrationPlanDefaults())
+		assert.Equal(t, []string{migrationInvalid}, planKinds(plan))
+	})
+}
+
+func TestFormatMigratedBody(t *testing.T) {
+	comments := migrationSourceComments()
+	body := formatMigratedBody(prRef{Owner: "owner", Name: "repo", PR: 123}, []Comment{comments[1], comments[2]})
+
+	expected := strings.Join([]string{
+		"> **@\u200dbob** commented on `src/api.go:10` in [owner/repo#123](https://github.com/owner/repo/pull/123#discussion_r2) · 2024-09-01 11:00 UTC",
+		">",
+		"> Handle errors",
+		">",
+		"> **@\u200dalice** replied · 2024-09-01 12:00 UTC",
+		">",
+		"> Done",
+		"> in abc123",
+		"",
+		"<!-- gh-comment-migrated-from: owner/repo#123/2 -->",
+	}, "\n")
+	assert.Equal(t, expected, body)
+
+	issue := formatMigratedBody(prRef{Owner: "owner", Name: "repo", PR: 123}, comments[:1])
+	assert.Contains(t, issue, "> **@\u200dalice** commented in [owner/repo#123](https://github.com/owner/repo/pull/123#issuecomment-1)")
+
+	// Reposting a thread doesn't notify the people it mentions again, except in code
+	mention := Comment{ID: 5, Type: "issue", Author: "carol", Body: "cc @dave, see `@ignored`", CreatedAt: comments[0].CreatedAt}
+	reposted := formatMigratedBody(prRef{Owner: "owner", Name: "repo", PR: 123}, []Comment{mention})
+	assert.Contains(t, reposted, "> cc @\u200ddave, see `@ignored`\n")
+	assert.NotRegexp(t, `@[a-z]`, strings.ReplaceAll(reposted, "`@ignored`", ""))
+}
+
+func TestRunMigrate(t *testing.T) {
+	originalClient, originalRepo := migrateClient, repo
+	originalFrom, originalTo, originalMissing, originalDryRun := migrateFrom, migrateTo, migrateMissing, dryRun
+	defer func() {
+		migrateClient, repo = originalClient, originalRepo
+		migrateFrom, migrateTo, migrateMissing, dryRun = originalFrom, originalTo, originalMissing, originalDryRun
+	}()
+
+	created := time.Date(2024, 9, 1, 10, 0, 0, 0, time.UTC)
+	client := &github.MockClient{
+		IssueComments: []github.Comment{{ID: 1, Body: "Splitting this PR", User: github.User{Login: "alice"}, CreatedAt: created}},
+		ReviewComments: []github.Comment{
+			{ID: 2, Body: "Handle errors", User: github.User{Login: "bob"}, Path: "test.go", Line: 42, CreatedAt: created.Add(time.Hour)},
+			{ID: 3, Body: "Elsewhere", User: github.User{Login: "bob"}, Path: "test.go", Line: 7, CreatedAt: created.Add(2 * time.Hour)},
+		},
+	}
+	migrateClient = client
+	repo = "owner/repo"
+	migrateFrom, migrateTo, migrateMissing = "owner/repo#1", "#2", MigrateMissingFile
+
+	t.Run("dry run", func(t *testing.T) {
+		dryRun = true
+		output := captureOutput(func() {
+			require.NoError(t, runMigrate(migrateCmd, nil))
+		})
+		assert.Contains(t, output, "Would migrate 3 thread(s) from owner/repo#1 to owner/repo#2")
+		assert.Contains(t, output, "line comment on test.go:42")
+		assert.Contains(t, output, "file comment on test.go")
+		assert.Empty(t, client.AddReviewCommentCalls)
+		assert.Empty(t, client.IssueCommentBodies)
+	})
+
+	t.Run("migrates", func(t *testing.T) {
+		dryRun = false
+		output := captureOutput(func() {
+			require.NoError(t, runMigrate(migrateCmd, nil))
+		})
+		assert.Contains(t, output, "Migrated 3 thread(s) to owner/repo#2 (0 skipped, 0 failed)")
+
+		require.Len(t, client.AddReviewCommentCalls, 2)
+		line := client.AddReviewCommentCalls[0]
+		assert.Equal(t, 42, line.Line)
+		assert.Equal(t, "abc123def456", line.CommitID)
+		assert.Equal(t, "RIGHT", line.Side)
+		file := client.AddReviewCommentCalls[1]
+		assert.Equal(t, "file", file.SubjectType)
+		assert.Zero(t, file.Line)
+
+		require.Len(t, client.IssueCommentBodies, 1)
+		assert.Contains(t, client.IssueCommentBodies[0], "<!-- gh-comment-migrated-from: owner/repo#1/1 -->")
+	})
+
+	t.Run("failures are reported", func(t *testing.T) {
+		client.CreateCommentError = errors.New("boom")
+		defer func() { client.CreateCommentError = nil }()
+		var err error
+		captureOutput(func() { err = runMigrate(migrateCmd, nil) })
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3 thread(s) failed")
+	})
+
+	t.Run("secrets and policy are checked before anything is sent", func(t *testing.T) {
+		withPolicy(t, "rules:\n  - name: no-rubber-stamps\n    banned_phrases: [\"elsewhere\"]\n")
+		before := len(client.IssueCommentBodies)
+		err := runMigrate(migrateCmd, nil)
+		assert.ErrorContains(t, err, "blocked by repository policy")
+		assert.ErrorContains(t, err, "comment on test.go contains banned phrase 'Elsewhere'")
+		assert.Len(t, client.IssueCommentBodies, before)
+
+		withPolicy(t, "rules: []\n")
+		client.IssueComments[0].Body = "Use " + fakeGitHubToken
+		defer func() { client.IssueComments[0].Body = "Splitting this PR" }()
+		err = runMigrate(migrateCmd, nil)
+		assert.ErrorContains(t, err, "migrated thread #1 looks like it contains secrets")
+		assert.Len(t, client.IssueCommentBodies, before)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		migrateTo = "owner/repo#1"
+		assert.ErrorContains(t, runMigrate(migrateCmd, nil), "same PR")
+
+		migrateTo, migrateMissing = "#2", "keep"
+		assert.Error(t, runMigrate(migrateCmd, nil))
+	})
+}
+
+func TestFindMigrationMarkers(t *testing.T) {
+	client := &github.MockClient{
+		IssueComments: []github.Comment{
+			{ID: 1, Body: "quoted\n\n<!-- gh-comment-migrated-from: owner/repo#1/7 -->"},
+			{ID: 2, Body: "unrelated"},
+		},
+		ReviewComments: []github.Comment{{ID: 3, Body: "<!-- gh-comment-migrated-from: other/repo#9/8 -->"}},
+	}
+
+	markers, err := findMigrationMarkers(client, prRef{Owner: "owner", Name: "repo", PR: 2})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"owner/repo#1/7": true, "other/repo#9/8": true}, markers)
+}
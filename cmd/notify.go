@@ -0,0 +1,609 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// Notification event kinds. A single watch event can have several kinds,
+// e.g. a new reply that mentions you is "new", "reply" and "mention".
+const (
+	NotifyKindReply            = "reply"
+	NotifyKindMention          = "mention"
+	NotifyKindApproved         = "approved"
+	NotifyKindChangesRequested = "changes_requested"
+	NotifyKindCommented        = "commented"
+	NotifyKindDismissed        = "dismissed"
+
+	// WebhookTimeout bounds each webhook delivery
+	WebhookTimeout = 10 * time.Second
+)
+
+// notificationEventKinds lists the kinds rules can match in 'events'
+var notificationEventKinds = []string{
+	WatchEventNew, WatchEventEdited, WatchEventResolved, WatchEventUnresolved, WatchEventReview,
+	NotifyKindReply, NotifyKindMention,
+	NotifyKindApproved, NotifyKindChangesRequested, NotifyKindCommented, NotifyKindDismissed,
+}
+
+var (
+	notifyInterval  time.Duration
+	notifyJitter    time.Duration
+	notifyPolls     int
+	notifyMe        string
+	notifyListRules bool
+
+	// Client for dependency injection (tests can override)
+	notifyClient github.GitHubAPI
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify [pr]",
+	Short: "Run notification rules while polling a PR",
+	Long: heredoc.Doc(`
+		Poll a pull request and run the notification rules from your configuration
+		for each new event, so you can wire up your own notifiers.
+
+		Polling works like 'gh comment watch': conditional requests every --interval
+		with random --jitter. Comments that already exist when notify starts are
+		not reported.
+
+		Rules live under 'notifications.rules' in the config file. Each rule
+		matches on any combination of:
+
+		  events   event kinds (any of):
+		           new, edited, resolved, unresolved, review,
+		           reply (a reply in a thread you started or joined),
+		           mention (a comment @-mentioning you),
+		           approved, changes_requested, commented, dismissed (reviews)
+		  author   comment author, with wildcards ('bot*')
+		  body     regular expression matched against the body
+		  file     file glob for review comments ('src/**/*.go')
+
+		and runs one or more actions:
+
+		  command   shell command; event JSON on stdin, GH_COMMENT_EVENT_* variables
+		            as in 'gh comment watch' plus GH_COMMENT_EVENT_RULE and
+		            GH_COMMENT_EVENT_KINDS
+		  write_to  file to append the event as a line of JSON
+		  webhook   local URL (localhost or loopback IP) to POST the event JSON to
+
+		Rules with a command or write_to only run when the user config file
+		(~/.config/gh-comment/config.yaml) defines them; such a rule in a
+		project's .gh-comment.yaml is refused, so cloning a repository can't run
+		its code or append to your files.
+
+		Use --dry-run to see which rules would fire without running actions.
+	`),
+	Example: heredoc.Doc(`
+		# ~/.config/gh-comment/config.yaml
+		notifications:
+		  rules:
+		    - name: replies-and-mentions
+		      events: [reply, mention]
+		      command: notify-send "PR #$GH_COMMENT_EVENT_PR" "$GH_COMMENT_EVENT_AUTHOR: $GH_COMMENT_EVENT_BODY"
+		    - name: changes-requested
+		      events: [changes_requested]
+		      webhook: http://localhost:8080/gh-comment
+		    - name: security-files
+		      file: "src/auth/**"
+		      body: "(?i)security|vulnerab"
+		      write_to: ~/.local/state/gh-comment/security.jsonl
+
+		# Run the rules against the current branch's PR
+		$ gh comment notify
+
+		# Poll PR 123 every 15 seconds
+		$ gh comment notify 123 --interval 15s
+
+		# Show configured rules
+		$ gh comment notify --list-rules
+
+		# See what would fire without running actions
+		$ gh comment notify 123 --dry-run
+	`),
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNotify,
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+
+	notifyCmd.Flags().DurationVar(&notifyInterval, "interval", DefaultWatchInterval, "Time between polls (minimum 5s)")
+	notifyCmd.Flags().DurationVar(&notifyJitter, "jitter", 0, "Random variation added to or subtracted from each interval (default: 10% of --interval)")
+	notifyCmd.Flags().IntVar(&notifyPolls, "polls", 0, "Stop after this many polls (0 = until interrupted)")
+	notifyCmd.Flags().StringVar(&notifyMe, "me", "", "Login used for reply and mention detection (default: authenticated user)")
+	notifyCmd.Flags().BoolVar(&notifyListRules, "list-rules", false, "Print the configured rules and exit")
+}
+
+// compiledNotificationRule is a NotificationRule with its patterns compiled
+type compiledNotificationRule struct {
+	NotificationRule
+	body *regexp.Regexp
+	file *regexp.Regexp
+}
+
+// notificationPayload is the JSON sent to actions
+type notificationPayload struct {
+	Rule  string   `json:"rule"`
+	Kinds []string `json:"kinds"`
+	WatchEvent
+}
+
+func runNotify(cmd *cobra.Command, args []string) error {
+	rules, err := compileNotificationRules(GetConfig().Notifications.Rules)
+	if err != nil {
+		return err
+	}
+
+	if notifyListRules {
+		displayNotificationRules(rules)
+		return nil
+	}
+
+	var trusted []NotificationRule
+	if user := loadUserConfig(); user != nil {
+		trusted = user.Notifications.Rules
+	}
+	if err := checkNotificationActions(rules, trusted); err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return fmt.Errorf("no notification rules configured\n\n💡 Add rules under 'notifications.rules' in your user config file (~/.config/gh-comment/config.yaml), for example:\n\n  notifications:\n    rules:\n      - name: mentions\n        events: [mention, reply]\n        command: notify-send \"$GH_COMMENT_EVENT_AUTHOR\" \"$GH_COMMENT_EVENT_BODY\"\n\nSee 'gh comment notify --help' for all options")
+	}
+
+	// Initialize client if not set (production use)
+	if notifyClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		notifyClient = client
+	}
+
+	if notifyInterval < MinWatchInterval {
+		return formatValidationError("interval", notifyInterval.String(), fmt.Sprintf("must be at least %s", MinWatchInterval))
+	}
+	if !cmd.Flags().Changed("jitter") {
+		notifyJitter = notifyInterval / DefaultWatchJitterDivisor
+	}
+	if notifyJitter < 0 || notifyJitter > notifyInterval/2 {
+		return formatValidationError("jitter", notifyJitter.String(), "must be between 0 and half the interval")
+	}
+	if notifyPolls < 0 {
+		return formatValidationError("polls", strconv.Itoa(notifyPolls), "must be zero or a positive integer")
+	}
+
+	repository, pr, err := getPRContextFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	me := notifyMe
+	if me == "" && rulesNeedLogin(rules) {
+		me, err = getCurrentUser()
+		if err != nil {
+			return err
+		}
+	}
+
+	watcher, err := newCommentWatcher(notifyClient, repository, pr)
+	if err != nil {
+		return err
+	}
+	watcher.threads = rulesNeedKinds(rules, WatchEventResolved, WatchEventUnresolved)
+	watcher.reviews = rulesNeedKinds(rules, WatchEventReview, NotifyKindApproved, NotifyKindChangesRequested, NotifyKindCommented, NotifyKindDismissed)
+
+	n := &notifier{
+		rules:      rules,
+		me:         me,
+		mention:    mentionPattern(me),
+		watcher:    watcher,
+		httpClient: &http.Client{Timeout: WebhookTimeout},
+		stdout:     os.Stdout,
+		stderr:     os.Stderr,
+		dryRun:     dryRun,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "🔔 Applying %d notification rules to PR #%d in %s every %s (Ctrl+C to stop)\n",
+		len(rules), pr, repository, notifyInterval)
+
+	opts := watchOptions{
+		Interval: notifyInterval,
+		Jitter:   notifyJitter,
+		Polls:    notifyPolls,
+		Sleep:    sleepContext,
+	}
+
+	return runWatchLoop(ctx, watcher, opts, n.Handle)
+}
+
+// compileNotificationRules validates rules and compiles their patterns
+func compileNotificationRules(rules []NotificationRule) ([]compiledNotificationRule, error) {
+	compiled := make([]compiledNotificationRule, 0, len(rules))
+
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+			rule.Name = name
+		}
+
+		c := compiledNotificationRule{NotificationRule: rule}
+
+		for _, kind := range rule.Events {
+			if !containsString(notificationEventKinds, kind) {
+				return nil, fmt.Errorf("notification rule %s: invalid event '%s' (must be one of: %s)",
+					name, kind, strings.Join(notificationEventKinds, ", "))
+			}
+		}
+
+		if rule.Body != "" {
+			re, err := regexp.Compile(rule.Body)
+			if err != nil {
+				return nil, fmt.Errorf("notification rule %s: invalid body regex: %w", name, err)
+			}
+			c.body = re
+		}
+
+		if rule.File != "" {
+			re, err := compilePathGlob(rule.File)
+			if err != nil {
+				return nil, fmt.Errorf("notification rule %s: invalid file pattern: %w", name, err)
+			}
+			c.file = re
+		}
+
+		if rule.Command == "" && rule.WriteTo == "" && rule.Webhook == "" {
+			return nil, fmt.Errorf("notification rule %s: no action (set command, write_to or webhook)", name)
+		}
+
+		if rule.Webhook != "" {
+			if err := validateLocalWebhook(rule.Webhook); err != nil {
+				return nil, fmt.Errorf("notification rule %s: %w", name, err)
+			}
+		}
+
+		compiled = append(compiled, c)
+	}
+
+	return compiled, nil
+}
+
+// checkNotificationActions refuses rules with a command or write_to file unless the
+// user config file defines the same rule, so neither can come from a project's
+// config file: one would run its code, the other append to files like ~/.bashrc
+func checkNotificationActions(rules []compiledNotificationRule, trusted []NotificationRule) error {
+	for _, rule := range rules {
+		if rule.Command == "" && rule.WriteTo == "" {
+			continue
+		}
+		allowed := false
+		for _, candidate := range trusted {
+			if candidate.Command == rule.Command && candidate.WriteTo == rule.WriteTo {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("notification rule %s: command and write_to only run from rules in the user config file, "+
+				"not from a project config file", rule.Name)
+		}
+	}
+	return nil
+}
+
+// validateLocalWebhook ensures event data is only sent to this machine
+func validateLocalWebhook(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid webhook URL '%s': scheme must be http or https", rawURL)
+	}
+
+	host := u.Hostname()
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+
+	return fmt.Errorf("webhook URL '%s' must point to localhost or a loopback address", rawURL)
+}
+
+// rulesNeedKinds reports whether any rule could match one of kinds
+func rulesNeedKinds(rules []compiledNotificationRule, kinds ...string) bool {
+	for _, rule := range rules {
+		if len(rule.Events) == 0 {
+			return true
+		}
+		for _, kind := range kinds {
+			if containsString(rule.Events, kind) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rulesNeedLogin reports whether any rule matches replies or mentions, which depend on who "me" is
+func rulesNeedLogin(rules []compiledNotificationRule) bool {
+	for _, rule := range rules {
+		if containsString(rule.Events, NotifyKindReply) || containsString(rule.Events, NotifyKindMention) {
+			return true
+		}
+	}
+	return false
+}
+
+// notifier applies notification rules to watch events
+type notifier struct {
+	rules      []compiledNotificationRule
+	me         string
+	mention    *regexp.Regexp
+	watcher    *commentWatcher
+	httpClient *http.Client
+	stdout     io.Writer
+	stderr     io.Writer
+	dryRun     bool
+}
+
+// Handle runs the actions of every rule matching the event
+func (n *notifier) Handle(event WatchEvent) {
+	kinds := n.eventKinds(event)
+
+	for _, rule := range n.rules {
+		if !rule.matches(event, kinds) {
+			continue
+		}
+
+		payload := notificationPayload{Rule: rule.Name, Kinds: kinds, WatchEvent: event}
+		fmt.Fprintf(n.stdout, "🔔 [%s] %s by %s on PR #%d%s\n",
+			rule.Name, strings.Join(kinds, ", "), event.Comment.Author, event.PR, describeEventLocation(event))
+
+		for _, err := range n.runActions(rule, payload) {
+			fmt.Fprintf(n.stderr, "⚠️  rule %s: %v\n", rule.Name, err)
+		}
+	}
+}
+
+// eventKinds classifies an event into the kinds rules can match
+func (n *notifier) eventKinds(event WatchEvent) []string {
+	kinds := []string{event.Type}
+	fromOthers := n.me == "" || !strings.EqualFold(event.Comment.Author, n.me)
+
+	if event.Type == WatchEventReview {
+		if event.ReviewState != "" {
+			kinds = append(kinds, strings.ToLower(event.ReviewState))
+		}
+		return kinds
+	}
+
+	if event.Type == WatchEventNew && fromOthers && n.me != "" && n.isReplyToMe(event.Comment) {
+		kinds = append(kinds, NotifyKindReply)
+	}
+
+	if (event.Type == WatchEventNew || event.Type == WatchEventEdited) && fromOthers && n.mention != nil && n.mention.MatchString(event.Comment.Body) {
+		kinds = append(kinds, NotifyKindMention)
+	}
+
+	return kinds
+}
+
+// isReplyToMe reports whether a review comment replies to a thread I started or took part in
+func (n *notifier) isReplyToMe(comment Comment) bool {
+	if comment.InReplyToID == 0 || n.watcher == nil {
+		return false
+	}
+
+	for _, other := range n.watcher.review {
+		if other.ID == comment.ID {
+			continue
+		}
+		inThread := other.ID == comment.InReplyToID || other.InReplyToID == comment.InReplyToID
+		if inThread && strings.EqualFold(other.Author, n.me) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionPattern matches bodies that @-mention login, or is nil without a login
+func mentionPattern(login string) *regexp.Regexp {
+	if login == "" {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)(^|[^\w@/])@` + regexp.QuoteMeta(login) + `([^\w-]|$)`)
+}
+
+// matches reports whether an event with the given kinds satisfies the rule
+func (r compiledNotificationRule) matches(event WatchEvent, kinds []string) bool {
+	if len(r.Events) > 0 {
+		matched := false
+		for _, kind := range kinds {
+			if containsString(r.Events, kind) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if r.Author != "" && !matchesAuthorFilter(event.Comment.Author, r.Author) {
+		return false
+	}
+	if r.body != nil && !r.body.MatchString(event.Comment.Body) {
+		return false
+	}
+	if r.file != nil && (event.Comment.Path == "" || !r.file.MatchString(event.Comment.Path)) {
+		return false
+	}
+
+	return true
+}
+
+// runActions runs every action configured on the rule, collecting failures
+func (n *notifier) runActions(rule compiledNotificationRule, payload notificationPayload) []error {
+	var errs []error
+
+	if rule.Command != "" {
+		if n.dryRun {
+			fmt.Fprintf(n.stdout, "   [dry-run] would run: %s\n", rule.Command)
+		} else {
+			env := append(watchEventEnv(payload.WatchEvent),
+				"GH_COMMENT_EVENT_RULE="+rule.Name,
+				"GH_COMMENT_EVENT_KINDS="+strings.Join(payload.Kinds, ","))
+			if err := runEventHook(rule.Command, payload, env, n.stdout, n.stderr); err != nil {
+				errs = append(errs, fmt.Errorf("command failed: %w", err))
+			}
+		}
+	}
+
+	if rule.WriteTo != "" {
+		if n.dryRun {
+			fmt.Fprintf(n.stdout, "   [dry-run] would append to: %s\n", rule.WriteTo)
+		} else if err := appendJSONLine(rule.WriteTo, payload); err != nil {
+			errs = append(errs, fmt.Errorf("write to %s failed: %w", rule.WriteTo, err))
+		}
+	}
+
+	if rule.Webhook != "" {
+		if n.dryRun {
+			fmt.Fprintf(n.stdout, "   [dry-run] would POST to: %s\n", rule.Webhook)
+		} else if err := postWebhook(n.httpClient, rule.Webhook, payload); err != nil {
+			errs = append(errs, fmt.Errorf("webhook failed: %w", err))
+		}
+	}
+
+	return errs
+}
+
+// appendJSONLine appends v as one line of JSON to path, creating it if needed
+func appendJSONLine(path string, v interface{}) error {
+	path = expandHomeDir(path)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// postWebhook sends v as JSON to a local webhook
+func postWebhook(client *http.Client, webhookURL string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(webhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d from %s", resp.StatusCode, webhookURL)
+	}
+	return nil
+}
+
+// expandHomeDir expands a leading ~/ to the user's home directory
+func expandHomeDir(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// describeEventLocation returns " (path:line)" for review comments
+func describeEventLocation(event WatchEvent) string {
+	if event.Comment.Path == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", formatCommentLocation(event.Comment))
+}
+
+// displayNotificationRules prints the configured rules
+func displayNotificationRules(rules []compiledNotificationRule) {
+	if len(rules) == 0 {
+		fmt.Println("No notification rules configured")
+		return
+	}
+
+	fmt.Printf("Notification rules (%d):\n\n", len(rules))
+	for _, rule := range rules {
+		fmt.Printf("%s\n", ColorizeHeader(rule.Name))
+
+		events := "any"
+		if len(rule.Events) > 0 {
+			events = strings.Join(rule.Events, ", ")
+		}
+		fmt.Printf("  events:   %s\n", events)
+		if rule.Author != "" {
+			fmt.Printf("  author:   %s\n", rule.Author)
+		}
+		if rule.Body != "" {
+			fmt.Printf("  body:     %s\n", rule.Body)
+		}
+		if rule.File != "" {
+			fmt.Printf("  file:     %s\n", rule.File)
+		}
+		if rule.Command != "" {
+			fmt.Printf("  command:  %s\n", rule.Command)
+		}
+		if rule.WriteTo != "" {
+			fmt.Printf("  write_to: %s\n", rule.WriteTo)
+		}
+		if rule.Webhook != "" {
+			fmt.Printf("  webhook:  %s\n", rule.Webhook)
+		}
+		fmt.Println()
+	}
+}
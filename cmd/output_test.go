@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleOutputComments() []Comment {
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return []Comment{
+		{
+			ID:        101,
+			Author:    "alice",
+			Body:      "Looks good overall",
+			CreatedAt: created,
+			Type:      "issue",
+		},
+		{
+			ID:        202,
+			Author:    "bob",
+			Body:      "Consider a constant here\nand add a test",
+			CreatedAt: created.Add(time.Hour),
+			Path:      "src/api.go",
+			Line:      42,
+			Type:      "review",
+		},
+	}
+}
+
+func TestValidateOutputOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    OutputOptions
+		wantErr string
+	}{
+		{name: "default format", opts: OutputOptions{Format: FormatDefault}},
+		{name: "empty format", opts: OutputOptions{}},
+		{name: "table format", opts: OutputOptions{Format: FormatTable}},
+		{name: "csv format", opts: OutputOptions{Format: FormatCSV}},
+		{name: "tsv format", opts: OutputOptions{Format: FormatTSV}},
+		{name: "jq with json format", opts: OutputOptions{Format: FormatJSON, JQ: ".total"}},
+		{name: "template alone", opts: OutputOptions{Format: FormatDefault, Template: "{{.ID}}"}},
+		{name: "invalid format", opts: OutputOptions{Format: "xml"}, wantErr: "invalid format 'xml'"},
+		{name: "template and jq", opts: OutputOptions{Template: "{{.ID}}", JQ: ".total"}, wantErr: "cannot use --template with --jq"},
+		{name: "template and table", opts: OutputOptions{Format: FormatTable, Template: "{{.ID}}"}, wantErr: "cannot use --template with --format table"},
+		{name: "jq and csv", opts: OutputOptions{Format: FormatCSV, JQ: ".total"}, wantErr: "cannot use --jq with --format csv"},
+		{name: "broken template", opts: OutputOptions{Template: "{{.ID"}, wantErr: "invalid template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutputOptions(tt.opts)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRenderCommentsTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderComments(&buf, sampleOutputComments(), listJSONOutput(sampleOutputComments(), 123), OutputOptions{Format: FormatTable, Width: 80})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "ID")
+	assert.Contains(t, lines[0], "AUTHOR")
+	assert.Contains(t, lines[0], "BODY")
+	assert.Contains(t, lines[1], "alice")
+	assert.Contains(t, lines[2], "src/api.go:42")
+
+	// Multi-line bodies are flattened onto one row
+	assert.Contains(t, lines[2], "Consider a constant")
+
+	// Columns are aligned: every row starts the second column at the same offset
+	typeColumn := strings.Index(lines[0], "TYPE")
+	assert.Equal(t, typeColumn, strings.Index(lines[1], "issue"))
+	assert.Equal(t, typeColumn, strings.Index(lines[2], "review"))
+
+	for _, line := range lines {
+		assert.LessOrEqual(t, len([]rune(line)), 80, "row should fit the requested width: %q", line)
+	}
+}
+
+func TestRenderCommentsTableTruncatesToWidth(t *testing.T) {
+	comments := sampleOutputComments()
+	comments[0].Body = strings.Repeat("very long body ", 20)
+
+	var buf bytes.Buffer
+	err := renderComments(&buf, comments, listJSONOutput(comments, 123), OutputOptions{Format: FormatTable, Width: 60})
+	require.NoError(t, err)
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		assert.LessOrEqual(t, len([]rune(line)), 60)
+	}
+	assert.Contains(t, buf.String(), "...")
+}
+
+func TestRenderCommentsTableHideAuthors(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderComments(&buf, sampleOutputComments(), listJSONOutput(sampleOutputComments(), 123), OutputOptions{Format: FormatTable, Width: 100, HideAuthors: true})
+	require.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), "AUTHOR")
+	assert.NotContains(t, buf.String(), "alice")
+}
+
+func TestRenderCommentsDelimited(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := renderComments(&buf, sampleOutputComments(), listJSONOutput(sampleOutputComments(), 123), OutputOptions{Format: FormatCSV})
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "id,type,author,path,line,created_at,updated_at,body", lines[0])
+		assert.Equal(t, "101,issue,alice,,,2024-01-15T10:30:00Z,,Looks good overall", lines[1])
+		assert.Equal(t, `202,review,bob,src/api.go,42,2024-01-15T11:30:00Z,,Consider a constant here\nand add a test`, lines[2])
+	})
+
+	t.Run("tsv", func(t *testing.T) {
+		comments := sampleOutputComments()
+		comments[0].Body = "tab\there"
+
+		var buf bytes.Buffer
+		err := renderComments(&buf, comments, listJSONOutput(comments, 123), OutputOptions{Format: FormatTSV, HideAuthors: true})
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "id\ttype\tpath\tline\tcreated_at\tupdated_at\tbody", lines[0])
+		assert.Equal(t, "101\tissue\t\t\t2024-01-15T10:30:00Z\t\ttab\\there", lines[1])
+	})
+}
+
+func TestRenderCommentsTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "fields",
+			template: "{{.ID}} {{.Author}}",
+			expected: "101 alice\n202 bob\n",
+		},
+		{
+			name:     "helpers",
+			template: "{{.ID}} {{upper .Type}} {{truncate 12 (oneline .Body)}}",
+			expected: "101 ISSUE Looks goo...\n202 REVIEW Consider ...\n",
+		},
+		{
+			name:     "explicit newline is not doubled",
+			template: "{{.Path}}\n",
+			expected: "\nsrc/api.go\n",
+		},
+		{
+			name:     "json helper",
+			template: "{{json .Body}}",
+			expected: "\"Looks good overall\"\n\"Consider a constant here\\nand add a test\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := renderComments(&buf, sampleOutputComments(), listJSONOutput(sampleOutputComments(), 123), OutputOptions{Template: tt.template})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := renderComments(&buf, sampleOutputComments(), listJSONOutput(sampleOutputComments(), 123), OutputOptions{Template: "{{.Missing}}"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "comment #101")
+	})
+}
+
+func TestRenderCommentsJQ(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected string
+	}{
+		{name: "scalar", expr: ".total", expected: "2\n"},
+		{name: "ids", expr: ".comments[].id", expected: "101\n202\n"},
+		{name: "select", expr: `.comments[] | select(.type == "review") | .path`, expected: "src/api.go\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := renderComments(&buf, sampleOutputComments(), listJSONOutput(sampleOutputComments(), 123), OutputOptions{JQ: tt.expr})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+
+	t.Run("invalid expression", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := renderComments(&buf, sampleOutputComments(), listJSONOutput(sampleOutputComments(), 123), OutputOptions{JQ: ".comments[ |"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to apply jq expression")
+	})
+}
+
+func TestListJSONOutputEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeJSON(&buf, listJSONOutput(nil, 7)))
+	assert.Contains(t, buf.String(), `"comments": []`)
+	assert.Contains(t, buf.String(), `"pr": 7`)
+}
+
+func TestTruncateDisplay(t *testing.T) {
+	assert.Equal(t, "short", truncateDisplay("short", 10))
+	assert.Equal(t, "abcdefg...", truncateDisplay("abcdefghijklmnop", 10))
+	assert.Equal(t, "ab", truncateDisplay("abcdef", 2))
+	assert.Equal(t, "héllo wö...", truncateDisplay("héllo wörld and more", 11))
+	assert.Equal(t, "unchanged", truncateDisplay("unchanged", 0))
+}
+
+func TestFormatCommentLocation(t *testing.T) {
+	assert.Equal(t, "-", formatCommentLocation(Comment{}))
+	assert.Equal(t, "main.go", formatCommentLocation(Comment{Path: "main.go"}))
+	assert.Equal(t, "main.go:7", formatCommentLocation(Comment{Path: "main.go", Line: 7}))
+}
+
+func TestRenderCommentsShowsPRColumnForSearchResults(t *testing.T) {
+	comments := sampleOutputComments()
+	comments[0].PR = 12
+	comments[1].PR = 34
+
+	var table bytes.Buffer
+	require.NoError(t, renderComments(&table, comments, nil, OutputOptions{Format: FormatTable, Width: 100}))
+	assert.Contains(t, table.String(), "PR")
+	assert.Contains(t, table.String(), "#34")
+
+	var csvOut bytes.Buffer
+	require.NoError(t, renderComments(&csvOut, comments, nil, OutputOptions{Format: FormatCSV}))
+	assert.True(t, strings.HasPrefix(csvOut.String(), "id,pr,type,author"))
+	assert.Contains(t, csvOut.String(), "202,34,review,bob")
+}
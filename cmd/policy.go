@@ -0,0 +1,381 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// overridePolicy is the --override-policy flag of the write commands
+var overridePolicy bool
+
+// Policy actions
+const (
+	PolicyBlock = "block"
+	PolicyWarn  = "warn"
+)
+
+// PolicyFile is where a repository keeps its review policy, relative to the repository root
+const PolicyFile = ".gh-comment/policy.yaml"
+
+// Policy is a repository's review policy, enforced by review, batch, add and
+// close-pending-review before anything is sent to GitHub
+type Policy struct {
+	DefaultAction string       `yaml:"default_action"` // block (default) or warn
+	Rules         []PolicyRule `yaml:"rules"`
+}
+
+// PolicyRule is one check. Each rule sets exactly one of RequireLineComment,
+// RequireTags (optionally limited to Paths), MaxNits or BannedPhrases.
+type PolicyRule struct {
+	Name   string `yaml:"name"`
+	Action string `yaml:"action"` // block or warn, defaults to the policy's default_action
+
+	RequireLineComment []string `yaml:"require_line_comment"` // review events that need at least one line comment
+	Paths              []string `yaml:"paths"`                // file globs RequireTags applies to ('migrations/**')
+	RequireTags        []string `yaml:"require_tags"`         // tags every matching line comment must carry
+	MaxNits            int      `yaml:"max_nits"`             // most comments tagged nit in one operation
+	BannedPhrases      []string `yaml:"banned_phrases"`       // case-insensitive phrases, or /regex/
+}
+
+// PolicyViolation is a rule broken by an operation
+type PolicyViolation struct {
+	Rule    string
+	Action  string
+	Message string
+}
+
+// policySubject is what a write command is about to send
+type policySubject struct {
+	Event    string          // review event, empty for plain comments
+	Body     string          // review or comment body
+	Comments []policyComment // line comments
+}
+
+// policyComment is a comment checked against the policy
+type policyComment struct {
+	Path string
+	Line int
+	Body string
+}
+
+type compiledPolicyRule struct {
+	PolicyRule
+	paths  []*regexp.Regexp
+	banned []*regexp.Regexp
+}
+
+// commentTagPattern matches [tag] markers; a following '(' or '[' makes it a link instead
+var commentTagPattern = regexp.MustCompile(`\[([A-Za-z][\w-]*)\]`)
+
+// commentLabelPattern matches a leading 'nit:' style label
+var commentLabelPattern = regexp.MustCompile(`^\s*([A-Za-z][\w-]*):\s`)
+
+// taskListItemPattern matches the list marker before a '- [x]' checkbox
+var taskListItemPattern = regexp.MustCompile(`^\s*[-*+]\s+$`)
+
+// addPolicyFlag registers --override-policy on a write command
+func addPolicyFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&overridePolicy, "override-policy", false, "Proceed even if the repository policy ("+PolicyFile+") blocks the operation")
+}
+
+// policyPath returns the policy file of the current repository
+func policyPath() string {
+	root := findGitRoot()
+	if root == "" {
+		root = "."
+	}
+	return filepath.Join(root, PolicyFile)
+}
+
+// loadPolicy reads the repository policy; no policy file means no rules
+func loadPolicy(path string) ([]compiledPolicyRule, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+
+	rules, err := compilePolicy(policy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// compilePolicy validates a policy and prepares its patterns
+func compilePolicy(policy Policy) ([]compiledPolicyRule, error) {
+	defaultAction := policy.DefaultAction
+	if defaultAction == "" {
+		defaultAction = PolicyBlock
+	}
+	if defaultAction != PolicyBlock && defaultAction != PolicyWarn {
+		return nil, fmt.Errorf("invalid default_action '%s' (must be block or warn)", defaultAction)
+	}
+
+	compiled := make([]compiledPolicyRule, 0, len(policy.Rules))
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("#%d", i+1)
+		}
+		if rule.Action == "" {
+			rule.Action = defaultAction
+		}
+		if rule.Action != PolicyBlock && rule.Action != PolicyWarn {
+			return nil, fmt.Errorf("policy rule %s: invalid action '%s' (must be block or warn)", rule.Name, rule.Action)
+		}
+
+		checks := 0
+		for _, set := range []bool{len(rule.RequireLineComment) > 0, len(rule.RequireTags) > 0, rule.MaxNits != 0, len(rule.BannedPhrases) > 0} {
+			if set {
+				checks++
+			}
+		}
+		if checks != 1 {
+			return nil, fmt.Errorf("policy rule %s: set exactly one of require_line_comment, require_tags, max_nits or banned_phrases", rule.Name)
+		}
+		if len(rule.Paths) > 0 && len(rule.RequireTags) == 0 {
+			return nil, fmt.Errorf("policy rule %s: paths only applies to require_tags", rule.Name)
+		}
+		if rule.MaxNits < 0 {
+			return nil, fmt.Errorf("policy rule %s: max_nits must be positive", rule.Name)
+		}
+
+		c := compiledPolicyRule{PolicyRule: rule}
+		for _, event := range rule.RequireLineComment {
+			if event != "APPROVE" && event != "REQUEST_CHANGES" && event != "COMMENT" {
+				return nil, fmt.Errorf("policy rule %s: invalid review event '%s' (must be APPROVE, REQUEST_CHANGES, or COMMENT)", rule.Name, event)
+			}
+		}
+		for _, glob := range rule.Paths {
+			re, err := compilePathGlob(glob)
+			if err != nil {
+				return nil, fmt.Errorf("policy rule %s: %w", rule.Name, err)
+			}
+			c.paths = append(c.paths, re)
+		}
+		for _, phrase := range rule.BannedPhrases {
+			re, err := compileBannedPhrase(phrase)
+			if err != nil {
+				return nil, fmt.Errorf("policy rule %s: invalid banned phrase '%s': %w", rule.Name, phrase, err)
+			}
+			c.banned = append(c.banned, re)
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+// compileBannedPhrase turns "/regex/" into a regex and anything else into a
+// case-insensitive phrase match on word boundaries where the phrase has them
+func compileBannedPhrase(phrase string) (*regexp.Regexp, error) {
+	if len(phrase) > 2 && strings.HasPrefix(phrase, "/") && strings.HasSuffix(phrase, "/") {
+		return regexp.Compile("(?i)" + phrase[1:len(phrase)-1])
+	}
+	if strings.TrimSpace(phrase) == "" {
+		return nil, fmt.Errorf("phrase cannot be empty")
+	}
+
+	expr := regexp.QuoteMeta(phrase)
+	if isIdentifierRune(rune(phrase[0])) {
+		expr = `\b` + expr
+	}
+	if isIdentifierRune(rune(phrase[len(phrase)-1])) {
+		expr += `\b`
+	}
+	return regexp.Compile("(?i)" + expr)
+}
+
+// evaluatePolicy returns the rules an operation breaks
+func evaluatePolicy(rules []compiledPolicyRule, subject policySubject) []PolicyViolation {
+	var violations []PolicyViolation
+	report := func(rule compiledPolicyRule, format string, args ...interface{}) {
+		violations = append(violations, PolicyViolation{Rule: rule.Name, Action: rule.Action, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, rule := range rules {
+		switch {
+		case len(rule.RequireLineComment) > 0:
+			if containsString(rule.RequireLineComment, subject.Event) && !hasLineComment(subject.Comments) {
+				report(rule, "%s reviews must include at least one line comment", subject.Event)
+			}
+
+		case len(rule.RequireTags) > 0:
+			for _, comment := range subject.Comments {
+				if comment.Path == "" || (len(rule.paths) > 0 && !matchesAnyPattern(rule.paths, comment.Path)) {
+					continue
+				}
+				if missing := missingTags(comment.Body, rule.RequireTags); len(missing) > 0 {
+					report(rule, "comment on %s needs tag %s", describePolicyComment(comment), formatTags(missing))
+				}
+			}
+
+		case rule.MaxNits > 0:
+			nits := 0
+			for _, comment := range subject.Comments {
+				if containsString(commentTags(comment.Body), "nit") {
+					nits++
+				}
+			}
+			if nits > rule.MaxNits {
+				report(rule, "%d nit comments, at most %d allowed", nits, rule.MaxNits)
+			}
+
+		case len(rule.banned) > 0:
+			if phrase := findBannedPhrase(rule.banned, subject.Body); phrase != "" {
+				report(rule, "body contains banned phrase '%s'", phrase)
+			}
+			for _, comment := range subject.Comments {
+				if phrase := findBannedPhrase(rule.banned, comment.Body); phrase != "" {
+					report(rule, "comment on %s contains banned phrase '%s'", describePolicyComment(comment), phrase)
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// enforcePolicy checks an operation against the repository policy. Warnings are
+// printed; blocking violations fail the operation unless --override-policy is set.
+func enforcePolicy(subject policySubject) error {
+	rules, err := loadPolicy(policyPath())
+	if err != nil {
+		return err
+	}
+
+	var blocking []PolicyViolation
+	for _, violation := range evaluatePolicy(rules, subject) {
+		if violation.Action == PolicyBlock && !overridePolicy {
+			blocking = append(blocking, violation)
+			continue
+		}
+		label := "Policy warning"
+		if violation.Action == PolicyBlock {
+			label = "Policy overridden"
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  %s (%s): %s\n", label, violation.Rule, violation.Message)
+	}
+
+	if len(blocking) == 0 {
+		return nil
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "blocked by repository policy (%s):\n", PolicyFile)
+	for _, violation := range blocking {
+		fmt.Fprintf(&message, "  • %s: %s\n", violation.Rule, violation.Message)
+	}
+	message.WriteString("\n💡 Fix the comments, or use --override-policy to proceed anyway")
+	return fmt.Errorf("%s", message.String())
+}
+
+// commentTags returns the lowercase tags of a comment: [tag] markers anywhere and
+// a leading 'tag:' label, so "nit: rename" and "[sec] check input" are both tagged
+func commentTags(body string) []string {
+	var tags []string
+	add := func(tag string) {
+		tag = strings.ToLower(tag)
+		if !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	if match := commentLabelPattern.FindStringSubmatch(body); match != nil {
+		add(match[1])
+	}
+	for _, loc := range commentTagPattern.FindAllStringSubmatchIndex(body, -1) {
+		if loc[1] < len(body) && (body[loc[1]] == '(' || body[loc[1]] == '[') {
+			continue // markdown link
+		}
+		lineStart := strings.LastIndex(body[:loc[0]], "\n") + 1
+		if taskListItemPattern.MatchString(body[lineStart:loc[0]]) && strings.EqualFold(body[loc[2]:loc[3]], "x") {
+			continue // task list checkbox
+		}
+		add(body[loc[2]:loc[3]])
+	}
+	return tags
+}
+
+// missingTags returns the required tags a comment doesn't carry
+func missingTags(body string, required []string) []string {
+	tags := commentTags(body)
+	var missing []string
+	for _, tag := range required {
+		if !containsString(tags, strings.ToLower(tag)) {
+			missing = append(missing, tag)
+		}
+	}
+	return missing
+}
+
+// formatTags renders tags as they are written in comments: [sec] [perf]
+func formatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "[" + tag + "]"
+	}
+	return strings.Join(formatted, " ")
+}
+
+// findBannedPhrase returns the first banned text found in s
+func findBannedPhrase(patterns []*regexp.Regexp, s string) string {
+	for _, pattern := range patterns {
+		if match := pattern.FindString(s); match != "" {
+			return match
+		}
+	}
+	return ""
+}
+
+// hasLineComment reports whether any of the comments is on a file
+func hasLineComment(comments []policyComment) bool {
+	for _, comment := range comments {
+		if comment.Path != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewPolicyComments converts review comments for policy checks
+func reviewPolicyComments(inputs []github.ReviewCommentInput) []policyComment {
+	comments := make([]policyComment, len(inputs))
+	for i, input := range inputs {
+		comments[i] = policyComment{Path: input.Path, Line: input.Line, Body: input.Body}
+	}
+	return comments
+}
+
+// pendingPolicyComments converts the comments of a pending review for policy checks
+func pendingPolicyComments(pending []github.Comment) []policyComment {
+	comments := make([]policyComment, len(pending))
+	for i, comment := range pending {
+		comments[i] = policyComment{Path: comment.Path, Line: comment.Line, Body: comment.Body}
+	}
+	return comments
+}
+
+// describePolicyComment names a comment in violation messages
+func describePolicyComment(comment policyComment) string {
+	if comment.Path == "" {
+		return "the PR"
+	}
+	if comment.Line == 0 {
+		return comment.Path
+	}
+	return fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+const testPolicy = `
+default_action: block
+rules:
+  - name: changes-need-line-comments
+    require_line_comment: [REQUEST_CHANGES]
+  - name: migrations-need-sec
+    paths: ["migrations/**"]
+    require_tags: [sec]
+  - name: nit-budget
+    max_nits: 2
+    action: warn
+  - name: no-rubber-stamps
+    banned_phrases: ["lgtm", "/just\\s+ship/"]
+`
+
+// withPolicy runs the test in a repository whose policy file has the given content
+func withPolicy(t *testing.T, policy string) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".gh-comment"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PolicyFile), []byte(policy), 0644))
+	t.Chdir(dir)
+
+	original := overridePolicy
+	t.Cleanup(func() { overridePolicy = original })
+	overridePolicy = false
+}
+
+func TestCommentTags(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"nit: rename this", []string{"nit"}},
+		{"[sec] validate input [PERF]", []string{"sec", "perf"}},
+		{"Nit: [sec] both", []string{"nit", "sec"}},
+		{"See [the docs](https://example.com) and [ref][1]", nil},
+		{"Plain comment: no label here", nil},
+		{"- [x] done", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			assert.Equal(t, tt.want, commentTags(tt.body))
+		})
+	}
+}
+
+func TestCompilePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{"bad default action", Policy{DefaultAction: "deny"}, "invalid default_action 'deny'"},
+		{"bad rule action", Policy{Rules: []PolicyRule{{Name: "r", Action: "stop", MaxNits: 1}}}, "policy rule r: invalid action 'stop'"},
+		{"no check", Policy{Rules: []PolicyRule{{}}}, "policy rule #1: set exactly one of"},
+		{"two checks", Policy{Rules: []PolicyRule{{MaxNits: 1, BannedPhrases: []string{"x"}}}}, "set exactly one of"},
+		{"paths without tags", Policy{Rules: []PolicyRule{{Paths: []string{"a/**"}, MaxNits: 1}}}, "paths only applies to require_tags"},
+		{"bad event", Policy{Rules: []PolicyRule{{RequireLineComment: []string{"MERGE"}}}}, "invalid review event 'MERGE'"},
+		{"bad regex", Policy{Rules: []PolicyRule{{BannedPhrases: []string{"/(/"}}}}, "invalid banned phrase '/(/'"},
+		{"negative nits", Policy{Rules: []PolicyRule{{MaxNits: -1}}}, "max_nits must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compilePolicy(tt.policy)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	rules, err := compilePolicy(Policy{DefaultAction: "warn", Rules: []PolicyRule{{MaxNits: 1}, {MaxNits: 1, Action: "block"}}})
+	require.NoError(t, err)
+	assert.Equal(t, PolicyWarn, rules[0].Action)
+	assert.Equal(t, PolicyBlock, rules[1].Action)
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	withPolicy(t, testPolicy)
+	rules, err := loadPolicy(policyPath())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		subject policySubject
+		want    []PolicyViolation
+	}{
+		{
+			name:    "request changes without line comments",
+			subject: policySubject{Event: "REQUEST_CHANGES", Body: "Please fix"},
+			want:    []PolicyViolation{{Rule: "changes-need-line-comments", Action: PolicyBlock, Message: "REQUEST_CHANGES reviews must include at least one line comment"}},
+		},
+		{
+			name: "migration comment without tag",
+			subject: policySubject{Event: "COMMENT", Comments: []policyComment{
+				{Path: "migrations/2024/001.sql", Line: 12, Body: "Drop this column later"},
+				{Path: "migrations/002.sql", Line: 3, Body: "[sec] Escape this"},
+				{Path: "src/db.go", Line: 8, Body: "Fine elsewhere"},
+			}},
+			want: []PolicyViolation{{Rule: "migrations-need-sec", Action: PolicyBlock, Message: "comment on migrations/2024/001.sql:12 needs tag [sec]"}},
+		},
+		{
+			name: "too many nits",
+			subject: policySubject{Comments: []policyComment{
+				{Path: "a.go", Line: 1, Body: "nit: spacing"},
+				{Path: "a.go", Line: 2, Body: "[nit] naming"},
+				{Path: "a.go", Line: 3, Body: "Nit: typo"},
+			}},
+			want: []PolicyViolation{{Rule: "nit-budget", Action: PolicyWarn, Message: "3 nit comments, at most 2 allowed"}},
+		},
+		{
+			name: "banned phrases",
+			subject: policySubject{Event: "APPROVE", Body: "LGTM!", Comments: []policyComment{
+				{Path: "a.go", Line: 4, Body: "Just  ship it"},
+				{Path: "a.go", Line: 5, Body: "Ok as algtm is a variable name"},
+			}},
+			want: []PolicyViolation{
+				{Rule: "no-rubber-stamps", Action: PolicyBlock, Message: "body contains banned phrase 'LGTM'"},
+				{Rule: "no-rubber-stamps", Action: PolicyBlock, Message: "comment on a.go:4 contains banned phrase 'Just  ship'"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, evaluatePolicy(rules, tt.subject))
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	rules, err := loadPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+	assert.Nil(t, rules)
+
+	withPolicy(t, "rules:\n  - max_nits: [\n")
+	_, err = loadPolicy(policyPath())
+	assert.ErrorContains(t, err, "failed to parse policy file")
+
+	withPolicy(t, "rules:\n  - name: empty\n")
+	assert.ErrorContains(t, enforcePolicy(policySubject{}), "invalid policy file")
+}
+
+func TestReviewPolicyEnforcement(t *testing.T) {
+	withPolicy(t, testPolicy)
+	originalClient, originalRepo := reviewClient, repo
+	originalComments, originalEvent := reviewCommentsFlag, reviewEventFlag
+	defer func() {
+		reviewClient, repo = originalClient, originalRepo
+		reviewCommentsFlag, reviewEventFlag = originalComments, originalEvent
+	}()
+
+	mockClient := github.NewMockClient()
+	reviewClient, repo = mockClient, "owner/repo"
+	reviewEventFlag, reviewCommentsFlag = "REQUEST_CHANGES", nil
+
+	err := runReview(reviewCmd, []string{"123", "Please rework this"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "blocked by repository policy (.gh-comment/policy.yaml):\n  • changes-need-line-comments: REQUEST_CHANGES reviews must include at least one line comment")
+	assert.Contains(t, err.Error(), "--override-policy")
+	assert.Empty(t, mockClient.CreateReviewCalls)
+
+	overridePolicy = true
+	captureOutput(func() {
+		require.NoError(t, runReview(reviewCmd, []string{"123", "Please rework this"}))
+	})
+	assert.Len(t, mockClient.CreateReviewCalls, 1)
+}
+
+func TestAddPolicyEnforcement(t *testing.T) {
+	withPolicy(t, testPolicy)
+	originalClient, originalRepo, originalMessages := addClient, repo, messages
+	defer func() { addClient, repo, messages = originalClient, originalRepo, originalMessages }()
+
+	mockClient := github.NewMockClient()
+	addClient, repo, messages = mockClient, "owner/repo", nil
+
+	err := runAdd(addCmd, []string{"123", "lgtm"})
+	assert.ErrorContains(t, err, "no-rubber-stamps: body contains banned phrase 'lgtm'")
+	assert.Empty(t, mockClient.IssueCommentBodies)
+
+	captureOutput(func() {
+		require.NoError(t, runAdd(addCmd, []string{"123", "Thanks, the retry logic reads well now"}))
+	})
+	assert.Len(t, mockClient.IssueCommentBodies, 1)
+}
+
+func TestClosePendingReviewPolicyEnforcement(t *testing.T) {
+	withPolicy(t, `
+rules:
+  - name: nit-budget
+    max_nits: 1
+    action: block
+`)
+	originalClient, originalRepo, originalPR := closePendingClient, repo, prNumber
+	originalEvent, originalBody := closePendingEvent, closePendingBody
+	defer func() {
+		closePendingClient, repo, prNumber = originalClient, originalRepo, originalPR
+		closePendingEvent, closePendingBody = originalEvent, originalBody
+	}()
+
+	mockClient := github.NewMockClient()
+	mockClient.PendingComments = []github.Comment{
+		{ID: 1, Path: "a.go", Line: 3, Body: "nit: spacing"},
+		{ID: 2, Path: "a.go", Line: 9, Body: "[nit] naming"},
+	}
+	closePendingClient, repo, prNumber = mockClient, "owner/repo", 123
+	closePendingEvent, closePendingBody = "COMMENT", ""
+
+	err := runClosePendingReview(nil, []string{"123", "A few small things"})
+	assert.ErrorContains(t, err, "nit-budget: 2 nit comments, at most 1 allowed")
+	assert.Zero(t, mockClient.SubmittedReviewID)
+
+	mockClient.PendingComments = mockClient.PendingComments[:1]
+	captureOutput(func() {
+		require.NoError(t, runClosePendingReview(nil, []string{"123", "A few small things"}))
+	})
+	assert.Equal(t, mockClient.PendingReviewID, mockClient.SubmittedReviewID)
+}
+
+func TestClosePendingReviewDryRunWithoutReview(t *testing.T) {
+	withPolicy(t, `
+rules:
+  - name: no-rubber-stamps
+    banned_phrases: ["lgtm"]
+    action: block
+`)
+	originalClient, originalRepo, originalPR := closePendingClient, repo, prNumber
+	originalEvent, originalBody, originalDryRun := closePendingEvent, closePendingBody, dryRun
+	defer func() {
+		closePendingClient, repo, prNumber = originalClient, originalRepo, originalPR
+		closePendingEvent, closePendingBody, dryRun = originalEvent, originalBody, originalDryRun
+	}()
+
+	mockClient := github.NewMockClient()
+	mockClient.FindPendingReviewError = fmt.Errorf("connection refused")
+	closePendingClient, repo, prNumber = mockClient, "owner/repo", 123
+	closePendingEvent, closePendingBody, dryRun = "APPROVE", "", true
+
+	output := captureOutput(func() {
+		require.NoError(t, runClosePendingReview(nil, []string{"123", "Ship it"}))
+	})
+	assert.Contains(t, output, "Would close pending review on PR #123")
+	assert.Contains(t, output, "Line comments: not checked against the repository policy (failed to find pending review: connection refused)")
+
+	// The body is still checked without the review
+	err := runClosePendingReview(nil, []string{"123", "lgtm"})
+	assert.ErrorContains(t, err, "no-rubber-stamps")
+
+	dryRun = false
+	err = runClosePendingReview(nil, []string{"123", "Ship it"})
+	assert.ErrorContains(t, err, "failed to find pending review: connection refused")
+}
+
+func TestBatchPolicySubject(t *testing.T) {
+	subject := batchPolicySubject(&BatchConfig{
+		Review: &ReviewConfig{Body: "Summary"},
+		Comments: []CommentConfig{
+			{File: "migrations/001.sql", Range: "10-12", Message: "Add an index"},
+			{Type: "issue", Message: "General note"},
+		},
+	})
+	assert.Equal(t, policySubject{
+		Event: "COMMENT",
+		Body:  "Summary",
+		Comments: []policyComment{
+			{Path: "migrations/001.sql", Line: 10, Body: "Add an index"},
+			{Body: "General note"},
+		},
+	}, subject)
+
+	withPolicy(t, testPolicy)
+	err := enforcePolicy(subject)
+	assert.ErrorContains(t, err, "migrations-need-sec: comment on migrations/001.sql:10 needs tag [sec]")
+}
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/MakeNowJust/heredoc"
 
@@ -11,21 +16,52 @@ import (
 	"github.com/silouanwright/gh-comment/internal/github"
 )
 
+// API requests one reaction takes once the comment's type is known: adding is a
+// single request, removal also looks up the reactions and the current user
+const (
+	addReactionCost    = 1
+	removeReactionCost = 3
+)
+
 var (
 	removeReactionFlag bool
+	reactPRBody        bool
+
+	// Bulk targets: IDs on stdin, or comments matching list-style filters
+	reactStdin       bool
+	reactAuthor      string
+	reactType        string
+	reactQuery       CommentQuery
+	reactConcurrency int
+
+	// Input for --stdin (tests can override)
+	reactInput io.Reader = os.Stdin
 
 	// Client for dependency injection (tests can override)
 	reactClient github.GitHubAPI
 )
 
 var reactCmd = &cobra.Command{
-	Use:   "react <comment-id> <emoji>",
+	Use:   "react [comment-id...] <emoji>",
 	Short: "Add or remove emoji reactions to comments",
 	Long: heredoc.Doc(`
 		Add or remove emoji reactions to any comment type.
 
 		Supports both issue comments and review comments automatically.
-		Comment type is auto-detected from the comment ID.
+		Comment type is auto-detected from the comment ID. Use --pr-body instead
+		of a comment ID to react to the pull request description itself.
+
+		Several comments can be reacted to at once: pass several IDs, read IDs
+		from stdin with --stdin, or select comments with the same filters as
+		'gh comment list' (--author, --type, --file, --grep, ...). When IDs and
+		filters are combined, only the given IDs that match are reacted to.
+
+		Bulk reactions list the PR's comments once to learn each comment's type,
+		then run --concurrency requests at a time and stay within the
+		remaining API rate limit, keeping api.rate_limit_buffer requests in
+		reserve. Comments that don't fit in the budget are skipped. A result is
+		printed for every comment, and the command fails if any reaction did not
+		go through.
 
 		Available reactions: +1, -1, laugh, confused, heart, hooray, rocket, eyes
 
@@ -44,8 +80,21 @@ var reactCmd = &cobra.Command{
 		# Works with both issue and review comments automatically
 		$ gh comment react 123456 eyes    # Works for issue comments
 		$ gh comment react 789012 hooray  # Works for review comments
+
+		# React to the PR description
+		$ gh comment react --pr-body rocket --pr 42
+
+		# React to several comments at once
+		$ gh comment react 123456 789012 +1
+		$ gh comment list 42 --author dependabot --ids-only | gh comment react --stdin +1 --pr 42
+
+		# Thumbs-up every dependabot comment that says it was addressed
+		$ gh comment react +1 --pr 42 --author "dependabot*" --grep addressed
+
+		# See who reacted
+		$ gh comment reactions 123456
 	`),
-	Args: cobra.ExactArgs(2),
+	Args: cobra.MinimumNArgs(1),
 	RunE: runReact,
 }
 
@@ -53,6 +102,12 @@ func init() {
 	rootCmd.AddCommand(reactCmd)
 
 	reactCmd.Flags().BoolVar(&removeReactionFlag, "remove", false, "Remove reaction instead of adding it")
+	reactCmd.Flags().BoolVar(&reactPRBody, "pr-body", false, "React to the PR description instead of a comment")
+	reactCmd.Flags().BoolVar(&reactStdin, "stdin", false, "Read comment IDs from stdin (whitespace or newline separated)")
+	reactCmd.Flags().StringVar(&reactAuthor, "author", "", "React to comments by this author (supports wildcards: 'bot*')")
+	reactCmd.Flags().StringVar(&reactType, "type", "", "React to comments of this type (issue|review)")
+	addCommentQueryFlags(reactCmd.Flags(), &reactQuery)
+	reactCmd.Flags().IntVar(&reactConcurrency, "concurrency", DefaultSearchConcurrency, "Number of reactions to send in parallel")
 }
 
 func runReact(cmd *cobra.Command, args []string) error {
@@ -65,21 +120,62 @@ func runReact(cmd *cobra.Command, args []string) error {
 		reactClient = client
 	}
 
-	// Parse comment ID
-	commentIDStr := args[0]
-	commentID, err := parsePositiveInt(commentIDStr, "comment ID")
+	// The reaction is always the last argument, comment IDs come before it
+	reaction := args[len(args)-1]
+	idArgs := args[:len(args)-1]
+
+	// Validate reaction
+	if !validateReaction(reaction) {
+		return formatValidationError("reaction", reaction, "must be one of: +1, -1, laugh, confused, heart, hooray, rocket, eyes")
+	}
+
+	if reactPRBody {
+		if _, err := parseReactionTarget(idArgs, true); err != nil {
+			return err
+		}
+		if reactStdin || reactFiltersSet() {
+			return fmt.Errorf("cannot use --stdin or filters with --pr-body")
+		}
+		return reactToTarget(0, reaction)
+	}
+
+	commentIDs, err := readReactTargetIDs(idArgs)
 	if err != nil {
 		return err
 	}
 
-	// Get reaction emoji
-	reaction := args[1]
+	bulk := reactStdin || reactFiltersSet() || len(commentIDs) > 1
+	if !bulk {
+		if len(commentIDs) == 0 {
+			return fmt.Errorf("must provide a comment ID, --stdin or a filter such as --author (or --pr-body for the PR description)")
+		}
+		return reactToTarget(commentIDs[0], reaction)
+	}
 
-	// Validate reaction
-	if !validateReaction(reaction) {
-		return formatValidationError("reaction", reaction, "must be one of: +1, -1, laugh, confused, heart, hooray, rocket, eyes")
+	if err := validateReactFilters(); err != nil {
+		return err
+	}
+
+	repository, pr, err := getPRContext()
+	if err != nil {
+		return err
 	}
 
+	targets, err := selectReactTargets(repository, pr, commentIDs)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Printf("No comments to react to on PR #%d\n", pr)
+		return nil
+	}
+
+	owner, repoName, _ := strings.Cut(repository, "/")
+	return reactToComments(owner, repoName, pr, reaction, targets)
+}
+
+// reactToTarget adds or removes one reaction on a comment, or on the PR description when commentID is 0
+func reactToTarget(commentID int, reaction string) error {
 	// Get repository context
 	repository, prNumber, err := getPRContext()
 	if err != nil {
@@ -93,9 +189,14 @@ func runReact(cmd *cobra.Command, args []string) error {
 	}
 	owner, repoName := parts[0], parts[1]
 
+	target := fmt.Sprintf("comment #%d", commentID)
+	if commentID == 0 {
+		target = fmt.Sprintf("PR #%d", prNumber)
+	}
+
 	if verbose {
 		fmt.Printf("Repository: %s\n", repository)
-		fmt.Printf("Comment ID: %d\n", commentID)
+		fmt.Printf("Target: %s\n", target)
 		fmt.Printf("Reaction: %s\n", reaction)
 		if removeReactionFlag {
 			fmt.Printf("Action: Remove reaction\n")
@@ -110,29 +211,300 @@ func runReact(cmd *cobra.Command, args []string) error {
 		if removeReactionFlag {
 			action = "remove"
 		}
-		fmt.Printf("Would %s %s reaction %s comment #%d\n", action, reaction,
-			map[bool]string{true: "from", false: "to"}[removeReactionFlag], commentID)
+		fmt.Printf("Would %s %s reaction %s %s\n", action, reaction,
+			map[bool]string{true: "from", false: "to"}[removeReactionFlag], target)
 		return nil
 	}
 
 	// Perform the reaction action
 	if removeReactionFlag {
-		err = reactClient.RemoveReaction(owner, repoName, commentID, prNumber, reaction)
+		if commentID == 0 {
+			err = reactClient.RemovePRReaction(owner, repoName, prNumber, reaction)
+		} else {
+			err = reactClient.RemoveReaction(owner, repoName, commentID, prNumber, reaction)
+		}
 		if err != nil {
 			return formatActionableError("reaction removal", err)
 		}
-		fmt.Printf("✅ Removed %s reaction from comment #%d\n", reaction, commentID)
+		fmt.Printf("✅ Removed %s reaction from %s\n", reaction, target)
 	} else {
-		err = reactClient.AddReaction(owner, repoName, commentID, prNumber, reaction)
+		if commentID == 0 {
+			err = reactClient.AddPRReaction(owner, repoName, prNumber, reaction)
+		} else {
+			err = reactClient.AddReaction(owner, repoName, commentID, prNumber, reaction)
+		}
 		if err != nil {
 			return formatActionableError("reaction addition", err)
 		}
-		fmt.Printf("✅ Added %s reaction to comment #%d\n", reaction, commentID)
+		fmt.Printf("✅ Added %s reaction to %s\n", reaction, target)
 	}
 
 	return nil
 }
 
+// reactFiltersSet reports whether any list-style filter selects the comments to react to
+func reactFiltersSet() bool {
+	return reactAuthor != "" || reactType != "" || !reactQuery.IsEmpty() || reactQuery.Invert
+}
+
+// validateReactFilters checks the bulk options before any request is made
+func validateReactFilters() error {
+	if reactType != "" && reactType != "issue" && reactType != "review" {
+		return formatValidationError("type", reactType, "must be one of: issue, review")
+	}
+	if reactConcurrency <= 0 || reactConcurrency > MaxSearchConcurrency {
+		return formatValidationError("concurrency", strconv.Itoa(reactConcurrency),
+			fmt.Sprintf("must be between 1 and %d", MaxSearchConcurrency))
+	}
+	return reactQuery.Compile()
+}
+
+// readReactTargetIDs parses comment IDs from the arguments and, with --stdin, from standard input.
+// Duplicates are dropped so each comment is reacted to once.
+func readReactTargetIDs(idArgs []string) ([]int, error) {
+	values := idArgs
+	if reactStdin {
+		scanner := bufio.NewScanner(reactInput)
+		scanner.Split(bufio.ScanWords)
+		for scanner.Scan() {
+			values = append(values, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read comment IDs from stdin: %w", err)
+		}
+	}
+
+	seen := make(map[int]bool)
+	var ids []int
+	for _, value := range values {
+		id, err := parsePositiveInt(value, "comment ID")
+		if err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// selectReactTargets returns the comments to react to: the given IDs, narrowed by the
+// filters when any are set, or every comment on the PR matching the filters. The PR's
+// comments are listed once so each reaction can go straight to its comment type's
+// endpoint; given IDs that aren't on the PR are returned without a type.
+func selectReactTargets(repository string, pr int, commentIDs []int) ([]Comment, error) {
+	comments, err := fetchAllComments(reactClient, repository, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reactFiltersSet() {
+		byID := make(map[int]Comment, len(comments))
+		for _, comment := range comments {
+			byID[comment.ID] = comment
+		}
+		targets := make([]Comment, len(commentIDs))
+		for i, id := range commentIDs {
+			targets[i] = Comment{ID: id}
+			if comment, ok := byID[id]; ok {
+				targets[i] = comment
+			}
+		}
+		return targets, nil
+	}
+
+	wanted := make(map[int]bool, len(commentIDs))
+	for _, id := range commentIDs {
+		wanted[id] = true
+	}
+
+	var targets []Comment
+	for _, comment := range comments {
+		if len(wanted) > 0 && !wanted[comment.ID] {
+			continue
+		}
+		if reactAuthor != "" && !matchesAuthorFilter(comment.Author, reactAuthor) {
+			continue
+		}
+		if reactType != "" && comment.Type != reactType {
+			continue
+		}
+		if !reactQuery.Matches(comment) {
+			continue
+		}
+		targets = append(targets, comment)
+	}
+	return targets, nil
+}
+
+// reactResult is the outcome of one reaction in a bulk run
+type reactResult struct {
+	Comment Comment
+	Err     error
+	Skipped string // why the reaction was not attempted
+}
+
+// reactToComments adds or removes a reaction on several comments with bounded concurrency,
+// skipping comments once the rate-limit budget is spent, and prints a result per comment
+func reactToComments(owner, repoName string, pr int, reaction string, targets []Comment) error {
+	verb, cost := "add", addReactionCost
+	if removeReactionFlag {
+		verb, cost = "remove", removeReactionCost
+	}
+
+	if dryRun {
+		preposition := map[bool]string{true: "from", false: "to"}[removeReactionFlag]
+		for _, target := range targets {
+			fmt.Printf("Would %s %s reaction %s comment #%d%s\n", verb, reaction, preposition, target.ID, describeReactTarget(target))
+		}
+		return nil
+	}
+
+	// Only start as many reactions as the remaining rate limit allows
+	allowed := len(targets)
+	limit, err := reactClient.GetRateLimit()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not check the API rate limit, reacting without a budget: %v\n", err)
+	} else {
+		budget := max(limit.Remaining-GetConfig().API.RateLimitBuffer, 0)
+		allowed = min(allowed, budget/cost)
+		if verbose {
+			fmt.Printf("Rate limit: %d of %d requests left, budget for %d reactions\n\n", limit.Remaining, limit.Limit, allowed)
+		}
+	}
+
+	// The budget goes to the first comments that exist, in order
+	inBudget := make([]bool, len(targets))
+	for i, target := range targets {
+		if target.Type != "" && allowed > 0 {
+			inBudget[i] = true
+			allowed--
+		}
+	}
+
+	results := make([]reactResult, len(targets))
+	var rateLimited atomic.Bool
+	forEachBounded(len(targets), reactConcurrency, func(i int) {
+		results[i].Comment = targets[i]
+		switch {
+		case targets[i].Type == "":
+			results[i].Err = fmt.Errorf("comment #%d not found on PR #%d", targets[i].ID, pr)
+			return
+		case !inBudget[i]:
+			results[i].Skipped = "rate-limit budget exhausted"
+			return
+		case rateLimited.Load():
+			results[i].Skipped = "rate limited"
+			return
+		}
+
+		var err error
+		if removeReactionFlag {
+			err = reactClient.RemoveCommentReaction(owner, repoName, targets[i].ID, targets[i].Type, reaction)
+		} else {
+			err = reactClient.AddCommentReaction(owner, repoName, targets[i].ID, targets[i].Type, reaction)
+		}
+		if github.IsRateLimited(err) {
+			rateLimited.Store(true)
+		}
+		results[i].Err = err
+	})
+
+	return printReactResults(results, reaction, limit)
+}
+
+// printReactResults prints one line per comment and a summary, failing when any reaction didn't go through
+func printReactResults(results []reactResult, reaction string, limit *github.RateLimit) error {
+	done := "Added"
+	if removeReactionFlag {
+		done = "Removed"
+	}
+
+	var failed, skipped int
+	for _, result := range results {
+		target := fmt.Sprintf("#%d%s", result.Comment.ID, describeReactTarget(result.Comment))
+		switch {
+		case result.Skipped != "":
+			skipped++
+			fmt.Printf("⏭️  %s: skipped, %s\n", target, result.Skipped)
+		case result.Err != nil:
+			failed++
+			message, _, _ := strings.Cut(result.Err.Error(), "\n")
+			fmt.Printf("❌ %s: %s\n", target, message)
+		default:
+			fmt.Printf("✅ %s: %s %s\n", target, strings.ToLower(done), reaction)
+		}
+	}
+
+	succeeded := len(results) - failed - skipped
+	fmt.Printf("\n%s %s on %d of %d comments", done, reaction, succeeded, len(results))
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	if skipped > 0 {
+		fmt.Printf(", %d skipped", skipped)
+	}
+	fmt.Println()
+
+	if skipped > 0 && limit != nil {
+		fmt.Printf("💡 The rate limit resets at %s; re-run then to react to the skipped comments\n", limit.Reset.Local().Format("15:04"))
+	}
+
+	if failed > 0 || skipped > 0 {
+		return fmt.Errorf("%d of %d reactions did not complete", failed+skipped, len(results))
+	}
+	return nil
+}
+
+// describeReactTarget adds the author and location of a comment when they are known
+func describeReactTarget(comment Comment) string {
+	if comment.Author == "" {
+		return ""
+	}
+	if comment.Path != "" {
+		return fmt.Sprintf(" (@%s, %s)", comment.Author, formatCommentLocation(comment))
+	}
+	return fmt.Sprintf(" (@%s)", comment.Author)
+}
+
+// reactionAliases maps emoji and common names onto GitHub reaction types
+var reactionAliases = map[string]string{
+	"👍":          "+1",
+	"thumbsup":   "+1",
+	"👎":          "-1",
+	"thumbsdown": "-1",
+	"😄":          "laugh",
+	"🎉":          "hooray",
+	"tada":       "hooray",
+	"😕":          "confused",
+	"❤️":         "heart",
+	"❤":          "heart",
+	"🚀":          "rocket",
+	"👀":          "eyes",
+}
+
+// reactionEmoji maps GitHub reaction types to the emoji GitHub displays
+var reactionEmoji = map[string]string{
+	"+1":       "👍",
+	"-1":       "👎",
+	"laugh":    "😄",
+	"hooray":   "🎉",
+	"confused": "😕",
+	"heart":    "❤️",
+	"rocket":   "🚀",
+	"eyes":     "👀",
+}
+
+// normalizeReaction converts emoji and aliases to the GitHub reaction name
+func normalizeReaction(reaction string) string {
+	reaction = strings.TrimSpace(reaction)
+	if alias, ok := reactionAliases[strings.ToLower(reaction)]; ok {
+		return alias
+	}
+	return reaction
+}
+
 func validateReaction(reaction string) bool {
 	validReactions := []string{"+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes"}
 	for _, valid := range validReactions {
@@ -2,10 +2,18 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/silouanwright/gh-comment/internal/github"
 )
@@ -288,6 +296,42 @@ func (m *ReactMockClient) RemoveReaction(owner, repo string, commentID int, prNu
 	return nil
 }
 
+func (m *ReactMockClient) ListReactions(owner, repo string, commentID int, prNumber int) ([]github.Reaction, error) {
+	return nil, nil
+}
+
+func (m *ReactMockClient) AddCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	return nil
+}
+
+func (m *ReactMockClient) RemoveCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	return nil
+}
+
+func (m *ReactMockClient) ListCommentReactions(owner, repo string, commentID int, commentType string) ([]github.Reaction, error) {
+	return nil, nil
+}
+
+func (m *ReactMockClient) AddPRReaction(owner, repo string, prNumber int, reaction string) error {
+	return nil
+}
+
+func (m *ReactMockClient) RemovePRReaction(owner, repo string, prNumber int, reaction string) error {
+	return nil
+}
+
+func (m *ReactMockClient) ListPRReactions(owner, repo string, prNumber int) ([]github.Reaction, error) {
+	return nil, nil
+}
+
+func (m *ReactMockClient) GetRateLimit() (*github.RateLimit, error) {
+	return &github.RateLimit{Limit: 5000, Remaining: 5000}, nil
+}
+
+func (m *ReactMockClient) ListCommentEdits(owner, repo string, commentID int, prNumber int) ([]github.CommentEdit, error) {
+	return nil, nil
+}
+
 func (m *ReactMockClient) EditComment(owner, repo string, commentID int, prNumber int, body string) error {
 	return nil
 }
@@ -312,13 +356,261 @@ func (m *ReactMockClient) FindPendingReview(owner, repo string, pr int) (int, er
 	return 0, nil
 }
 
+func (m *ReactMockClient) ListReviewCommentsForReview(owner, repo string, pr, reviewID int) ([]github.Comment, error) {
+	return nil, nil
+}
+
 func (m *ReactMockClient) SubmitReview(owner, repo string, pr, reviewID int, body, event string) error {
 	return nil
 }
 
+func (m *ReactMockClient) ListReviewThreads(owner, repo string, prNumber int) ([]github.ReviewThread, error) {
+	return nil, nil
+}
+
+func (m *ReactMockClient) SearchPullRequests(query string, limit int) ([]github.PullRequest, error) {
+	return nil, nil
+}
+
+func (m *ReactMockClient) ListRepoReviewComments(owner, repo string, since time.Time, keep func(github.Comment) bool) ([]github.Comment, error) {
+	return nil, nil
+}
+
 func (m *ReactMockClient) GetReviewComment(owner, repo string, commentID int) (*github.Comment, error) {
 	return &github.Comment{
 		ID:   commentID,
 		Body: "Mock review comment",
 	}, nil
 }
+
+func (m *ReactMockClient) ListIssueCommentsIfChanged(owner, repo string, prNumber int, etag string) (*github.ConditionalComments, error) {
+	return &github.ConditionalComments{}, nil
+}
+
+func (m *ReactMockClient) ListReviewCommentsIfChanged(owner, repo string, prNumber int, etag string) (*github.ConditionalComments, error) {
+	return &github.ConditionalComments{}, nil
+}
+
+func (m *ReactMockClient) ListReviews(owner, repo string, pr int) ([]github.Review, error) {
+	return nil, nil
+}
+
+// bulkReactClient records reactions sent concurrently and fails for chosen comments
+type bulkReactClient struct {
+	*github.MockClient
+	mu       sync.Mutex
+	reacted  []int
+	types    map[int]string
+	detected int // reactions that made the client look the comment type up
+	failFor  map[int]error
+}
+
+func (c *bulkReactClient) AddCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failFor[commentID]; err != nil {
+		return err
+	}
+	c.reacted = append(c.reacted, commentID)
+	c.types[commentID] = commentType
+	return nil
+}
+
+func (c *bulkReactClient) AddReaction(owner, repo string, commentID int, prNumber int, reaction string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.detected++
+	return nil
+}
+
+func (c *bulkReactClient) reactedIDs() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := append([]int(nil), c.reacted...)
+	sort.Ints(ids)
+	return ids
+}
+
+func setupBulkReactTest(t *testing.T) *bulkReactClient {
+	t.Helper()
+	originalClient, originalRepo, originalPR, originalDryRun := reactClient, repo, prNumber, dryRun
+	originalStdin, originalInput, originalAuthor, originalType := reactStdin, reactInput, reactAuthor, reactType
+	originalQuery, originalConcurrency, originalRemove, originalPRBody := reactQuery, reactConcurrency, removeReactionFlag, reactPRBody
+	t.Cleanup(func() {
+		reactClient, repo, prNumber, dryRun = originalClient, originalRepo, originalPR, originalDryRun
+		reactStdin, reactInput, reactAuthor, reactType = originalStdin, originalInput, originalAuthor, originalType
+		reactQuery, reactConcurrency, removeReactionFlag, reactPRBody = originalQuery, originalConcurrency, originalRemove, originalPRBody
+	})
+
+	mock := github.NewMockClient()
+	mock.IssueComments = []github.Comment{
+		{ID: 1, Body: "Bumps lodash from 4.17.20 to 4.17.21", User: github.User{Login: "dependabot[bot]"}},
+		{ID: 2, Body: "LGTM", User: github.User{Login: "alice"}},
+	}
+	mock.ReviewComments = []github.Comment{
+		{ID: 3, Body: "Addressed: pinned the version", User: github.User{Login: "dependabot[bot]"}, Path: "go.mod", Line: 5},
+		{ID: 4, Body: "Addressed in abc123", User: github.User{Login: "bob"}, Path: "main.go", Line: 9},
+	}
+
+	client := &bulkReactClient{MockClient: mock, types: map[int]string{}, failFor: map[int]error{}}
+	reactClient, repo, prNumber, dryRun = client, "owner/repo", 42, false
+	reactStdin, reactInput, reactAuthor, reactType = false, strings.NewReader(""), "", ""
+	reactQuery, reactConcurrency, removeReactionFlag, reactPRBody = CommentQuery{}, DefaultSearchConcurrency, false, false
+	return client
+}
+
+func TestBulkReactByIDs(t *testing.T) {
+	client := setupBulkReactTest(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"1", "3", "1", "+1"}))
+	})
+
+	assert.Equal(t, []int{1, 3}, client.reactedIDs())
+	assert.Equal(t, map[int]string{1: "issue", 3: "review"}, client.types, "types come from one listing of the PR")
+	assert.Zero(t, client.detected)
+	assert.Contains(t, output, "✅ #1 (@dependabot[bot]): added +1\n✅ #3 (@dependabot[bot], go.mod:5): added +1\n")
+	assert.Contains(t, output, "Added +1 on 2 of 2 comments\n")
+
+	// IDs that aren't on the PR fail without a request
+	client.reacted = nil
+	var err error
+	output = captureOutput(func() {
+		err = runReact(reactCmd, []string{"2", "99", "+1"})
+	})
+	assert.ErrorContains(t, err, "1 of 2 reactions did not complete")
+	assert.Equal(t, []int{2}, client.reactedIDs())
+	assert.Contains(t, output, "❌ #99: comment #99 not found on PR #42\n")
+}
+
+func TestBulkReactFromStdin(t *testing.T) {
+	client := setupBulkReactTest(t)
+	reactStdin = true
+	reactInput = strings.NewReader("2\n4 2\n")
+
+	captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"heart"}))
+	})
+	assert.Equal(t, []int{2, 4}, client.reactedIDs())
+
+	reactInput = strings.NewReader("2\nnope\n")
+	assert.ErrorContains(t, runReact(reactCmd, []string{"heart"}), "invalid comment ID")
+}
+
+func TestBulkReactWithFilters(t *testing.T) {
+	client := setupBulkReactTest(t)
+	reactAuthor = "dependabot*"
+	reactQuery = CommentQuery{Grep: []string{"addressed"}}
+
+	output := captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"+1"}))
+	})
+	assert.Equal(t, []int{3}, client.reactedIDs())
+	assert.Contains(t, output, "✅ #3 (@dependabot[bot], go.mod:5): added +1")
+
+	// IDs are narrowed by the filters
+	client.reacted = nil
+	reactAuthor, reactQuery, reactType = "", CommentQuery{}, "issue"
+	captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"1", "2", "3", "+1"}))
+	})
+	assert.Equal(t, []int{1, 2}, client.reactedIDs())
+
+	reactAuthor, reactType = "nobody", ""
+	output = captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"+1"}))
+	})
+	assert.Equal(t, "No comments to react to on PR #42\n", output)
+}
+
+func TestBulkReactRespectsRateLimitBudget(t *testing.T) {
+	client := setupBulkReactTest(t)
+	originalConfig := globalConfig
+	t.Cleanup(func() { globalConfig = originalConfig })
+	globalConfig = NewDefaultConfig()
+	globalConfig.API.RateLimitBuffer = 4
+
+	// Enough for two reactions after keeping 4 requests in reserve
+	client.RateLimit = &github.RateLimit{Limit: 5000, Remaining: 4 + 2*addReactionCost, Reset: time.Now().Add(time.Hour)}
+
+	var err error
+	output := captureOutput(func() {
+		err = runReact(reactCmd, []string{"1", "2", "3", "4", "+1"})
+	})
+
+	assert.ErrorContains(t, err, "2 of 4 reactions did not complete")
+	assert.Len(t, client.reactedIDs(), 2)
+	assert.Equal(t, 2, strings.Count(output, "skipped, rate-limit budget exhausted"))
+	assert.Contains(t, output, "Added +1 on 2 of 4 comments, 2 skipped")
+	assert.Contains(t, output, "💡 The rate limit resets at")
+}
+
+func TestBulkReactReportsFailures(t *testing.T) {
+	client := setupBulkReactTest(t)
+	client.failFor[2] = errors.New("Operation failed: HTTP 403\n\nmore help")
+
+	var err error
+	output := captureOutput(func() {
+		err = runReact(reactCmd, []string{"1", "2", "3", "rocket"})
+	})
+
+	assert.ErrorContains(t, err, "1 of 3 reactions did not complete")
+	assert.Equal(t, []int{1, 3}, client.reactedIDs())
+	assert.Contains(t, output, "❌ #2 (@alice): Operation failed: HTTP 403\n")
+	assert.Contains(t, output, "Added rocket on 2 of 3 comments, 1 failed")
+
+	// A 403 for missing permissions isn't rate limiting, so later comments are still tried
+	client.failFor = map[int]error{1: &api.HTTPError{StatusCode: http.StatusForbidden, Message: "Resource not accessible by integration"}}
+	client.reacted = nil
+	reactConcurrency = 1
+	captureOutput(func() {
+		assert.Error(t, runReact(reactCmd, []string{"1", "2", "3", "rocket"}))
+	})
+	assert.Equal(t, []int{2, 3}, client.reactedIDs())
+
+	// Once GitHub rate limits a reaction, the rest are skipped instead of sent
+	tooMany := &api.HTTPError{StatusCode: http.StatusTooManyRequests, Message: "Too Many Requests"}
+	client.failFor = map[int]error{1: tooMany, 2: tooMany, 3: tooMany}
+	output = captureOutput(func() {
+		assert.Error(t, runReact(reactCmd, []string{"1", "2", "3", "rocket"}))
+	})
+	assert.Equal(t, 1, strings.Count(output, "HTTP 429"))
+	assert.Equal(t, 2, strings.Count(output, "skipped, rate limited"))
+	reactConcurrency = DefaultSearchConcurrency
+
+	// Without a known rate limit the reactions still go out
+	client.failFor = map[int]error{}
+	client.reacted = nil
+	client.RateLimitError = errors.New("boom")
+	captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"1", "2", "rocket"}))
+	})
+	assert.Equal(t, []int{1, 2}, client.reactedIDs())
+}
+
+func TestBulkReactDryRunAndValidation(t *testing.T) {
+	client := setupBulkReactTest(t)
+
+	dryRun = true
+	reactAuthor = "dependabot*"
+	output := captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"eyes"}))
+	})
+	assert.Equal(t, "Would add eyes reaction to comment #1 (@dependabot[bot])\nWould add eyes reaction to comment #3 (@dependabot[bot], go.mod:5)\n", output)
+	assert.Empty(t, client.reactedIDs())
+	dryRun = false
+
+	reactConcurrency = 0
+	assert.ErrorContains(t, runReact(reactCmd, []string{"eyes"}), "must be between 1 and 16")
+	reactConcurrency = DefaultSearchConcurrency
+
+	reactType = "commit"
+	assert.ErrorContains(t, runReact(reactCmd, []string{"eyes"}), "must be one of: issue, review")
+	reactType, reactAuthor = "", ""
+
+	reactPRBody, reactStdin = true, true
+	assert.ErrorContains(t, runReact(reactCmd, []string{"eyes"}), "cannot use --stdin or filters with --pr-body")
+	reactPRBody, reactStdin = false, false
+
+	assert.ErrorContains(t, runReact(reactCmd, []string{"eyes"}), "must provide a comment ID, --stdin or a filter")
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+var (
+	reactionsPRBody bool
+	reactionsFormat string
+
+	// Client for dependency injection (tests can override)
+	reactionsClient github.GitHubAPI
+)
+
+var reactionsCmd = &cobra.Command{
+	Use:   "reactions [comment-id]",
+	Short: "Show who reacted to a comment or the PR",
+	Long: heredoc.Doc(`
+		Show the reactions on a comment, grouped by emoji, with the users who left them.
+
+		Works with both issue and review comments; the comment type is detected
+		from the comment ID. Use --pr-body instead of a comment ID to see the
+		reactions on the pull request description itself.
+
+		Comment IDs can be found in the output of 'gh comment list'.
+	`),
+	Example: heredoc.Doc(`
+		# Who reacted to a comment
+		$ gh comment reactions 123456
+
+		# Reactions on the PR description
+		$ gh comment reactions --pr-body --pr 42
+
+		# Structured output for scripts
+		$ gh comment reactions 123456 --format json | jq '.reactions[] | select(.content == "+1") | .users'
+	`),
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReactions,
+}
+
+func init() {
+	rootCmd.AddCommand(reactionsCmd)
+
+	reactionsCmd.Flags().BoolVar(&reactionsPRBody, "pr-body", false, "Show reactions on the PR description instead of a comment")
+	reactionsCmd.Flags().StringVar(&reactionsFormat, "format", FormatDefault, "Output format (default|json)")
+}
+
+// reactionGroup holds the users who left one kind of reaction
+type reactionGroup struct {
+	Content string   `json:"content"`
+	Count   int      `json:"count"`
+	Users   []string `json:"users"`
+}
+
+func runReactions(cmd *cobra.Command, args []string) error {
+	// Initialize client if not set (production use)
+	if reactionsClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		reactionsClient = client
+	}
+
+	commentID, err := parseReactionTarget(args, reactionsPRBody)
+	if err != nil {
+		return err
+	}
+
+	if reactionsFormat != FormatDefault && reactionsFormat != FormatJSON {
+		return formatValidationError("format", reactionsFormat, "must be one of: default, json")
+	}
+
+	// Get repository context
+	repository, pr, err := getPRContext()
+	if err != nil {
+		return err
+	}
+
+	// Parse owner/repo
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid repository format: %s (expected owner/repo)", repository)
+	}
+	owner, repoName := parts[0], parts[1]
+
+	var reactions []github.Reaction
+	if reactionsPRBody {
+		reactions, err = reactionsClient.ListPRReactions(owner, repoName, pr)
+	} else {
+		reactions, err = reactionsClient.ListReactions(owner, repoName, commentID, pr)
+	}
+	if err != nil {
+		return formatActionableError("reaction listing", err)
+	}
+
+	target := fmt.Sprintf("comment #%d", commentID)
+	if reactionsPRBody {
+		target = fmt.Sprintf("PR #%d", pr)
+	}
+	groups := groupReactions(reactions)
+
+	if reactionsFormat == FormatJSON {
+		if groups == nil {
+			groups = []reactionGroup{}
+		}
+		return writeJSON(os.Stdout, struct {
+			PR        int             `json:"pr"`
+			CommentID int             `json:"comment_id,omitempty"`
+			Total     int             `json:"total"`
+			Reactions []reactionGroup `json:"reactions"`
+		}{PR: pr, CommentID: commentID, Total: len(reactions), Reactions: groups})
+	}
+
+	if len(groups) == 0 {
+		fmt.Printf("No reactions on %s\n", target)
+		return nil
+	}
+
+	fmt.Printf("Reactions on %s (%d total)\n\n", target, len(reactions))
+	for _, group := range groups {
+		users := make([]string, len(group.Users))
+		for i, user := range group.Users {
+			users[i] = "@" + user
+		}
+		fmt.Printf("%s %-8s %3d  %s\n", reactionEmoji[group.Content], group.Content, group.Count, strings.Join(users, ", "))
+	}
+	return nil
+}
+
+// parseReactionTarget returns the comment ID from args, or 0 when targeting the PR description
+func parseReactionTarget(args []string, prBody bool) (int, error) {
+	if prBody {
+		if len(args) > 0 {
+			return 0, fmt.Errorf("cannot use a comment ID with --pr-body")
+		}
+		return 0, nil
+	}
+	if len(args) == 0 {
+		return 0, fmt.Errorf("must provide a comment ID (or --pr-body for the PR description)")
+	}
+	return parsePositiveInt(args[0], "comment ID")
+}
+
+// groupReactions groups reactions by type in GitHub's display order, keeping users in reaction order
+func groupReactions(reactions []github.Reaction) []reactionGroup {
+	users := make(map[string][]string)
+	for _, reaction := range reactions {
+		users[reaction.Content] = append(users[reaction.Content], reaction.User.Login)
+	}
+
+	var groups []reactionGroup
+	for _, content := range github.ReactionContents {
+		if len(users[content]) > 0 {
+			groups = append(groups, reactionGroup{Content: content, Count: len(users[content]), Users: users[content]})
+		}
+	}
+	return groups
+}
+
+// reactorsByContent maps each reaction type to the users who left it
+func reactorsByContent(reactions []github.Reaction) map[string][]string {
+	groups := groupReactions(reactions)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	reactors := make(map[string][]string, len(groups))
+	for _, group := range groups {
+		reactors[group.Content] = group.Users
+	}
+	return reactors
+}
+
+// reactorTarget is a comment whose reactors are looked up
+type reactorTarget struct {
+	ID        int
+	Type      string // "issue" or "review"
+	Reactions github.ReactionSummary
+}
+
+// fetchReactors looks up who reacted to each comment that has reactions, keyed by comment ID.
+// The comment types are known, so each lookup is a single call to the right endpoint;
+// a few run at a time.
+func fetchReactors(client github.GitHubAPI, owner, repoName string, targets []reactorTarget) (map[int]map[string][]string, error) {
+	var withReactions []reactorTarget
+	for _, target := range targets {
+		if target.Reactions.TotalCount > 0 {
+			withReactions = append(withReactions, target)
+		}
+	}
+
+	results := make([]map[string][]string, len(withReactions))
+	errs := make([]error, len(withReactions))
+	forEachBounded(len(withReactions), DefaultSearchConcurrency, func(i int) {
+		target := withReactions[i]
+		reactions, err := client.ListCommentReactions(owner, repoName, target.ID, target.Type)
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to fetch reactions for comment #%d: %w", target.ID, err)
+			return
+		}
+		results[i] = reactorsByContent(reactions)
+	})
+
+	reactors := make(map[int]map[string][]string, len(withReactions))
+	for i, target := range withReactions {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		reactors[target.ID] = results[i]
+	}
+	return reactors, nil
+}
+
+// formatReactionSummary renders counts like "👍 2 (alice, bob) · 🚀 1", adding users when known
+func formatReactionSummary(summary github.ReactionSummary, reactors map[string][]string) string {
+	var parts []string
+	for _, content := range github.ReactionContents {
+		count := summary.Count(content)
+		if count == 0 {
+			continue
+		}
+		part := fmt.Sprintf("%s %d", reactionEmoji[content], count)
+		if users := reactors[content]; len(users) > 0 {
+			part += " (" + strings.Join(users, ", ") + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/fakegithub"
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func reactionFixture(content, login string) github.Reaction {
+	return github.Reaction{Content: content, User: github.User{Login: login}}
+}
+
+func setupReactionsTest(t *testing.T) *github.MockClient {
+	t.Helper()
+	originalReactions, originalReact, originalRepo, originalPR := reactionsClient, reactClient, repo, prNumber
+	originalPRBody, originalFormat, originalReactPRBody, originalRemove := reactionsPRBody, reactionsFormat, reactPRBody, removeReactionFlag
+	t.Cleanup(func() {
+		reactionsClient, reactClient, repo, prNumber = originalReactions, originalReact, originalRepo, originalPR
+		reactionsPRBody, reactionsFormat, reactPRBody, removeReactionFlag = originalPRBody, originalFormat, originalReactPRBody, originalRemove
+	})
+
+	mock := github.NewMockClient()
+	mock.Reactions = map[int][]github.Reaction{
+		123456: {
+			reactionFixture("rocket", "carol"),
+			reactionFixture("+1", "alice"),
+			reactionFixture("+1", "bob"),
+		},
+	}
+	mock.PRReactions = []github.Reaction{reactionFixture("hooray", "dave")}
+
+	reactionsClient, reactClient, repo, prNumber = mock, mock, "owner/repo", 7
+	reactionsPRBody, reactionsFormat, reactPRBody, removeReactionFlag = false, FormatDefault, false, false
+	return mock
+}
+
+func TestReactionsCommand(t *testing.T) {
+	setupReactionsTest(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runReactions(reactionsCmd, []string{"123456"}))
+	})
+	assert.Contains(t, output, "Reactions on comment #123456 (3 total)")
+	assert.Contains(t, output, "👍 +1         2  @alice, @bob\n🚀 rocket     1  @carol\n")
+
+	reactionsPRBody = true
+	output = captureOutput(func() {
+		require.NoError(t, runReactions(reactionsCmd, nil))
+	})
+	assert.Contains(t, output, "Reactions on PR #7 (1 total)")
+	assert.Contains(t, output, "@dave")
+	reactionsPRBody = false
+
+	output = captureOutput(func() {
+		require.NoError(t, runReactions(reactionsCmd, []string{"654321"}))
+	})
+	assert.Equal(t, "No reactions on comment #654321\n", output)
+}
+
+func TestReactionsCommandJSON(t *testing.T) {
+	setupReactionsTest(t)
+	reactionsFormat = FormatJSON
+
+	output := captureOutput(func() {
+		require.NoError(t, runReactions(reactionsCmd, []string{"123456"}))
+	})
+	assert.JSONEq(t, `{
+		"pr": 7,
+		"comment_id": 123456,
+		"total": 3,
+		"reactions": [
+			{"content": "+1", "count": 2, "users": ["alice", "bob"]},
+			{"content": "rocket", "count": 1, "users": ["carol"]}
+		]
+	}`, output)
+}
+
+func TestReactionsCommandErrors(t *testing.T) {
+	mock := setupReactionsTest(t)
+
+	assert.ErrorContains(t, runReactions(reactionsCmd, nil), "must provide a comment ID")
+	assert.ErrorContains(t, runReactions(reactionsCmd, []string{"abc"}), "invalid comment ID")
+
+	reactionsPRBody = true
+	assert.ErrorContains(t, runReactions(reactionsCmd, []string{"123456"}), "cannot use a comment ID with --pr-body")
+	reactionsPRBody = false
+
+	reactionsFormat = "table"
+	assert.ErrorContains(t, runReactions(reactionsCmd, []string{"123456"}), "must be one of: default, json")
+	reactionsFormat = FormatDefault
+
+	mock.ListReactionsError = errors.New("rate limited")
+	assert.ErrorContains(t, runReactions(reactionsCmd, []string{"123456"}), "rate limited")
+}
+
+func TestReactToPRBody(t *testing.T) {
+	mock := setupReactionsTest(t)
+	reactPRBody = true
+
+	output := captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"rocket"}))
+	})
+	assert.Contains(t, output, "✅ Added rocket reaction to PR #7")
+	assert.Equal(t, []string{"rocket"}, mock.AddedPRReactions)
+
+	removeReactionFlag = true
+	output = captureOutput(func() {
+		require.NoError(t, runReact(reactCmd, []string{"rocket"}))
+	})
+	assert.Contains(t, output, "✅ Removed rocket reaction from PR #7")
+	assert.Equal(t, []string{"rocket"}, mock.RemovedPRReactions)
+
+	assert.ErrorContains(t, runReact(reactCmd, []string{"123456", "rocket"}), "cannot use a comment ID with --pr-body")
+
+	reactPRBody = false
+	assert.ErrorContains(t, runReact(reactCmd, []string{"rocket"}), "must provide a comment ID")
+}
+
+func TestFormatReactionSummary(t *testing.T) {
+	summary := github.ReactionSummary{TotalCount: 4, PlusOne: 2, Heart: 1, Eyes: 1}
+	assert.Equal(t, "👍 2 · ❤️ 1 · 👀 1", formatReactionSummary(summary, nil))
+	assert.Equal(t, "👍 2 (alice, bob) · ❤️ 1 · 👀 1",
+		formatReactionSummary(summary, map[string][]string{"+1": {"alice", "bob"}}))
+	assert.Empty(t, formatReactionSummary(github.ReactionSummary{}, nil))
+}
+
+func TestAddReactorsOnlyFetchesReactedComments(t *testing.T) {
+	mock := setupReactionsTest(t)
+	comments := []Comment{
+		{ID: 123456, Reactions: github.ReactionSummary{TotalCount: 3, PlusOne: 2, Rocket: 1}},
+		{ID: 222, Body: "no reactions"},
+	}
+
+	require.NoError(t, addReactors(mock, "owner/repo", 7, comments))
+	assert.Equal(t, map[string][]string{"+1": {"alice", "bob"}, "rocket": {"carol"}}, comments[0].Reactors)
+	assert.Nil(t, comments[1].Reactors)
+
+	output := captureOutput(func() { displayComment(comments[0]) })
+	assert.Contains(t, output, "👍 2 (alice, bob) · 🚀 1 (carol)")
+
+	mock.ListReactionsError = errors.New("boom")
+	assert.ErrorContains(t, addReactors(mock, "owner/repo", 7, comments), "failed to fetch reactions for comment #123456")
+}
+
+func TestAddReactorsUsesKnownCommentTypes(t *testing.T) {
+	fans := []fakegithub.ReactionFixture{{Content: "+1", User: "alice"}, {Content: "+1", User: "bob"}}
+	server := fakegithub.New()
+	defer server.Close()
+	require.NoError(t, server.Seed(&fakegithub.Fixture{Repos: map[string]fakegithub.RepoFixture{
+		"owner/repo": {Pulls: []fakegithub.PullFixture{{
+			Number:         7,
+			Title:          "Speed up startup",
+			Author:         "octo",
+			Comments:       []fakegithub.CommentFixture{{ID: 11, Author: "octo", Body: "Shipping", Reactions: fans}},
+			ReviewComments: []fakegithub.CommentFixture{{ID: 12, Author: "octo", Body: "Nice", Path: "a.go", Line: 1, Reactions: fans}},
+		}}},
+	}}))
+	client, err := github.NewRealClientForServer(server.URL())
+	require.NoError(t, err)
+
+	comments := []Comment{
+		{ID: 11, Type: "issue", Reactions: github.ReactionSummary{TotalCount: 2, PlusOne: 2}},
+		{ID: 12, Type: "review", Reactions: github.ReactionSummary{TotalCount: 2, PlusOne: 2}},
+	}
+	require.NoError(t, addReactors(client, "owner/repo", 7, comments))
+	assert.Equal(t, map[string][]string{"+1": {"alice", "bob"}}, comments[1].Reactors)
+
+	// One request per comment, straight to its type's endpoint, without listing the PR's comments
+	var paths []string
+	for _, request := range server.Requests() {
+		path, _, _ := strings.Cut(request, "?")
+		paths = append(paths, path)
+	}
+	assert.ElementsMatch(t, []string{
+		"GET /repos/owner/repo/issues/comments/11/reactions",
+		"GET /repos/owner/repo/pulls/comments/12/reactions",
+	}, paths)
+}
+
+func TestExportReactions(t *testing.T) {
+	comment := ExportComment{
+		ID: 1, Type: "issue", Author: "alice", Body: "Ship it",
+		Reactions: &github.ReactionSummary{TotalCount: 2, PlusOne: 2},
+		Reactors:  map[string][]string{"+1": {"bob", "carol"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, exportMarkdown(&buf, []ExportComment{comment}, "owner/repo", 7))
+	assert.Contains(t, buf.String(), "**Reactions:** 👍 2 (bob, carol)  \n")
+
+	buf.Reset()
+	require.NoError(t, exportCSV(&buf, []ExportComment{comment}))
+	assert.Contains(t, buf.String(), ",Resolved,Reactions\n")
+	assert.Contains(t, buf.String(), `,false,"👍 2 (bob, carol)"`)
+
+	fields := exportFieldMap(comment, []string{"id", "reactors"})
+	assert.Equal(t, map[string][]string{"+1": {"bob", "carol"}}, fields["reactors"])
+
+	html := newHTMLComment(comment)
+	assert.Equal(t, []htmlReaction{{Emoji: "👍", Count: 2, Users: "bob, carol"}}, html.Reactions)
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// DefaultQuoteLines is how many lines of an issue comment a reply quotes
+const DefaultQuoteLines = 3
+
+// quotedMentionPattern finds @mentions; group 1 is what precedes the @
+var quotedMentionPattern = regexp.MustCompile(`(^|[^\w@/])@[A-Za-z0-9]`)
+
+var (
+	replyMessages            []string
+	replyQuoteLines          int
+	noExpandSuggestionsReply bool
+
+	// Client for dependency injection (tests can override)
+	replyClient github.GitHubAPI
+)
+
+var replyCmd = &cobra.Command{
+	Use:   "reply <comment-id> [message]",
+	Short: "Reply to any comment, threaded or quoted",
+	Long: heredoc.Doc(`
+		Reply to a review comment or an issue comment.
+
+		The comment type is detected from the comment ID:
+		• Review comments get a threaded reply in the same conversation. Replies to a
+		  reply go to the start of its thread.
+		• Issue comments have no threads, so the reply is a new PR comment that quotes
+		  the original with its author and a link to it.
+
+		--quote-lines sets how many lines of an issue comment are quoted (0 quotes
+		nothing). Longer comments are cut off with an ellipsis. @mentions in the
+		quoted text are neutralized so nobody is notified a second time, and the
+		whole reply, quote included, must pass the comment safety check.
+
+		Comment IDs can be found in the output of 'gh comment list'.
+	`),
+	Example: heredoc.Doc(`
+		# Threaded reply to a review comment
+		$ gh comment reply 789012 "Fixed in the latest commit"
+
+		# Reply to an issue comment, quoting its first 3 lines
+		$ gh comment reply 123456 "Agreed, let's do that in a follow-up"
+
+		# Quote more (or nothing) of the original
+		$ gh comment reply 123456 "Done" --quote-lines 10
+		$ gh comment reply 123456 "Done" --quote-lines 0
+
+		# Multi-line reply
+		$ gh comment reply 123456 -m "Thanks!" -m "Opened #130 for the rest."
+	`),
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runReply,
+}
+
+func init() {
+	rootCmd.AddCommand(replyCmd)
+
+	replyCmd.Flags().StringArrayVarP(&replyMessages, "message", "m", []string{}, "Reply message (can be used multiple times for multi-line replies)")
+	replyCmd.Flags().IntVar(&replyQuoteLines, "quote-lines", DefaultQuoteLines, "Lines of an issue comment to quote in the reply (0 for none)")
+	replyCmd.Flags().BoolVar(&noExpandSuggestionsReply, "no-expand-suggestions", false, "Disable automatic expansion of [SUGGEST:] and <<<SUGGEST>>> syntax")
+	addPolicyFlag(replyCmd)
+}
+
+func runReply(cmd *cobra.Command, args []string) error {
+	// Initialize client if not set (production use)
+	if replyClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		replyClient = client
+	}
+
+	// Parse comment ID
+	commentID, err := parsePositiveInt(args[0], "comment ID")
+	if err != nil {
+		return err
+	}
+
+	if replyQuoteLines < 0 {
+		return formatValidationError("quote lines", fmt.Sprintf("%d", replyQuoteLines), "must be 0 or more")
+	}
+
+	// Handle message from positional arg or --message flags
+	var message string
+	if len(args) == 2 {
+		message = args[1]
+	} else if len(replyMessages) > 0 {
+		message = strings.Join(replyMessages, "\n")
+	}
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("must provide a reply message (argument or --message)")
+	}
+
+	// Validate comment body length
+	if err := validateCommentBody(message); err != nil {
+		return err
+	}
+
+	// Check for secrets and personal data before anything is sent
+	message, err = scanCommentBody("reply", message)
+	if err != nil {
+		return err
+	}
+
+	// Check the repository policy before anything is sent
+	if err := enforcePolicy(policySubject{Body: message}); err != nil {
+		return err
+	}
+
+	if !noExpandSuggestionsReply {
+		message = expandSuggestions(message)
+	}
+
+	// Get repository context
+	repository, pr, err := getPRContext()
+	if err != nil {
+		return err
+	}
+
+	// Validate repository name
+	if err := validateRepositoryName(repository); err != nil {
+		return err
+	}
+
+	// Parse owner/repo
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid repository format: %s (expected owner/repo)", repository)
+	}
+	owner, repoName := parts[0], parts[1]
+
+	// Find out what kind of comment we're replying to
+	info, err := github.FindComment(replyClient, owner, repoName, commentID, pr)
+	if err != nil {
+		return formatActionableError("comment lookup", err)
+	}
+	if !info.Found {
+		return fmt.Errorf("comment #%d not found on PR #%d\n\n💡 Run 'gh comment list %d' to see comment IDs", commentID, pr, pr)
+	}
+
+	if verbose {
+		fmt.Printf("Repository: %s\n", repository)
+		fmt.Printf("PR: %d\n", pr)
+		fmt.Printf("Comment ID: %d\n", commentID)
+		fmt.Printf("Comment Type: %s\n", info.Type)
+		fmt.Println()
+	}
+
+	if info.Type == "review" {
+		return replyToReviewComment(owner, repoName, pr, info.Comment, message)
+	}
+	return replyToIssueComment(owner, repoName, pr, info.Comment, message)
+}
+
+// replyToReviewComment posts a threaded reply. GitHub only threads replies under
+// the first comment of a conversation, so replies to replies go there.
+func replyToReviewComment(owner, repoName string, pr int, comment *github.Comment, message string) error {
+	target := comment.ID
+	if comment.InReplyToID != 0 {
+		target = comment.InReplyToID
+	}
+
+	if dryRun {
+		fmt.Printf("Would reply to review comment #%d on %s:\n%s\n", target, describeCommentLocation(comment), message)
+		return nil
+	}
+
+	if _, err := replyClient.CreateReviewCommentReply(owner, repoName, target, message); err != nil {
+		return handleReviewReplyError(err, target, message, owner, repoName, pr)
+	}
+	fmt.Printf("✅ Replied to review comment #%d on %s\n", target, describeCommentLocation(comment))
+	return nil
+}
+
+// replyToIssueComment posts a new PR comment quoting the original
+func replyToIssueComment(owner, repoName string, pr int, comment *github.Comment, message string) error {
+	body := quoteComment(comment, issueCommentURL(owner, repoName, pr, comment), replyQuoteLines) + message
+	if err := validateCommentBody(body); err != nil {
+		return fmt.Errorf("reply quoting comment #%d: %w\n\n💡 Use --quote-lines 0 to leave the quoted text out", comment.ID, err)
+	}
+
+	if dryRun {
+		fmt.Printf("Would reply to issue comment #%d on PR #%d:\n%s\n", comment.ID, pr, body)
+		return nil
+	}
+
+	created, err := replyClient.CreateIssueComment(owner, repoName, pr, body)
+	if err != nil {
+		return formatActionableError("reply", err)
+	}
+	fmt.Printf("✅ Replied to issue comment #%d by @%s", comment.ID, comment.User.Login)
+	if created != nil && created.HTMLURL != "" {
+		fmt.Printf(": %s", created.HTMLURL)
+	}
+	fmt.Println()
+	return nil
+}
+
+// quoteComment renders the attribution and first maxLines lines of a comment as a
+// markdown quote followed by a blank line; maxLines 0 leaves only the attribution.
+// Mentions in the quoted text are neutralized so quoting doesn't notify anyone again.
+func quoteComment(comment *github.Comment, url string, maxLines int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "> **@%s** [commented](%s):\n", comment.User.Login, url)
+
+	quoted := neutralizeMentions(strings.TrimSpace(strings.ReplaceAll(comment.Body, "\r\n", "\n")))
+	lines := strings.Split(quoted, "\n")
+	if maxLines > 0 {
+		b.WriteString(">\n")
+		for i, line := range lines {
+			if i == maxLines {
+				b.WriteString("> …\n")
+				break
+			}
+			b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	return b.String()
+}
+
+// neutralizeMentions puts a zero-width joiner after the @ of each mention outside
+// code, which GitHub then renders as plain text instead of a notification
+func neutralizeMentions(text string) string {
+	masked := maskMarkdownCode(text)
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range quotedMentionPattern.FindAllStringSubmatchIndex(masked, -1) {
+		at := loc[3] // the @ follows group 1
+		b.WriteString(text[last : at+1])
+		b.WriteString("\u200d")
+		last = at + 1
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// issueCommentURL links to an issue comment, building the URL when the API didn't return one
+func issueCommentURL(owner, repoName string, pr int, comment *github.Comment) string {
+	if comment.HTMLURL != "" {
+		return comment.HTMLURL
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d#issuecomment-%d", owner, repoName, pr, comment.ID)
+}
+
+// describeCommentLocation names the file and line of a review comment
+func describeCommentLocation(comment *github.Comment) string {
+	if comment.Line == 0 {
+		return comment.Path
+	}
+	return fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+}
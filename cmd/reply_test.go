@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// replyRecordingClient records which comments review replies were posted to
+type replyRecordingClient struct {
+	*github.MockClient
+	replyTargets []int
+	replyBodies  []string
+}
+
+func (c *replyRecordingClient) CreateReviewCommentReply(owner, repo string, commentID int, body string) (*github.Comment, error) {
+	c.replyTargets = append(c.replyTargets, commentID)
+	c.replyBodies = append(c.replyBodies, body)
+	return c.MockClient.CreateReviewCommentReply(owner, repo, commentID, body)
+}
+
+func setupReplyTest(t *testing.T) *replyRecordingClient {
+	t.Helper()
+	originalClient, originalRepo, originalPR := replyClient, repo, prNumber
+	originalQuote, originalMessages, originalDryRun := replyQuoteLines, replyMessages, dryRun
+	t.Cleanup(func() {
+		replyClient, repo, prNumber = originalClient, originalRepo, originalPR
+		replyQuoteLines, replyMessages, dryRun = originalQuote, originalMessages, originalDryRun
+	})
+
+	mock := github.NewMockClient()
+	mock.IssueComments = []github.Comment{{
+		ID:      111,
+		Body:    "Could we split this PR?\r\n\r\nThe migration and the API change\nare unrelated.\nAlso see #12.",
+		User:    github.User{Login: "alice"},
+		HTMLURL: "https://github.com/owner/repo/pull/7#issuecomment-111",
+	}}
+	mock.ReviewComments = []github.Comment{
+		{ID: 222, Body: "Rename this", User: github.User{Login: "bob"}, Path: "main.go", Line: 42},
+		{ID: 333, Body: "Agreed", User: github.User{Login: "carol"}, Path: "main.go", Line: 42, InReplyToID: 222},
+	}
+
+	client := &replyRecordingClient{MockClient: mock}
+	replyClient, repo, prNumber = client, "owner/repo", 7
+	replyQuoteLines, replyMessages, dryRun = DefaultQuoteLines, nil, false
+	return client
+}
+
+func TestReplyToIssueCommentQuotesOriginal(t *testing.T) {
+	client := setupReplyTest(t)
+
+	output := captureOutput(func() {
+		require.NoError(t, runReply(replyCmd, []string{"111", "Yes, splitting now"}))
+	})
+
+	require.Len(t, client.IssueCommentBodies, 1)
+	assert.Equal(t, "> **@alice** [commented](https://github.com/owner/repo/pull/7#issuecomment-111):\n"+
+		">\n"+
+		"> Could we split this PR?\n"+
+		">\n"+
+		"> The migration and the API change\n"+
+		"> …\n"+
+		"\n"+
+		"Yes, splitting now", client.IssueCommentBodies[0])
+	assert.Empty(t, client.replyTargets)
+	assert.Contains(t, output, "✅ Replied to issue comment #111 by @alice")
+}
+
+func TestReplyQuoteLines(t *testing.T) {
+	client := setupReplyTest(t)
+	client.IssueComments[0].HTMLURL = ""
+
+	replyQuoteLines = 0
+	captureOutput(func() {
+		require.NoError(t, runReply(replyCmd, []string{"111", "Done"}))
+	})
+	assert.Equal(t, "> **@alice** [commented](https://github.com/owner/repo/pull/7#issuecomment-111):\n\nDone", client.IssueCommentBodies[0])
+
+	replyQuoteLines = 10
+	captureOutput(func() {
+		require.NoError(t, runReply(replyCmd, []string{"111", "Done"}))
+	})
+	assert.NotContains(t, client.IssueCommentBodies[1], "…")
+	assert.Contains(t, client.IssueCommentBodies[1], "> Also see #12.\n\nDone")
+
+	replyQuoteLines = -1
+	assert.ErrorContains(t, runReply(replyCmd, []string{"111", "Done"}), "must be 0 or more")
+}
+
+func TestReplyQuoteNeutralizesMentions(t *testing.T) {
+	client := setupReplyTest(t)
+	client.IssueComments[0].Body = "@bob and @acme/security, see `@config` (mail a@b.co)\n(@carol)"
+
+	captureOutput(func() {
+		require.NoError(t, runReply(replyCmd, []string{"111", "Thanks @alice"}))
+	})
+	body := client.IssueCommentBodies[0]
+	assert.Contains(t, body, "> @\u200dbob and @\u200dacme/security, see `@config` (mail a@b.co)\n> (@\u200dcarol)\n")
+	assert.Contains(t, body, "**@alice**", "the attribution still mentions the author")
+	assert.True(t, strings.HasSuffix(body, "\n\nThanks @alice"), "the reply's own mentions are kept")
+}
+
+func TestReplyQuoteIsValidated(t *testing.T) {
+	client := setupReplyTest(t)
+	client.IssueComments[0].Body = "Looks fine <script>alert(1)</script>"
+
+	err := runReply(replyCmd, []string{"111", "Agreed"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reply quoting comment #111")
+	assert.Contains(t, err.Error(), "--quote-lines 0")
+	assert.Empty(t, client.IssueCommentBodies)
+
+	replyQuoteLines = 0
+	captureOutput(func() {
+		require.NoError(t, runReply(replyCmd, []string{"111", "Agreed"}))
+	})
+	assert.Len(t, client.IssueCommentBodies, 1)
+}
+
+func TestReplyToReviewCommentThreads(t *testing.T) {
+	client := setupReplyTest(t)
+	replyMessages = []string{"Renamed", "in abc123"}
+
+	output := captureOutput(func() {
+		require.NoError(t, runReply(replyCmd, []string{"222"}))
+		// Replies to a reply go to the start of the thread
+		require.NoError(t, runReply(replyCmd, []string{"333", "Thanks"}))
+	})
+
+	assert.Equal(t, []int{222, 222}, client.replyTargets)
+	assert.Equal(t, []string{"Renamed\nin abc123", "Thanks"}, client.replyBodies)
+	assert.Empty(t, client.IssueCommentBodies)
+	assert.Contains(t, output, "✅ Replied to review comment #222 on main.go:42")
+}
+
+func TestReplyDryRunAndErrors(t *testing.T) {
+	client := setupReplyTest(t)
+
+	dryRun = true
+	output := captureOutput(func() {
+		require.NoError(t, runReply(replyCmd, []string{"111", "Sure"}))
+	})
+	assert.Contains(t, output, "Would reply to issue comment #111 on PR #7:\n> **@alice**")
+	assert.Empty(t, client.IssueCommentBodies)
+	dryRun = false
+
+	assert.ErrorContains(t, runReply(replyCmd, []string{"999", "Hello"}), "comment #999 not found on PR #7")
+	assert.ErrorContains(t, runReply(replyCmd, []string{"111"}), "must provide a reply message")
+	assert.ErrorContains(t, runReply(replyCmd, []string{"abc", "Hello"}), "invalid comment ID")
+	assert.ErrorContains(t, runReply(replyCmd, []string{"111", "<iframe src=x>"}), "dangerous HTML tags detected")
+
+	client.ListIssueCommentsError = errors.New("boom")
+	client.ListReviewCommentsError = errors.New("boom")
+	assert.ErrorContains(t, runReply(replyCmd, []string{"111", "Hello"}), "failed to list comments")
+}
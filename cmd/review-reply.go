@@ -35,7 +35,9 @@ var reviewReplyCmd = &cobra.Command{
 		• Use emoji reactions for quick feedback
 		• Resolve conversations (often works when replies don't)
 
-		For general PR discussion, use 'gh comment add' instead.
+		To reply to any comment type, use 'gh comment reply', which quotes issue
+		comments since they have no threads. For general PR discussion, use
+		'gh comment add' instead.
 		For emoji reactions, use 'gh comment react' command.
 
 		Comment IDs can be found in the output of 'gh comment list'.
@@ -106,6 +108,13 @@ func runReviewReply(cmd *cobra.Command, args []string) error {
 		if err := validateCommentBody(message); err != nil {
 			return err
 		}
+
+		// Check for secrets and personal data before anything is sent
+		scanned, err := scanCommentBody("reply", message)
+		if err != nil {
+			return err
+		}
+		message = scanned
 	}
 
 	// Get repository context
@@ -196,10 +205,10 @@ func handleReviewReplyError(err error, commentID int, message, owner, repo strin
 		fmt.Printf("   • Issue comments appear in the main conversation tab\n")
 		fmt.Printf("   • They don't support threading like review comments do\n\n")
 
-		fmt.Printf("💡 **Solution**: Use the general comment command instead:\n")
-		fmt.Printf("      gh comment add %d \"%s\"\n\n", prNumber, message)
+		fmt.Printf("💡 **Solution**: Use the reply command, which quotes issue comments instead:\n")
+		fmt.Printf("      gh comment reply %d \"%s\"\n\n", commentID, message)
 
-		return fmt.Errorf("comment #%d is an issue comment - use 'gh comment add' for general PR discussion", commentID)
+		return fmt.Errorf("comment #%d is an issue comment - use 'gh comment reply' to answer it", commentID)
 	}
 
 	// Check for the most common issue: GitHub API limitation with review comment threading
@@ -20,6 +20,7 @@ var (
 	// Review-specific flags
 	reviewEventFlag    string
 	reviewCommentsFlag []string
+	reviewTemplateVars []string
 )
 
 var reviewCmd = &cobra.Command{
@@ -33,6 +34,10 @@ var reviewCmd = &cobra.Command{
 		where you want to comment on multiple code locations and submit a review
 		decision (APPROVE/REQUEST_CHANGES/COMMENT) in one operation.
 
+		If the repository has a .gh-comment/policy.yaml, the review is checked
+		against it before anything is sent; use --override-policy to bypass a
+		blocking rule.
+
 		For general PR discussion comments, use: 'gh comment add'
 	`),
 	Example: heredoc.Doc(`
@@ -63,6 +68,11 @@ var reviewCmd = &cobra.Command{
 		  --comment utils.go:45:"Consider using dependency injection pattern here" \
 		  --comment test-helpers.js:89:"Add integration tests for this critical business logic" \
 		  --event REQUEST_CHANGES
+
+		# Reuse snippets from the library (see 'gh comment templates list')
+		$ gh comment review 123 \
+		  --comment src/parse.go:42:@missing-test --var func=ParseConfig \
+		  --event COMMENT
 	`),
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runReview,
@@ -70,8 +80,10 @@ var reviewCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(reviewCmd)
+	addPolicyFlag(reviewCmd)
 	reviewCmd.Flags().StringVar(&reviewEventFlag, "event", "COMMENT", "Review event (APPROVE|REQUEST_CHANGES|COMMENT) (default: COMMENT)")
 	reviewCmd.Flags().StringArrayVar(&reviewCommentsFlag, "comment", []string{}, "Add comment in format file:line:message or file:start:end:message (default: empty)")
+	reviewCmd.Flags().StringArrayVar(&reviewTemplateVars, "var", nil, "Set a variable for snippets used as file:line:@name (key=value, repeatable)")
 }
 
 func runReview(cmd *cobra.Command, args []string) error {
@@ -135,6 +147,11 @@ func runReview(cmd *cobra.Command, args []string) error {
 		if err := validateCommentBody(body); err != nil {
 			return err
 		}
+
+		// Check for secrets and personal data before anything is sent
+		if body, err = scanCommentBody("review body", body); err != nil {
+			return err
+		}
 	}
 
 	// Get repository context
@@ -175,15 +192,25 @@ func runReview(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("invalid comment %d (%s): %w", i+1, commentSpec, err)
 		}
+		commentInput.Body, err = scanCommentBody(fmt.Sprintf("comment %d (%s)", i+1, describePolicyComment(policyComment{Path: commentInput.Path, Line: commentInput.Line})), commentInput.Body)
+		if err != nil {
+			return err
+		}
+		reviewCommentInputs = append(reviewCommentInputs, commentInput)
+	}
 
-		// Validate line exists in diff if validation is enabled
-		if validateDiff {
+	// Check the repository policy before anything is sent
+	if err := enforcePolicy(policySubject{Event: reviewEventFlag, Body: body, Comments: reviewPolicyComments(reviewCommentInputs)}); err != nil {
+		return err
+	}
+
+	// Validate lines exist in diff if validation is enabled
+	if validateDiff {
+		for i, commentInput := range reviewCommentInputs {
 			if err := validateCommentLine(reviewClient, owner, repoName, pr, commentInput); err != nil {
-				return fmt.Errorf("comment %d validation failed (%s): %w", i+1, commentSpec, err)
+				return fmt.Errorf("comment %d validation failed (%s): %w", i+1, reviewCommentsFlag[i], err)
 			}
 		}
-
-		reviewCommentInputs = append(reviewCommentInputs, commentInput)
 	}
 
 	if verbose {
@@ -310,6 +337,12 @@ func parseReviewCommentSpec(spec string) (github.ReviewCommentInput, error) {
 		return github.ReviewCommentInput{}, fmt.Errorf("message cannot be empty")
 	}
 
+	// A message of the form @name uses a snippet from the library
+	message, err := resolveSnippetReference(message, reviewTemplateVars)
+	if err != nil {
+		return github.ReviewCommentInput{}, err
+	}
+
 	// Validate file path
 	if err := validateFilePath(filePath); err != nil {
 		return github.ReviewCommentInput{}, err
@@ -353,6 +353,13 @@ func TestParseReviewCommentSpec(t *testing.T) {
 		wantErr        bool
 		expectedErrMsg string
 	}{
+		{
+			name:         "comment that only mentions someone",
+			spec:         "src/a.go:10:@alice",
+			expectedFile: "src/a.go",
+			expectedLine: 10,
+			expectedBody: "@alice",
+		},
 		{
 			name:         "single line comment",
 			spec:         "src/main.go:42:Fix this issue",
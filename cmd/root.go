@@ -9,6 +9,8 @@ import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/cli/go-gh/v2"
 	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
 )
 
 var (
@@ -52,16 +54,27 @@ var rootCmd = &cobra.Command{
 	Example: heredoc.Doc(`
 		Commands:
 		  add                     Add general PR discussion comments
+		  alias                   Create shortcuts for gh-comment commands
 		  batch                   Process multiple comments from YAML configuration
 		  close-pending-review    Submit GUI-created pending reviews
 		  edit                    Modify existing comments
+		  history                 Show a comment's edit history with word diffs
+		  hotspots                Rank files and functions by review feedback
 		  lines                   Show commentable lines in PR files
 		  list                    List and filter comments with advanced options
+		  migrate                 Copy review discussion from one PR to another
+		  notify                  Run notification rules while polling a PR
 		  prompts                 Get AI-powered code review prompts and best practices
 		  react                   Add or remove emoji reactions to comments
+		  reactions               Show who reacted to a comment or the PR
+		  reply                   Reply to any comment (threaded or quoted)
 		  resolve                 Resolve conversation threads
 		  review                  Create line-specific code reviews
 		  review-reply            Reply to review comments with text messages
+		  search                  Search comments across pull requests
+		  stats                   Show review statistics and reviewer metrics
+		  templates               List and preview reusable comment snippets
+		  watch                   Stream new, edited and resolved comments
 		  help                    Help about any command
 
 		Global Flags:
@@ -70,6 +83,7 @@ var rootCmd = &cobra.Command{
 		      --dry-run       Show what would be commented without executing
 		  -v, --verbose       Show detailed API interactions
 		      --validate      Validate line exists in diff before commenting (default false)
+		      --profile name  Apply a config profile (default: auto-select by repository)
 
 		Filtering Flags (list command):
 		      --author string     Filter by author (supports wildcards: 'user*')
@@ -162,7 +176,27 @@ var rootCmd = &cobra.Command{
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	return rootCmd.Execute()
+	// Aliases from the config file are expanded before cobra sees the arguments
+	if len(os.Args) > 1 {
+		expanded, shellCommand, err := expandCommandLine(os.Args[1:])
+		if err != nil {
+			return err
+		}
+		if shellCommand != "" {
+			return runShellAlias(os.Args[1], shellCommand, expanded)
+		}
+		if expanded != nil {
+			rootCmd.SetArgs(expanded)
+		}
+	}
+
+	err := rootCmd.Execute()
+
+	// Write the cassette if GH_COMMENT_RECORD_CASSETTE recorded the API calls
+	if closeErr := github.CloseRecordings(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
 }
 
 func init() {
@@ -170,6 +204,7 @@ func init() {
 	rootCmd.PersistentFlags().IntVarP(&prNumber, "pr", "p", 0, "PR number (default: auto-detect from branch)")
 	rootCmd.PersistentFlags().StringVarP(&repo, "repo", "R", "", "Repository in owner/repo format (default: auto-detect from current directory)")
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Configuration file path (default: search standard locations)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Configuration profile to apply (default: auto-select by repository owner or remote)")
 
 	rootCmd.PersistentFlags().BoolVar(&validateDiff, "validate", false, "Validate line exists in diff before commenting (default: false)")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show what would be commented without executing (default: false)")
@@ -194,6 +229,7 @@ func initializeConfig() {
 	}
 
 	config := GetConfig()
+	applyFlagOverrides(config, GetConfigSources(), rootCmd.PersistentFlags())
 
 	// Apply config defaults if flags weren't explicitly set
 	applyConfigDefaults(config)
@@ -256,3 +292,13 @@ func getCurrentPR() (int, error) {
 
 	return pr, nil
 }
+
+// getCurrentUser returns the login of the authenticated GitHub user
+func getCurrentUser() (string, error) {
+	stdout, _, err := gh.Exec("api", "user", "--jq", ".login")
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w (try 'gh auth status')", err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
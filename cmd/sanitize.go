@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kinds of unsafe content reported by findUnsafeMarkdown
+const (
+	UnsafeElement   = "dangerous HTML tags detected"
+	UnsafeAttribute = "dangerous attributes detected"
+	UnsafeScript    = "JavaScript content detected"
+	UnsafeURL       = "unsafe URL detected"
+)
+
+// unsafeContent is raw HTML or a link in a comment body that GitHub would not render safely
+type unsafeContent struct {
+	Offset int // byte offset in the body
+	Line   int
+	Column int // in characters, starting at 1
+	Kind   string
+	Detail string
+}
+
+// allowedHTMLElements are the raw HTML elements allowed outside code, following
+// the elements GitHub keeps when it renders comments
+var allowedHTMLElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdo": true, "blockquote": true, "br": true,
+	"caption": true, "cite": true, "code": true, "dd": true, "del": true, "details": true,
+	"dfn": true, "div": true, "dl": true, "dt": true, "em": true, "figcaption": true,
+	"figure": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "i": true, "img": true, "ins": true, "kbd": true, "li": true, "mark": true,
+	"ol": true, "p": true, "picture": true, "pre": true, "q": true, "rp": true, "rt": true,
+	"ruby": true, "s": true, "samp": true, "small": true, "source": true, "span": true,
+	"strike": true, "strong": true, "sub": true, "summary": true, "sup": true, "table": true,
+	"tbody": true, "td": true, "tfoot": true, "th": true, "thead": true, "time": true,
+	"tr": true, "tt": true, "u": true, "ul": true, "var": true, "wbr": true,
+}
+
+// allowedHTMLAttributes are allowed on every allowed element
+var allowedHTMLAttributes = map[string]bool{
+	"abbr": true, "align": true, "alt": true, "aria-describedby": true, "aria-hidden": true,
+	"aria-label": true, "aria-labelledby": true, "axis": true, "border": true,
+	"cellpadding": true, "cellspacing": true, "clear": true, "colspan": true, "color": true,
+	"compact": true, "datetime": true, "dir": true, "height": true, "hreflang": true,
+	"hspace": true, "id": true, "itemprop": true, "lang": true, "media": true, "name": true,
+	"nowrap": true, "open": true, "rel": true, "rev": true, "role": true, "rowspan": true,
+	"scope": true, "span": true, "start": true, "summary": true, "tabindex": true,
+	"target": true, "title": true, "type": true, "valign": true, "value": true,
+	"vspace": true, "width": true,
+}
+
+// allowedElementAttributes are URL attributes allowed on particular elements
+var allowedElementAttributes = map[string][]string{
+	"a":          {"href"},
+	"img":        {"src", "srcset", "longdesc"},
+	"source":     {"src", "srcset"},
+	"blockquote": {"cite"},
+	"q":          {"cite"},
+	"del":        {"cite"},
+	"ins":        {"cite"},
+}
+
+// scriptURLSchemes run code when a link is followed
+var scriptURLSchemes = []string{"javascript", "vbscript", "livescript", "mocha"}
+
+var (
+	// HTML comments, which browsers never execute
+	sanitizeComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+	// Raw HTML as defined by CommonMark
+	sanitizeAttribute = "[A-Za-z_:][A-Za-z0-9_.:-]*(?:\\s*=\\s*(?:[^\\s\"'=<>`]+|'[^']*'|\"[^\"]*\"))?"
+	sanitizeOpenTag   = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9-]*)((?:\s+` + sanitizeAttribute + `)*)\s*/?>`)
+	sanitizeTagAttr   = regexp.MustCompile("([A-Za-z_:][A-Za-z0-9_.:-]*)(?:\\s*=\\s*([^\\s\"'=<>`]+|'[^']*'|\"[^\"]*\"))?")
+	sanitizeCloseTag  = regexp.MustCompile(`^</([A-Za-z][A-Za-z0-9-]*)\s*>`)
+	sanitizeAutolink  = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+
+	// A tag name at the start of a line begins an HTML block even when the tag isn't well formed
+	sanitizeBlockStart = regexp.MustCompile(`^</?([A-Za-z][A-Za-z0-9-]*)(?:\s|/?>|$)`)
+	sanitizeLinePrefix = regexp.MustCompile(`^(?: {0,3}(?:>|[-*+]|\d{1,9}[.)]) ?)* {0,3}$`)
+
+	// Markdown link and image destinations, inline and in reference definitions
+	sanitizeLinkTarget = regexp.MustCompile(`\]\(\s*(<[^>\n]*>|[^\s)]+)`)
+	sanitizeReference  = regexp.MustCompile(`(?m)^[ \t>]*\[[^\]\n]+\]:[ \t]*(<[^>\n]*>|\S+)`)
+
+	sanitizeURLScheme = regexp.MustCompile(`^([a-z][a-z0-9+.-]*):`)
+)
+
+// findUnsafeMarkdown parses body as GitHub markdown and reports raw HTML and links
+// that could run script or load unsafe content. Anything inside code spans and
+// fenced code blocks (including suggestion blocks) is allowed; outside code, raw
+// HTML must use allowed elements and attributes. Indented code blocks are checked
+// like text, so code samples with HTML need fences or backticks.
+func findUnsafeMarkdown(body string) []unsafeContent {
+	text := maskMarkdownCode(body)
+	text = sanitizeComment.ReplaceAllStringFunc(text, blankOut)
+
+	var issues []unsafeContent
+	report := func(offset int, kind, detail string) {
+		line := strings.Count(body[:offset], "\n") + 1
+		column := utf8.RuneCountInString(body[strings.LastIndex(body[:offset], "\n")+1:offset]) + 1
+		issues = append(issues, unsafeContent{Offset: offset, Line: line, Column: column, Kind: kind, Detail: detail})
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '<' || isMarkdownEscaped(text, i) {
+			continue
+		}
+		rest := text[i:]
+
+		if m := sanitizeAutolink.FindStringSubmatch(rest); m != nil {
+			checkUnsafeURL(m[1], "link", i, report)
+			i += len(m[0]) - 1
+			continue
+		}
+
+		if m := sanitizeCloseTag.FindStringSubmatch(rest); m != nil {
+			name := strings.ToLower(m[1])
+			if !allowedHTMLElements[name] {
+				report(i, UnsafeElement, fmt.Sprintf("</%s> is not an allowed HTML element", name))
+			}
+			i += len(m[0]) - 1
+			continue
+		}
+
+		if loc := sanitizeOpenTag.FindStringSubmatchIndex(rest); loc != nil {
+			name := strings.ToLower(rest[loc[2]:loc[3]])
+			for _, attr := range sanitizeTagAttr.FindAllStringSubmatchIndex(rest[loc[4]:loc[5]], -1) {
+				offset := i + loc[4] + attr[0]
+				attrs := rest[loc[4]:loc[5]]
+				attrName := strings.ToLower(attrs[attr[2]:attr[3]])
+				value := ""
+				if attr[4] >= 0 {
+					value = strings.Trim(attrs[attr[4]:attr[5]], `"'`)
+				}
+				if scheme := urlScheme(value); containsString(scriptURLSchemes, scheme) {
+					report(offset, UnsafeScript, fmt.Sprintf("%s: URL in %s attribute of <%s>", scheme, attrName, name))
+				} else if scheme == "data" && isHTMLURLAttribute(attrName) {
+					report(offset, UnsafeURL, fmt.Sprintf("data: URL in %s attribute of <%s>", attrName, name))
+				}
+				if !isAllowedHTMLAttribute(name, attrName) {
+					report(offset, UnsafeAttribute, fmt.Sprintf("attribute %s is not allowed on <%s>", attrName, name))
+				}
+			}
+			if !allowedHTMLElements[name] {
+				report(i, UnsafeElement, fmt.Sprintf("<%s> is not an allowed HTML element", name))
+			}
+			i += loc[1] - 1
+			continue
+		}
+
+		// An unfinished tag at the start of a line still starts an HTML block
+		lineStart := strings.LastIndex(text[:i], "\n") + 1
+		if sanitizeLinePrefix.MatchString(text[lineStart:i]) {
+			if m := sanitizeBlockStart.FindStringSubmatch(rest); m != nil && !allowedHTMLElements[strings.ToLower(m[1])] {
+				report(i, UnsafeElement, fmt.Sprintf("<%s> is not an allowed HTML element", strings.ToLower(m[1])))
+			}
+		}
+	}
+
+	for _, pattern := range []*regexp.Regexp{sanitizeLinkTarget, sanitizeReference} {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			target := strings.TrimSuffix(strings.TrimPrefix(text[loc[2]:loc[3]], "<"), ">")
+			checkUnsafeURL(target, "link", loc[2], report)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Offset < issues[j].Offset })
+	return issues
+}
+
+// checkUnsafeURL reports script and data URLs
+func checkUnsafeURL(target, what string, offset int, report func(int, string, string)) {
+	switch scheme := urlScheme(target); {
+	case containsString(scriptURLSchemes, scheme):
+		report(offset, UnsafeScript, fmt.Sprintf("%s: URL in %s", scheme, what))
+	case scheme == "data":
+		report(offset, UnsafeURL, fmt.Sprintf("data: URL in %s", what))
+	}
+}
+
+// urlScheme returns the lower-case scheme of a URL as a browser would read it:
+// after decoding entities and backslash escapes and dropping whitespace and
+// control characters. Relative URLs have no scheme.
+func urlScheme(raw string) string {
+	decoded := html.UnescapeString(markdownUnescape(raw))
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, decoded)
+	if m := sanitizeURLScheme.FindStringSubmatch(strings.ToLower(cleaned)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// isAllowedHTMLAttribute reports whether attr may be used on an allowed element
+func isAllowedHTMLAttribute(element, attr string) bool {
+	return allowedHTMLAttributes[attr] || containsString(allowedElementAttributes[element], attr)
+}
+
+// isHTMLURLAttribute reports whether a browser loads or follows the attribute's value
+func isHTMLURLAttribute(attr string) bool {
+	switch attr {
+	case "href", "src", "srcset", "longdesc", "cite", "action", "formaction", "poster", "background", "data":
+		return true
+	}
+	return false
+}
+
+// maskMarkdownCode blanks out fenced code blocks and code spans, keeping byte
+// offsets and line breaks, so what remains is the text GitHub renders as markdown.
+// Blocks are found with parseMarkdownBlocks, so what counts as code here is what
+// list and export render as code; fences inside blockquotes end when the quote does.
+func maskMarkdownCode(body string) string {
+	masked := []byte(body)
+	var lines []string
+	var offsets []int
+	offset := 0
+	for _, line := range strings.SplitAfter(body, "\n") {
+		lines = append(lines, strings.TrimRight(line, "\r\n"))
+		offsets = append(offsets, offset)
+		offset += len(line)
+	}
+	maskCodeLines(masked, lines, offsets)
+	return string(masked)
+}
+
+// maskCodeLines masks the code in lines, which start at offsets in masked.
+// Quotes are masked like a body of their own, as they are rendered.
+func maskCodeLines(masked []byte, lines []string, offsets []int) {
+	for _, block := range parseMarkdownBlocks(strings.Join(lines, "\n")) {
+		switch block.Kind {
+		case MarkdownCode:
+			for i := block.Start; i < block.End; i++ {
+				blankRange(masked, offsets[i], offsets[i]+len(lines[i]))
+			}
+		case MarkdownQuote:
+			inner := make([]string, 0, block.End-block.Start)
+			innerOffsets := make([]int, 0, block.End-block.Start)
+			for i := block.Start; i < block.End; i++ {
+				prefix := mdQuotePattern.FindStringSubmatchIndex(lines[i])[2]
+				inner = append(inner, lines[i][prefix:])
+				innerOffsets = append(innerOffsets, offsets[i]+prefix)
+			}
+			maskCodeLines(masked, inner, innerOffsets)
+		default:
+			for i := block.Start; i < block.End; i++ {
+				maskCodeSpans(masked, offsets[i], lines[i])
+			}
+		}
+	}
+}
+
+// maskCodeSpans blanks out the code spans of one line. A backtick run opens a
+// span only when the same number of backticks closes it later on the line.
+func maskCodeSpans(masked []byte, offset int, line string) {
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && isASCIIPunct(line[i+1]):
+			i += 2
+		case line[i] == '`':
+			n := backtickRun(line, i)
+			end := -1
+			for j := i + n; j < len(line); {
+				if line[j] != '`' {
+					j++
+					continue
+				}
+				m := backtickRun(line, j)
+				if m == n {
+					end = j + m
+					break
+				}
+				j += m
+			}
+			if end < 0 {
+				i += n
+				continue
+			}
+			blankRange(masked, offset+i, offset+end)
+			i = end
+		default:
+			i++
+		}
+	}
+}
+
+// backtickRun returns the number of backticks starting at i
+func backtickRun(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// blankRange replaces bytes with spaces, keeping line breaks
+func blankRange(b []byte, start, end int) {
+	for i := start; i < end; i++ {
+		if b[i] != '\n' {
+			b[i] = ' '
+		}
+	}
+}
+
+// blankOut returns s with every byte but line breaks replaced by a space
+func blankOut(s string) string {
+	b := []byte(s)
+	blankRange(b, 0, len(b))
+	return string(b)
+}
+
+// isMarkdownEscaped reports whether the character at i follows an odd number of backslashes
+func isMarkdownEscaped(s string, i int) bool {
+	n := 0
+	for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// markdownUnescape removes backslash escapes from ASCII punctuation
+func markdownUnescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// isASCIIPunct reports whether c is ASCII punctuation, which markdown lets you escape
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
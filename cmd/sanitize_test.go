@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUnsafeMarkdownAllowsCode(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"code span", "Don't use `<script>alert(1)</script>` here"},
+		{"double backtick span", "Write ``<a href=`javascript:x`>`` instead"},
+		{"fence", "```html\n<iframe src=\"javascript:alert(1)\"></iframe>\n```"},
+		{"tilde fence", "~~~\n<button onclick=\"go()\">\n~~~"},
+		{"suggestion block", "```suggestion\n<input type=\"text\" onchange={handleChange} />\n```"},
+		{"longer closing fence", "````\n<script>\n```\nstill code\n`````"},
+		{"quoted fence", "> ```jsx\n> <form onSubmit={save}>\n> ```"},
+		{"unclosed fence runs to the end", "```\n<script>"},
+		{"backticks don't close a tilde fence", "~~~\n```\n<script>\n~~~"},
+		{"quoted fence with offsets", "> text `<b onclick=x>`\n>\n> ~~~\n> <script>"},
+		{"allowed html", "<details><summary>Logs</summary>\n\n<sub>see <kbd>Ctrl</kbd>+<kbd>C</kbd></sub>\n</details>"},
+		{"allowed attributes", `<img src="https://example.com/a.png" alt="diagram" width="200"> <a href="#section" title="jump">link</a>`},
+		{"html comment", "<!-- <script>alert(1)</script> -->\nLooks good"},
+		{"generics with commas", "Return Result<T, E> from this"},
+		{"escaped tag", `Type \<script> literally`},
+		{"web links", "[docs](https://example.com) and <https://example.com> and [mail](mailto:a@b.c)"},
+		{"prose mention of javascript:", "Avoid javascript: URLs in templates"},
+		{"comparison", "if a < b and c > d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Empty(t, findUnsafeMarkdown(tt.body))
+			assert.NoError(t, validateCommentSecurity(tt.body))
+		})
+	}
+}
+
+func TestFindUnsafeMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []unsafeContent
+	}{
+		{
+			name: "backticks in the info string make it inline code",
+			body: "```js`\n<script>",
+			want: []unsafeContent{
+				{Offset: 7, Line: 2, Column: 1, Kind: UnsafeElement, Detail: "<script> is not an allowed HTML element"},
+			},
+		},
+		{
+			name: "indented four spaces is not a fence",
+			body: "    ```\n<script>\n```",
+			want: []unsafeContent{
+				{Offset: 8, Line: 2, Column: 1, Kind: UnsafeElement, Detail: "<script> is not an allowed HTML element"},
+			},
+		},
+		{
+			name: "script after code span",
+			body: "Use `x` then <script>alert(1)</script>",
+			want: []unsafeContent{
+				{Offset: 13, Line: 1, Column: 14, Kind: UnsafeElement, Detail: "<script> is not an allowed HTML element"},
+				{Offset: 29, Line: 1, Column: 30, Kind: UnsafeElement, Detail: "</script> is not an allowed HTML element"},
+			},
+		},
+		{
+			name: "event handler on allowed element",
+			body: "ok\n  <img src=x.png onerror=\"steal()\">",
+			want: []unsafeContent{
+				{Offset: 20, Line: 2, Column: 18, Kind: UnsafeAttribute, Detail: "attribute onerror is not allowed on <img>"},
+			},
+		},
+		{
+			name: "obfuscated javascript URL",
+			body: `<a href="jav&#x09;ascript:alert(1)">x</a>`,
+			want: []unsafeContent{
+				{Offset: 3, Line: 1, Column: 4, Kind: UnsafeScript, Detail: "javascript: URL in href attribute of <a>"},
+			},
+		},
+		{
+			name: "markdown link",
+			body: "[click](javascript:alert(1)) and ![img](data:image/png;base64,AAAA)",
+			want: []unsafeContent{
+				{Offset: 8, Line: 1, Column: 9, Kind: UnsafeScript, Detail: "javascript: URL in link"},
+				{Offset: 40, Line: 1, Column: 41, Kind: UnsafeURL, Detail: "data: URL in link"},
+			},
+		},
+		{
+			name: "reference definition and autolink",
+			body: "[x]\n\n[x]: JAVASCRIPT:go()\n<vbscript:msgbox>",
+			want: []unsafeContent{
+				{Offset: 10, Line: 3, Column: 6, Kind: UnsafeScript, Detail: "javascript: URL in link"},
+				{Offset: 26, Line: 4, Column: 1, Kind: UnsafeScript, Detail: "vbscript: URL in link"},
+			},
+		},
+		{
+			name: "unfinished tag starting an html block",
+			body: "Intro\n<script\nalert(1)",
+			want: []unsafeContent{
+				{Offset: 6, Line: 2, Column: 1, Kind: UnsafeElement, Detail: "<script> is not an allowed HTML element"},
+			},
+		},
+		{
+			name: "code span does not hide html on the next line",
+			body: "`open\n<iframe src=x>`",
+			want: []unsafeContent{
+				{Offset: 6, Line: 2, Column: 1, Kind: UnsafeElement, Detail: "<iframe> is not an allowed HTML element"},
+				{Offset: 14, Line: 2, Column: 9, Kind: UnsafeAttribute, Detail: "attribute src is not allowed on <iframe>"},
+			},
+		},
+		{
+			name: "escaped backtick is not a code span",
+			body: "\\`<script>`",
+			want: []unsafeContent{
+				{Offset: 2, Line: 1, Column: 3, Kind: UnsafeElement, Detail: "<script> is not an allowed HTML element"},
+			},
+		},
+		{
+			name: "quote ends the fence",
+			body: "> ```\n> code\n<style>",
+			want: []unsafeContent{
+				{Offset: 13, Line: 3, Column: 1, Kind: UnsafeElement, Detail: "<style> is not an allowed HTML element"},
+			},
+		},
+		{
+			name: "columns count characters",
+			body: "héllo <form>",
+			want: []unsafeContent{
+				{Offset: 7, Line: 1, Column: 7, Kind: UnsafeElement, Detail: "<form> is not an allowed HTML element"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findUnsafeMarkdown(tt.body))
+		})
+	}
+}
+
+func TestValidateCommentSecurityReportsPositions(t *testing.T) {
+	err := validateCommentSecurity("Fine\n\n```\n<script>\n```\n<a href='javascript:x' onclick='y'>z</a>")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "🔒 security validation failed for comment body: JavaScript content detected; dangerous attributes detected\n")
+	assert.Contains(t, err.Error(), "  • line 6, column 4: javascript: URL in href attribute of <a>\n")
+	assert.Contains(t, err.Error(), "  • line 6, column 24: attribute onclick is not allowed on <a>\n")
+	assert.NotContains(t, err.Error(), "line 4")
+
+	body := ""
+	for i := 0; i < 12; i++ {
+		body += "<iframe>\n"
+	}
+	err = validateCommentSecurity(body)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "  • line 10, column 1: <iframe> is not an allowed HTML element\n  • ... and 2 more")
+}
@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Actions taken on scanner findings
+const (
+	ScanBlock  = "block"
+	ScanRedact = "redact"
+	ScanWarn   = "warn"
+	ScanOff    = "off"
+)
+
+// scanActions are the valid actions, in order of severity
+var scanActions = []string{ScanWarn, ScanRedact, ScanBlock}
+
+// secretDetector finds one kind of sensitive content in a comment body
+type secretDetector struct {
+	Name          string
+	DefaultAction string
+
+	// pattern finds candidates; a group named 'secret' narrows the finding to that part
+	pattern *regexp.Regexp
+	// ignore drops candidates that are known to be harmless
+	ignore *regexp.Regexp
+	// check, when set, decides whether a candidate is a finding
+	check func(candidate string) bool
+}
+
+// builtinDetectors are always available; scan.detectors can change their action
+var builtinDetectors = []secretDetector{
+	{
+		Name:          "private-key",
+		DefaultAction: ScanBlock,
+		pattern:       regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----(?s:.*?)(?:-----END [A-Z0-9 ]*PRIVATE KEY-----|\z)`),
+	},
+	{
+		Name:          "github-token",
+		DefaultAction: ScanBlock,
+		pattern:       regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`),
+	},
+	{
+		Name:          "aws-access-key",
+		DefaultAction: ScanBlock,
+		pattern:       regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`),
+	},
+	{
+		Name:          "aws-secret-key",
+		DefaultAction: ScanBlock,
+		pattern:       regexp.MustCompile(`(?i)aws\w{0,20}?(?:secret|key)\w{0,20}\W{0,3}\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+]{40})\b`),
+	},
+	{
+		Name:          "slack-token",
+		DefaultAction: ScanBlock,
+		pattern:       regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}|https://hooks\.slack\.com/services/[A-Za-z0-9_]+/[A-Za-z0-9_]+/[A-Za-z0-9_]+`),
+	},
+	{
+		Name:          "connection-string",
+		DefaultAction: ScanBlock,
+		pattern:       regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.-]*://[^\s:/@]+:(?P<secret>[^\s@/]+)@[^\s/]+`),
+		ignore:        regexp.MustCompile(`(?i)^(?:\$\{?\w+\}?|<[^>]*>|\{\{[^}]*\}\}|\*+|x+|\.+|password|pass|secret)$`),
+	},
+	{
+		Name:          "email",
+		DefaultAction: ScanWarn,
+		pattern:       regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`),
+		ignore:        regexp.MustCompile(`(?i)^git@|@(?:example\.(?:com|org|net)|users\.noreply\.github\.com)$`),
+	},
+	{
+		Name:          "entropy",
+		DefaultAction: ScanWarn,
+		pattern:       regexp.MustCompile(`[A-Za-z0-9+/=_-]{24,}`),
+		check:         isHighEntropy,
+	},
+}
+
+// scanFinding is one piece of sensitive content found in a body
+type scanFinding struct {
+	Detector string
+	Action   string
+	Start    int
+	End      int
+	Line     int
+	order    int // detector position, to prefer earlier detectors on overlap
+}
+
+// compiledScanner holds the detectors with their effective actions
+type compiledScanner struct {
+	detectors []compiledDetector
+	allow     []*regexp.Regexp
+}
+
+type compiledDetector struct {
+	secretDetector
+	action string
+}
+
+// compileScanner resolves the scan configuration into detectors. A disabled
+// scanner has no detectors and finds nothing.
+func compileScanner(config ScanConfig) (*compiledScanner, error) {
+	if config.Action != "" && !containsString(scanActions, config.Action) {
+		return nil, fmt.Errorf("invalid scan action '%s' (must be %s)", config.Action, strings.Join(scanActions, ", "))
+	}
+
+	scanner := &compiledScanner{}
+	for _, pattern := range config.Allow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scan allow pattern '%s': %w", pattern, err)
+		}
+		scanner.allow = append(scanner.allow, re)
+	}
+
+	for name, action := range config.Detectors {
+		if findBuiltinDetector(name) == nil {
+			return nil, fmt.Errorf("unknown scan detector '%s' (available: %s)", name, strings.Join(builtinDetectorNames(), ", "))
+		}
+		if action != ScanOff && !containsString(scanActions, action) {
+			return nil, fmt.Errorf("invalid action '%s' for scan detector %s (must be %s or %s)", action, name, strings.Join(scanActions, ", "), ScanOff)
+		}
+	}
+
+	for _, detector := range builtinDetectors {
+		action := detector.DefaultAction
+		if config.Action != "" {
+			action = config.Action
+		}
+		if override, ok := config.Detectors[detector.Name]; ok {
+			action = override
+		}
+		if action != ScanOff {
+			scanner.detectors = append(scanner.detectors, compiledDetector{secretDetector: detector, action: action})
+		}
+	}
+
+	for i, custom := range config.Custom {
+		name := custom.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if findBuiltinDetector(name) != nil {
+			return nil, fmt.Errorf("custom scan pattern %s: name is already used by a built-in detector", name)
+		}
+		if custom.Pattern == "" {
+			return nil, fmt.Errorf("custom scan pattern %s: pattern is required", name)
+		}
+		re, err := regexp.Compile(custom.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("custom scan pattern %s: invalid pattern: %w", name, err)
+		}
+		action := custom.Action
+		if action == "" {
+			action = config.Action
+		}
+		if action == "" {
+			action = ScanBlock
+		}
+		if !containsString(scanActions, action) {
+			return nil, fmt.Errorf("custom scan pattern %s: invalid action '%s' (must be %s)", name, action, strings.Join(scanActions, ", "))
+		}
+		scanner.detectors = append(scanner.detectors, compiledDetector{
+			secretDetector: secretDetector{Name: name, pattern: re},
+			action:         action,
+		})
+	}
+
+	if !config.Enabled {
+		scanner.detectors = nil
+	}
+	return scanner, nil
+}
+
+// scan returns the findings in body, ordered by position. Where findings overlap
+// only the most severe is kept, then the longest, then the earliest detector's.
+func (s *compiledScanner) scan(body string) []scanFinding {
+	var candidates []scanFinding
+	for order, detector := range s.detectors {
+		secret := detector.pattern.SubexpIndex("secret")
+		for _, loc := range detector.pattern.FindAllStringSubmatchIndex(body, -1) {
+			start, end := loc[0], loc[1]
+			if secret > 0 && loc[2*secret] >= 0 {
+				start, end = loc[2*secret], loc[2*secret+1]
+			}
+			if start == end {
+				continue
+			}
+			match := body[start:end]
+			if detector.ignore != nil && detector.ignore.MatchString(match) {
+				continue
+			}
+			if detector.check != nil && !detector.check(match) {
+				continue
+			}
+			if s.allowed(match) {
+				continue
+			}
+			candidates = append(candidates, scanFinding{
+				Detector: detector.Name,
+				Action:   detector.action,
+				Start:    start,
+				End:      end,
+				Line:     strings.Count(body[:start], "\n") + 1,
+				order:    order,
+			})
+		}
+	}
+
+	severity := func(action string) int {
+		for i, a := range scanActions {
+			if a == action {
+				return i
+			}
+		}
+		return -1
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if severity(a.Action) != severity(b.Action) {
+			return severity(a.Action) > severity(b.Action)
+		}
+		if a.End-a.Start != b.End-b.Start {
+			return a.End-a.Start > b.End-b.Start
+		}
+		return a.order < b.order
+	})
+
+	var findings []scanFinding
+	for _, candidate := range candidates {
+		overlaps := false
+		for _, kept := range findings {
+			if candidate.Start < kept.End && kept.Start < candidate.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			findings = append(findings, candidate)
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Start < findings[j].Start })
+	return findings
+}
+
+// allowed reports whether an allow pattern matches the finding
+func (s *compiledScanner) allowed(match string) bool {
+	for _, re := range s.allow {
+		if re.MatchString(match) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanCommentBody checks an outbound body for secrets and personal data using the
+// scan configuration. Blocking findings return an error, redacted findings are
+// replaced in the returned body, and warnings are printed to stderr.
+// what describes the body in messages, for example "review body".
+func scanCommentBody(what, body string) (string, error) {
+	scanner, err := compileScanner(GetConfig().Scan)
+	if err != nil {
+		return "", err
+	}
+	findings := scanner.scan(body)
+	if len(findings) == 0 {
+		return body, nil
+	}
+
+	var blocked []string
+	for _, finding := range findings {
+		if finding.Action == ScanBlock {
+			blocked = append(blocked, fmt.Sprintf("  • %s on line %d: %s", finding.Detector, finding.Line, maskSecret(body[finding.Start:finding.End])))
+		}
+	}
+	if len(blocked) > 0 {
+		return "", fmt.Errorf("%s looks like it contains secrets or personal data:\n%s\n\n💡 Remove them before posting, or adjust scan.detectors, scan.allow or scan.action in your config",
+			what, strings.Join(blocked, "\n"))
+	}
+
+	var redacted strings.Builder
+	last := 0
+	for _, finding := range findings {
+		switch finding.Action {
+		case ScanRedact:
+			redacted.WriteString(body[last:finding.Start])
+			redacted.WriteString(redactionMarker(finding.Detector))
+			last = finding.End
+			fmt.Fprintf(os.Stderr, "⚠️  Redacted %s in %s (line %d)\n", finding.Detector, what, finding.Line)
+		case ScanWarn:
+			fmt.Fprintf(os.Stderr, "⚠️  Possible %s in %s (line %d): %s\n", finding.Detector, what, finding.Line, maskSecret(body[finding.Start:finding.End]))
+		}
+	}
+	redacted.WriteString(body[last:])
+	return redacted.String(), nil
+}
+
+// redactionMarker replaces redacted content
+func redactionMarker(detector string) string {
+	return "[REDACTED:" + detector + "]"
+}
+
+// maskSecret shows enough of a finding to locate it without repeating it
+func maskSecret(s string) string {
+	s = strings.SplitN(s, "\n", 2)[0]
+	runes := []rune(s)
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + "****" + fmt.Sprintf(" (%d chars)", len(runes))
+}
+
+// isHighEntropy reports whether s looks like random key material: mixed case
+// letters and digits with high Shannon entropy. Hex strings such as commit SHAs
+// are never reported.
+func isHighEntropy(s string) bool {
+	if hexPattern.MatchString(s) {
+		return false
+	}
+	var upper, lower, digit bool
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+	return shannonEntropy(counts, len(s)) >= 4.0
+}
+
+var hexPattern = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+// shannonEntropy returns the entropy in bits per character
+func shannonEntropy(counts map[rune]int, length int) float64 {
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(length)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// findBuiltinDetector returns the built-in detector with the given name, or nil
+func findBuiltinDetector(name string) *secretDetector {
+	for i := range builtinDetectors {
+		if builtinDetectors[i].Name == name {
+			return &builtinDetectors[i]
+		}
+	}
+	return nil
+}
+
+// builtinDetectorNames lists the built-in detectors
+func builtinDetectorNames() []string {
+	names := make([]string, len(builtinDetectors))
+	for i, detector := range builtinDetectors {
+		names[i] = detector.Name
+	}
+	return names
+}
+
+// scanBatchConfig scans the review body and every comment of a batch file,
+// redacting in place
+func scanBatchConfig(config *BatchConfig) error {
+	if config.Review != nil && config.Review.Body != "" {
+		body, err := scanCommentBody("review body", config.Review.Body)
+		if err != nil {
+			return err
+		}
+		config.Review.Body = body
+	}
+	for i := range config.Comments {
+		comment := &config.Comments[i]
+		what := fmt.Sprintf("comment %d", i+1)
+		if comment.File != "" {
+			what = fmt.Sprintf("comment %d (%s)", i+1, comment.File)
+		}
+		message, err := scanCommentBody(what, comment.Message)
+		if err != nil {
+			return err
+		}
+		comment.Message = message
+	}
+	return nil
+}
@@ -0,0 +1,565 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// Defaults for repository-wide searches
+const (
+	DefaultSearchLimit       = 50
+	DefaultSearchConcurrency = 4
+	MaxSearchConcurrency     = 16
+)
+
+var (
+	searchState       string
+	searchAuthor      string
+	searchPRAuthor    string
+	searchReviewer    string
+	searchLabels      []string
+	searchSince       string
+	searchUntil       string
+	searchType        string
+	searchStatus      string
+	searchLimit       int
+	searchConcurrency int
+	searchQuery       CommentQuery
+
+	// Output format flags
+	searchFormat   string
+	searchTemplate string
+	searchJQ       string
+
+	// Client for dependency injection (tests can override)
+	searchClient github.GitHubAPI
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search comments across pull requests in a repository",
+	Long: heredoc.Doc(`
+		Search comments across many pull requests in a repository.
+
+		PRs are selected with GitHub's search API using --state, --pr-author,
+		--reviewer, --label and the --since date window, then their comments are
+		fetched concurrently (bounded by --concurrency) and filtered with the same
+		options as 'gh comment list'.
+
+		When only review comments are requested across all PR states and no PR
+		filters are set, the repository-level pulls/comments endpoint is used
+		instead, which needs far fewer API calls. --limit then keeps the PRs with
+		the most recent review comments, and listing stops at the first comment
+		on a PR past the limit, so older comments on the kept PRs are left out.
+
+		Use --status open or --status resolved to filter review comments by the
+		resolution state of their thread. Use @me with --author, --pr-author or
+		--reviewer to refer to the authenticated user.
+
+		Output formats are shared with 'gh comment list': default, table, json,
+		csv, tsv, --template and --jq.
+	`),
+	Example: heredoc.Doc(`
+		# Unresolved review comments I left this month, across every PR
+		$ gh comment search --author @me --type review --status open --state all --since "1 month ago"
+
+		# Comments by the security team on open PRs
+		$ gh comment search --author "security-team*" --state open
+
+		# Review comments on PRs labelled 'backend' that mention TODO
+		$ gh comment search --label backend --type review --grep TODO --format table
+
+		# Comments on PRs I reviewed, as CSV
+		$ gh comment search --reviewer @me --since 2024-01-01 --format csv > reviewed.csv
+
+		# Fast repository-wide scan of review comments
+		$ gh comment search --type review --state all --since "2 weeks ago" --jq '.comments[].id'
+	`),
+	Args: cobra.NoArgs,
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	// PR selection flags
+	searchCmd.Flags().StringVar(&searchState, "state", "open", "PR state: open, closed, merged, all")
+	searchCmd.Flags().StringVar(&searchPRAuthor, "pr-author", "", "Only search PRs opened by this user (@me for yourself)")
+	searchCmd.Flags().StringVar(&searchReviewer, "reviewer", "", "Only search PRs reviewed by this user (@me for yourself)")
+	searchCmd.Flags().StringArrayVar(&searchLabels, "label", nil, "Only search PRs with this label (repeatable)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", DefaultSearchLimit, "Maximum number of PRs to search")
+	searchCmd.Flags().IntVar(&searchConcurrency, "concurrency", DefaultSearchConcurrency, "Number of PRs to fetch in parallel")
+
+	// Comment filter flags
+	searchCmd.Flags().StringVar(&searchAuthor, "author", "", "Filter by comment author (supports wildcards, @me for yourself)")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Show comments after this date/time (flexible formats)")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "Show comments before this date/time (flexible formats)")
+	searchCmd.Flags().StringVar(&searchType, "type", "", "Filter by type (issue|review)")
+	searchCmd.Flags().StringVar(&searchStatus, "status", "all", "Filter review comments by thread status: open, resolved, all")
+	addCommentQueryFlags(searchCmd.Flags(), &searchQuery)
+
+	// Output format flags
+	searchCmd.Flags().StringVar(&searchFormat, "format", FormatDefault, "Output format (default|table|json|csv|tsv)")
+	searchCmd.Flags().StringVar(&searchTemplate, "template", "", "Format each comment using a Go template")
+	searchCmd.Flags().StringVar(&searchJQ, "jq", "", "Filter JSON output using a jq expression")
+	searchCmd.Flags().BoolVar(&rawBodies, "raw", false, "Print comment bodies as-is instead of rendering markdown")
+	searchCmd.Flags().BoolVar(&hideAuthors, "hide-authors", false, "Hide comment authors in output")
+}
+
+// searchOptions holds the validated search criteria
+type searchOptions struct {
+	State       string
+	Author      string
+	PRAuthor    string
+	Reviewer    string
+	Labels      []string
+	Type        string
+	Status      string
+	Since       *time.Time
+	Until       *time.Time
+	Limit       int
+	Concurrency int
+	Query       *CommentQuery
+}
+
+// searchResult holds the comments found and the PRs they came from
+type searchResult struct {
+	Comments     []Comment
+	PullRequests map[int]github.PullRequest
+	Errors       []error
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	// Initialize client if not set (production use)
+	if searchClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		searchClient = client
+	}
+
+	opts, err := parseSearchOptions()
+	if err != nil {
+		return err
+	}
+
+	outputOpts := OutputOptions{Format: searchFormat, Template: searchTemplate, JQ: searchJQ, HideAuthors: hideAuthors}
+	if err := validateOutputOptions(outputOpts); err != nil {
+		return err
+	}
+
+	repository, err := getCurrentRepo()
+	if err != nil {
+		return err
+	}
+	if err := validateRepositoryName(repository); err != nil {
+		return err
+	}
+
+	// Resolve @me for the comment author filter; search qualifiers understand @me natively
+	if strings.EqualFold(opts.Author, "@me") {
+		login, err := getCurrentUser()
+		if err != nil {
+			return err
+		}
+		opts.Author = login
+	}
+
+	result, err := searchComments(searchClient, repository, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, fetchErr := range result.Errors {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", fetchErr)
+	}
+
+	if outputOpts.Template != "" || outputOpts.JQ != "" || (searchFormat != "" && searchFormat != FormatDefault) {
+		return renderComments(os.Stdout, result.Comments, searchJSONOutput(repository, result), outputOpts)
+	}
+
+	displaySearchResults(repository, result)
+	return nil
+}
+
+// parseSearchOptions validates the search flags
+func parseSearchOptions() (searchOptions, error) {
+	opts := searchOptions{
+		State:       strings.ToLower(searchState),
+		Author:      searchAuthor,
+		PRAuthor:    searchPRAuthor,
+		Reviewer:    searchReviewer,
+		Labels:      searchLabels,
+		Type:        searchType,
+		Status:      strings.ToLower(searchStatus),
+		Limit:       searchLimit,
+		Concurrency: searchConcurrency,
+		Query:       &searchQuery,
+	}
+
+	if !containsString([]string{"open", "closed", "merged", "all"}, opts.State) {
+		return opts, formatValidationError("state", searchState, "must be one of: open, closed, merged, all")
+	}
+	if !containsString([]string{"", "issue", "review"}, opts.Type) {
+		return opts, formatValidationError("type", searchType, "must be one of: issue, review")
+	}
+	if !containsString([]string{"all", "open", "resolved"}, opts.Status) {
+		return opts, formatValidationError("status", searchStatus, "must be one of: open, resolved, all")
+	}
+	if opts.Limit <= 0 {
+		return opts, formatValidationError("limit", fmt.Sprintf("%d", opts.Limit), "must be a positive integer")
+	}
+	if opts.Concurrency <= 0 || opts.Concurrency > MaxSearchConcurrency {
+		return opts, formatValidationError("concurrency", fmt.Sprintf("%d", opts.Concurrency),
+			fmt.Sprintf("must be between 1 and %d", MaxSearchConcurrency))
+	}
+
+	if searchSince != "" {
+		parsed, err := parseFlexibleDate(searchSince)
+		if err != nil {
+			return opts, fmt.Errorf("invalid since date '%s': %w", searchSince, err)
+		}
+		opts.Since = &parsed
+	}
+	if searchUntil != "" {
+		parsed, err := parseFlexibleDate(searchUntil)
+		if err != nil {
+			return opts, fmt.Errorf("invalid until date '%s': %w", searchUntil, err)
+		}
+		opts.Until = &parsed
+	}
+	if opts.Since != nil && opts.Until != nil && opts.Since.After(*opts.Until) {
+		return opts, fmt.Errorf("since date (%s) cannot be after until date (%s)", searchSince, searchUntil)
+	}
+
+	if err := opts.Query.Compile(); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+// searchComments finds comments matching opts across the repository's PRs
+func searchComments(client github.GitHubAPI, repository string, opts searchOptions) (*searchResult, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid repository format: %s (expected owner/repo)", repository)
+	}
+	owner, repoName := parts[0], parts[1]
+
+	var result *searchResult
+	var err error
+	if useRepoCommentsEndpoint(opts) {
+		result, err = searchRepoReviewComments(client, owner, repoName, opts)
+	} else {
+		result, err = searchPullRequestComments(client, repository, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []Comment
+	for _, comment := range result.Comments {
+		if matchesSearchFilters(comment, opts) {
+			filtered = append(filtered, comment)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if filtered[i].PR != filtered[j].PR {
+			return filtered[i].PR > filtered[j].PR
+		}
+		return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+	})
+
+	result.Comments = filtered
+	return result, nil
+}
+
+// useRepoCommentsEndpoint reports whether the repository-level review comment endpoint can answer the search
+func useRepoCommentsEndpoint(opts searchOptions) bool {
+	return opts.Type == "review" && opts.State == "all" && opts.Status == "all" &&
+		opts.PRAuthor == "" && opts.Reviewer == "" && len(opts.Labels) == 0
+}
+
+// searchRepoReviewComments lists review comments with the repository-level pulls/comments endpoint
+func searchRepoReviewComments(client github.GitHubAPI, owner, repoName string, opts searchOptions) (*searchResult, error) {
+	var since time.Time
+	if opts.Since != nil {
+		since = *opts.Since
+	}
+
+	if verbose {
+		fmt.Printf("Listing review comments in %s/%s via pulls/comments\n\n", owner, repoName)
+	}
+
+	// Comments come newest first, so stopping at the first comment on a PR past the
+	// limit keeps the most recently active PRs without paging the whole history
+	seen := make(map[string]bool)
+	reviewComments, err := client.ListRepoReviewComments(owner, repoName, since, func(comment github.Comment) bool {
+		if !seen[comment.PullRequestURL] && len(seen) >= opts.Limit {
+			return false
+		}
+		seen[comment.PullRequestURL] = true
+		return true
+	})
+	if err != nil {
+		return nil, formatActionableError("repository comment search", err)
+	}
+
+	result := &searchResult{PullRequests: make(map[int]github.PullRequest)}
+
+	converted := convertReviewComments(reviewComments)
+	for i, comment := range reviewComments {
+		pr, err := github.PRNumberFromURL(comment.PullRequestURL)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("skipping comment #%d: %w", comment.ID, err))
+			continue
+		}
+
+		result.PullRequests[pr] = github.PullRequest{Number: pr}
+		converted[i].PR = pr
+		result.Comments = append(result.Comments, converted[i])
+	}
+
+	return result, nil
+}
+
+// searchPullRequestComments selects PRs with the search API and fetches their comments concurrently
+func searchPullRequestComments(client github.GitHubAPI, repository string, opts searchOptions) (*searchResult, error) {
+	query := buildPRSearchQuery(repository, opts)
+	if verbose {
+		fmt.Printf("Search query: %s\n", query)
+		fmt.Printf("Concurrency: %d\n\n", opts.Concurrency)
+	}
+
+	pullRequests, err := client.SearchPullRequests(query, opts.Limit)
+	if err != nil {
+		return nil, formatActionableError("pull request search", err)
+	}
+
+	result := &searchResult{PullRequests: make(map[int]github.PullRequest)}
+	numbers := make([]int, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		result.PullRequests[pr.Number] = pr
+		numbers = append(numbers, pr.Number)
+	}
+
+	comments, errs := fetchCommentsForPRs(client, repository, numbers, opts.Concurrency, opts.Status != "all")
+	result.Comments = comments
+	result.Errors = errs
+
+	if len(numbers) > 0 && len(errs) == len(numbers) {
+		return nil, fmt.Errorf("failed to fetch comments for all %d PRs: %w", len(numbers), errs[0])
+	}
+
+	return result, nil
+}
+
+// fetchCommentsForPRs fetches comments for several PRs with at most concurrency requests in flight.
+// Results keep the order of prs; failures are returned per PR instead of aborting the whole search.
+func fetchCommentsForPRs(client github.GitHubAPI, repository string, prs []int, concurrency int, withThreads bool) ([]Comment, []error) {
+	parts := strings.Split(repository, "/")
+	owner, repoName := parts[0], parts[len(parts)-1]
+
+	perPR := make([][]Comment, len(prs))
+	errs := forEachPR(prs, concurrency, func(i, pr int) error {
+		comments, err := fetchAllComments(client, repository, pr)
+		if err != nil {
+			return err
+		}
+
+		if withThreads {
+			threads, err := client.ListReviewThreads(owner, repoName, pr)
+			if err != nil {
+				return fmt.Errorf("failed to fetch review threads: %w", err)
+			}
+			applyThreadState(comments, threads)
+		}
+
+		for j := range comments {
+			comments[j].PR = pr
+		}
+		perPR[i] = comments
+		return nil
+	})
+
+	var all []Comment
+	for _, comments := range perPR {
+		all = append(all, comments...)
+	}
+
+	return all, errs
+}
+
+// forEachPR calls fn for every PR with at most concurrency calls running at once.
+// fn receives the PR's index so results can be stored in order. Errors are
+// returned in PR order, prefixed with the PR number.
+func forEachPR(prs []int, concurrency int, fn func(i, pr int) error) []error {
+	perPRErr := make([]error, len(prs))
+	forEachBounded(len(prs), concurrency, func(i int) {
+		if err := fn(i, prs[i]); err != nil {
+			perPRErr[i] = fmt.Errorf("PR #%d: %w", prs[i], err)
+		}
+	})
+
+	var errs []error
+	for _, err := range perPRErr {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// forEachBounded calls fn for 0..n-1 with at most concurrency calls running at once
+func forEachBounded(n, concurrency int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// buildPRSearchQuery builds the GitHub search query used to select PRs
+func buildPRSearchQuery(repository string, opts searchOptions) string {
+	terms := []string{"repo:" + repository, "is:pr"}
+
+	switch opts.State {
+	case "open":
+		terms = append(terms, "is:open")
+	case "closed":
+		terms = append(terms, "is:closed")
+	case "merged":
+		terms = append(terms, "is:merged")
+	}
+
+	if opts.PRAuthor != "" {
+		terms = append(terms, "author:"+opts.PRAuthor)
+	}
+	if opts.Reviewer != "" {
+		terms = append(terms, "reviewed-by:"+opts.Reviewer)
+	}
+	for _, label := range opts.Labels {
+		if strings.ContainsAny(label, " \t") {
+			terms = append(terms, fmt.Sprintf("label:%q", label))
+		} else {
+			terms = append(terms, "label:"+label)
+		}
+	}
+
+	// A PR with comments in the window must have been updated since it started
+	if opts.Since != nil {
+		terms = append(terms, "updated:>="+opts.Since.UTC().Format("2006-01-02"))
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// matchesSearchFilters applies the comment-level search filters
+func matchesSearchFilters(comment Comment, opts searchOptions) bool {
+	if opts.Author != "" && !matchesAuthorFilter(comment.Author, opts.Author) {
+		return false
+	}
+	if opts.Type != "" && comment.Type != opts.Type {
+		return false
+	}
+	if opts.Since != nil && comment.CreatedAt.Before(*opts.Since) {
+		return false
+	}
+	if opts.Until != nil && comment.CreatedAt.After(*opts.Until) {
+		return false
+	}
+
+	switch opts.Status {
+	case "open":
+		if comment.Type != "review" || comment.Resolved {
+			return false
+		}
+	case "resolved":
+		if comment.Type != "review" || !comment.Resolved {
+			return false
+		}
+	}
+
+	if opts.Query != nil && !opts.Query.Matches(comment) {
+		return false
+	}
+
+	return true
+}
+
+// searchJSONOutput builds the JSON document for 'search --format json' and --jq
+func searchJSONOutput(repository string, result *searchResult) interface{} {
+	comments := result.Comments
+	if comments == nil {
+		comments = []Comment{}
+	}
+
+	return struct {
+		Repository   string    `json:"repository"`
+		PullRequests int       `json:"pull_requests"`
+		Total        int       `json:"total"`
+		Comments     []Comment `json:"comments"`
+	}{
+		Repository:   repository,
+		PullRequests: len(result.PullRequests),
+		Total:        len(comments),
+		Comments:     comments,
+	}
+}
+
+// displaySearchResults prints matching comments grouped by PR
+func displaySearchResults(repository string, result *searchResult) {
+	if len(result.Comments) == 0 {
+		fmt.Printf("No matching comments found in %s (%d PRs searched)\n", repository, len(result.PullRequests))
+		return
+	}
+
+	matchedPRs := 0
+	currentPR := 0
+	for _, comment := range result.Comments {
+		if comment.PR != currentPR {
+			matchedPRs++
+			currentPR = comment.PR
+		}
+	}
+
+	fmt.Printf("🔎 %d comments across %d PRs in %s (%d PRs searched)\n\n",
+		len(result.Comments), matchedPRs, repository, len(result.PullRequests))
+
+	currentPR = 0
+	for _, comment := range result.Comments {
+		if comment.PR != currentPR {
+			currentPR = comment.PR
+			header := fmt.Sprintf("PR #%d", currentPR)
+			if pr, ok := result.PullRequests[currentPR]; ok && pr.Title != "" {
+				header = fmt.Sprintf("%s · %s (%s)", header, pr.Title, pr.State)
+			}
+			fmt.Printf("%s\n\n", ColorizeHeader(header))
+		}
+		displayComment(comment)
+	}
+}
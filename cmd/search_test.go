@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/fakegithub"
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func TestBuildPRSearchQuery(t *testing.T) {
+	since := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		opts     searchOptions
+		expected string
+	}{
+		{
+			name:     "all states",
+			opts:     searchOptions{State: "all"},
+			expected: "repo:owner/repo is:pr",
+		},
+		{
+			name:     "open",
+			opts:     searchOptions{State: "open"},
+			expected: "repo:owner/repo is:pr is:open",
+		},
+		{
+			name:     "merged with author and reviewer",
+			opts:     searchOptions{State: "merged", PRAuthor: "alice", Reviewer: "@me"},
+			expected: "repo:owner/repo is:pr is:merged author:alice reviewed-by:@me",
+		},
+		{
+			name:     "labels and since",
+			opts:     searchOptions{State: "closed", Labels: []string{"backend", "needs review"}, Since: &since},
+			expected: `repo:owner/repo is:pr is:closed label:backend label:"needs review" updated:>=2024-03-01`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildPRSearchQuery("owner/repo", tt.opts))
+		})
+	}
+}
+
+func TestUseRepoCommentsEndpoint(t *testing.T) {
+	assert.True(t, useRepoCommentsEndpoint(searchOptions{Type: "review", State: "all", Status: "all"}))
+	assert.False(t, useRepoCommentsEndpoint(searchOptions{Type: "", State: "all", Status: "all"}))
+	assert.False(t, useRepoCommentsEndpoint(searchOptions{Type: "review", State: "open", Status: "all"}))
+	assert.False(t, useRepoCommentsEndpoint(searchOptions{Type: "review", State: "all", Status: "open"}))
+	assert.False(t, useRepoCommentsEndpoint(searchOptions{Type: "review", State: "all", Status: "all", Labels: []string{"x"}}))
+}
+
+func newSearchMockClient() *github.MockClient {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return &github.MockClient{
+		PullRequests: []github.PullRequest{
+			{Number: 10, Title: "Add caching", State: "open"},
+			{Number: 11, Title: "Fix login", State: "open"},
+		},
+		IssueComments: []github.Comment{
+			{ID: 1, Body: "Looks good", User: github.User{Login: "alice"}, CreatedAt: created},
+		},
+		ReviewComments: []github.Comment{
+			{ID: 2, Body: "TODO: handle errors", User: github.User{Login: "bob"}, Path: "api.go", Line: 5, CreatedAt: created.Add(time.Hour)},
+			{ID: 3, Body: "Nit: rename", User: github.User{Login: "alice"}, Path: "api.go", Line: 9, CreatedAt: created.Add(2 * time.Hour)},
+		},
+		ReviewThreads: []github.ReviewThread{
+			{ID: "T1", IsResolved: true, Path: "api.go", Line: 9, CommentIDs: []int{3}},
+			{ID: "T2", IsResolved: false, Path: "api.go", Line: 5, CommentIDs: []int{2}},
+		},
+	}
+}
+
+func TestSearchCommentsAcrossPRs(t *testing.T) {
+	client := newSearchMockClient()
+	opts := searchOptions{State: "open", Status: "all", Limit: 50, Concurrency: 2, Query: &CommentQuery{}}
+
+	result, err := searchComments(client, "owner/repo", opts)
+	require.NoError(t, err)
+
+	require.Len(t, client.SearchQueries, 1)
+	assert.Equal(t, "repo:owner/repo is:pr is:open", client.SearchQueries[0])
+	assert.Empty(t, client.ListedThreadPRs, "threads are only fetched when filtering by status")
+
+	// 3 comments per PR, sorted by PR descending then newest first
+	require.Len(t, result.Comments, 6)
+	assert.Equal(t, 11, result.Comments[0].PR)
+	assert.Equal(t, 3, result.Comments[0].ID)
+	assert.Equal(t, 10, result.Comments[5].PR)
+	assert.Equal(t, 1, result.Comments[5].ID)
+	assert.Len(t, result.PullRequests, 2)
+}
+
+func TestSearchCommentsFilters(t *testing.T) {
+	t.Run("author and type", func(t *testing.T) {
+		opts := searchOptions{State: "open", Status: "all", Author: "ali*", Type: "review", Limit: 50, Concurrency: 1, Query: &CommentQuery{}}
+		result, err := searchComments(newSearchMockClient(), "owner/repo", opts)
+		require.NoError(t, err)
+
+		require.Len(t, result.Comments, 2)
+		for _, comment := range result.Comments {
+			assert.Equal(t, 3, comment.ID)
+		}
+	})
+
+	t.Run("unresolved threads", func(t *testing.T) {
+		client := newSearchMockClient()
+		opts := searchOptions{State: "open", Status: "open", Limit: 50, Concurrency: 4, Query: &CommentQuery{}}
+		result, err := searchComments(client, "owner/repo", opts)
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []int{10, 11}, client.ListedThreadPRs)
+		require.Len(t, result.Comments, 2)
+		for _, comment := range result.Comments {
+			assert.Equal(t, 2, comment.ID)
+			assert.Equal(t, "T2", comment.ThreadID)
+		}
+	})
+
+	t.Run("resolved threads", func(t *testing.T) {
+		opts := searchOptions{State: "open", Status: "resolved", Limit: 50, Concurrency: 4, Query: &CommentQuery{}}
+		result, err := searchComments(newSearchMockClient(), "owner/repo", opts)
+		require.NoError(t, err)
+
+		require.Len(t, result.Comments, 2)
+		assert.True(t, result.Comments[0].Resolved)
+	})
+
+	t.Run("grep", func(t *testing.T) {
+		query := &CommentQuery{Grep: []string{"todo"}}
+		require.NoError(t, query.Compile())
+
+		opts := searchOptions{State: "open", Status: "all", Limit: 50, Concurrency: 4, Query: query}
+		result, err := searchComments(newSearchMockClient(), "owner/repo", opts)
+		require.NoError(t, err)
+
+		require.Len(t, result.Comments, 2)
+		assert.Equal(t, 2, result.Comments[0].ID)
+	})
+
+	t.Run("date window", func(t *testing.T) {
+		since := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+		until := time.Date(2024, 5, 1, 13, 30, 0, 0, time.UTC)
+		opts := searchOptions{State: "open", Status: "all", Since: &since, Until: &until, Limit: 50, Concurrency: 4, Query: &CommentQuery{}}
+		result, err := searchComments(newSearchMockClient(), "owner/repo", opts)
+		require.NoError(t, err)
+
+		require.Len(t, result.Comments, 2)
+		assert.Equal(t, 2, result.Comments[0].ID)
+	})
+}
+
+func TestSearchCommentsUsesRepoEndpoint(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := &github.MockClient{
+		RepoReviewComments: []github.Comment{
+			{ID: 20, Body: "First", User: github.User{Login: "alice"}, Path: "a.go", Line: 1, CreatedAt: created, UpdatedAt: created,
+				PullRequestURL: "https://api.github.com/repos/owner/repo/pulls/7"},
+			{ID: 21, Body: "Reply", User: github.User{Login: "bob"}, Path: "a.go", Line: 1, CreatedAt: created.Add(time.Hour), UpdatedAt: created.Add(time.Hour),
+				InReplyToID: 20, PullRequestURL: "https://api.github.com/repos/owner/repo/pulls/7"},
+			{ID: 22, Body: "Other PR", User: github.User{Login: "alice"}, Path: "b.go", Line: 3, CreatedAt: created, UpdatedAt: created,
+				PullRequestURL: "https://api.github.com/repos/owner/repo/pulls/9"},
+		},
+	}
+
+	opts := searchOptions{State: "all", Status: "all", Type: "review", Limit: 50, Concurrency: 4, Query: &CommentQuery{}}
+	result, err := searchComments(client, "owner/repo", opts)
+	require.NoError(t, err)
+
+	assert.Empty(t, client.SearchQueries, "search API should not be used")
+	require.Len(t, result.Comments, 3)
+	assert.Equal(t, 9, result.Comments[0].PR)
+	assert.Equal(t, 7, result.Comments[1].PR)
+	assert.Equal(t, 21, result.Comments[1].ID)
+	assert.Equal(t, 1, result.Comments[2].Replies)
+	assert.Len(t, result.PullRequests, 2)
+}
+
+func TestSearchRepoReviewCommentsLimit(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	comment := func(id, pr int, age time.Duration) github.Comment {
+		return github.Comment{ID: id, Body: "Note", User: github.User{Login: "alice"}, Path: "a.go", Line: 1,
+			CreatedAt: created.Add(-age), UpdatedAt: created.Add(-age),
+			PullRequestURL: fmt.Sprintf("https://api.github.com/repos/owner/repo/pulls/%d", pr)}
+	}
+	client := &github.MockClient{
+		// Newest first, as the pulls/comments endpoint returns them
+		RepoReviewComments: []github.Comment{
+			comment(30, 9, 0),
+			comment(31, 7, time.Hour),
+			comment(32, 5, 2*time.Hour),
+			comment(33, 9, 3*time.Hour),
+		},
+	}
+
+	opts := searchOptions{State: "all", Status: "all", Type: "review", Limit: 2, Concurrency: 4, Query: &CommentQuery{}}
+	result, err := searchComments(client, "owner/repo", opts)
+	require.NoError(t, err)
+
+	assert.Len(t, result.PullRequests, 2)
+	var ids []int
+	for _, c := range result.Comments {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []int{30, 31}, ids, "listing stops at PR #5, the first past the limit")
+}
+
+func TestSearchRepoReviewCommentsStopsPaging(t *testing.T) {
+	// 150 review comments on each of three PRs, PR #9's newest
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var pulls []fakegithub.PullFixture
+	for p, number := range []int{9, 7, 5} {
+		pull := fakegithub.PullFixture{Number: number, Title: fmt.Sprintf("PR %d", number), Author: "alice"}
+		for i := 0; i < 150; i++ {
+			at := created.Add(-time.Duration(p*150+i) * time.Minute)
+			pull.ReviewComments = append(pull.ReviewComments, fakegithub.CommentFixture{
+				ID: number*1000 + i, Author: "bob", Body: "Note", Path: "a.go", Line: 1, CreatedAt: at, UpdatedAt: at,
+			})
+		}
+		pulls = append(pulls, pull)
+	}
+	server := fakegithub.New()
+	defer server.Close()
+	require.NoError(t, server.Seed(&fakegithub.Fixture{Repos: map[string]fakegithub.RepoFixture{"owner/repo": {Pulls: pulls}}}))
+	client, err := github.NewRealClientForServer(server.URL())
+	require.NoError(t, err)
+
+	opts := searchOptions{State: "all", Status: "all", Type: "review", Limit: 1, Concurrency: 4, Query: &CommentQuery{}}
+	result, err := searchComments(client, "owner/repo", opts)
+	require.NoError(t, err)
+	assert.Len(t, result.PullRequests, 1)
+	assert.Len(t, result.Comments, 150)
+
+	// PR #7 first shows up on the second page; the other three pages are never requested
+	pages := 0
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, "GET /repos/owner/repo/pulls/comments") {
+			pages++
+		}
+	}
+	assert.Equal(t, 2, pages)
+}
+
+func TestSearchCommentsErrors(t *testing.T) {
+	t.Run("search API failure", func(t *testing.T) {
+		client := newSearchMockClient()
+		client.SearchError = errors.New("rate limit exceeded")
+
+		opts := searchOptions{State: "open", Status: "all", Limit: 50, Concurrency: 4, Query: &CommentQuery{}}
+		_, err := searchComments(client, "owner/repo", opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pull request search")
+	})
+
+	t.Run("every PR fails", func(t *testing.T) {
+		client := newSearchMockClient()
+		client.ListIssueCommentsError = errors.New("boom")
+
+		opts := searchOptions{State: "open", Status: "all", Limit: 50, Concurrency: 4, Query: &CommentQuery{}}
+		_, err := searchComments(client, "owner/repo", opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch comments for all 2 PRs")
+	})
+
+	t.Run("thread failures are reported per PR", func(t *testing.T) {
+		client := newSearchMockClient()
+		client.ListThreadsError = errors.New("graphql down")
+
+		opts := searchOptions{State: "open", Status: "open", Limit: 50, Concurrency: 4, Query: &CommentQuery{}}
+		_, err := searchComments(client, "owner/repo", opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "review threads")
+	})
+}
+
+// concurrencyTrackingClient records the peak number of in-flight comment fetches
+type concurrencyTrackingClient struct {
+	*github.MockClient
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (c *concurrencyTrackingClient) ListIssueComments(owner, repo string, prNumber int) ([]github.Comment, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.peak {
+		c.peak = c.inFlight
+	}
+	c.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return c.MockClient.ListIssueComments(owner, repo, prNumber)
+}
+
+func TestFetchCommentsForPRsBoundedConcurrency(t *testing.T) {
+	client := &concurrencyTrackingClient{MockClient: newSearchMockClient()}
+	prs := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	comments, errs := fetchCommentsForPRs(client, "owner/repo", prs, 3, false)
+	assert.Empty(t, errs)
+	assert.Len(t, comments, 30)
+	assert.LessOrEqual(t, client.peak, 3)
+	assert.Greater(t, client.peak, 0)
+
+	// Results keep the PR order regardless of completion order
+	assert.Equal(t, 1, comments[0].PR)
+	assert.Equal(t, 10, comments[len(comments)-1].PR)
+}
+
+func TestParseSearchOptionsValidation(t *testing.T) {
+	defer func() {
+		searchState, searchType, searchStatus = "open", "", "all"
+		searchLimit, searchConcurrency = DefaultSearchLimit, DefaultSearchConcurrency
+		searchSince, searchUntil = "", ""
+	}()
+
+	tests := []struct {
+		name    string
+		setup   func()
+		wantErr string
+	}{
+		{name: "bad state", setup: func() { searchState = "draft" }, wantErr: "state"},
+		{name: "bad type", setup: func() { searchType = "commit" }, wantErr: "type"},
+		{name: "bad status", setup: func() { searchStatus = "pending" }, wantErr: "status"},
+		{name: "bad limit", setup: func() { searchLimit = 0 }, wantErr: "limit"},
+		{name: "bad concurrency", setup: func() { searchConcurrency = MaxSearchConcurrency + 1 }, wantErr: "concurrency"},
+		{name: "inverted window", setup: func() { searchSince, searchUntil = "2024-02-01", "2024-01-01" }, wantErr: "cannot be after"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchState, searchType, searchStatus = "open", "", "all"
+			searchLimit, searchConcurrency = DefaultSearchLimit, DefaultSearchConcurrency
+			searchSince, searchUntil = "", ""
+			tt.setup()
+
+			_, err := parseSearchOptions()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSearchJSONOutput(t *testing.T) {
+	result := &searchResult{PullRequests: map[int]github.PullRequest{10: {Number: 10}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderComments(&buf, nil, searchJSONOutput("owner/repo", result), OutputOptions{JQ: ".repository, .pull_requests, (.comments | length)"}))
+	assert.Equal(t, "owner/repo\n1\n0\n", buf.String())
+}
+
+func TestDisplaySearchResultsGroupsByPR(t *testing.T) {
+	result := &searchResult{
+		PullRequests: map[int]github.PullRequest{
+			10: {Number: 10, Title: "Add caching", State: "open"},
+			11: {Number: 11, Title: "Fix login", State: "merged"},
+		},
+		Comments: []Comment{
+			{ID: 1, PR: 11, Author: "alice", Body: "first", Type: "issue", CreatedAt: time.Now()},
+			{ID: 2, PR: 10, Author: "bob", Body: "second", Type: "issue", CreatedAt: time.Now()},
+		},
+	}
+
+	output := captureOutput(func() {
+		displaySearchResults("owner/repo", result)
+	})
+
+	assert.Contains(t, output, "2 comments across 2 PRs")
+	assert.Contains(t, output, "PR #11 · Fix login (merged)")
+	assert.Less(t, strings.Index(output, "PR #11"), strings.Index(output, "PR #10"))
+}
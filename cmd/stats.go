@@ -0,0 +1,666 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// Stats output formats
+const (
+	StatsFormatTable    = "table"
+	StatsFormatJSON     = "json"
+	StatsFormatMarkdown = "markdown"
+
+	DefaultStatsTop = 10
+)
+
+var (
+	statsSince       string
+	statsState       string
+	statsLimit       int
+	statsConcurrency int
+	statsTop         int
+	statsFormat      string
+
+	// Client for dependency injection (tests can override)
+	statsClient github.GitHubAPI
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [pr]",
+	Short: "Show review statistics and reviewer metrics",
+	Long: heredoc.Doc(`
+		Report review activity for one pull request, or for every PR updated
+		since a date when --since is given without a PR number.
+
+		Metrics:
+		• Comment counts per author, per file, per directory and per type
+		• Median time to first review: from PR creation to the first review
+		  comment or submitted review by someone other than the PR author
+		• Time to resolution per thread: from a resolved thread's first
+		  comment to its last reply (GitHub does not expose when a thread
+		  was resolved, so the last reply is used as the closing point);
+		  threads resolved without a reply are left out
+		• Reply ratios: replies per thread and share of threads with replies
+		• Suggestion acceptance: threads containing a suggestion block that
+		  were resolved or became outdated (the suggested line changed)
+		• Reaction distribution across all comments
+
+		Output as a table (default), JSON or markdown (for PR descriptions,
+		wikis and retrospectives).
+	`),
+	Example: heredoc.Doc(`
+		# Statistics for a single PR
+		$ gh comment stats 123
+
+		# Team metrics for everything updated this month
+		$ gh comment stats --since "1 month ago"
+
+		# Only merged PRs, as markdown for a retro doc
+		$ gh comment stats --since 2024-01-01 --state merged --format markdown > review-retro.md
+
+		# Machine-readable metrics
+		$ gh comment stats --since "2 weeks ago" --format json | jq '.time_to_first_review'
+	`),
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Include PRs updated since this date (flexible formats); enables multi-PR mode")
+	statsCmd.Flags().StringVar(&statsState, "state", "all", "PR state in multi-PR mode: open, closed, merged, all")
+	statsCmd.Flags().IntVar(&statsLimit, "limit", DefaultSearchLimit, "Maximum number of PRs in multi-PR mode")
+	statsCmd.Flags().IntVar(&statsConcurrency, "concurrency", DefaultSearchConcurrency, "Number of PRs to fetch in parallel")
+	statsCmd.Flags().IntVar(&statsTop, "top", DefaultStatsTop, "Rows to show per ranking (authors, files, directories)")
+	statsCmd.Flags().StringVar(&statsFormat, "format", StatsFormatTable, "Output format: table, json, markdown")
+}
+
+// statsPR is the data gathered for one PR
+type statsPR struct {
+	PullRequest github.PullRequest
+	Comments    []Comment
+	Reviews     []github.Review
+}
+
+// CountEntry is one row of a ranking
+type CountEntry struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DurationSummary summarizes a set of durations
+type DurationSummary struct {
+	Samples       int     `json:"samples"`
+	MedianSeconds float64 `json:"median_seconds"`
+	MaxSeconds    float64 `json:"max_seconds"`
+}
+
+// ThreadStat describes one review thread
+type ThreadStat struct {
+	PR                int     `json:"pr"`
+	RootID            int     `json:"root_id"`
+	Path              string  `json:"path,omitempty"`
+	Comments          int     `json:"comments"`
+	Resolved          bool    `json:"resolved"`
+	Outdated          bool    `json:"outdated"`
+	HasSuggestion     bool    `json:"has_suggestion"`
+	ResolutionSeconds float64 `json:"resolution_seconds,omitempty"`
+}
+
+// ReviewStats holds every metric reported by 'gh comment stats'
+type ReviewStats struct {
+	Repository    string `json:"repository"`
+	PullRequests  []int  `json:"pull_requests"`
+	TotalComments int    `json:"total_comments"`
+
+	ByType      map[string]int `json:"by_type"`
+	ByAuthor    []CountEntry   `json:"by_author"`
+	ByFile      []CountEntry   `json:"by_file"`
+	ByDirectory []CountEntry   `json:"by_directory"`
+
+	TimeToFirstReview DurationSummary `json:"time_to_first_review"`
+	TimeToResolution  DurationSummary `json:"time_to_resolution"`
+
+	Threads            int     `json:"threads"`
+	ResolvedThreads    int     `json:"resolved_threads"`
+	Replies            int     `json:"replies"`
+	RepliesPerThread   float64 `json:"replies_per_thread"`
+	ThreadsWithReplies float64 `json:"threads_with_replies_ratio"`
+
+	Suggestions          int            `json:"suggestions"`
+	AcceptedSuggestions  int            `json:"accepted_suggestions"`
+	SuggestionAcceptance float64        `json:"suggestion_acceptance_rate"`
+	ReactionDistribution map[string]int `json:"reactions"`
+	ThreadDetails        []ThreadStat   `json:"thread_details"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	// Initialize client if not set (production use)
+	if statsClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		statsClient = client
+	}
+
+	if !containsString([]string{StatsFormatTable, StatsFormatJSON, StatsFormatMarkdown}, statsFormat) {
+		return formatValidationError("format", statsFormat, "must be one of: table, json, markdown")
+	}
+	if statsTop <= 0 {
+		return formatValidationError("top", strconv.Itoa(statsTop), "must be a positive integer")
+	}
+
+	var prs []statsPR
+	var repository string
+	var err error
+
+	if len(args) == 0 && statsSince != "" {
+		repository, prs, err = gatherMultiPRStats()
+	} else {
+		repository, prs, err = gatherSinglePRStats(args)
+	}
+	if err != nil {
+		return err
+	}
+
+	stats := computeReviewStats(repository, prs, statsTop)
+
+	switch statsFormat {
+	case StatsFormatJSON:
+		return writeJSON(os.Stdout, stats)
+	case StatsFormatMarkdown:
+		return renderStatsMarkdown(os.Stdout, stats)
+	default:
+		return renderStatsTable(os.Stdout, stats, terminalWidth())
+	}
+}
+
+// gatherSinglePRStats fetches the data for one PR
+func gatherSinglePRStats(args []string) (string, []statsPR, error) {
+	repository, pr, err := getPRContextFromArgs(args)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := fetchStatsPR(statsClient, repository, github.PullRequest{Number: pr}, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return repository, []statsPR{data}, nil
+}
+
+// gatherMultiPRStats searches for PRs updated since --since and fetches their data concurrently
+func gatherMultiPRStats() (string, []statsPR, error) {
+	repository, err := getCurrentRepo()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := validateRepositoryName(repository); err != nil {
+		return "", nil, err
+	}
+
+	sinceTime, err := parseFlexibleDate(statsSince)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid since date '%s': %w", statsSince, err)
+	}
+	if !containsString([]string{"open", "closed", "merged", "all"}, statsState) {
+		return "", nil, formatValidationError("state", statsState, "must be one of: open, closed, merged, all")
+	}
+	if statsLimit <= 0 {
+		return "", nil, formatValidationError("limit", strconv.Itoa(statsLimit), "must be a positive integer")
+	}
+	if statsConcurrency <= 0 || statsConcurrency > MaxSearchConcurrency {
+		return "", nil, formatValidationError("concurrency", strconv.Itoa(statsConcurrency),
+			fmt.Sprintf("must be between 1 and %d", MaxSearchConcurrency))
+	}
+
+	query := buildPRSearchQuery(repository, searchOptions{State: statsState, Since: &sinceTime})
+	pullRequests, err := statsClient.SearchPullRequests(query, statsLimit)
+	if err != nil {
+		return "", nil, formatActionableError("pull request search", err)
+	}
+
+	numbers := make([]int, len(pullRequests))
+	for i, pr := range pullRequests {
+		numbers[i] = pr.Number
+	}
+
+	results := make([]statsPR, len(pullRequests))
+	ok := make([]bool, len(pullRequests))
+	errs := forEachPR(numbers, statsConcurrency, func(i, pr int) error {
+		data, err := fetchStatsPR(statsClient, repository, pullRequests[i], false)
+		if err != nil {
+			return err
+		}
+		results[i] = data
+		ok[i] = true
+		return nil
+	})
+
+	for _, fetchErr := range errs {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", fetchErr)
+	}
+	if len(numbers) > 0 && len(errs) == len(numbers) {
+		return "", nil, fmt.Errorf("failed to fetch data for all %d PRs: %w", len(numbers), errs[0])
+	}
+
+	var prs []statsPR
+	for i := range results {
+		if ok[i] {
+			prs = append(prs, results[i])
+		}
+	}
+
+	return repository, prs, nil
+}
+
+// fetchStatsPR fetches comments, thread state and reviews for a PR.
+// With fetchDetails the PR's author and creation time are looked up as well.
+func fetchStatsPR(client github.GitHubAPI, repository string, pr github.PullRequest, fetchDetails bool) (statsPR, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return statsPR{}, fmt.Errorf("invalid repository format: %s (expected owner/repo)", repository)
+	}
+	owner, repoName := parts[0], parts[1]
+
+	if fetchDetails {
+		details, err := client.GetPRDetails(owner, repoName, pr.Number)
+		if err != nil {
+			return statsPR{}, formatActionableError("PR details fetch", err)
+		}
+		pr = pullRequestFromDetails(pr.Number, details)
+	}
+
+	comments, err := fetchAllComments(client, repository, pr.Number)
+	if err != nil {
+		return statsPR{}, err
+	}
+	for i := range comments {
+		comments[i].PR = pr.Number
+	}
+
+	threads, err := client.ListReviewThreads(owner, repoName, pr.Number)
+	if err != nil {
+		return statsPR{}, fmt.Errorf("failed to fetch review threads: %w", err)
+	}
+	applyThreadState(comments, threads)
+
+	reviews, err := client.ListReviews(owner, repoName, pr.Number)
+	if err != nil {
+		return statsPR{}, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	return statsPR{PullRequest: pr, Comments: comments, Reviews: reviews}, nil
+}
+
+// computeReviewStats derives every metric from the gathered PR data
+func computeReviewStats(repository string, prs []statsPR, top int) *ReviewStats {
+	stats := &ReviewStats{
+		Repository:           repository,
+		PullRequests:         []int{},
+		ByType:               map[string]int{"issue": 0, "review": 0},
+		ReactionDistribution: make(map[string]int),
+		ThreadDetails:        []ThreadStat{},
+	}
+
+	authors := make(map[string]int)
+	files := make(map[string]int)
+	dirs := make(map[string]int)
+	var firstReviewTimes, resolutionTimes []time.Duration
+
+	for _, pr := range prs {
+		stats.PullRequests = append(stats.PullRequests, pr.PullRequest.Number)
+
+		for _, comment := range pr.Comments {
+			stats.TotalComments++
+			stats.ByType[comment.Type]++
+			authors[comment.Author]++
+			if comment.Path != "" {
+				files[comment.Path]++
+				dirs[path.Dir(comment.Path)]++
+			}
+			for _, reaction := range github.ReactionContents {
+				if count := comment.Reactions.Count(reaction); count > 0 {
+					stats.ReactionDistribution[reaction] += count
+				}
+			}
+		}
+
+		if d, ok := timeToFirstReview(pr); ok {
+			firstReviewTimes = append(firstReviewTimes, d)
+		}
+
+		for _, thread := range buildThreadStats(pr.PullRequest.Number, pr.Comments) {
+			stats.Threads++
+			stats.Replies += thread.Comments - 1
+			if thread.Resolved {
+				stats.ResolvedThreads++
+				// Without a reply there is no closing point to measure to
+				if thread.Comments > 1 {
+					resolutionTimes = append(resolutionTimes, time.Duration(thread.ResolutionSeconds*float64(time.Second)))
+				}
+			}
+			if thread.HasSuggestion {
+				stats.Suggestions++
+				if thread.Resolved || thread.Outdated {
+					stats.AcceptedSuggestions++
+				}
+			}
+			stats.ThreadDetails = append(stats.ThreadDetails, thread)
+		}
+	}
+
+	stats.ByAuthor = topCounts(authors, top)
+	stats.ByFile = topCounts(files, top)
+	stats.ByDirectory = topCounts(dirs, top)
+	stats.TimeToFirstReview = summarizeDurations(firstReviewTimes)
+	stats.TimeToResolution = summarizeDurations(resolutionTimes)
+
+	if stats.Threads > 0 {
+		withReplies := 0
+		for _, thread := range stats.ThreadDetails {
+			if thread.Comments > 1 {
+				withReplies++
+			}
+		}
+		stats.RepliesPerThread = float64(stats.Replies) / float64(stats.Threads)
+		stats.ThreadsWithReplies = float64(withReplies) / float64(stats.Threads)
+	}
+	if stats.Suggestions > 0 {
+		stats.SuggestionAcceptance = float64(stats.AcceptedSuggestions) / float64(stats.Suggestions)
+	}
+
+	return stats
+}
+
+// timeToFirstReview returns the time from PR creation to the first review activity by someone else
+func timeToFirstReview(pr statsPR) (time.Duration, bool) {
+	if pr.PullRequest.CreatedAt.IsZero() {
+		return 0, false
+	}
+
+	author := pr.PullRequest.User.Login
+	var first time.Time
+	consider := func(login string, at time.Time) {
+		if at.IsZero() || (author != "" && strings.EqualFold(login, author)) {
+			return
+		}
+		if first.IsZero() || at.Before(first) {
+			first = at
+		}
+	}
+
+	for _, comment := range pr.Comments {
+		if comment.Type == "review" {
+			consider(comment.Author, comment.CreatedAt)
+		}
+	}
+	for _, review := range pr.Reviews {
+		if review.State != "PENDING" {
+			consider(review.User.Login, review.SubmittedAt)
+		}
+	}
+
+	if first.IsZero() || first.Before(pr.PullRequest.CreatedAt) {
+		return 0, false
+	}
+	return first.Sub(pr.PullRequest.CreatedAt), true
+}
+
+// buildThreadStats groups a PR's review comments into threads by their root comment
+func buildThreadStats(pr int, comments []Comment) []ThreadStat {
+	type thread struct {
+		root    Comment
+		members []Comment
+	}
+
+	threads := make(map[int]*thread)
+	var order []int
+	for _, comment := range comments {
+		if comment.Type != "review" {
+			continue
+		}
+		rootID := comment.ID
+		if comment.InReplyToID != 0 {
+			rootID = comment.InReplyToID
+		}
+		t, ok := threads[rootID]
+		if !ok {
+			t = &thread{}
+			threads[rootID] = t
+			order = append(order, rootID)
+		}
+		if comment.ID == rootID {
+			t.root = comment
+		}
+		t.members = append(t.members, comment)
+	}
+
+	stats := make([]ThreadStat, 0, len(order))
+	for _, rootID := range order {
+		t := threads[rootID]
+		first, last := t.members[0].CreatedAt, t.members[0].CreatedAt
+		stat := ThreadStat{PR: pr, RootID: rootID, Path: t.members[0].Path, Comments: len(t.members)}
+
+		for _, member := range t.members {
+			if member.CreatedAt.Before(first) {
+				first = member.CreatedAt
+			}
+			if member.CreatedAt.After(last) {
+				last = member.CreatedAt
+			}
+			stat.Resolved = stat.Resolved || member.Resolved
+			stat.Outdated = stat.Outdated || member.Outdated
+			stat.HasSuggestion = stat.HasSuggestion || hasSuggestionBlock(member.Body)
+		}
+
+		if stat.Resolved && stat.Comments > 1 {
+			stat.ResolutionSeconds = last.Sub(first).Seconds()
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats
+}
+
+// topCounts returns the largest counts, ties broken by name
+func topCounts(counts map[string]int, top int) []CountEntry {
+	entries := make([]CountEntry, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, CountEntry{Name: name, Count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	if top > 0 && len(entries) > top {
+		entries = entries[:top]
+	}
+	return entries
+}
+
+// summarizeDurations computes the median and maximum of durations
+func summarizeDurations(durations []time.Duration) DurationSummary {
+	if len(durations) == 0 {
+		return DurationSummary{}
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mid := len(sorted) / 2
+	median := sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return DurationSummary{
+		Samples:       len(sorted),
+		MedianSeconds: median.Seconds(),
+		MaxSeconds:    sorted[len(sorted)-1].Seconds(),
+	}
+}
+
+// formatStatsDuration renders seconds as a compact duration such as "2d 3h" or "45m"
+func formatStatsDuration(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second))
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// formatDurationSummary renders a summary for display
+func formatDurationSummary(summary DurationSummary) string {
+	if summary.Samples == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%s median, %s max (%d samples)",
+		formatStatsDuration(summary.MedianSeconds), formatStatsDuration(summary.MaxSeconds), summary.Samples)
+}
+
+// formatPercent renders a ratio as a percentage
+func formatPercent(ratio float64) string {
+	return fmt.Sprintf("%.0f%%", ratio*100)
+}
+
+// statsSummaryRows returns the headline metrics shared by the table and markdown renderers
+func statsSummaryRows(stats *ReviewStats) [][2]string {
+	suggestions := "n/a"
+	if stats.Suggestions > 0 {
+		suggestions = fmt.Sprintf("%s (%d of %d)", formatPercent(stats.SuggestionAcceptance), stats.AcceptedSuggestions, stats.Suggestions)
+	}
+
+	return [][2]string{
+		{"Pull requests", strconv.Itoa(len(stats.PullRequests))},
+		{"Comments", fmt.Sprintf("%d (%d issue, %d review)", stats.TotalComments, stats.ByType["issue"], stats.ByType["review"])},
+		{"Review threads", fmt.Sprintf("%d (%d resolved)", stats.Threads, stats.ResolvedThreads)},
+		{"Time to first review", formatDurationSummary(stats.TimeToFirstReview)},
+		{"Time to resolution", formatDurationSummary(stats.TimeToResolution)},
+		{"Replies per thread", fmt.Sprintf("%.2f", stats.RepliesPerThread)},
+		{"Threads with replies", formatPercent(stats.ThreadsWithReplies)},
+		{"Suggestion acceptance", suggestions},
+		{"Reactions", formatReactionDistribution(stats.ReactionDistribution)},
+	}
+}
+
+// formatReactionDistribution renders reaction counts in GitHub's order
+func formatReactionDistribution(reactions map[string]int) string {
+	var parts []string
+	for _, reaction := range github.ReactionContents {
+		if count := reactions[reaction]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", reaction, count))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderStatsTable prints the statistics as aligned tables
+func renderStatsTable(w io.Writer, stats *ReviewStats, width int) error {
+	fmt.Fprintf(w, "📊 Review statistics for %s\n\n", stats.Repository)
+
+	summary := tableprinter.New(w, true, width)
+	for _, row := range statsSummaryRows(stats) {
+		summary.AddField(row[0])
+		summary.AddField(row[1])
+		summary.EndRow()
+	}
+	if err := summary.Render(); err != nil {
+		return err
+	}
+
+	rankings := []struct {
+		title   string
+		entries []CountEntry
+	}{
+		{"AUTHOR", stats.ByAuthor},
+		{"FILE", stats.ByFile},
+		{"DIRECTORY", stats.ByDirectory},
+	}
+
+	for _, ranking := range rankings {
+		if len(ranking.entries) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		table := tableprinter.New(w, true, width)
+		table.AddHeader([]string{ranking.title, "COMMENTS"})
+		for _, entry := range ranking.entries {
+			table.AddField(entry.Name)
+			table.AddField(strconv.Itoa(entry.Count))
+			table.EndRow()
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderStatsMarkdown prints the statistics as markdown tables
+func renderStatsMarkdown(w io.Writer, stats *ReviewStats) error {
+	fmt.Fprintf(w, "## Review statistics for %s\n\n", stats.Repository)
+	fmt.Fprintln(w, "| Metric | Value |")
+	fmt.Fprintln(w, "| --- | --- |")
+	for _, row := range statsSummaryRows(stats) {
+		fmt.Fprintf(w, "| %s | %s |\n", row[0], escapeMarkdownCell(row[1]))
+	}
+
+	rankings := []struct {
+		title   string
+		entries []CountEntry
+	}{
+		{"Author", stats.ByAuthor},
+		{"File", stats.ByFile},
+		{"Directory", stats.ByDirectory},
+	}
+
+	for _, ranking := range rankings {
+		if len(ranking.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n### Comments by %s\n\n", strings.ToLower(ranking.title))
+		fmt.Fprintf(w, "| %s | Comments |\n", ranking.title)
+		fmt.Fprintln(w, "| --- | ---: |")
+		for _, entry := range ranking.entries {
+			fmt.Fprintf(w, "| %s | %d |\n", escapeMarkdownCell(entry.Name), entry.Count)
+		}
+	}
+
+	return nil
+}
+
+// escapeMarkdownCell keeps pipes from breaking markdown table cells
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func newStatsFixture() []statsPR {
+	created := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	return []statsPR{
+		{
+			PullRequest: github.PullRequest{Number: 1, User: github.User{Login: "alice"}, CreatedAt: created},
+			Comments: []Comment{
+				{ID: 1, Type: "issue", Author: "alice", Body: "Ready for review", CreatedAt: created.Add(10 * time.Minute)},
+				{ID: 2, Type: "review", Author: "bob", Path: "src/api/handler.go", Body: "```suggestion\nreturn nil\n```",
+					CreatedAt: created.Add(2 * time.Hour), Resolved: true,
+					Reactions: github.ReactionSummary{TotalCount: 2, PlusOne: 1, Heart: 1}},
+				{ID: 3, Type: "review", Author: "alice", Path: "src/api/handler.go", Body: "Done", InReplyToID: 2,
+					CreatedAt: created.Add(5 * time.Hour), Resolved: true},
+				{ID: 4, Type: "review", Author: "bob", Path: "README.md", Body: "Typo",
+					CreatedAt: created.Add(3 * time.Hour), Resolved: true,
+					Reactions: github.ReactionSummary{TotalCount: 1, PlusOne: 1}},
+			},
+		},
+		{
+			PullRequest: github.PullRequest{Number: 2, User: github.User{Login: "bob"}, CreatedAt: created},
+			Comments: []Comment{
+				{ID: 10, Type: "review", Author: "bob", Path: "src/api/router.go", Body: "Self note", CreatedAt: created.Add(time.Minute)},
+				{ID: 11, Type: "review", Author: "carol", Path: "src/api/router.go", Body: "```suggestion\nx := 1\n```",
+					CreatedAt: created.Add(4 * time.Hour)},
+			},
+			Reviews: []github.Review{
+				{ID: 1, User: github.User{Login: "carol"}, State: "COMMENTED", SubmittedAt: created.Add(4 * time.Hour)},
+				{ID: 2, User: github.User{Login: "dave"}, State: "PENDING"},
+			},
+		},
+	}
+}
+
+func TestComputeReviewStats(t *testing.T) {
+	stats := computeReviewStats("owner/repo", newStatsFixture(), 10)
+
+	assert.Equal(t, []int{1, 2}, stats.PullRequests)
+	assert.Equal(t, 6, stats.TotalComments)
+	assert.Equal(t, map[string]int{"issue": 1, "review": 5}, stats.ByType)
+	assert.Equal(t, []CountEntry{{"bob", 3}, {"alice", 2}, {"carol", 1}}, stats.ByAuthor)
+	assert.Equal(t, CountEntry{"src/api/handler.go", 2}, stats.ByFile[0])
+	assert.Equal(t, []CountEntry{{"src/api", 4}, {".", 1}}, stats.ByDirectory)
+
+	// PR 1: bob's first review comment after 2h; PR 2: the author's own comment is ignored, carol after 4h
+	assert.Equal(t, 2, stats.TimeToFirstReview.Samples)
+	assert.Equal(t, (3 * time.Hour).Seconds(), stats.TimeToFirstReview.MedianSeconds)
+	assert.Equal(t, (4 * time.Hour).Seconds(), stats.TimeToFirstReview.MaxSeconds)
+
+	// Threads: {2,3} resolved after 3h, {4} resolved without a reply, {10}, {11}
+	assert.Equal(t, 4, stats.Threads)
+	assert.Equal(t, 2, stats.ResolvedThreads)
+	assert.Equal(t, 1, stats.Replies)
+	assert.Equal(t, 0.25, stats.RepliesPerThread)
+	assert.Equal(t, 0.25, stats.ThreadsWithReplies)
+	assert.Equal(t, (3 * time.Hour).Seconds(), stats.TimeToResolution.MedianSeconds)
+	assert.Equal(t, 1, stats.TimeToResolution.Samples, "a thread resolved without a reply has no resolution time")
+
+	assert.Equal(t, 2, stats.Suggestions)
+	assert.Equal(t, 1, stats.AcceptedSuggestions)
+	assert.Equal(t, 0.5, stats.SuggestionAcceptance)
+
+	assert.Equal(t, map[string]int{"+1": 2, "heart": 1}, stats.ReactionDistribution)
+}
+
+func TestComputeReviewStatsEmpty(t *testing.T) {
+	stats := computeReviewStats("owner/repo", nil, 10)
+
+	assert.Zero(t, stats.TotalComments)
+	assert.Zero(t, stats.TimeToFirstReview.Samples)
+	assert.Zero(t, stats.SuggestionAcceptance)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderStatsTable(&buf, stats, 80))
+	assert.Contains(t, buf.String(), "n/a")
+}
+
+func TestTopCountsLimitAndTies(t *testing.T) {
+	counts := map[string]int{"b": 2, "a": 2, "c": 5, "d": 1}
+	assert.Equal(t, []CountEntry{{"c", 5}, {"a", 2}}, topCounts(counts, 2))
+}
+
+func TestSummarizeDurations(t *testing.T) {
+	summary := summarizeDurations([]time.Duration{4 * time.Hour, time.Hour, 2 * time.Hour})
+	assert.Equal(t, 3, summary.Samples)
+	assert.Equal(t, (2 * time.Hour).Seconds(), summary.MedianSeconds)
+	assert.Equal(t, (4 * time.Hour).Seconds(), summary.MaxSeconds)
+
+	assert.Equal(t, DurationSummary{}, summarizeDurations(nil))
+}
+
+func TestFormatStatsDuration(t *testing.T) {
+	assert.Equal(t, "45s", formatStatsDuration(45))
+	assert.Equal(t, "12m", formatStatsDuration(12*60))
+	assert.Equal(t, "3h 5m", formatStatsDuration(3*3600+5*60))
+	assert.Equal(t, "2d 4h", formatStatsDuration(52*3600))
+}
+
+func TestRenderStatsFormats(t *testing.T) {
+	stats := computeReviewStats("owner/repo", newStatsFixture(), 10)
+
+	var table bytes.Buffer
+	require.NoError(t, renderStatsTable(&table, stats, 120))
+	assert.Contains(t, table.String(), "Review statistics for owner/repo")
+	assert.Contains(t, table.String(), "Suggestion acceptance")
+	assert.Contains(t, table.String(), "50% (1 of 2)")
+	assert.Contains(t, table.String(), "src/api/handler.go")
+
+	var markdown bytes.Buffer
+	require.NoError(t, renderStatsMarkdown(&markdown, stats))
+	assert.Contains(t, markdown.String(), "## Review statistics for owner/repo")
+	assert.Contains(t, markdown.String(), "| Time to first review | 3h 0m median, 4h 0m max (2 samples) |")
+	assert.Contains(t, markdown.String(), "### Comments by directory")
+	assert.Contains(t, markdown.String(), "| +1 2, heart 1 |")
+
+	var out bytes.Buffer
+	require.NoError(t, writeJSON(&out, stats))
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, 0.5, decoded["suggestion_acceptance_rate"])
+	assert.Len(t, decoded["thread_details"], 4)
+}
+
+func TestFetchStatsPR(t *testing.T) {
+	client := newSearchMockClient()
+	client.Reviews = []github.Review{{ID: 1, User: github.User{Login: "bob"}, State: "APPROVED"}}
+
+	data, err := fetchStatsPR(client, "owner/repo", github.PullRequest{Number: 10}, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Test PR", data.PullRequest.Title)
+	assert.Len(t, data.Comments, 3)
+	assert.Len(t, data.Reviews, 1)
+	for _, comment := range data.Comments {
+		assert.Equal(t, 10, comment.PR)
+		if comment.ID == 3 {
+			assert.True(t, comment.Resolved)
+		}
+	}
+
+	_, err = fetchStatsPR(client, "invalid", github.PullRequest{Number: 10}, false)
+	assert.Error(t, err)
+}
+
+func TestPullRequestFromDetails(t *testing.T) {
+	pr := pullRequestFromDetails(5, map[string]interface{}{
+		"title":      "Add stats",
+		"state":      "closed",
+		"user":       map[string]interface{}{"login": "alice"},
+		"created_at": "2024-07-01T09:00:00Z",
+	})
+
+	assert.Equal(t, 5, pr.Number)
+	assert.Equal(t, "alice", pr.User.Login)
+	assert.Equal(t, time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC), pr.CreatedAt)
+
+	// Missing fields are tolerated
+	pr = pullRequestFromDetails(6, map[string]interface{}{"created_at": "not a date"})
+	assert.True(t, pr.CreatedAt.IsZero())
+}
+
+func TestRunStatsMultiPR(t *testing.T) {
+	originalClient, originalRepo := statsClient, repo
+	originalSince, originalFormat, originalState := statsSince, statsFormat, statsState
+	defer func() {
+		statsClient, repo = originalClient, originalRepo
+		statsSince, statsFormat, statsState = originalSince, originalFormat, originalState
+	}()
+
+	client := newSearchMockClient()
+	statsClient = client
+	repo = "owner/repo"
+	statsSince = "2024-01-01"
+	statsState = "merged"
+	statsFormat = StatsFormatJSON
+
+	output := captureOutput(func() {
+		require.NoError(t, runStats(statsCmd, nil))
+	})
+
+	require.Len(t, client.SearchQueries, 1)
+	assert.Equal(t, "repo:owner/repo is:pr is:merged updated:>=2024-01-01", client.SearchQueries[0])
+
+	var decoded ReviewStats
+	require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+	assert.ElementsMatch(t, []int{10, 11}, decoded.PullRequests)
+	assert.Equal(t, 6, decoded.TotalComments)
+
+	statsFormat = "xml"
+	assert.Error(t, runStats(statsCmd, nil))
+
+	originalLimit := statsLimit
+	defer func() { statsLimit = originalLimit }()
+	statsFormat, statsLimit = StatsFormatJSON, 0
+	assert.ErrorContains(t, runStats(statsCmd, nil), "invalid limit '0'")
+}
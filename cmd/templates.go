@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// SnippetSourceConfig is the source shown for snippets defined in the config file
+const SnippetSourceConfig = "config"
+
+var (
+	templateVarFlags []string
+
+	snippetVariablePattern  = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+	snippetReferencePattern = regexp.MustCompile(`^@([A-Za-z0-9][A-Za-z0-9_.-]*)$`)
+)
+
+// Snippet is a reusable comment body from the config file or a snippet directory
+type Snippet struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Vars        map[string]string `json:"vars,omitempty"`
+	Body        string            `json:"body"`
+	Source      string            `json:"source"`
+}
+
+// snippetFrontMatter is the YAML header of a snippet markdown file
+type snippetFrontMatter struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Vars        map[string]string `yaml:"vars"`
+}
+
+// templatesCmd represents the templates command
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Browse reusable comment snippets",
+	Long: heredoc.Doc(`
+		Browse the snippet library used by 'add --template' and 'review --comment file:line:@name'.
+		A review comment of just @name that matches no snippet is posted as
+		written, so a comment that only mentions someone still works.
+
+		Snippets are loaded from, in priority order:
+		1. templates.snippets in the config file
+		2. Markdown files in templates.dir (relative paths are resolved from the config file)
+		3. .gh-comment/templates/*.md in the repository root
+		4. ~/.config/gh-comment/templates/*.md
+
+		A snippet file's name is its file name without .md, unless the front-matter
+		sets one. Bodies reference variables as {{name}}; front-matter 'vars' gives
+		their defaults, and variables without a default must be passed with --var.
+
+		  ---
+		  description: Ask for a test covering a function
+		  vars:
+		    func: ""
+		    kind: unit
+		  ---
+		  Please add a {{kind}} test covering {{func}}.
+	`),
+	Example: heredoc.Doc(`
+		# List snippets and their variables
+		$ gh comment templates list
+
+		# Show a snippet, optionally rendered with variables
+		$ gh comment templates show missing-test
+		$ gh comment templates show missing-test --var func=Parse
+
+		# Use snippets in comments and reviews
+		$ gh comment add 123 --template missing-test --var func=Parse
+		$ gh comment review 123 --comment src/log.go:42:@log-pii --comment src/parse.go:10:@missing-test --var func=Parse
+	`),
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available snippets",
+	Args:  cobra.NoArgs,
+	RunE:  runTemplatesList,
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a snippet's variables and body",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplatesShow,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+
+	templatesShowCmd.Flags().StringArrayVar(&templateVarFlags, "var", nil, "Render the snippet with this variable (key=value, repeatable)")
+
+	rootCmd.AddCommand(templatesCmd)
+}
+
+func runTemplatesList(cmd *cobra.Command, args []string) error {
+	snippets, err := loadSnippets(GetConfig())
+	if err != nil {
+		return err
+	}
+
+	if len(snippets) == 0 {
+		fmt.Println("No snippets found")
+		fmt.Println("💡 Add templates.snippets to the config file or markdown files to .gh-comment/templates/")
+		return nil
+	}
+
+	for _, name := range sortedSnippetNames(snippets) {
+		snippet := snippets[name]
+		fmt.Printf("%s", ColorizeHeader(name))
+		if snippet.Description != "" {
+			fmt.Printf(" - %s", snippet.Description)
+		}
+		fmt.Println()
+		if vars := snippetVariables(snippet); len(vars) > 0 {
+			fmt.Printf("    vars: %s\n", formatSnippetVars(snippet, vars))
+		}
+		fmt.Printf("    source: %s\n", snippet.Source)
+	}
+	return nil
+}
+
+func runTemplatesShow(cmd *cobra.Command, args []string) error {
+	snippet, err := findSnippet(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(templateVarFlags) > 0 {
+		vars, err := parseTemplateVars(templateVarFlags)
+		if err != nil {
+			return err
+		}
+		body, err := renderSnippet(snippet, vars)
+		if err != nil {
+			return err
+		}
+		fmt.Println(body)
+		return nil
+	}
+
+	fmt.Printf("Name: %s\n", snippet.Name)
+	if snippet.Description != "" {
+		fmt.Printf("Description: %s\n", snippet.Description)
+	}
+	if vars := snippetVariables(snippet); len(vars) > 0 {
+		fmt.Printf("Vars: %s\n", formatSnippetVars(snippet, vars))
+	}
+	fmt.Printf("Source: %s\n\n", snippet.Source)
+	fmt.Println(snippet.Body)
+	return nil
+}
+
+// loadSnippets collects snippets from the config file and the snippet directories.
+// Earlier sources win when several define the same name.
+func loadSnippets(config *Config) (map[string]Snippet, error) {
+	snippets := make(map[string]Snippet)
+
+	for name, snippet := range config.Templates.Snippets {
+		snippets[name] = Snippet{
+			Name:        name,
+			Description: snippet.Description,
+			Vars:        snippet.Vars,
+			Body:        strings.TrimSpace(snippet.Body),
+			Source:      SnippetSourceConfig,
+		}
+	}
+
+	for _, dir := range snippetDirs(config) {
+		dirSnippets, err := loadSnippetDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for name, snippet := range dirSnippets {
+			if _, exists := snippets[name]; !exists {
+				snippets[name] = snippet
+			}
+		}
+	}
+
+	return snippets, nil
+}
+
+// snippetDirs returns the directories searched for snippet files, in priority order
+func snippetDirs(config *Config) []string {
+	var dirs []string
+
+	if dir := config.Templates.Dir; dir != "" {
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				dir = filepath.Join(home, rest)
+			}
+		} else if !filepath.IsAbs(dir) {
+			if path := activeConfigPath(); path != "" {
+				dir = filepath.Join(filepath.Dir(path), dir)
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+	if gitRoot := findGitRoot(); gitRoot != "" {
+		dirs = append(dirs, filepath.Join(gitRoot, ".gh-comment", "templates"))
+	}
+	if dir, err := userConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "templates"))
+	}
+
+	return dirs
+}
+
+// activeConfigPath returns the config file in use, or "" when running on defaults
+func activeConfigPath() string {
+	if configPath != "" {
+		return configPath
+	}
+	path, _ := findConfigFile()
+	return path
+}
+
+// loadSnippetDir loads every .md file in dir; a missing directory has no snippets
+func loadSnippetDir(dir string) (map[string]Snippet, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snippet directory %s: %w", dir, err)
+	}
+
+	snippets := make(map[string]Snippet)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snippet %s: %w", path, err)
+		}
+
+		snippet, err := parseSnippetFile(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())), content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snippet %s: %w", path, err)
+		}
+		snippet.Source = path
+		snippets[snippet.Name] = snippet
+	}
+	return snippets, nil
+}
+
+// parseSnippetFile parses a markdown snippet with optional YAML front-matter
+func parseSnippetFile(name string, content []byte) (Snippet, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	snippet := Snippet{Name: name}
+
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		end := strings.Index(rest, "\n---")
+		if end < 0 {
+			return Snippet{}, fmt.Errorf("front-matter is not closed with ---")
+		}
+
+		var meta snippetFrontMatter
+		if err := yaml.Unmarshal([]byte(rest[:end]), &meta); err != nil {
+			return Snippet{}, fmt.Errorf("failed to parse front-matter: %w", err)
+		}
+		if meta.Name != "" {
+			snippet.Name = meta.Name
+		}
+		snippet.Description = meta.Description
+		snippet.Vars = meta.Vars
+
+		text = rest[end+len("\n---"):]
+	}
+
+	snippet.Body = strings.TrimSpace(text)
+	if snippet.Body == "" {
+		return Snippet{}, fmt.Errorf("snippet body is empty")
+	}
+	return snippet, nil
+}
+
+// findSnippet looks up a snippet by name in the current configuration
+func findSnippet(name string) (Snippet, error) {
+	snippets, err := loadSnippets(GetConfig())
+	if err != nil {
+		return Snippet{}, err
+	}
+
+	snippet, ok := snippets[name]
+	if !ok {
+		return Snippet{}, formatValidationError("template", name, "no such snippet (see 'gh comment templates list')")
+	}
+	return snippet, nil
+}
+
+// renderSnippet substitutes {{variables}} in a snippet body, using defaults for unset variables
+func renderSnippet(snippet Snippet, vars map[string]string) (string, error) {
+	var missing []string
+	for _, name := range snippetVariables(snippet) {
+		if _, ok := vars[name]; !ok && snippet.Vars[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("template %s needs a value for %s (use --var %s=...)",
+			snippet.Name, strings.Join(missing, ", "), missing[0])
+	}
+
+	return snippetVariablePattern.ReplaceAllStringFunc(snippet.Body, func(placeholder string) string {
+		name := snippetVariablePattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return snippet.Vars[name]
+	}), nil
+}
+
+// snippetVariables returns the variables declared in front-matter or used in the body, sorted
+func snippetVariables(snippet Snippet) []string {
+	seen := make(map[string]bool)
+	for name := range snippet.Vars {
+		seen[name] = true
+	}
+	for _, match := range snippetVariablePattern.FindAllStringSubmatch(snippet.Body, -1) {
+		seen[match[1]] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatSnippetVars lists variables with their defaults; required ones are marked
+func formatSnippetVars(snippet Snippet, vars []string) string {
+	parts := make([]string, len(vars))
+	for i, name := range vars {
+		if value := snippet.Vars[name]; value != "" {
+			parts[i] = fmt.Sprintf("%s=%s", name, value)
+		} else {
+			parts[i] = name + " (required)"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// resolveSnippetReference expands a message of the form @name into the named snippet.
+// Other messages, and @name when there is no such snippet, are returned unchanged,
+// so a comment that only mentions someone is still posted as written.
+func resolveSnippetReference(message string, varFlags []string) (string, error) {
+	match := snippetReferencePattern.FindStringSubmatch(strings.TrimSpace(message))
+	if match == nil {
+		return message, nil
+	}
+
+	snippets, err := loadSnippets(GetConfig())
+	if err != nil {
+		return "", err
+	}
+	if _, ok := snippets[match[1]]; !ok {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "⚠️  No snippet named '%s'; posting %q as written\n", match[1], message)
+		}
+		return message, nil
+	}
+	return renderSnippetByName(match[1], varFlags)
+}
+
+// renderSnippetByName renders a snippet with --var flags
+func renderSnippetByName(name string, varFlags []string) (string, error) {
+	vars, err := parseTemplateVars(varFlags)
+	if err != nil {
+		return "", err
+	}
+	snippet, err := findSnippet(name)
+	if err != nil {
+		return "", err
+	}
+	return renderSnippet(snippet, vars)
+}
+
+// parseTemplateVars parses key=value --var flags
+func parseTemplateVars(flags []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, formatValidationError("--var", flag, "must be in key=value format")
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+func sortedSnippetNames(snippets map[string]Snippet) []string {
+	names := make([]string, 0, len(snippets))
+	for name := range snippets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// withSnippetConfig installs a config with the given snippets and snippet directory for one test
+func withSnippetConfig(t *testing.T, snippets map[string]SnippetConfig, dir string) {
+	t.Helper()
+	originalConfig, originalConfigPath := globalConfig, configPath
+	t.Cleanup(func() { globalConfig, configPath = originalConfig, originalConfigPath })
+
+	config := NewDefaultConfig()
+	config.Templates.Snippets = snippets
+	config.Templates.Dir = dir
+	globalConfig = config
+	configPath = filepath.Join(t.TempDir(), "config.yaml")
+}
+
+func TestParseSnippetFile(t *testing.T) {
+	snippet, err := parseSnippetFile("missing-test", []byte("---\ndescription: Ask for a test\nvars:\n  func: \"\"\n  kind: unit\n---\n\nPlease add a {{kind}} test for `{{ func }}`.\n"))
+	require.NoError(t, err)
+	assert.Equal(t, Snippet{
+		Name:        "missing-test",
+		Description: "Ask for a test",
+		Vars:        map[string]string{"func": "", "kind": "unit"},
+		Body:        "Please add a {{kind}} test for `{{ func }}`.",
+	}, snippet)
+
+	renamed, err := parseSnippetFile("file", []byte("---\nname: log-pii\n---\nDon't log user data"))
+	require.NoError(t, err)
+	assert.Equal(t, "log-pii", renamed.Name)
+
+	plain, err := parseSnippetFile("nit", []byte("Just a nit.\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "Just a nit.", plain.Body)
+
+	_, err = parseSnippetFile("broken", []byte("---\ndescription: x\nno closing fence"))
+	assert.ErrorContains(t, err, "front-matter is not closed")
+	_, err = parseSnippetFile("empty", []byte("---\ndescription: x\n---\n"))
+	assert.ErrorContains(t, err, "snippet body is empty")
+}
+
+func TestRenderSnippet(t *testing.T) {
+	snippet := Snippet{
+		Name: "missing-test",
+		Vars: map[string]string{"kind": "unit"},
+		Body: "Please add a {{kind}} test for `{{ func }}`. {{func}} is exported.",
+	}
+
+	body, err := renderSnippet(snippet, map[string]string{"func": "Parse"})
+	require.NoError(t, err)
+	assert.Equal(t, "Please add a unit test for `Parse`. Parse is exported.", body)
+
+	body, err = renderSnippet(snippet, map[string]string{"func": "Parse", "kind": "fuzz", "unused": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, "Please add a fuzz test for `Parse`. Parse is exported.", body)
+
+	_, err = renderSnippet(snippet, nil)
+	assert.EqualError(t, err, "template missing-test needs a value for func (use --var func=...)")
+
+	assert.Equal(t, []string{"func", "kind"}, snippetVariables(snippet))
+	assert.Equal(t, "func (required), kind=unit", formatSnippetVars(snippet, snippetVariables(snippet)))
+}
+
+func TestParseTemplateVars(t *testing.T) {
+	vars, err := parseTemplateVars([]string{"func=Parse", "note=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"func": "Parse", "note": "a=b", "empty": ""}, vars)
+
+	_, err = parseTemplateVars([]string{"novalue"})
+	assert.ErrorContains(t, err, "must be in key=value format")
+	_, err = parseTemplateVars([]string{"=x"})
+	assert.Error(t, err)
+}
+
+func TestSnippetConfigShorthand(t *testing.T) {
+	var templates TemplatesConfig
+	err := yaml.Unmarshal([]byte(`
+snippets:
+  nit: "Nit: {{what}}"
+  log-pii:
+    description: Logging personal data
+    body: Please don't log {{field}}.
+    vars:
+      field: email addresses
+`), &templates)
+	require.NoError(t, err)
+	assert.Equal(t, SnippetConfig{Body: "Nit: {{what}}"}, templates.Snippets["nit"])
+	assert.Equal(t, SnippetConfig{
+		Description: "Logging personal data",
+		Body:        "Please don't log {{field}}.",
+		Vars:        map[string]string{"field": "email addresses"},
+	}, templates.Snippets["log-pii"])
+}
+
+func TestLoadSnippets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "missing-test.md"), []byte("---\ndescription: Ask for a test\n---\nPlease add a test for {{func}}."), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nit.md"), []byte("Overridden by config"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+
+	withSnippetConfig(t, map[string]SnippetConfig{"nit": {Body: "  Nit: {{what}}\n"}}, dir)
+
+	snippets, err := loadSnippets(GetConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "Nit: {{what}}", snippets["nit"].Body)
+	assert.Equal(t, SnippetSourceConfig, snippets["nit"].Source)
+	assert.Equal(t, "Please add a test for {{func}}.", snippets["missing-test"].Body)
+	assert.Equal(t, filepath.Join(dir, "missing-test.md"), snippets["missing-test"].Source)
+	assert.NotContains(t, snippets, "notes")
+
+	// Relative directories are resolved from the config file
+	relative := NewDefaultConfig()
+	relative.Templates.Dir = "snippets"
+	assert.Equal(t, filepath.Join(filepath.Dir(configPath), "snippets"), snippetDirs(relative)[0])
+
+	// Broken snippet files are reported
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.md"), []byte("---\nvars: [\n---\nbody"), 0644))
+	_, err = loadSnippets(GetConfig())
+	assert.ErrorContains(t, err, "broken.md")
+}
+
+func TestResolveSnippetReference(t *testing.T) {
+	withSnippetConfig(t, map[string]SnippetConfig{"missing-test": {Body: "Please test {{func}}."}}, "")
+
+	message, err := resolveSnippetReference("@missing-test", []string{"func=Parse"})
+	require.NoError(t, err)
+	assert.Equal(t, "Please test Parse.", message)
+
+	message, err = resolveSnippetReference("@alice please take a look", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "@alice please take a look", message, "mentions inside a message are left alone")
+
+	message, err = resolveSnippetReference("@unknown", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "@unknown", message, "a lone mention without a snippet of that name is posted as written")
+	_, err = resolveSnippetReference("@missing-test", nil)
+	assert.ErrorContains(t, err, "needs a value for func")
+}
+
+func TestAddWithTemplate(t *testing.T) {
+	originalClient, originalRepo := addClient, repo
+	originalTemplate, originalVars, originalMessages := addTemplate, addTemplateVars, messages
+	defer func() {
+		addClient, repo = originalClient, originalRepo
+		addTemplate, addTemplateVars, messages = originalTemplate, originalVars, originalMessages
+	}()
+	withSnippetConfig(t, map[string]SnippetConfig{"missing-test": {Body: "Please add a test for `{{func}}`."}}, "")
+
+	mockClient := github.NewMockClient()
+	addClient, repo, messages = mockClient, "owner/repo", nil
+	addTemplate, addTemplateVars = "missing-test", []string{"func=Parse"}
+
+	output := captureOutput(func() {
+		require.NoError(t, runAdd(addCmd, []string{"123"}))
+	})
+	assert.Contains(t, output, "Added comment to PR #123")
+	assert.Equal(t, []string{"Please add a test for `Parse`."}, mockClient.IssueCommentBodies)
+
+	assert.ErrorContains(t, runAdd(addCmd, []string{"123", "also a message"}), "expected [pr] or no args")
+	messages = []string{"line"}
+	assert.ErrorContains(t, runAdd(addCmd, []string{"123"}), "cannot be combined with --message")
+	messages, addTemplateVars = nil, nil
+	assert.ErrorContains(t, runAdd(addCmd, []string{"123"}), "needs a value for func")
+}
+
+func TestReviewWithSnippetComments(t *testing.T) {
+	originalClient, originalRepo := reviewClient, repo
+	originalComments, originalVars, originalEvent := reviewCommentsFlag, reviewTemplateVars, reviewEventFlag
+	defer func() {
+		reviewClient, repo = originalClient, originalRepo
+		reviewCommentsFlag, reviewTemplateVars, reviewEventFlag = originalComments, originalVars, originalEvent
+	}()
+	withSnippetConfig(t, map[string]SnippetConfig{
+		"log-pii":      {Body: "Avoid logging {{field}}.", Vars: map[string]string{"field": "personal data"}},
+		"missing-test": {Body: "Please add a test for {{func}}."},
+	}, "")
+
+	mockClient := github.NewMockClient()
+	reviewClient, repo, reviewEventFlag = mockClient, "owner/repo", "COMMENT"
+	reviewCommentsFlag = []string{"src/log.go:42:@log-pii", "src/parse.go:10:12:@missing-test", "src/x.go:1:plain text"}
+	reviewTemplateVars = []string{"func=Parse"}
+
+	captureOutput(func() {
+		require.NoError(t, runReview(reviewCmd, []string{"123"}))
+	})
+	require.Len(t, mockClient.CreateReviewCalls, 1)
+	comments := mockClient.CreateReviewCalls[0].Comments
+	require.Len(t, comments, 3)
+	assert.Equal(t, "Avoid logging personal data.", comments[0].Body)
+	assert.Equal(t, "Please add a test for Parse.", comments[1].Body)
+	assert.Equal(t, 10, comments[1].StartLine)
+	assert.Equal(t, "plain text", comments[2].Body)
+}
@@ -8,10 +8,14 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cli/go-gh/v2"
 	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/fakegithub"
+	"github.com/silouanwright/gh-comment/internal/github"
 )
 
 var (
@@ -19,7 +23,21 @@ var (
 	inspect        bool
 	scenario       string
 	force          bool
+	offline        bool
+	offlineFixture string
 	integrationLog *log.Logger
+
+	// Where the scenarios run and how they check their results
+	integrationRepo     string
+	integrationTestFile string
+	integrationClient   github.GitHubAPI
+)
+
+const (
+	// The PR the offline fake GitHub holds for the scenarios
+	offlineRepo     = "gh-comment/integration-test"
+	offlinePRNumber = 1
+	offlineTestFile = "test-file.js"
 )
 
 // testIntegrationCmd represents the test-integration command
@@ -35,6 +53,9 @@ Integration tests are MANUAL-ONLY by default to avoid accidental API usage:
 - Use --force flag to run when needed
 - Control with environment variables for CI/automation
 
+With --offline the scenarios run against an in-process fake GitHub instead,
+so no PR is created and no network access or authentication is needed.
+
 Environment Variables:
   GH_COMMENT_INTEGRATION_TESTS=always    # Always run (for CI)
   GH_COMMENT_INTEGRATION_TESTS=never     # Never run (explicit disable)
@@ -50,7 +71,11 @@ Examples:
   gh comment test-integration --scenario=comments --inspect
 
   # Run tests with cleanup disabled for debugging
-  gh comment test-integration --no-cleanup`,
+  gh comment test-integration --no-cleanup
+
+  # Run every scenario offline, optionally with extra data seeded from a fixture
+  gh comment test-integration --offline
+  gh comment test-integration --offline --fixture testdata/fixtures/github.yaml`,
 	RunE: runTestIntegration,
 }
 
@@ -61,11 +86,17 @@ func init() {
 	testIntegrationCmd.Flags().BoolVar(&inspect, "inspect", false, "Leave PR open for manual inspection (implies --no-cleanup)")
 	testIntegrationCmd.Flags().StringVar(&scenario, "scenario", "", "Run specific test scenario only (comments, reviews, reactions, batch, suggestions)")
 	testIntegrationCmd.Flags().BoolVar(&force, "force", false, "Force integration tests to run (bypasses frequency controls)")
+	testIntegrationCmd.Flags().BoolVar(&offline, "offline", false, "Run against an in-process fake GitHub instead of a real PR")
+	testIntegrationCmd.Flags().StringVar(&offlineFixture, "fixture", "", "Fixture file to seed the fake GitHub with (requires --offline)")
 }
 
 func runTestIntegration(cmd *cobra.Command, args []string) error {
-	// Check if integration tests should run based on frequency controls
-	if !force && !shouldRunIntegrationTests() {
+	if offlineFixture != "" && !offline {
+		return formatValidationError("fixture", offlineFixture, "only applies with --offline")
+	}
+
+	// Offline runs never touch GitHub, so the frequency controls do not apply
+	if !force && !offline && !shouldRunIntegrationTests() {
 		fmt.Println("⏭️  Skipping integration tests (use --force flag to override)")
 		return nil
 	}
@@ -77,39 +108,59 @@ func runTestIntegration(cmd *cobra.Command, args []string) error {
 
 	integrationLog.Println("🚀 Starting integration tests...")
 
-	// If inspect is enabled, disable cleanup
-	if inspect {
-		cleanup = false
-		integrationLog.Println("📋 Inspect mode enabled - PR will remain open")
-	}
+	var prNumber int
+	if offline {
+		server, err := startOfflineGitHub()
+		if err != nil {
+			return fmt.Errorf("failed to start fake GitHub: %w", err)
+		}
+		defer server.Close()
+
+		prNumber = offlinePRNumber
+		integrationLog.Printf("🔌 Offline mode: fake GitHub at %s", server.URL())
+	} else {
+		// If inspect is enabled, disable cleanup
+		if inspect {
+			cleanup = false
+			integrationLog.Println("📋 Inspect mode enabled - PR will remain open")
+		}
 
-	// Get current repository
-	currentRepo, err := getCurrentRepo()
-	if err != nil {
-		return fmt.Errorf("failed to get current repository: %w", err)
-	}
-	integrationLog.Printf("📂 Testing repository: %s", currentRepo)
+		// Get current repository
+		currentRepo, err := getCurrentRepo()
+		if err != nil {
+			return fmt.Errorf("failed to get current repository: %w", err)
+		}
+		integrationRepo = currentRepo
 
-	// Create test PR
-	branchName := fmt.Sprintf("integration-test-%d", time.Now().Unix())
-	prNumber, err := createTestPR(branchName)
-	if err != nil {
-		return fmt.Errorf("failed to create test PR: %w", err)
-	}
+		// Create test PR
+		branchName := fmt.Sprintf("integration-test-%d", time.Now().Unix())
+		prNumber, err = createTestPR(branchName)
+		if err != nil {
+			return fmt.Errorf("failed to create test PR: %w", err)
+		}
 
-	integrationLog.Printf("🔧 Created test PR #%d on branch %s", prNumber, branchName)
+		integrationLog.Printf("🔧 Created test PR #%d on branch %s", prNumber, branchName)
+
+		// Defer cleanup if enabled
+		if cleanup {
+			defer func() {
+				integrationLog.Printf("🧹 Cleaning up test PR #%d...", prNumber)
+				if err := cleanupTestPR(branchName, prNumber); err != nil {
+					integrationLog.Printf("⚠️  Cleanup failed: %v", err)
+				} else {
+					integrationLog.Printf("✅ Cleanup completed")
+				}
+			}()
+		}
+	}
+	integrationLog.Printf("📂 Testing repository: %s", integrationRepo)
 
-	// Defer cleanup if enabled
-	if cleanup {
-		defer func() {
-			integrationLog.Printf("🧹 Cleaning up test PR #%d...", prNumber)
-			if err := cleanupTestPR(branchName, prNumber); err != nil {
-				integrationLog.Printf("⚠️  Cleanup failed: %v", err)
-			} else {
-				integrationLog.Printf("✅ Cleanup completed")
-			}
-		}()
+	// Results are checked through the API rather than by scraping command output
+	client, err := createGitHubClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
 	}
+	integrationClient = client
 
 	// Run test scenarios
 	if scenario != "" {
@@ -152,6 +203,7 @@ func createTestPR(branchName string) (int, error) {
 	// Copy template file
 	templatePath := "integration-tests/templates/dummy-code.js"
 	targetPath := fmt.Sprintf("test-file-%d.js", time.Now().Unix())
+	integrationTestFile = targetPath
 
 	if err := copyTemplateFile(templatePath, targetPath); err != nil {
 		return 0, fmt.Errorf("failed to copy template: %w", err)
@@ -211,6 +263,59 @@ func createTestPR(branchName string) (int, error) {
 	return prNum, nil
 }
 
+// startOfflineGitHub serves a fake GitHub holding one open PR that adds the
+// dummy template, and points this process and the commands it runs at it
+func startOfflineGitHub() (*fakegithub.Server, error) {
+	server := fakegithub.New()
+	if offlineFixture != "" {
+		fixture, err := fakegithub.LoadFixture(offlineFixture)
+		if err != nil {
+			server.Close()
+			return nil, err
+		}
+		if err := server.Seed(fixture); err != nil {
+			server.Close()
+			return nil, err
+		}
+	}
+
+	pull := fakegithub.PullFixture{
+		Number:  offlinePRNumber,
+		Title:   "Integration Test: offline",
+		Author:  fakegithub.DefaultUser,
+		HeadSHA: "0000000000000000000000000000000000000001",
+		Diff:    newFileDiff(offlineTestFile, dummyTemplate),
+	}
+	err := server.Seed(&fakegithub.Fixture{
+		Repos: map[string]fakegithub.RepoFixture{offlineRepo: {Pulls: []fakegithub.PullFixture{pull}}},
+	})
+	if err != nil {
+		server.Close()
+		return nil, err
+	}
+
+	// Commands the scenarios run inherit the environment
+	if err := os.Setenv("MOCK_SERVER_URL", server.URL()); err != nil {
+		server.Close()
+		return nil, err
+	}
+	integrationRepo = offlineRepo
+	integrationTestFile = offlineTestFile
+	return server, nil
+}
+
+// newFileDiff is the unified diff of a commit adding a file with the given content
+func newFileDiff(path, content string) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "diff --git a/%s b/%s\nnew file mode 100644\n--- /dev/null\n+++ b/%s\n", path, path, path)
+	fmt.Fprintf(&diff, "@@ -0,0 +1,%d @@\n", len(lines))
+	for _, line := range lines {
+		diff.WriteString("+" + line + "\n")
+	}
+	return diff.String()
+}
+
 func cleanupTestPR(branchName string, prNumber int) error {
 	// Close PR
 	_, _, err := gh.Exec("pr", "close", fmt.Sprintf("%d", prNumber))
@@ -307,7 +412,11 @@ func createDummyTemplate(path string) error {
 		return err
 	}
 
-	template := `// Integration Test File - Contains intentional issues for commenting
+	return os.WriteFile(path, []byte(dummyTemplate), 0644)
+}
+
+// dummyTemplate is the file the scenarios comment on; the line numbers they use point into it
+const dummyTemplate = `// Integration Test File - Contains intentional issues for commenting
 function calculateTotal(items) {
     let total = 0;
     for (let i = 0; i < items.length; i++) {
@@ -326,9 +435,6 @@ const processOrder = (order) => {
 module.exports = { calculateTotal, processOrder };
 `
 
-	return os.WriteFile(path, []byte(template), 0644)
-}
-
 // shouldRunIntegrationTests checks if integration tests should run based on environment variables and frequency controls
 func shouldRunIntegrationTests() bool {
 	// Check environment variables for controls
@@ -104,30 +104,32 @@ func TestRunSpecificScenario(t *testing.T) {
 		{
 			name:        "valid comments scenario",
 			scenario:    "comments",
-			expectError: true, // Will fail without real PR setup
+			expectError: true, // PR 999 does not exist
 		},
 		{
 			name:        "valid reviews scenario",
 			scenario:    "reviews",
-			expectError: true, // Will fail without real PR setup
+			expectError: true, // PR 999 does not exist
 		},
 		{
 			name:        "valid reactions scenario",
 			scenario:    "reactions",
-			expectError: true, // Will fail without real PR setup
+			expectError: true, // PR 999 does not exist
 		},
 		{
 			name:        "valid batch scenario",
 			scenario:    "batch",
-			expectError: true, // Will fail without real PR setup
+			expectError: true, // PR 999 does not exist
 		},
 		{
 			name:        "valid suggestions scenario",
 			scenario:    "suggestions",
-			expectError: true, // Will fail without real PR setup
+			expectError: true, // PR 999 does not exist
 		},
 	}
 
+	useOfflineGitHub(t)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := runSpecificScenario(tt.scenario, 999)
@@ -145,7 +147,9 @@ func TestRunSpecificScenario(t *testing.T) {
 }
 
 func TestRunAllScenarios(t *testing.T) {
-	// This will fail in test environment without real PR
+	useOfflineGitHub(t)
+
+	// PR 999 does not exist on the fake GitHub
 	err := runAllScenarios(999)
 	assert.Error(t, err)
 	// Should fail on first scenario
@@ -184,6 +188,56 @@ func TestIntegrationFlags(t *testing.T) {
 	scenarioFlag := flags.Lookup("scenario")
 	assert.NotNil(t, scenarioFlag)
 	assert.Equal(t, "", scenarioFlag.DefValue)
+
+	offlineFlag := flags.Lookup("offline")
+	assert.NotNil(t, offlineFlag)
+	assert.Equal(t, "false", offlineFlag.DefValue)
+
+	assert.NotNil(t, flags.Lookup("fixture"))
+}
+
+func TestStartOfflineGitHub(t *testing.T) {
+	useOfflineGitHub(t)
+	assert.Equal(t, offlineRepo, integrationRepo)
+	assert.Equal(t, offlineTestFile, getTestFileName())
+
+	// Every line of the template can be commented on
+	owner, name := integrationRepoParts()
+	diff, err := integrationClient.FetchPRDiff(owner, name, offlinePRNumber)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, offlineTestFile, diff.Files[0].Filename)
+	assert.Len(t, diff.Files[0].Lines, strings.Count(dummyTemplate, "\n"))
+}
+
+func TestStartOfflineGitHubWithFixture(t *testing.T) {
+	t.Setenv("MOCK_SERVER_URL", "")
+	original := offlineFixture
+	defer func() { offlineFixture = original }()
+
+	offlineFixture = "../testdata/fixtures/github.yaml"
+	server, err := startOfflineGitHub()
+	require.NoError(t, err)
+	defer server.Close()
+
+	client, err := createGitHubClient()
+	require.NoError(t, err)
+	comments, err := client.ListIssueComments("test-owner", "test-repo", 123)
+	require.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	offlineFixture = "missing.yaml"
+	_, err = startOfflineGitHub()
+	assert.Error(t, err)
+}
+
+func TestOfflineFixtureRequiresOffline(t *testing.T) {
+	originalFixture, originalOffline := offlineFixture, offline
+	defer func() { offlineFixture, offline = originalFixture, originalOffline }()
+
+	offlineFixture, offline = "fixture.yaml", false
+	err := runTestIntegration(testIntegrationCmd, nil)
+	assert.ErrorContains(t, err, "only applies with --offline")
 }
 
 func TestInspectModeDisablesCleanup(t *testing.T) {
@@ -0,0 +1,513 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+// Watch timing defaults
+const (
+	DefaultWatchInterval = 30 * time.Second
+	MinWatchInterval     = 5 * time.Second
+
+	// DefaultWatchJitterDivisor sets the default jitter to a tenth of the interval
+	DefaultWatchJitterDivisor = 10
+)
+
+// Watch event types
+const (
+	WatchEventNew        = "new"
+	WatchEventEdited     = "edited"
+	WatchEventResolved   = "resolved"
+	WatchEventUnresolved = "unresolved"
+	WatchEventReview     = "review"
+)
+
+var (
+	watchInterval     time.Duration
+	watchJitter       time.Duration
+	watchBell         bool
+	watchExec         string
+	watchShowExisting bool
+	watchPolls        int
+	watchNoThreads    bool
+	watchAuthor       string
+	watchType         string
+	watchJSON         bool
+
+	// Client for dependency injection (tests can override)
+	watchClient github.GitHubAPI
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [pr]",
+	Short: "Stream new, edited and resolved comments as they happen",
+	Long: heredoc.Doc(`
+		Watch a pull request and print comments as they arrive.
+
+		The PR is polled every --interval (plus or minus a random --jitter so many
+		watchers don't hit the API in lockstep). Comment listings use conditional
+		requests (ETag / If-None-Match), so polls where nothing changed return
+		304 Not Modified and don't count against your rate limit.
+
+		Only changes are printed: new comments, edited comments and review threads
+		that were resolved or unresolved. Existing comments are recorded on the
+		first poll and skipped unless --show-existing is set.
+
+		For every event you can ring the terminal bell (--bell) or run a hook
+		command (--exec). The hook runs through 'sh -c' with the event JSON on
+		stdin and these environment variables:
+
+		  GH_COMMENT_EVENT          new, edited, resolved or unresolved
+		  GH_COMMENT_EVENT_ID       comment ID
+		  GH_COMMENT_EVENT_PR       PR number
+		  GH_COMMENT_EVENT_AUTHOR   comment author
+		  GH_COMMENT_EVENT_TYPE     issue or review
+		  GH_COMMENT_EVENT_PATH     file path (review comments)
+		  GH_COMMENT_EVENT_LINE     line number (review comments)
+		  GH_COMMENT_EVENT_BODY     comment body
+
+		The GH_COMMENT_EVENT_ prefix keeps these apart from configuration overrides
+		such as GH_COMMENT_AUTHOR, so a hook that runs gh comment itself is not
+		filtered by the event's author.
+
+		Thread resolution is checked with one GraphQL query per poll, which has no
+		conditional form; use --no-threads to skip it.
+
+		Press Ctrl+C to stop watching.
+	`),
+	Example: heredoc.Doc(`
+		# Watch the current branch's PR
+		$ gh comment watch
+
+		# Poll every 10 seconds and ring the bell on activity
+		$ gh comment watch 123 --interval 10s --bell
+
+		# Desktop notification for each new review comment
+		$ gh comment watch 123 --type review --exec 'notify-send "PR #$GH_COMMENT_EVENT_PR" "$GH_COMMENT_EVENT_AUTHOR: $GH_COMMENT_EVENT_BODY"'
+
+		# Stream events as JSON lines for another tool
+		$ gh comment watch 123 --json | jq -r 'select(.event == "new") | .comment.body'
+
+		# Print what's there, check once more and exit
+		$ gh comment watch 123 --show-existing --polls 2
+	`),
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", DefaultWatchInterval, "Time between polls (minimum 5s)")
+	watchCmd.Flags().DurationVar(&watchJitter, "jitter", 0, "Random variation added to or subtracted from each interval (default: 10% of --interval)")
+	watchCmd.Flags().BoolVar(&watchBell, "bell", false, "Ring the terminal bell for each event")
+	watchCmd.Flags().StringVar(&watchExec, "exec", "", "Shell command to run for each event (event JSON on stdin)")
+	watchCmd.Flags().BoolVar(&watchShowExisting, "show-existing", false, "Print existing comments on the first poll")
+	watchCmd.Flags().IntVar(&watchPolls, "polls", 0, "Stop after this many polls (0 = until interrupted)")
+	watchCmd.Flags().BoolVar(&watchNoThreads, "no-threads", false, "Don't poll review thread resolution state")
+	watchCmd.Flags().StringVar(&watchAuthor, "author", "", "Only report comments by this author (supports wildcards)")
+	watchCmd.Flags().StringVar(&watchType, "type", "", "Only report this comment type (issue|review)")
+	watchCmd.Flags().BoolVar(&watchJSON, "json", false, "Print each event as a line of JSON")
+	watchCmd.Flags().BoolVar(&rawBodies, "raw", false, "Print comment bodies as-is instead of rendering markdown")
+}
+
+// WatchEvent describes a change observed while watching a PR
+type WatchEvent struct {
+	Type    string    `json:"event"`
+	PR      int       `json:"pr"`
+	Time    time.Time `json:"time"`
+	Comment Comment   `json:"comment"`
+
+	// ReviewState is set for review events (APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED)
+	ReviewState string `json:"review_state,omitempty"`
+}
+
+// watchOptions controls the polling loop
+type watchOptions struct {
+	Interval     time.Duration
+	Jitter       time.Duration
+	Polls        int
+	ShowExisting bool
+
+	// Sleep waits between polls; tests replace it to run instantly
+	Sleep func(ctx context.Context, d time.Duration) error
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	// Initialize client if not set (production use)
+	if watchClient == nil {
+		client, err := createGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		watchClient = client
+	}
+
+	if watchInterval < MinWatchInterval {
+		return formatValidationError("interval", watchInterval.String(), fmt.Sprintf("must be at least %s", MinWatchInterval))
+	}
+	if !cmd.Flags().Changed("jitter") {
+		watchJitter = watchInterval / DefaultWatchJitterDivisor
+	}
+	if watchJitter < 0 || watchJitter > watchInterval/2 {
+		return formatValidationError("jitter", watchJitter.String(), "must be between 0 and half the interval")
+	}
+	if watchPolls < 0 {
+		return formatValidationError("polls", strconv.Itoa(watchPolls), "must be zero or a positive integer")
+	}
+	if !containsString([]string{"", "issue", "review"}, watchType) {
+		return formatValidationError("type", watchType, "must be one of: issue, review")
+	}
+
+	repository, pr, err := getPRContextFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := newCommentWatcher(watchClient, repository, pr)
+	if err != nil {
+		return err
+	}
+	watcher.threads = !watchNoThreads
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if !watchJSON {
+		fmt.Fprintf(os.Stderr, "👀 Watching PR #%d in %s every %s (Ctrl+C to stop)\n", pr, repository, watchInterval)
+	}
+
+	opts := watchOptions{
+		Interval:     watchInterval,
+		Jitter:       watchJitter,
+		Polls:        watchPolls,
+		ShowExisting: watchShowExisting,
+		Sleep:        sleepContext,
+	}
+
+	return runWatchLoop(ctx, watcher, opts, handleWatchEvent)
+}
+
+// runWatchLoop polls until the context is cancelled or the poll limit is reached.
+// Poll errors are reported and retried on the next interval rather than ending the watch.
+func runWatchLoop(ctx context.Context, watcher *commentWatcher, opts watchOptions, handle func(WatchEvent)) error {
+	for poll := 1; ; poll++ {
+		events, err := watcher.Poll()
+		if err != nil {
+			if poll == 1 {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  poll failed, will retry: %v\n", err)
+		}
+
+		if poll > 1 || opts.ShowExisting {
+			for _, event := range events {
+				handle(event)
+			}
+		}
+
+		if opts.Polls > 0 && poll >= opts.Polls {
+			return nil
+		}
+
+		if err := opts.Sleep(ctx, nextPollDelay(opts.Interval, opts.Jitter)); err != nil {
+			// Interrupted: stop quietly
+			return nil
+		}
+	}
+}
+
+// nextPollDelay returns interval shifted by a random amount in [-jitter, +jitter]
+func nextPollDelay(interval, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	offset := time.Duration(rand.Int64N(int64(2*jitter)+1)) - jitter
+	return interval + offset
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// commentWatcher tracks the comments of a PR between polls
+type commentWatcher struct {
+	client   github.GitHubAPI
+	owner    string
+	repoName string
+	pr       int
+	threads  bool
+	reviews  bool
+
+	issueETag  string
+	reviewETag string
+	issue      []Comment
+	review     []Comment
+
+	// seen maps comment IDs to the last update time observed
+	seen map[int]time.Time
+	// resolved maps review thread IDs to their last observed state
+	resolved map[string]bool
+	// seenReviews records submitted reviews already reported
+	seenReviews map[int]bool
+}
+
+// newCommentWatcher creates a watcher for a PR in owner/repo
+func newCommentWatcher(client github.GitHubAPI, repository string, pr int) (*commentWatcher, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid repository format: %s (expected owner/repo)", repository)
+	}
+
+	return &commentWatcher{
+		client:      client,
+		owner:       parts[0],
+		repoName:    parts[1],
+		pr:          pr,
+		threads:     true,
+		seen:        make(map[int]time.Time),
+		resolved:    make(map[string]bool),
+		seenReviews: make(map[int]bool),
+	}, nil
+}
+
+// Poll fetches the PR's comments and returns what changed since the previous poll.
+// On the first poll every comment is reported as new.
+func (w *commentWatcher) Poll() ([]WatchEvent, error) {
+	now := time.Now()
+
+	issueResult, err := w.client.ListIssueCommentsIfChanged(w.owner, w.repoName, w.pr, w.issueETag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue comments: %w", err)
+	}
+	if !issueResult.NotModified {
+		w.issue = convertIssueComments(issueResult.Comments)
+		w.issueETag = issueResult.ETag
+	}
+
+	reviewResult, err := w.client.ListReviewCommentsIfChanged(w.owner, w.repoName, w.pr, w.reviewETag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review comments: %w", err)
+	}
+	if !reviewResult.NotModified {
+		w.review = convertReviewComments(reviewResult.Comments)
+		w.reviewETag = reviewResult.ETag
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "poll PR #%d: issue comments modified=%t, review comments modified=%t\n",
+			w.pr, !issueResult.NotModified, !reviewResult.NotModified)
+	}
+
+	var threads []github.ReviewThread
+	if w.threads && len(w.review) > 0 {
+		threads, err = w.client.ListReviewThreads(w.owner, w.repoName, w.pr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch review threads: %w", err)
+		}
+		applyThreadState(w.review, threads)
+	}
+
+	var events []WatchEvent
+	for _, comment := range append(append([]Comment{}, w.issue...), w.review...) {
+		lastUpdate, seen := w.seen[comment.ID]
+		switch {
+		case !seen:
+			events = append(events, WatchEvent{Type: WatchEventNew, PR: w.pr, Time: now, Comment: comment})
+		case comment.UpdatedAt.After(lastUpdate):
+			events = append(events, WatchEvent{Type: WatchEventEdited, PR: w.pr, Time: now, Comment: comment})
+		}
+		w.seen[comment.ID] = comment.UpdatedAt
+	}
+
+	events = append(events, w.threadEvents(threads, now)...)
+
+	if w.reviews {
+		reviews, err := w.client.ListReviews(w.owner, w.repoName, w.pr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+		}
+		events = append(events, w.reviewEvents(reviews, now)...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Comment.CreatedAt.Before(events[j].Comment.CreatedAt)
+	})
+
+	return events, nil
+}
+
+// threadEvents reports threads whose resolution state changed since the last poll
+func (w *commentWatcher) threadEvents(threads []github.ReviewThread, now time.Time) []WatchEvent {
+	byID := make(map[int]Comment, len(w.review))
+	for _, comment := range w.review {
+		byID[comment.ID] = comment
+	}
+
+	var events []WatchEvent
+	for _, thread := range threads {
+		previous, known := w.resolved[thread.ID]
+		w.resolved[thread.ID] = thread.IsResolved
+		if !known || previous == thread.IsResolved || len(thread.CommentIDs) == 0 {
+			continue
+		}
+
+		// Report the change against the comment that started the thread
+		comment, ok := byID[thread.CommentIDs[0]]
+		if !ok {
+			continue
+		}
+
+		eventType := WatchEventUnresolved
+		if thread.IsResolved {
+			eventType = WatchEventResolved
+		}
+		events = append(events, WatchEvent{Type: eventType, PR: w.pr, Time: now, Comment: comment})
+	}
+
+	return events
+}
+
+// reviewEvents reports reviews submitted since the last poll
+func (w *commentWatcher) reviewEvents(reviews []github.Review, now time.Time) []WatchEvent {
+	var events []WatchEvent
+	for _, review := range reviews {
+		if review.State == "PENDING" || w.seenReviews[review.ID] {
+			continue
+		}
+		w.seenReviews[review.ID] = true
+
+		events = append(events, WatchEvent{
+			Type:        WatchEventReview,
+			PR:          w.pr,
+			Time:        now,
+			ReviewState: review.State,
+			Comment: Comment{
+				ID:        review.ID,
+				Author:    review.User.Login,
+				Body:      review.Body,
+				CreatedAt: review.SubmittedAt,
+				UpdatedAt: review.SubmittedAt,
+				Type:      "review",
+			},
+		})
+	}
+	return events
+}
+
+// matchesWatchFilters applies --author and --type to an event
+func matchesWatchFilters(event WatchEvent) bool {
+	if watchAuthor != "" && !matchesAuthorFilter(event.Comment.Author, watchAuthor) {
+		return false
+	}
+	if watchType != "" && event.Comment.Type != watchType {
+		return false
+	}
+	return true
+}
+
+// handleWatchEvent prints an event and runs the configured bell and hook
+func handleWatchEvent(event WatchEvent) {
+	if !matchesWatchFilters(event) {
+		return
+	}
+
+	if watchJSON {
+		data, err := json.Marshal(event)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  failed to encode event: %v\n", err)
+		} else {
+			fmt.Println(string(data))
+		}
+	} else {
+		displayWatchEvent(event)
+	}
+
+	if watchBell {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+
+	if watchExec != "" {
+		if err := runEventHook(watchExec, event, watchEventEnv(event), os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  hook failed for comment #%d: %v\n", event.Comment.ID, err)
+		}
+	}
+}
+
+// displayWatchEvent prints an event header followed by the comment
+func displayWatchEvent(event WatchEvent) {
+	labels := map[string]string{
+		WatchEventNew:        "🆕 New comment",
+		WatchEventEdited:     "✏️  Edited comment",
+		WatchEventResolved:   "✅ Thread resolved",
+		WatchEventUnresolved: "🔄 Thread unresolved",
+		WatchEventReview:     "📝 Review submitted",
+	}
+
+	fmt.Printf("%s %s on PR #%d\n", ColorizeHeader(event.Time.Format("15:04:05")), labels[event.Type], event.PR)
+	displayComment(event.Comment)
+}
+
+// runEventHook runs a shell command with payload as JSON on stdin and env added to the environment
+func runEventHook(command string, payload interface{}, env []string, stdout, stderr io.Writer) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	hook := exec.Command("sh", "-c", command)
+	hook.Stdin = bytes.NewReader(data)
+	hook.Stdout = stdout
+	hook.Stderr = stderr
+	hook.Env = append(os.Environ(), env...)
+
+	return hook.Run()
+}
+
+// watchEventEnv returns the GH_COMMENT_EVENT* variables describing an event
+func watchEventEnv(event WatchEvent) []string {
+	env := []string{
+		"GH_COMMENT_EVENT=" + event.Type,
+		"GH_COMMENT_EVENT_ID=" + strconv.Itoa(event.Comment.ID),
+		"GH_COMMENT_EVENT_PR=" + strconv.Itoa(event.PR),
+		"GH_COMMENT_EVENT_AUTHOR=" + event.Comment.Author,
+		"GH_COMMENT_EVENT_TYPE=" + event.Comment.Type,
+		"GH_COMMENT_EVENT_PATH=" + event.Comment.Path,
+		"GH_COMMENT_EVENT_BODY=" + event.Comment.Body,
+	}
+	line := ""
+	if event.Comment.Line > 0 {
+		line = strconv.Itoa(event.Comment.Line)
+	}
+	env = append(env, "GH_COMMENT_EVENT_LINE="+line)
+	if event.ReviewState != "" {
+		env = append(env, "GH_COMMENT_EVENT_REVIEW_STATE="+event.ReviewState)
+	}
+	return env
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/github"
+)
+
+func newWatchMockClient() *github.MockClient {
+	created := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	return &github.MockClient{
+		IssueComments: []github.Comment{
+			{ID: 1, Body: "Kicking off review", User: github.User{Login: "alice"}, CreatedAt: created, UpdatedAt: created},
+		},
+		ReviewComments: []github.Comment{
+			{ID: 2, Body: "Needs a test", User: github.User{Login: "bob"}, Path: "main.go", Line: 4, CreatedAt: created.Add(time.Minute), UpdatedAt: created.Add(time.Minute)},
+		},
+		ReviewThreads: []github.ReviewThread{
+			{ID: "T1", IsResolved: false, Path: "main.go", Line: 4, CommentIDs: []int{2}},
+		},
+	}
+}
+
+func eventTypes(events []WatchEvent) []string {
+	var types []string
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestCommentWatcherPoll(t *testing.T) {
+	client := newWatchMockClient()
+	watcher, err := newCommentWatcher(client, "owner/repo", 7)
+	require.NoError(t, err)
+
+	// First poll reports everything as new
+	events, err := watcher.Poll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{WatchEventNew, WatchEventNew}, eventTypes(events))
+	assert.Equal(t, 7, events[0].PR)
+
+	// Nothing changed: both listings are served from the ETag cache
+	events, err = watcher.Poll()
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, 2, client.NotModifiedCount)
+
+	// A new reply, an edit and a resolved thread
+	later := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	client.IssueComments[0].Body = "Kicking off review (edited)"
+	client.IssueComments[0].UpdatedAt = later
+	client.ReviewComments = append(client.ReviewComments, github.Comment{
+		ID: 3, Body: "Added", User: github.User{Login: "carol"}, Path: "main.go", Line: 4,
+		InReplyToID: 2, CreatedAt: later, UpdatedAt: later,
+	})
+	client.ReviewThreads[0].IsResolved = true
+	client.ReviewThreads[0].CommentIDs = []int{2, 3}
+
+	events, err = watcher.Poll()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{WatchEventEdited, WatchEventNew, WatchEventResolved}, eventTypes(events))
+
+	for _, event := range events {
+		switch event.Type {
+		case WatchEventEdited:
+			assert.Equal(t, 1, event.Comment.ID)
+		case WatchEventNew:
+			assert.Equal(t, 3, event.Comment.ID)
+		case WatchEventResolved:
+			assert.Equal(t, 2, event.Comment.ID, "resolution is reported on the thread's first comment")
+			assert.True(t, event.Comment.Resolved)
+		}
+	}
+
+	// Unresolving is reported too
+	client.ReviewThreads[0].IsResolved = false
+	events, err = watcher.Poll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{WatchEventUnresolved}, eventTypes(events))
+}
+
+func TestCommentWatcherNoThreads(t *testing.T) {
+	client := newWatchMockClient()
+	watcher, err := newCommentWatcher(client, "owner/repo", 7)
+	require.NoError(t, err)
+	watcher.threads = false
+
+	_, err = watcher.Poll()
+	require.NoError(t, err)
+	assert.Empty(t, client.ListedThreadPRs)
+}
+
+func TestCommentWatcherPollError(t *testing.T) {
+	client := newWatchMockClient()
+	client.ListReviewCommentsError = errors.New("server error")
+
+	watcher, err := newCommentWatcher(client, "owner/repo", 7)
+	require.NoError(t, err)
+
+	_, err = watcher.Poll()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "review comments")
+
+	_, err = newCommentWatcher(client, "invalid", 7)
+	assert.Error(t, err)
+}
+
+func TestRunWatchLoop(t *testing.T) {
+	noSleep := func(ctx context.Context, d time.Duration) error { return nil }
+
+	t.Run("skips existing comments by default", func(t *testing.T) {
+		client := newWatchMockClient()
+		watcher, err := newCommentWatcher(client, "owner/repo", 7)
+		require.NoError(t, err)
+
+		var handled []WatchEvent
+		err = runWatchLoop(context.Background(), watcher, watchOptions{Polls: 2, Sleep: noSleep}, func(e WatchEvent) {
+			handled = append(handled, e)
+		})
+		require.NoError(t, err)
+		assert.Empty(t, handled)
+	})
+
+	t.Run("show existing", func(t *testing.T) {
+		watcher, err := newCommentWatcher(newWatchMockClient(), "owner/repo", 7)
+		require.NoError(t, err)
+
+		var handled []WatchEvent
+		err = runWatchLoop(context.Background(), watcher, watchOptions{Polls: 1, ShowExisting: true, Sleep: noSleep}, func(e WatchEvent) {
+			handled = append(handled, e)
+		})
+		require.NoError(t, err)
+		assert.Len(t, handled, 2)
+	})
+
+	t.Run("stops when interrupted", func(t *testing.T) {
+		watcher, err := newCommentWatcher(newWatchMockClient(), "owner/repo", 7)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		polls := 0
+		err = runWatchLoop(ctx, watcher, watchOptions{Interval: time.Hour, Sleep: func(ctx context.Context, d time.Duration) error {
+			polls++
+			return sleepContext(ctx, d)
+		}}, func(WatchEvent) {})
+		require.NoError(t, err)
+		assert.Equal(t, 1, polls)
+	})
+
+	t.Run("first poll failure is fatal", func(t *testing.T) {
+		client := newWatchMockClient()
+		client.ListIssueCommentsError = errors.New("not found")
+		watcher, err := newCommentWatcher(client, "owner/repo", 7)
+		require.NoError(t, err)
+
+		err = runWatchLoop(context.Background(), watcher, watchOptions{Polls: 3, Sleep: noSleep}, func(WatchEvent) {})
+		assert.Error(t, err)
+	})
+}
+
+func TestMatchesWatchFilters(t *testing.T) {
+	defer func() { watchAuthor, watchType = "", "" }()
+
+	event := WatchEvent{Comment: Comment{Author: "bob", Type: "review"}}
+
+	watchAuthor, watchType = "", ""
+	assert.True(t, matchesWatchFilters(event))
+
+	watchAuthor = "b*"
+	assert.True(t, matchesWatchFilters(event))
+
+	watchType = "issue"
+	assert.False(t, matchesWatchFilters(event))
+
+	watchAuthor, watchType = "alice", ""
+	assert.False(t, matchesWatchFilters(event))
+}
+
+func TestNextPollDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, nextPollDelay(30*time.Second, 0))
+
+	for i := 0; i < 100; i++ {
+		delay := nextPollDelay(30*time.Second, 3*time.Second)
+		assert.GreaterOrEqual(t, delay, 27*time.Second)
+		assert.LessOrEqual(t, delay, 33*time.Second)
+	}
+}
+
+func TestRunEventHook(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "event.json")
+
+	event := WatchEvent{
+		Type:    WatchEventNew,
+		PR:      7,
+		Comment: Comment{ID: 42, Author: "bob", Type: "review", Path: "main.go", Line: 4, Body: "hello"},
+	}
+
+	var stdout bytes.Buffer
+	err := runEventHook(`cat > "`+out+`"; echo "$GH_COMMENT_EVENT $GH_COMMENT_EVENT_ID $GH_COMMENT_EVENT_PR $GH_COMMENT_EVENT_PATH:$GH_COMMENT_EVENT_LINE"`, event, watchEventEnv(event), &stdout, &stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "new 42 7 main.go:4\n", stdout.String())
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	var decoded WatchEvent
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 42, decoded.Comment.ID)
+	assert.Equal(t, "new", decoded.Type)
+
+	err = runEventHook("exit 3", event, nil, &stdout, &stdout)
+	assert.Error(t, err)
+}
+
+func TestEventHookEnvDoesNotOverrideConfig(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env")
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("defaults:\n  author: \"\"\n"), 0644))
+
+	event := WatchEvent{
+		Type:    WatchEventNew,
+		PR:      7,
+		Comment: Comment{ID: 42, Author: "bob", Type: "review", Path: "main.go", Line: 4, Body: "hello"},
+	}
+	var stdout bytes.Buffer
+	require.NoError(t, runEventHook(`env > "`+envFile+`"`, event, watchEventEnv(event), &stdout, &stdout))
+
+	// Load the config the way gh comment run from the hook would
+	data, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	for _, line := range strings.Split(string(data), "\n") {
+		name, value, ok := strings.Cut(line, "=")
+		if ok && strings.HasPrefix(name, "GH_COMMENT_") {
+			t.Setenv(name, value)
+		}
+	}
+	assert.Equal(t, "bob", os.Getenv("GH_COMMENT_EVENT_AUTHOR"))
+
+	config, err := LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Empty(t, config.Defaults.Author)
+	assert.Zero(t, config.Defaults.PR)
+}
+
+func TestDisplayWatchEvent(t *testing.T) {
+	output := captureOutput(func() {
+		displayWatchEvent(WatchEvent{
+			Type:    WatchEventResolved,
+			PR:      7,
+			Time:    time.Date(2024, 6, 1, 14, 5, 0, 0, time.UTC),
+			Comment: Comment{ID: 2, Author: "bob", Body: "Needs a test", Type: "review", CreatedAt: time.Now()},
+		})
+	})
+
+	assert.Contains(t, output, "14:05:00")
+	assert.Contains(t, output, "Thread resolved on PR #7")
+	assert.True(t, strings.Contains(output, "Needs a test"))
+}
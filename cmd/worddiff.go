@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// maxWordDiffCells bounds the LCS table; larger changes fall back to replacing the differing middle
+const maxWordDiffCells = 4_000_000
+
+var (
+	// wordDiffToken splits text into runs of whitespace, words and single punctuation marks
+	wordDiffToken = regexp.MustCompile(`\s+|[\p{L}\p{N}_]+|[^\s\p{L}\p{N}_]`)
+	wordDiffWord  = regexp.MustCompile(`[\p{L}\p{N}_]+`)
+)
+
+type wordDiffOp int
+
+const (
+	wordEqual wordDiffOp = iota
+	wordDelete
+	wordInsert
+)
+
+// wordDiffSegment is a run of text that was kept, deleted or inserted
+type wordDiffSegment struct {
+	Op   wordDiffOp
+	Text string
+}
+
+// wordDiff compares two texts word by word
+func wordDiff(oldText, newText string) []wordDiffSegment {
+	oldTokens := wordDiffToken.FindAllString(oldText, -1)
+	newTokens := wordDiffToken.FindAllString(newText, -1)
+
+	// Most edits touch a small part of the body, so trim the shared ends first
+	prefix := 0
+	for prefix < len(oldTokens) && prefix < len(newTokens) && oldTokens[prefix] == newTokens[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldTokens)-prefix && suffix < len(newTokens)-prefix &&
+		oldTokens[len(oldTokens)-1-suffix] == newTokens[len(newTokens)-1-suffix] {
+		suffix++
+	}
+
+	var segments []wordDiffSegment
+	add := func(op wordDiffOp, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(segments); n > 0 && segments[n-1].Op == op {
+			segments[n-1].Text += text
+			return
+		}
+		segments = append(segments, wordDiffSegment{Op: op, Text: text})
+	}
+
+	add(wordEqual, strings.Join(oldTokens[:prefix], ""))
+	oldMiddle := oldTokens[prefix : len(oldTokens)-suffix]
+	newMiddle := newTokens[prefix : len(newTokens)-suffix]
+	for _, segment := range mergeChangeRuns(diffTokens(oldMiddle, newMiddle)) {
+		add(segment.Op, segment.Text)
+	}
+	add(wordEqual, strings.Join(oldTokens[len(oldTokens)-suffix:], ""))
+	return segments
+}
+
+// diffTokens runs a longest-common-subsequence diff, deletions before insertions at each change
+func diffTokens(oldTokens, newTokens []string) []wordDiffSegment {
+	if len(oldTokens)*len(newTokens) > maxWordDiffCells || len(oldTokens) == 0 || len(newTokens) == 0 {
+		return []wordDiffSegment{
+			{Op: wordDelete, Text: strings.Join(oldTokens, "")},
+			{Op: wordInsert, Text: strings.Join(newTokens, "")},
+		}
+	}
+
+	// lcs[i][j] is the common length of oldTokens[i:] and newTokens[j:]
+	lcs := make([][]int, len(oldTokens)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newTokens)+1)
+	}
+	for i := len(oldTokens) - 1; i >= 0; i-- {
+		for j := len(newTokens) - 1; j >= 0; j-- {
+			if oldTokens[i] == newTokens[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var segments []wordDiffSegment
+	i, j := 0, 0
+	for i < len(oldTokens) || j < len(newTokens) {
+		switch {
+		case i < len(oldTokens) && j < len(newTokens) && oldTokens[i] == newTokens[j]:
+			segments = append(segments, wordDiffSegment{Op: wordEqual, Text: oldTokens[i]})
+			i++
+			j++
+		case j == len(newTokens) || (i < len(oldTokens) && lcs[i+1][j] >= lcs[i][j+1]):
+			segments = append(segments, wordDiffSegment{Op: wordDelete, Text: oldTokens[i]})
+			i++
+		default:
+			segments = append(segments, wordDiffSegment{Op: wordInsert, Text: newTokens[j]})
+			j++
+		}
+	}
+	return segments
+}
+
+// mergeChangeRuns folds the spaces that merely separate rewritten words into the
+// change, so "[-a-]{+b+} [-c-]{+d+}" reads as "[-a c-]{+b d+}"
+func mergeChangeRuns(segments []wordDiffSegment) []wordDiffSegment {
+	var merged []wordDiffSegment
+	for i := 0; i < len(segments); {
+		if segments[i].Op == wordEqual {
+			merged = append(merged, segments[i])
+			i++
+			continue
+		}
+
+		// A run is a stretch of changes joined only by whitespace
+		end := i
+		hasDelete, hasInsert := false, false
+		for end < len(segments) {
+			segment := segments[end]
+			if segment.Op == wordEqual &&
+				(strings.TrimSpace(segment.Text) != "" || end+1 == len(segments) || segments[end+1].Op == wordEqual) {
+				break
+			}
+			hasDelete = hasDelete || segment.Op == wordDelete
+			hasInsert = hasInsert || segment.Op == wordInsert
+			end++
+		}
+
+		if !hasDelete || !hasInsert {
+			merged = append(merged, segments[i:end]...)
+			i = end
+			continue
+		}
+
+		var deleted, inserted strings.Builder
+		for _, segment := range segments[i:end] {
+			if segment.Op != wordInsert {
+				deleted.WriteString(segment.Text)
+			}
+			if segment.Op != wordDelete {
+				inserted.WriteString(segment.Text)
+			}
+		}
+		merged = append(merged,
+			wordDiffSegment{Op: wordDelete, Text: deleted.String()},
+			wordDiffSegment{Op: wordInsert, Text: inserted.String()})
+		i = end
+	}
+	return merged
+}
+
+// wordDiffStats counts the words (not whitespace or punctuation) inserted and deleted
+func wordDiffStats(segments []wordDiffSegment) (added, removed int) {
+	for _, segment := range segments {
+		words := len(wordDiffWord.FindAllString(segment.Text, -1))
+		switch segment.Op {
+		case wordInsert:
+			added += words
+		case wordDelete:
+			removed += words
+		}
+	}
+	return added, removed
+}
+
+// renderWordDiff colours deletions red and insertions green, or marks them
+// [-like this-]{+like this+} when colour is off so the diff survives pipes and logs
+func renderWordDiff(segments []wordDiffSegment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		switch segment.Op {
+		case wordEqual:
+			b.WriteString(segment.Text)
+		case wordDelete:
+			if color.NoColor {
+				b.WriteString("[-" + segment.Text + "-]")
+			} else {
+				b.WriteString(mdDelStyle.Sprint(mdStrikeStyle.Sprint(segment.Text)))
+			}
+		case wordInsert:
+			if color.NoColor {
+				b.WriteString("{+" + segment.Text + "+}")
+			} else {
+				b.WriteString(mdAddStyle.Sprint(segment.Text))
+			}
+		}
+	}
+	return b.String()
+}
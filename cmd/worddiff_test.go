@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+// disableColor turns colour off for the rest of the test so diffs use text markers
+func disableColor(t *testing.T) {
+	t.Helper()
+	original := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = original })
+}
+
+func TestWordDiff(t *testing.T) {
+	disableColor(t)
+
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"unchanged", "Looks good", "Looks good", "Looks good"},
+		{"replaced word", "Use a map here", "Use a slice here", "Use a [-map-]{+slice+} here"},
+		{"inserted words", "Fix the bug", "Fix the nasty race bug", "Fix the {+nasty race +}bug"},
+		{"deleted sentence", "Nice. Please add tests.", "Nice.", "Nice.[- Please add tests.-]"},
+		{"punctuation is its own token", "done.", "done!", "done[-.-]{+!+}"},
+		{"from empty", "", "New text", "{+New text+}"},
+		{"rewrite reads as one change", "Great work on this PR.", "Flag message", "[-Great work on this PR.-]{+Flag message+}"},
+		{"separate edits stay separate", "one two three four", "ONE two three FOUR", "[-one-]{+ONE+} two three [-four-]{+FOUR+}"},
+		{"multi-line", "line one\nline two", "line one\nline 2", "line one\nline [-two-]{+2+}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderWordDiff(wordDiff(tt.old, tt.new)))
+		})
+	}
+}
+
+func TestWordDiffLargeChangeFallsBack(t *testing.T) {
+	disableColor(t)
+
+	old := strings.Repeat("a ", 3000)
+	new := strings.Repeat("b ", 3000)
+	segments := wordDiff("start "+old, "start "+new)
+	assert.Equal(t, []wordDiffSegment{
+		{Op: wordEqual, Text: "start "},
+		{Op: wordDelete, Text: strings.TrimSuffix(old, " ")},
+		{Op: wordInsert, Text: strings.TrimSuffix(new, " ")},
+		{Op: wordEqual, Text: " "},
+	}, segments)
+}
+
+func TestWordDiffStats(t *testing.T) {
+	added, removed := wordDiffStats(wordDiff("Use a map here", "Use a sorted slice here"))
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 1, removed)
+}
+
+func TestRenderWordDiffColor(t *testing.T) {
+	var rendered string
+	withNoColor(t, false, func() {
+		rendered = renderWordDiff(wordDiff("old word", "new word"))
+	})
+	assert.Contains(t, rendered, "\x1b[31m")
+	assert.Contains(t, rendered, "\x1b[32mnew")
+	assert.NotContains(t, rendered, "{+")
+}
@@ -0,0 +1,131 @@
+package fakegithub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is the seed state of a fake GitHub, usually loaded from a YAML file:
+//
+//	user: test-user
+//	repos:
+//	  test-owner/test-repo:
+//	    pulls:
+//	      - number: 123
+//	        title: Add login flow
+//	        author: test-user
+//	        comments:
+//	          - author: reviewer
+//	            body: Looks good!
+//	        review_comments:
+//	          - author: reviewer
+//	            path: src/main.go
+//	            line: 42
+//	            body: Please fix the typo
+//
+// IDs and timestamps are optional; missing ones are filled in when seeding.
+type Fixture struct {
+	User  string                 `yaml:"user"` // the authenticated user; defaults to "test-user"
+	Repos map[string]RepoFixture `yaml:"repos"`
+}
+
+// RepoFixture holds the pull requests of one "owner/repo"
+type RepoFixture struct {
+	Pulls []PullFixture `yaml:"pulls"`
+}
+
+// PullFixture is a pull request with its conversation
+type PullFixture struct {
+	Number    int        `yaml:"number"`
+	Title     string     `yaml:"title"`
+	Body      string     `yaml:"body"`
+	Author    string     `yaml:"author"`
+	State     string     `yaml:"state"` // open (default), closed or merged
+	Labels    []string   `yaml:"labels"`
+	CreatedAt time.Time  `yaml:"created_at"`
+	MergedAt  *time.Time `yaml:"merged_at"`
+	HeadSHA   string     `yaml:"head_sha"`
+
+	// Diff is the unified diff served at the PR's diff_url. When set, review
+	// comments must target lines inside its hunks, as on GitHub.
+	Diff string `yaml:"diff"`
+
+	Reactions      []ReactionFixture `yaml:"reactions"` // on the PR description
+	Comments       []CommentFixture  `yaml:"comments"`  // issue (conversation) comments
+	ReviewComments []CommentFixture  `yaml:"review_comments"`
+	Reviews        []ReviewFixture   `yaml:"reviews"`
+}
+
+// CommentFixture is an issue or review comment
+type CommentFixture struct {
+	ID        int       `yaml:"id"`
+	Author    string    `yaml:"author"`
+	Body      string    `yaml:"body"`
+	CreatedAt time.Time `yaml:"created_at"`
+	UpdatedAt time.Time `yaml:"updated_at"`
+
+	// Review comments only
+	Path      string `yaml:"path"`
+	Line      int    `yaml:"line"`
+	StartLine int    `yaml:"start_line"`
+	InReplyTo int    `yaml:"in_reply_to"` // ID of the first comment of the thread
+	Review    int    `yaml:"review"`      // ID of the review it belongs to
+	Resolved  bool   `yaml:"resolved"`    // thread state, set on the first comment
+	Outdated  bool   `yaml:"outdated"`
+
+	Reactions []ReactionFixture `yaml:"reactions"`
+	Edits     []EditFixture     `yaml:"edits"` // earlier versions of the body, oldest first
+}
+
+// ReactionFixture is one user's reaction
+type ReactionFixture struct {
+	Content string `yaml:"content"`
+	User    string `yaml:"user"`
+}
+
+// EditFixture is an earlier version of a comment body
+type EditFixture struct {
+	Body     string    `yaml:"body"`
+	Editor   string    `yaml:"editor"`
+	EditedAt time.Time `yaml:"edited_at"`
+}
+
+// ReviewFixture is a submitted or pending review
+type ReviewFixture struct {
+	ID          int       `yaml:"id"`
+	Author      string    `yaml:"author"`
+	Body        string    `yaml:"body"`
+	State       string    `yaml:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED or PENDING
+	SubmittedAt time.Time `yaml:"submitted_at"`
+}
+
+// LoadFixture reads a fixture file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	fixture, err := ParseFixture(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// ParseFixture parses fixture YAML, rejecting unknown fields so typos do not go unnoticed
+func ParseFixture(data []byte) (*Fixture, error) {
+	var fixture Fixture
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixture); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+	return &fixture, nil
+}
@@ -0,0 +1,200 @@
+package fakegithub
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// graphQLRequest is the body of a POST /graphql
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// serveGraphQL answers the queries gh-comment sends. It recognises each by the
+// field it asks for rather than parsing GraphQL, and returns every field that
+// query can select; clients ignore the ones they did not ask for.
+func (s *Server) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	var data interface{}
+	var err error
+	switch {
+	case strings.Contains(req.Query, "unresolveReviewThread"):
+		data, err = s.setThreadResolved(req.Variables, false)
+	case strings.Contains(req.Query, "resolveReviewThread"):
+		data, err = s.setThreadResolved(req.Variables, true)
+	case strings.Contains(req.Query, "reviewThreads"):
+		data, err = s.reviewThreads(req.Variables)
+	case strings.Contains(req.Query, "userContentEdits"):
+		data, err = s.userContentEdits(req.Variables)
+	default:
+		err = fmt.Errorf("fake GitHub does not support this query: %s", strings.Join(strings.Fields(req.Query), " "))
+	}
+
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data":   nil,
+			"errors": []map[string]interface{}{{"message": err.Error()}},
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+// reviewThreads answers repository(owner, name) { pullRequest(number) { reviewThreads } }
+func (s *Server) reviewThreads(variables map[string]interface{}) (interface{}, error) {
+	owner, _ := variables["owner"].(string)
+	name, _ := variables["name"].(string)
+	number, _ := variables["number"].(float64)
+
+	p := s.pulls[pullKey(owner+"/"+name, int(number))]
+	if p == nil {
+		return nil, fmt.Errorf("Could not resolve to a PullRequest with the number of %d.", int(number))
+	}
+
+	// A thread is a top-level review comment and the replies to it
+	replies := make(map[int][]interface{})
+	var roots []*comment
+	for _, c := range p.reviewComments {
+		if !s.isVisible(c) {
+			continue
+		}
+		if c.inReplyTo == 0 {
+			roots = append(roots, c)
+		}
+		root := c.inReplyTo
+		if root == 0 {
+			root = c.id
+		}
+		replies[root] = append(replies[root], map[string]interface{}{"databaseId": c.id})
+	}
+
+	nodes := make([]interface{}, 0, len(roots))
+	for _, root := range roots {
+		var resolvedBy interface{}
+		if root.resolved {
+			resolvedBy = map[string]interface{}{"login": root.resolvedBy}
+		}
+		var line interface{}
+		if root.line > 0 && !root.outdated {
+			line = root.line
+		}
+		nodes = append(nodes, map[string]interface{}{
+			"id":         threadID(root),
+			"isResolved": root.resolved,
+			"isOutdated": root.outdated,
+			"path":       root.path,
+			"line":       line,
+			"resolvedBy": resolvedBy,
+			"comments":   map[string]interface{}{"nodes": replies[root.id]},
+		})
+	}
+
+	return map[string]interface{}{
+		"repository": map[string]interface{}{
+			"pullRequest": map[string]interface{}{
+				"reviewThreads": map[string]interface{}{"nodes": nodes},
+			},
+		},
+	}, nil
+}
+
+// setThreadResolved answers the resolveReviewThread and unresolveReviewThread mutations
+func (s *Server) setThreadResolved(variables map[string]interface{}, resolved bool) (interface{}, error) {
+	id, _ := variables["threadId"].(string)
+	rootID, err := strconv.Atoi(strings.TrimPrefix(id, "PRRT_"))
+	root := s.comments[rootID]
+	if err != nil || !strings.HasPrefix(id, "PRRT_") || root == nil || !root.review || root.inReplyTo != 0 {
+		return nil, fmt.Errorf("Could not resolve to a node with the global id of '%s'", id)
+	}
+
+	root.resolved = resolved
+	root.resolvedBy = ""
+	if resolved {
+		root.resolvedBy = s.user
+	}
+
+	field := "resolveReviewThread"
+	if !resolved {
+		field = "unresolveReviewThread"
+	}
+	return map[string]interface{}{
+		field: map[string]interface{}{
+			"thread": map[string]interface{}{"id": id, "isResolved": resolved},
+		},
+	}, nil
+}
+
+// userContentEdits answers node(id) { ... on UserContentEditable { userContentEdits } },
+// newest edit first, in pages of `first` after the `cursor` variable
+func (s *Server) userContentEdits(variables map[string]interface{}) (interface{}, error) {
+	id, _ := variables["id"].(string)
+	c := s.commentByNodeID(id)
+	if c == nil {
+		return nil, fmt.Errorf("Could not resolve to a node with the global id of '%s'", id)
+	}
+
+	start := 0
+	if cursor, ok := variables["cursor"].(string); ok && cursor != "" {
+		n, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		start = n
+	}
+	pageSize := 100
+	if first, ok := variables["first"].(float64); ok && first > 0 {
+		pageSize = int(first)
+	}
+
+	var nodes []interface{}
+	end := min(start+pageSize, len(c.edits))
+	for i := start; i < end; i++ {
+		e := c.edits[len(c.edits)-1-i]
+		nodes = append(nodes, map[string]interface{}{
+			"editedAt":  e.editedAt,
+			"deletedAt": nil,
+			"diff":      e.body,
+			"editor":    map[string]interface{}{"login": e.editor},
+		})
+	}
+	if nodes == nil {
+		nodes = []interface{}{}
+	}
+
+	return map[string]interface{}{
+		"node": map[string]interface{}{
+			"userContentEdits": map[string]interface{}{
+				"nodes": nodes,
+				"pageInfo": map[string]interface{}{
+					"hasNextPage": end < len(c.edits),
+					"endCursor":   strconv.Itoa(end),
+				},
+			},
+		},
+	}, nil
+}
+
+// commentByNodeID finds a published comment from an IC_ or PRRC_ node ID
+func (s *Server) commentByNodeID(id string) *comment {
+	prefix, number, ok := strings.Cut(id, "_")
+	if !ok {
+		return nil
+	}
+	commentID, err := strconv.Atoi(number)
+	c := s.comments[commentID]
+	if err != nil || c == nil || !s.isVisible(c) || c.review != (prefix == "PRRC") || (prefix != "PRRC" && prefix != "IC") {
+		return nil
+	}
+	return c
+}
+
+func threadID(root *comment) string {
+	return fmt.Sprintf("PRRT_%d", root.id)
+}
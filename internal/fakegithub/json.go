@@ -0,0 +1,165 @@
+package fakegithub
+
+import (
+	"fmt"
+	"time"
+)
+
+// The functions here render state in the shape of GitHub's REST responses.
+// Maps keep optional fields out of responses the way GitHub does.
+
+func (s *Server) userJSON(login string) map[string]interface{} {
+	id, ok := s.userIDs[login]
+	if !ok {
+		id = 100 + len(s.userIDs)
+		s.userIDs[login] = id
+	}
+	return map[string]interface{}{
+		"login":      login,
+		"id":         id,
+		"node_id":    fmt.Sprintf("U_%d", id),
+		"avatar_url": fmt.Sprintf("https://avatars.githubusercontent.com/u/%d?v=4", id),
+		"html_url":   "https://github.com/" + login,
+		"type":       "User",
+	}
+}
+
+func (s *Server) apiURL(format string, args ...interface{}) string {
+	return s.URL() + fmt.Sprintf(format, args...)
+}
+
+func (s *Server) pullJSON(p *pull) map[string]interface{} {
+	labels := make([]interface{}, 0, len(p.labels))
+	for _, label := range p.labels {
+		labels = append(labels, map[string]interface{}{"name": label})
+	}
+	state := p.state
+	if state == "merged" {
+		state = "closed"
+	}
+
+	return map[string]interface{}{
+		"number":     p.number,
+		"node_id":    fmt.Sprintf("PR_%d", p.number),
+		"title":      p.title,
+		"body":       p.body,
+		"state":      state,
+		"merged":     p.mergedAt != nil,
+		"merged_at":  timeJSON(p.mergedAt),
+		"user":       s.userJSON(p.author),
+		"labels":     labels,
+		"created_at": p.createdAt,
+		"updated_at": p.updatedAt,
+		"url":        s.apiURL("/repos/%s/pulls/%d", p.repo, p.number),
+		"html_url":   fmt.Sprintf("https://github.com/%s/pull/%d", p.repo, p.number),
+		"diff_url":   s.apiURL("/%s/pull/%d.diff", p.repo, p.number),
+		"head":       map[string]interface{}{"sha": p.headSHA, "ref": fmt.Sprintf("pr-%d", p.number)},
+		"base":       map[string]interface{}{"ref": "main"},
+		"comments":   len(p.issueComments),
+	}
+}
+
+func (s *Server) searchItemJSON(p *pull) map[string]interface{} {
+	item := s.pullJSON(p)
+	item["pull_request"] = map[string]interface{}{
+		"url":       item["url"],
+		"html_url":  item["html_url"],
+		"diff_url":  item["diff_url"],
+		"merged_at": item["merged_at"],
+	}
+	item["url"] = s.apiURL("/repos/%s/issues/%d", p.repo, p.number)
+	return item
+}
+
+func (s *Server) commentJSON(c *comment) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":         c.id,
+		"body":       c.body,
+		"user":       s.userJSON(c.author),
+		"created_at": c.createdAt,
+		"updated_at": c.updatedAt,
+		"reactions":  s.reactionSummaryJSON(c),
+	}
+
+	if !c.review {
+		result["node_id"] = fmt.Sprintf("IC_%d", c.id)
+		result["url"] = s.apiURL("/repos/%s/issues/comments/%d", c.pull.repo, c.id)
+		result["issue_url"] = s.apiURL("/repos/%s/issues/%d", c.pull.repo, c.pull.number)
+		result["html_url"] = fmt.Sprintf("https://github.com/%s/pull/%d#issuecomment-%d", c.pull.repo, c.pull.number, c.id)
+		return result
+	}
+
+	result["node_id"] = fmt.Sprintf("PRRC_%d", c.id)
+	result["url"] = s.apiURL("/repos/%s/pulls/comments/%d", c.pull.repo, c.id)
+	result["pull_request_url"] = s.apiURL("/repos/%s/pulls/%d", c.pull.repo, c.pull.number)
+	result["html_url"] = fmt.Sprintf("https://github.com/%s/pull/%d#discussion_r%d", c.pull.repo, c.pull.number, c.id)
+	result["path"] = c.path
+	result["commit_id"] = c.pull.headSHA
+	result["original_commit_id"] = c.pull.headSHA
+	result["diff_hunk"] = fmt.Sprintf("@@ -%d,1 +%d,1 @@", max(c.line, 1), max(c.line, 1))
+	result["side"] = "RIGHT"
+	if c.line > 0 {
+		result["line"] = c.line
+		result["original_line"] = c.line
+		result["subject_type"] = "line"
+	} else {
+		result["line"] = nil
+		result["subject_type"] = "file"
+	}
+	if c.startLine > 0 {
+		result["start_line"] = c.startLine
+	} else {
+		result["start_line"] = nil
+	}
+	if c.inReplyTo != 0 {
+		result["in_reply_to_id"] = c.inReplyTo
+	}
+	if c.reviewID != 0 {
+		result["pull_request_review_id"] = c.reviewID
+	}
+	return result
+}
+
+func (s *Server) reactionSummaryJSON(c *comment) map[string]interface{} {
+	summary := map[string]interface{}{"total_count": len(c.reactions)}
+	for _, content := range []string{"+1", "-1", "laugh", "hooray", "confused", "heart", "rocket", "eyes"} {
+		summary[content] = 0
+	}
+	for _, re := range c.reactions {
+		summary[re.content] = summary[re.content].(int) + 1
+	}
+	return summary
+}
+
+func (s *Server) reactionJSON(re *reaction) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         re.id,
+		"node_id":    fmt.Sprintf("REA_%d", re.id),
+		"content":    re.content,
+		"user":       s.userJSON(re.user),
+		"created_at": re.createdAt,
+	}
+}
+
+func (s *Server) reviewJSON(rv *review) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":        rv.id,
+		"node_id":   fmt.Sprintf("PRR_%d", rv.id),
+		"user":      s.userJSON(rv.author),
+		"body":      rv.body,
+		"state":     rv.state,
+		"commit_id": rv.pull.headSHA,
+		"html_url":  fmt.Sprintf("https://github.com/%s/pull/%d#pullrequestreview-%d", rv.pull.repo, rv.pull.number, rv.id),
+	}
+	if rv.state != "PENDING" {
+		result["submitted_at"] = rv.submittedAt
+	}
+	return result
+}
+
+func timeJSON(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
@@ -0,0 +1,842 @@
+package fakegithub
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// restRoutes lists the REST endpoints. Literal segments are listed before
+// parameters that could also match them, e.g. pulls/comments before pulls/{number}.
+func (s *Server) restRoutes() []route {
+	routes := []struct {
+		pattern string
+		handle  func(w http.ResponseWriter, r *http.Request, params map[string]string)
+	}{
+		{"GET user", s.getUser},
+		{"GET rate_limit", s.getRateLimit},
+		{"GET search/issues", s.searchIssues},
+
+		{"GET repos/{owner}/{repo}/pulls/comments", s.listRepoReviewComments},
+		{"GET repos/{owner}/{repo}/pulls/comments/{id}", s.getComment(true)},
+		{"PATCH repos/{owner}/{repo}/pulls/comments/{id}", s.editComment(true)},
+		{"GET repos/{owner}/{repo}/pulls/comments/{id}/reactions", s.listCommentReactions(true)},
+		{"POST repos/{owner}/{repo}/pulls/comments/{id}/reactions", s.addCommentReaction(true)},
+		{"DELETE repos/{owner}/{repo}/pulls/comments/{id}/reactions/{reaction}", s.deleteCommentReaction(true)},
+
+		{"GET repos/{owner}/{repo}/issues/comments/{id}", s.getComment(false)},
+		{"PATCH repos/{owner}/{repo}/issues/comments/{id}", s.editComment(false)},
+		{"GET repos/{owner}/{repo}/issues/comments/{id}/reactions", s.listCommentReactions(false)},
+		{"POST repos/{owner}/{repo}/issues/comments/{id}/reactions", s.addCommentReaction(false)},
+		{"DELETE repos/{owner}/{repo}/issues/comments/{id}/reactions/{reaction}", s.deleteCommentReaction(false)},
+
+		{"GET repos/{owner}/{repo}/pulls/{number}", s.getPull},
+		{"GET repos/{owner}/{repo}/pulls/{number}/comments", s.listReviewComments},
+		{"POST repos/{owner}/{repo}/pulls/{number}/comments", s.createReviewComment},
+		{"GET repos/{owner}/{repo}/pulls/{number}/reviews", s.listReviews},
+		{"POST repos/{owner}/{repo}/pulls/{number}/reviews", s.createReview},
+		{"GET repos/{owner}/{repo}/pulls/{number}/reviews/{id}/comments", s.listReviewCommentsForReview},
+		{"POST repos/{owner}/{repo}/pulls/{number}/reviews/{id}/events", s.submitReview},
+
+		{"GET repos/{owner}/{repo}/issues/{number}/comments", s.listIssueComments},
+		{"POST repos/{owner}/{repo}/issues/{number}/comments", s.createIssueComment},
+		{"GET repos/{owner}/{repo}/issues/{number}/reactions", s.listPullReactions},
+		{"POST repos/{owner}/{repo}/issues/{number}/reactions", s.addPullReaction},
+		{"DELETE repos/{owner}/{repo}/issues/{number}/reactions/{reaction}", s.deletePullReaction},
+
+		// diff_url points here, like https://github.com/owner/repo/pull/123.diff
+		{"GET {owner}/{repo}/pull/{diff}", s.getDiff},
+	}
+
+	result := make([]route, 0, len(routes))
+	for _, rt := range routes {
+		method, path, _ := strings.Cut(rt.pattern, " ")
+		result = append(result, route{method: method, segments: strings.Split(path, "/"), handle: rt.handle})
+	}
+	return result
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	writeJSON(w, http.StatusOK, s.userJSON(s.user))
+}
+
+func (s *Server) getRateLimit(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	core := map[string]interface{}{
+		"limit":     5000,
+		"remaining": s.rateLimitRemaining(),
+		"used":      5000 - s.rateLimitRemaining(),
+		"reset":     s.now().Add(time.Hour).Unix(),
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"resources": map[string]interface{}{"core": core},
+		"rate":      core,
+	})
+}
+
+func (s *Server) getPull(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.pullJSON(p))
+}
+
+func (s *Server) getDiff(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	number, err := strconv.Atoi(strings.TrimSuffix(params["diff"], ".diff"))
+	if err != nil || !strings.HasSuffix(params["diff"], ".diff") {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	p := s.pulls[pullKey(params["owner"]+"/"+params["repo"], number)]
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(p.diff))
+}
+
+func (s *Server) listIssueComments(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	items := make([]interface{}, 0, len(p.issueComments))
+	for _, c := range p.issueComments {
+		items = append(items, s.commentJSON(c))
+	}
+	s.writeList(w, r, items)
+}
+
+func (s *Server) listReviewComments(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	items := make([]interface{}, 0, len(p.reviewComments))
+	for _, c := range p.reviewComments {
+		if s.isVisible(c) {
+			items = append(items, s.commentJSON(c))
+		}
+	}
+	s.writeList(w, r, items)
+}
+
+// listRepoReviewComments serves review comments across a repository's PRs
+func (s *Server) listRepoReviewComments(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	repo := params["owner"] + "/" + params["repo"]
+	query := r.URL.Query()
+
+	var since time.Time
+	if value := query.Get("since"); value != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid value for since: "+value)
+			return
+		}
+	}
+
+	var comments []*comment
+	for _, c := range s.comments {
+		if c.review && c.pull.repo == repo && s.isVisible(c) && !c.updatedAt.Before(since) {
+			comments = append(comments, c)
+		}
+	}
+
+	sortKey := func(c *comment) time.Time {
+		if query.Get("sort") == "updated" {
+			return c.updatedAt
+		}
+		return c.createdAt
+	}
+	desc := query.Get("direction") == "desc"
+	sort.SliceStable(comments, func(i, j int) bool {
+		a, b := sortKey(comments[i]), sortKey(comments[j])
+		if a.Equal(b) {
+			return (comments[i].id < comments[j].id) != desc
+		}
+		return a.Before(b) != desc
+	})
+
+	items := make([]interface{}, 0, len(comments))
+	for _, c := range comments {
+		items = append(items, s.commentJSON(c))
+	}
+	s.writeList(w, r, items)
+}
+
+func (s *Server) getComment(isReview bool) func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		c, ok := s.findComment(w, params, isReview)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, s.commentJSON(c))
+	}
+}
+
+func (s *Server) createIssueComment(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	var input struct {
+		Body string `json:"body"`
+	}
+	if !decodeBody(w, r, &input) {
+		return
+	}
+	if strings.TrimSpace(input.Body) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: body is missing")
+		return
+	}
+
+	c := s.addComment(p, false, input.Body)
+	writeJSON(w, http.StatusCreated, s.commentJSON(c))
+}
+
+// createReviewComment adds a standalone line or file comment, or a reply when in_reply_to is set
+func (s *Server) createReviewComment(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	var input struct {
+		Body        string `json:"body"`
+		Path        string `json:"path"`
+		Line        int    `json:"line"`
+		StartLine   int    `json:"start_line"`
+		CommitID    string `json:"commit_id"`
+		SubjectType string `json:"subject_type"`
+		InReplyTo   int    `json:"in_reply_to"`
+	}
+	if !decodeBody(w, r, &input) {
+		return
+	}
+	if strings.TrimSpace(input.Body) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: body is missing")
+		return
+	}
+
+	if input.InReplyTo != 0 {
+		parent := s.comments[input.InReplyTo]
+		if parent == nil || !parent.review || parent.pull != p || !s.isVisible(parent) {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		// Replies join the thread of its first comment
+		if parent.inReplyTo != 0 {
+			parent = s.comments[parent.inReplyTo]
+		}
+		c := s.addComment(p, true, input.Body)
+		c.path, c.line, c.startLine, c.inReplyTo = parent.path, parent.line, parent.startLine, parent.id
+		writeJSON(w, http.StatusCreated, s.commentJSON(c))
+		return
+	}
+
+	if input.CommitID == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: commit_id is missing")
+		return
+	}
+	if input.Path == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: path is missing")
+		return
+	}
+	if input.SubjectType != "file" {
+		if message := p.checkLines(input.Path, input.StartLine, input.Line); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+	}
+
+	c := s.addComment(p, true, input.Body)
+	c.path, c.line, c.startLine = input.Path, input.Line, input.StartLine
+	writeJSON(w, http.StatusCreated, s.commentJSON(c))
+}
+
+// editComment updates a comment body, keeping the previous version in its edit history
+func (s *Server) editComment(isReview bool) func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		c, ok := s.findComment(w, params, isReview)
+		if !ok {
+			return
+		}
+		var input struct {
+			Body string `json:"body"`
+		}
+		if !decodeBody(w, r, &input) {
+			return
+		}
+		if strings.TrimSpace(input.Body) == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed: body is missing")
+			return
+		}
+
+		now := s.now().UTC()
+		if len(c.edits) == 0 {
+			c.edits = append(c.edits, edit{body: c.body, editor: c.author, editedAt: c.updatedAt})
+		}
+		c.edits = append(c.edits, edit{body: input.Body, editor: s.user, editedAt: now})
+		c.body = input.Body
+		c.updatedAt = now
+		c.pull.updatedAt = now
+		writeJSON(w, http.StatusOK, s.commentJSON(c))
+	}
+}
+
+func (s *Server) listReviews(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	items := make([]interface{}, 0, len(p.reviews))
+	for _, rv := range p.reviews {
+		// Pending reviews are only visible to their author
+		if rv.state != "PENDING" || rv.author == s.user {
+			items = append(items, s.reviewJSON(rv))
+		}
+	}
+	s.writeList(w, r, items)
+}
+
+// listReviewCommentsForReview serves the comments of one review, including a
+// pending review's to its author
+func (s *Server) listReviewCommentsForReview(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(params["id"])
+	rv := s.reviews[id]
+	if err != nil || rv == nil || rv.pull != p || (rv.state == "PENDING" && rv.author != s.user) {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	items := make([]interface{}, 0)
+	for _, c := range p.reviewComments {
+		if c.reviewID == rv.id {
+			items = append(items, s.commentJSON(c))
+		}
+	}
+	s.writeList(w, r, items)
+}
+
+// reviewEvents maps a review event to the state it leaves the review in
+var reviewEvents = map[string]string{
+	"APPROVE":         "APPROVED",
+	"REQUEST_CHANGES": "CHANGES_REQUESTED",
+	"COMMENT":         "COMMENTED",
+}
+
+// createReview submits a review, or starts a pending one when no event is given
+func (s *Server) createReview(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	var input struct {
+		Body     string `json:"body"`
+		Event    string `json:"event"`
+		Comments []struct {
+			Body      string `json:"body"`
+			Path      string `json:"path"`
+			Line      int    `json:"line"`
+			StartLine int    `json:"start_line"`
+		} `json:"comments"`
+	}
+	if !decodeBody(w, r, &input) {
+		return
+	}
+
+	state := "PENDING"
+	if input.Event != "" {
+		var known bool
+		if state, known = reviewEvents[input.Event]; !known {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Unprocessable Entity: event %q is not one of APPROVE, REQUEST_CHANGES, COMMENT", input.Event))
+			return
+		}
+	}
+	if state == "PENDING" {
+		for _, rv := range p.reviews {
+			if rv.state == "PENDING" && rv.author == s.user {
+				writeError(w, http.StatusUnprocessableEntity, "Unprocessable Entity: User can only have one pending review per pull request")
+				return
+			}
+		}
+	}
+	if (state == "CHANGES_REQUESTED" || state == "COMMENTED") && strings.TrimSpace(input.Body) == "" && len(input.Comments) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "Unprocessable Entity: Review body is required")
+		return
+	}
+	for i, ci := range input.Comments {
+		if strings.TrimSpace(ci.Body) == "" || ci.Path == "" {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Unprocessable Entity: comment %d needs a body and a path", i+1))
+			return
+		}
+		if message := p.checkLines(ci.Path, ci.StartLine, ci.Line); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+	}
+
+	now := s.now().UTC()
+	rv := &review{id: s.claimID(0), pull: p, author: s.user, body: input.Body, state: state}
+	if state != "PENDING" {
+		rv.submittedAt = now
+	}
+	s.reviews[rv.id] = rv
+	p.reviews = append(p.reviews, rv)
+
+	for _, ci := range input.Comments {
+		c := s.addComment(p, true, ci.Body)
+		c.path, c.line, c.startLine, c.reviewID = ci.Path, ci.Line, ci.StartLine, rv.id
+	}
+	p.updatedAt = now
+	writeJSON(w, http.StatusOK, s.reviewJSON(rv))
+}
+
+// submitReview submits a pending review, publishing its comments
+func (s *Server) submitReview(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	p, ok := s.findPull(w, params)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(params["id"])
+	rv := s.reviews[id]
+	if err != nil || rv == nil || rv.pull != p || (rv.state == "PENDING" && rv.author != s.user) {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var input struct {
+		Body  string `json:"body"`
+		Event string `json:"event"`
+	}
+	if !decodeBody(w, r, &input) {
+		return
+	}
+	if rv.state != "PENDING" {
+		writeError(w, http.StatusUnprocessableEntity, "Unprocessable Entity: Can not submit a non-pending review")
+		return
+	}
+	state, known := reviewEvents[input.Event]
+	if !known {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Unprocessable Entity: event %q is not one of APPROVE, REQUEST_CHANGES, COMMENT", input.Event))
+		return
+	}
+
+	now := s.now().UTC()
+	rv.state, rv.submittedAt = state, now
+	if input.Body != "" {
+		rv.body = input.Body
+	}
+	p.updatedAt = now
+	writeJSON(w, http.StatusOK, s.reviewJSON(rv))
+}
+
+func (s *Server) listCommentReactions(isReview bool) func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		if c, ok := s.findComment(w, params, isReview); ok {
+			s.writeReactions(w, r, c.reactions)
+		}
+	}
+}
+
+func (s *Server) addCommentReaction(isReview bool) func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		if c, ok := s.findComment(w, params, isReview); ok {
+			s.addReaction(w, r, &c.reactions)
+		}
+	}
+}
+
+func (s *Server) deleteCommentReaction(isReview bool) func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		if c, ok := s.findComment(w, params, isReview); ok {
+			s.deleteReaction(w, params, &c.reactions)
+		}
+	}
+}
+
+func (s *Server) listPullReactions(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if p, ok := s.findPull(w, params); ok {
+		s.writeReactions(w, r, p.reactions)
+	}
+}
+
+func (s *Server) addPullReaction(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if p, ok := s.findPull(w, params); ok {
+		s.addReaction(w, r, &p.reactions)
+	}
+}
+
+func (s *Server) deletePullReaction(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	if p, ok := s.findPull(w, params); ok {
+		s.deleteReaction(w, params, &p.reactions)
+	}
+}
+
+func (s *Server) writeReactions(w http.ResponseWriter, r *http.Request, reactions []*reaction) {
+	items := make([]interface{}, 0, len(reactions))
+	for _, re := range reactions {
+		items = append(items, s.reactionJSON(re))
+	}
+	s.writeList(w, r, items)
+}
+
+// addReaction creates the user's reaction, returning the existing one (200) for a repeat
+func (s *Server) addReaction(w http.ResponseWriter, r *http.Request, reactions *[]*reaction) {
+	var input struct {
+		Content string `json:"content"`
+	}
+	if !decodeBody(w, r, &input) {
+		return
+	}
+	if !isReactionContent(input.Content) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Validation Failed: content %q is not a valid reaction", input.Content))
+		return
+	}
+
+	for _, re := range *reactions {
+		if re.content == input.Content && re.user == s.user {
+			writeJSON(w, http.StatusOK, s.reactionJSON(re))
+			return
+		}
+	}
+	re := s.newReaction(input.Content, s.user, s.now().UTC())
+	*reactions = append(*reactions, re)
+	writeJSON(w, http.StatusCreated, s.reactionJSON(re))
+}
+
+func (s *Server) deleteReaction(w http.ResponseWriter, params map[string]string, reactions *[]*reaction) {
+	id, err := strconv.Atoi(params["reaction"])
+	for i, re := range *reactions {
+		if err == nil && re.id == id {
+			if re.user != s.user {
+				writeError(w, http.StatusForbidden, "Must have admin rights to Repository.")
+				return
+			}
+			*reactions = append((*reactions)[:i], (*reactions)[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// searchIssues supports the qualifiers gh-comment builds: repo:, is:, type:,
+// author:, reviewed-by:, label: and updated:>=; other words must appear in the title
+func (s *Server) searchIssues(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	terms, err := splitSearchQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: "+err.Error())
+		return
+	}
+
+	var matches []*pull
+	for _, p := range s.pulls {
+		matched, err := s.matchesSearch(p, terms)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed: "+err.Error())
+			return
+		}
+		if matched {
+			matches = append(matches, p)
+		}
+	}
+	// GitHub's default order is best match; newest first keeps results stable
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].createdAt.Equal(matches[j].createdAt) {
+			return pullKey(matches[i].repo, matches[i].number) > pullKey(matches[j].repo, matches[j].number)
+		}
+		return matches[i].createdAt.After(matches[j].createdAt)
+	})
+
+	items := make([]interface{}, 0, len(matches))
+	for _, p := range matches {
+		items = append(items, s.searchItemJSON(p))
+	}
+	page, _, _ := paginate(w, r, items)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_count":        len(items),
+		"incomplete_results": false,
+		"items":              page,
+	})
+}
+
+func (s *Server) matchesSearch(p *pull, terms []string) (bool, error) {
+	for _, term := range terms {
+		qualifier, value, hasQualifier := strings.Cut(term, ":")
+		if !hasQualifier {
+			if !strings.Contains(strings.ToLower(p.title), strings.ToLower(term)) {
+				return false, nil
+			}
+			continue
+		}
+
+		switch qualifier {
+		case "repo":
+			if !strings.EqualFold(p.repo, value) {
+				return false, nil
+			}
+		case "is", "type":
+			switch value {
+			case "pr":
+			case "issue":
+				return false, nil
+			case "open", "closed", "merged":
+				if p.state != value && !(value == "closed" && p.state == "merged") {
+					return false, nil
+				}
+			default:
+				return false, fmt.Errorf("unsupported qualifier %q", term)
+			}
+		case "author":
+			if !strings.EqualFold(p.author, value) {
+				return false, nil
+			}
+		case "reviewed-by":
+			reviewed := false
+			for _, rv := range p.reviews {
+				reviewed = reviewed || (rv.state != "PENDING" && strings.EqualFold(rv.author, value))
+			}
+			if !reviewed {
+				return false, nil
+			}
+		case "label":
+			labelled := false
+			for _, label := range p.labels {
+				labelled = labelled || strings.EqualFold(label, value)
+			}
+			if !labelled {
+				return false, nil
+			}
+		case "updated", "created":
+			date, err := time.Parse("2006-01-02", strings.TrimPrefix(value, ">="))
+			if err != nil || !strings.HasPrefix(value, ">=") {
+				return false, fmt.Errorf("unsupported qualifier %q", term)
+			}
+			at := p.updatedAt
+			if qualifier == "created" {
+				at = p.createdAt
+			}
+			if at.Before(date) {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("unsupported qualifier %q", term)
+		}
+	}
+	return true, nil
+}
+
+// splitSearchQuery splits a search query on spaces, keeping quoted values like label:"needs review" whole
+func splitSearchQuery(query string) ([]string, error) {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in query %q", query)
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
+
+// writeList sends a page of a list with GitHub's Link header, an ETag, and
+// 304 Not Modified when the client already has this page
+func (s *Server) writeList(w http.ResponseWriter, r *http.Request, items []interface{}) {
+	page, _, ok := paginate(w, r, items)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(page)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	etag := fmt.Sprintf(`"%x"`, sha1.Sum(data))
+	w.Header().Set("ETag", etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.TrimPrefix(match, "W/") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(append(data, '\n'))
+}
+
+// paginate applies per_page (default 30, at most 100) and page, and sets the Link header
+func paginate(w http.ResponseWriter, r *http.Request, items []interface{}) ([]interface{}, int, bool) {
+	query := r.URL.Query()
+	perPage, page := 30, 1
+	if value := query.Get("per_page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid per_page: "+value)
+			return nil, 0, false
+		}
+		perPage = min(n, 100)
+	}
+	if value := query.Get("page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid page: "+value)
+			return nil, 0, false
+		}
+		page = n
+	}
+
+	lastPage := max((len(items)+perPage-1)/perPage, 1)
+	if page < lastPage {
+		next := *r.URL
+		values := next.Query()
+		values.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = values.Encode()
+		last := next
+		values.Set("page", strconv.Itoa(lastPage))
+		last.RawQuery = values.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next", <%s>; rel="last"`, linkURL(r, &next), linkURL(r, &last)))
+	}
+
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	return items[start:end], lastPage, true
+}
+
+func linkURL(r *http.Request, u *url.URL) string {
+	return "http://" + r.Host + u.RequestURI()
+}
+
+// decodeBody reads a JSON request body, answering 400 when it is malformed
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return false
+	}
+	return true
+}
+
+func (s *Server) findPull(w http.ResponseWriter, params map[string]string) (*pull, bool) {
+	number, err := strconv.Atoi(params["number"])
+	p := s.pulls[pullKey(params["owner"]+"/"+params["repo"], number)]
+	if err != nil || p == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil, false
+	}
+	return p, true
+}
+
+func (s *Server) findComment(w http.ResponseWriter, params map[string]string, isReview bool) (*comment, bool) {
+	id, err := strconv.Atoi(params["id"])
+	c := s.comments[id]
+	if err != nil || c == nil || c.review != isReview || c.pull.repo != params["owner"]+"/"+params["repo"] || !s.isVisible(c) {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil, false
+	}
+	return c, true
+}
+
+// isVisible reports whether a comment has been published; pending review comments are not
+func (s *Server) isVisible(c *comment) bool {
+	rv := s.reviews[c.reviewID]
+	return rv == nil || rv.state != "PENDING"
+}
+
+// addComment creates a comment by the authenticated user
+func (s *Server) addComment(p *pull, isReview bool, body string) *comment {
+	now := s.now().UTC()
+	c := &comment{id: s.claimID(0), review: isReview, pull: p, author: s.user, body: body, createdAt: now, updatedAt: now}
+	s.comments[c.id] = c
+	if isReview {
+		p.reviewComments = append(p.reviewComments, c)
+	} else {
+		p.issueComments = append(p.issueComments, c)
+	}
+	p.updatedAt = now
+	return c
+}
+
+// checkLines rejects comments on lines outside the PR's diff, as GitHub does.
+// Without a diff every line is accepted.
+func (p *pull) checkLines(path string, startLine, line int) string {
+	if p.diff == "" {
+		return ""
+	}
+	lines, ok := diffLines(p.diff)[path]
+	if !ok {
+		return fmt.Sprintf("Unprocessable Entity: Path %s could not be resolved", path)
+	}
+	if line <= 0 {
+		return "Unprocessable Entity: line must be part of the diff"
+	}
+	first := line
+	if startLine > 0 {
+		first = startLine
+	}
+	for n := first; n <= line; n++ {
+		if !lines[n] {
+			return fmt.Sprintf("Unprocessable Entity: Line %d could not be resolved in %s", n, path)
+		}
+	}
+	return ""
+}
+
+// diffLines lists the commentable lines (added or context) of each file in a unified diff
+func diffLines(diff string) map[string]map[int]bool {
+	files := make(map[string]map[int]bool)
+	var current map[int]bool
+	line := 0
+	for _, text := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			fields := strings.Fields(text)
+			current = make(map[int]bool)
+			files[strings.TrimPrefix(fields[len(fields)-1], "b/")] = current
+			line = 0
+		case strings.HasPrefix(text, "@@"):
+			// @@ -old,count +new,count @@
+			for _, field := range strings.Fields(text) {
+				if strings.HasPrefix(field, "+") {
+					start, _, _ := strings.Cut(field[1:], ",")
+					line, _ = strconv.Atoi(start)
+					break
+				}
+			}
+		case current == nil || line == 0 || strings.HasPrefix(text, "+++") || strings.HasPrefix(text, "---"):
+		case strings.HasPrefix(text, "+"), strings.HasPrefix(text, " "):
+			current[line] = true
+			line++
+		}
+	}
+	return files
+}
+
+func isReactionContent(content string) bool {
+	switch content {
+	case "+1", "-1", "laugh", "hooray", "confused", "heart", "rocket", "eyes":
+		return true
+	}
+	return false
+}
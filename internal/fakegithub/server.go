@@ -0,0 +1,424 @@
+// Package fakegithub is an in-process, stateful fake of the GitHub API for tests
+// and offline demos. It serves the REST endpoints and the GraphQL subset that
+// gh-comment uses: comments, reviews, review threads, reactions, edit history,
+// diffs and pending reviews. Writes change the state, so a comment added through
+// the API shows up in the next listing.
+//
+// Point the real client at it with github.NewRealClientForServer(server.URL()),
+// or set MOCK_SERVER_URL for the gh-comment binary.
+package fakegithub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultUser is the authenticated user when a fixture does not name one
+const DefaultUser = "test-user"
+
+// Server is a fake GitHub API backed by an httptest server
+type Server struct {
+	server *httptest.Server
+	routes []route
+
+	mu       sync.Mutex
+	user     string
+	now      func() time.Time
+	nextID   int
+	userIDs  map[string]int
+	pulls    map[string]*pull // by "owner/repo#number"
+	comments map[int]*comment
+	reviews  map[int]*review
+	requests []string
+}
+
+type pull struct {
+	repo      string
+	number    int
+	title     string
+	body      string
+	author    string
+	state     string
+	labels    []string
+	createdAt time.Time
+	updatedAt time.Time
+	mergedAt  *time.Time
+	headSHA   string
+	diff      string
+
+	reactions      []*reaction
+	issueComments  []*comment
+	reviewComments []*comment
+	reviews        []*review
+}
+
+type comment struct {
+	id        int
+	review    bool // review (diff) comment rather than issue comment
+	pull      *pull
+	author    string
+	body      string
+	createdAt time.Time
+	updatedAt time.Time
+
+	path       string
+	line       int
+	startLine  int
+	inReplyTo  int
+	reviewID   int
+	resolved   bool
+	resolvedBy string
+	outdated   bool
+
+	reactions []*reaction
+	edits     []edit // every version once edited, oldest first
+}
+
+type reaction struct {
+	id        int
+	content   string
+	user      string
+	createdAt time.Time
+}
+
+type edit struct {
+	body     string
+	editor   string
+	editedAt time.Time
+}
+
+type review struct {
+	id          int
+	pull        *pull
+	author      string
+	body        string
+	state       string
+	submittedAt time.Time
+}
+
+// New starts an empty fake GitHub. Call Close when done.
+func New() *Server {
+	s := &Server{
+		user:     DefaultUser,
+		now:      time.Now,
+		nextID:   1000,
+		userIDs:  make(map[string]int),
+		pulls:    make(map[string]*pull),
+		comments: make(map[int]*comment),
+		reviews:  make(map[int]*review),
+	}
+	s.routes = s.restRoutes()
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewFromFixture starts a fake GitHub seeded from a fixture file
+func NewFromFixture(path string) (*Server, error) {
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := New()
+	if err := s.Seed(fixture); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// URL is the base URL of the fake API
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetClock replaces the time source used for new comments, edits and reviews
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// Requests lists the requests served so far as "METHOD /path?query", in order
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Seed adds the fixture's pull requests and conversations to the server state
+func (s *Server) Seed(fixture *Fixture) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fixture.User != "" {
+		s.user = fixture.User
+	}
+
+	// Seed in a stable order so generated IDs do not depend on map iteration
+	repos := make([]string, 0, len(fixture.Repos))
+	for name := range fixture.Repos {
+		repos = append(repos, name)
+	}
+	sort.Strings(repos)
+
+	for _, name := range repos {
+		if strings.Count(name, "/") != 1 {
+			return fmt.Errorf("invalid repository %q: expected owner/repo", name)
+		}
+		for _, pf := range fixture.Repos[name].Pulls {
+			if err := s.seedPull(name, pf); err != nil {
+				return fmt.Errorf("%s#%d: %w", name, pf.Number, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) seedPull(repo string, pf PullFixture) error {
+	if pf.Number <= 0 {
+		return fmt.Errorf("pull request number must be positive")
+	}
+	key := pullKey(repo, pf.Number)
+	if _, exists := s.pulls[key]; exists {
+		return fmt.Errorf("pull request seeded twice")
+	}
+
+	p := &pull{
+		repo:      repo,
+		number:    pf.Number,
+		title:     pf.Title,
+		body:      pf.Body,
+		author:    orDefault(pf.Author, s.user),
+		state:     orDefault(pf.State, "open"),
+		labels:    pf.Labels,
+		createdAt: orNow(pf.CreatedAt, s.now),
+		mergedAt:  pf.MergedAt,
+		headSHA:   orDefault(pf.HeadSHA, fmt.Sprintf("%040d", pf.Number)),
+		diff:      pf.Diff,
+	}
+	if p.title == "" {
+		p.title = fmt.Sprintf("Pull request #%d", pf.Number)
+	}
+	if p.state == "merged" && p.mergedAt == nil {
+		mergedAt := p.createdAt
+		p.mergedAt = &mergedAt
+	}
+	p.updatedAt = p.createdAt
+	s.pulls[key] = p
+
+	for _, rf := range pf.Reactions {
+		p.reactions = append(p.reactions, s.newReaction(rf.Content, rf.User, p.createdAt))
+	}
+
+	for _, rf := range pf.Reviews {
+		r := &review{
+			id:          s.claimID(rf.ID),
+			pull:        p,
+			author:      orDefault(rf.Author, s.user),
+			body:        rf.Body,
+			state:       orDefault(rf.State, "COMMENTED"),
+			submittedAt: orNow(rf.SubmittedAt, func() time.Time { return p.createdAt }),
+		}
+		if _, exists := s.reviews[r.id]; exists {
+			return fmt.Errorf("review ID %d used twice", r.id)
+		}
+		s.reviews[r.id] = r
+		p.reviews = append(p.reviews, r)
+	}
+
+	for _, cf := range pf.Comments {
+		if cf.Path != "" || cf.InReplyTo != 0 {
+			return fmt.Errorf("issue comment %q cannot have a path or in_reply_to; use review_comments", cf.Body)
+		}
+		c, err := s.seedComment(p, cf, false)
+		if err != nil {
+			return err
+		}
+		p.issueComments = append(p.issueComments, c)
+	}
+
+	for _, cf := range pf.ReviewComments {
+		c, err := s.seedComment(p, cf, true)
+		if err != nil {
+			return err
+		}
+		if c.inReplyTo != 0 {
+			parent, ok := s.comments[c.inReplyTo]
+			if !ok || !parent.review || parent.pull != p {
+				return fmt.Errorf("review comment %d replies to unknown comment %d", c.id, c.inReplyTo)
+			}
+			c.path, c.line, c.startLine = parent.path, parent.line, parent.startLine
+		} else if c.path == "" {
+			return fmt.Errorf("review comment %d needs a path", c.id)
+		}
+		if c.reviewID != 0 && s.reviews[c.reviewID] == nil {
+			return fmt.Errorf("review comment %d belongs to unknown review %d", c.id, c.reviewID)
+		}
+		p.reviewComments = append(p.reviewComments, c)
+	}
+	return nil
+}
+
+func (s *Server) seedComment(p *pull, cf CommentFixture, isReview bool) (*comment, error) {
+	c := &comment{
+		id:        s.claimID(cf.ID),
+		review:    isReview,
+		pull:      p,
+		author:    orDefault(cf.Author, s.user),
+		body:      cf.Body,
+		createdAt: orNow(cf.CreatedAt, func() time.Time { return p.createdAt }),
+		path:      cf.Path,
+		line:      cf.Line,
+		startLine: cf.StartLine,
+		inReplyTo: cf.InReplyTo,
+		reviewID:  cf.Review,
+		resolved:  cf.Resolved,
+		outdated:  cf.Outdated,
+	}
+	if _, exists := s.comments[c.id]; exists {
+		return nil, fmt.Errorf("comment ID %d used twice", c.id)
+	}
+	c.updatedAt = orNow(cf.UpdatedAt, func() time.Time { return c.createdAt })
+	if c.resolved {
+		c.resolvedBy = p.author
+	}
+
+	if len(cf.Edits) > 0 {
+		for _, ef := range cf.Edits {
+			c.edits = append(c.edits, edit{
+				body:     ef.Body,
+				editor:   orDefault(ef.Editor, c.author),
+				editedAt: orNow(ef.EditedAt, func() time.Time { return c.createdAt }),
+			})
+		}
+		c.edits = append(c.edits, edit{body: c.body, editor: c.author, editedAt: c.updatedAt})
+	}
+
+	for _, rf := range cf.Reactions {
+		c.reactions = append(c.reactions, s.newReaction(rf.Content, rf.User, c.createdAt))
+	}
+
+	s.comments[c.id] = c
+	return c, nil
+}
+
+// claimID returns id when set, or the next free generated ID
+func (s *Server) claimID(id int) int {
+	if id > 0 {
+		if id >= s.nextID {
+			s.nextID = id + 1
+		}
+		return id
+	}
+	s.nextID++
+	return s.nextID - 1
+}
+
+func (s *Server) newReaction(content, user string, at time.Time) *reaction {
+	return &reaction{id: s.claimID(0), content: content, user: orDefault(user, s.user), createdAt: at}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+	w.Header().Set("X-RateLimit-Limit", "5000")
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(s.rateLimitRemaining()))
+
+	if r.Method == http.MethodPost && r.URL.Path == "/graphql" {
+		s.serveGraphQL(w, r)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	matchedPath := false
+	for _, rt := range s.routes {
+		params, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		matchedPath = true
+		if rt.method == r.Method {
+			rt.handle(w, r, params)
+			return
+		}
+	}
+
+	if matchedPath {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) rateLimitRemaining() int {
+	return max(5000-len(s.requests), 0)
+}
+
+// route is a REST endpoint like "GET repos/{owner}/{repo}/pulls/{number}"
+type route struct {
+	method   string
+	segments []string
+	handle   func(w http.ResponseWriter, r *http.Request, params map[string]string)
+}
+
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "{") {
+			params[strings.Trim(segment, "{}")] = segments[i]
+		} else if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// writeJSON sends a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) // the client hung up; nothing to report to
+}
+
+// writeError sends an error in GitHub's format
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+func pullKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", repo, number)
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func orNow(t time.Time, now func() time.Time) time.Time {
+	if t.IsZero() {
+		return now().UTC()
+	}
+	return t.UTC()
+}
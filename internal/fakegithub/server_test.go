@@ -0,0 +1,422 @@
+package fakegithub
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFixture = `
+user: me
+repos:
+  octo/app:
+    pulls:
+      - number: 7
+        title: Add caching
+        author: alice
+        labels: [perf]
+        created_at: 2024-03-01T10:00:00Z
+        diff: |
+          diff --git a/cache.go b/cache.go
+          --- a/cache.go
+          +++ b/cache.go
+          @@ -10,2 +10,3 @@
+           func get() {
+          +	lock()
+           }
+        comments:
+          - id: 11
+            author: bob
+            body: Nice
+            reactions:
+              - content: heart
+                user: carol
+        review_comments:
+          - id: 21
+            author: bob
+            path: cache.go
+            line: 11
+            body: Why lock here?
+            edits:
+              - body: Why?
+          - id: 22
+            author: alice
+            in_reply_to: 21
+            body: Races
+          - id: 23
+            author: me
+            review: 31
+            path: cache.go
+            line: 10
+            body: Draft note
+        reviews:
+          - id: 31
+            author: me
+            state: PENDING
+      - number: 8
+        title: Fix typo
+        state: merged
+        author: bob
+        created_at: 2024-02-01T10:00:00Z
+`
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	fixture, err := ParseFixture([]byte(testFixture))
+	require.NoError(t, err)
+
+	s := New()
+	t.Cleanup(s.Close)
+	require.NoError(t, s.Seed(fixture))
+	s.SetClock(func() time.Time { return time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC) })
+	return s
+}
+
+// call sends a request and decodes the JSON response into out, returning the response
+func call(t *testing.T, s *Server, method, path, body string, out interface{}) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL()+path, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if out != nil && len(data) > 0 {
+		require.NoError(t, json.Unmarshal(data, out), string(data))
+	}
+	return resp
+}
+
+func TestParseFixtureRejectsUnknownFields(t *testing.T) {
+	_, err := ParseFixture([]byte("repos:\n  o/r:\n    pulls:\n      - number: 1\n        titel: typo\n"))
+	assert.ErrorContains(t, err, "field titel not found")
+
+	fixture, err := ParseFixture(nil)
+	require.NoError(t, err)
+	assert.Empty(t, fixture.Repos)
+}
+
+func TestSeedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		wantErr string
+	}{
+		{"bad repo name", "repos:\n  app:\n    pulls: [{number: 1}]", `invalid repository "app"`},
+		{"duplicate comment ID", "repos:\n  o/r:\n    pulls:\n      - number: 1\n        comments: [{id: 5, body: a}, {id: 5, body: b}]", "comment ID 5 used twice"},
+		{"reply to unknown comment", "repos:\n  o/r:\n    pulls:\n      - number: 1\n        review_comments: [{in_reply_to: 9, body: a}]", "replies to unknown comment 9"},
+		{"review comment without path", "repos:\n  o/r:\n    pulls:\n      - number: 1\n        review_comments: [{body: a}]", "needs a path"},
+		{"issue comment with path", "repos:\n  o/r:\n    pulls:\n      - number: 1\n        comments: [{path: a.go, body: a}]", "use review_comments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture, err := ParseFixture([]byte(tt.fixture))
+			require.NoError(t, err)
+			s := New()
+			defer s.Close()
+			assert.ErrorContains(t, s.Seed(fixture), tt.wantErr)
+		})
+	}
+}
+
+func TestListingsHidePendingReviewComments(t *testing.T) {
+	s := newTestServer(t)
+
+	var comments []map[string]interface{}
+	call(t, s, "GET", "/repos/octo/app/pulls/7/comments", "", &comments)
+	require.Len(t, comments, 2)
+	assert.Equal(t, float64(21), comments[0]["id"])
+	assert.Equal(t, "PRRC_21", comments[0]["node_id"])
+	assert.Equal(t, float64(21), comments[1]["in_reply_to_id"])
+	assert.Equal(t, "cache.go", comments[1]["path"], "replies take the thread's location")
+	assert.True(t, strings.HasSuffix(comments[0]["pull_request_url"].(string), "/repos/octo/app/pulls/7"))
+
+	var issueComments []map[string]interface{}
+	call(t, s, "GET", "/repos/octo/app/issues/7/comments", "", &issueComments)
+	require.Len(t, issueComments, 1)
+	assert.Equal(t, "bob", issueComments[0]["user"].(map[string]interface{})["login"])
+	assert.Equal(t, float64(1), issueComments[0]["reactions"].(map[string]interface{})["heart"])
+
+	resp := call(t, s, "GET", "/repos/octo/app/pulls/comments/23", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestPaginationAndConditionalRequests(t *testing.T) {
+	s := New()
+	t.Cleanup(s.Close)
+	require.NoError(t, s.Seed(&Fixture{Repos: map[string]RepoFixture{"o/r": {Pulls: []PullFixture{{Number: 1}}}}}))
+	for i := 0; i < 5; i++ {
+		call(t, s, "POST", "/repos/o/r/issues/1/comments", `{"body":"hi"}`, nil)
+	}
+
+	var page []map[string]interface{}
+	resp := call(t, s, "GET", "/repos/o/r/issues/1/comments?per_page=2&page=2", "", &page)
+	assert.Len(t, page, 2)
+	assert.Contains(t, resp.Header.Get("Link"), `page=3&per_page=2>; rel="next"`)
+	assert.Contains(t, resp.Header.Get("Link"), `rel="last"`)
+
+	resp = call(t, s, "GET", "/repos/o/r/issues/1/comments?per_page=2&page=3", "", &page)
+	assert.Len(t, page, 1)
+	assert.Empty(t, resp.Header.Get("Link"))
+
+	resp = call(t, s, "GET", "/repos/o/r/issues/1/comments", "", nil)
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req, err := http.NewRequest("GET", s.URL()+"/repos/o/r/issues/1/comments", nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	call(t, s, "POST", "/repos/o/r/issues/1/comments", `{"body":"changed"}`, nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestErrorsUseGitHubShape(t *testing.T) {
+	s := newTestServer(t)
+
+	var body map[string]string
+	resp := call(t, s, "GET", "/repos/octo/app/pulls/99", "", &body)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "Not Found", body["message"])
+	assert.NotEmpty(t, body["documentation_url"])
+
+	resp = call(t, s, "DELETE", "/repos/octo/app/pulls/7", "", &body)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp = call(t, s, "POST", "/repos/octo/app/issues/7/comments", `{"body":`, &body)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = call(t, s, "POST", "/repos/octo/app/pulls/7/comments",
+		`{"body":"x","path":"cache.go","line":30,"commit_id":"abc"}`, &body)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Contains(t, body["message"], "Line 30 could not be resolved in cache.go")
+
+	resp = call(t, s, "POST", "/repos/octo/app/pulls/7/comments",
+		`{"body":"x","path":"cache.go","line":11,"commit_id":"abc"}`, nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+}
+
+func TestPendingReviewLifecycle(t *testing.T) {
+	s := newTestServer(t)
+
+	var body map[string]string
+	resp := call(t, s, "POST", "/repos/octo/app/pulls/7/reviews", `{"body":"another draft"}`, &body)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Contains(t, body["message"], "one pending review")
+
+	var reviews []map[string]interface{}
+	call(t, s, "GET", "/repos/octo/app/pulls/7/reviews", "", &reviews)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "PENDING", reviews[0]["state"])
+	assert.NotContains(t, reviews[0], "submitted_at")
+
+	var review map[string]interface{}
+	resp = call(t, s, "POST", "/repos/octo/app/pulls/7/reviews/31/events", `{"event":"APPROVE","body":"Ship it"}`, &review)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "APPROVED", review["state"])
+	assert.Equal(t, "2024-03-02T12:00:00Z", review["submitted_at"])
+
+	var comments []map[string]interface{}
+	call(t, s, "GET", "/repos/octo/app/pulls/7/comments", "", &comments)
+	assert.Len(t, comments, 3, "submitting publishes the draft comment")
+
+	resp = call(t, s, "POST", "/repos/octo/app/pulls/7/reviews/31/events", `{"event":"APPROVE"}`, &body)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestReactions(t *testing.T) {
+	s := newTestServer(t)
+
+	var reaction map[string]interface{}
+	resp := call(t, s, "POST", "/repos/octo/app/issues/comments/11/reactions", `{"content":"rocket"}`, &reaction)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp = call(t, s, "POST", "/repos/octo/app/issues/comments/11/reactions", `{"content":"rocket"}`, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "reacting twice returns the existing reaction")
+	resp = call(t, s, "POST", "/repos/octo/app/issues/comments/11/reactions", `{"content":"tada"}`, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var reactions []map[string]interface{}
+	call(t, s, "GET", "/repos/octo/app/issues/comments/11/reactions", "", &reactions)
+	require.Len(t, reactions, 2)
+
+	// Only your own reactions can be deleted
+	resp = call(t, s, "DELETE", "/repos/octo/app/issues/comments/11/reactions/"+jsonNumber(reactions[0]["id"]), "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = call(t, s, "DELETE", "/repos/octo/app/issues/comments/11/reactions/"+jsonNumber(reaction["id"]), "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Review comments and PR descriptions have their own reactions
+	resp = call(t, s, "POST", "/repos/octo/app/pulls/comments/21/reactions", `{"content":"eyes"}`, nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp = call(t, s, "POST", "/repos/octo/app/issues/7/reactions", `{"content":"hooray"}`, nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	call(t, s, "GET", "/repos/octo/app/issues/7/reactions", "", &reactions)
+	assert.Len(t, reactions, 1)
+}
+
+func TestSearch(t *testing.T) {
+	s := newTestServer(t)
+
+	search := func(q string) []float64 {
+		var result struct {
+			TotalCount int `json:"total_count"`
+			Items      []struct {
+				Number float64 `json:"number"`
+			} `json:"items"`
+		}
+		resp := call(t, s, "GET", "/search/issues?q="+strings.ReplaceAll(q, " ", "+"), "", &result)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var numbers []float64
+		for _, item := range result.Items {
+			numbers = append(numbers, item.Number)
+		}
+		return numbers
+	}
+
+	assert.Equal(t, []float64{7, 8}, search("repo:octo/app is:pr"))
+	assert.Equal(t, []float64{7}, search("repo:octo/app is:pr is:open"))
+	assert.Equal(t, []float64{8}, search("repo:octo/app is:merged author:bob"))
+	assert.Equal(t, []float64{7}, search(`repo:octo/app label:"perf"`))
+	assert.Equal(t, []float64{7}, search("repo:octo/app updated:>=2024-02-15"))
+	assert.Equal(t, []float64{8}, search("repo:octo/app typo"))
+	assert.Empty(t, search("repo:other/app"))
+
+	var body map[string]string
+	resp := call(t, s, "GET", "/search/issues?q=repo:octo/app+stars:>5", "", &body)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Contains(t, body["message"], `unsupported qualifier "stars:>5"`)
+}
+
+func TestGraphQL(t *testing.T) {
+	s := newTestServer(t)
+
+	graphql := func(query string, variables map[string]interface{}, out interface{}) {
+		payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+		require.NoError(t, err)
+		call(t, s, "POST", "/graphql", string(payload), out)
+	}
+
+	var threads struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							ID         string `json:"id"`
+							IsResolved bool   `json:"isResolved"`
+							Comments   struct {
+								Nodes []struct {
+									DatabaseID int `json:"databaseId"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	threadsQuery := `query { repository { pullRequest { reviewThreads(first: 100) { nodes { id } } } } }`
+	graphql(threadsQuery, map[string]interface{}{"owner": "octo", "name": "app", "number": 7}, &threads)
+	nodes := threads.Data.Repository.PullRequest.ReviewThreads.Nodes
+	require.Len(t, nodes, 1, "the pending draft is not a thread yet")
+	assert.Equal(t, "PRRT_21", nodes[0].ID)
+	assert.Len(t, nodes[0].Comments.Nodes, 2)
+	assert.False(t, nodes[0].IsResolved)
+
+	graphql(`mutation($threadId: ID!) { resolveReviewThread(input: {threadId: $threadId}) { thread { id } } }`,
+		map[string]interface{}{"threadId": "PRRT_21"}, nil)
+	graphql(threadsQuery, map[string]interface{}{"owner": "octo", "name": "app", "number": 7}, &threads)
+	assert.True(t, threads.Data.Repository.PullRequest.ReviewThreads.Nodes[0].IsResolved)
+
+	var edits struct {
+		Data struct {
+			Node struct {
+				UserContentEdits struct {
+					Nodes []struct {
+						Diff string `json:"diff"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"userContentEdits"`
+			} `json:"node"`
+		} `json:"data"`
+	}
+	editsQuery := `query($id: ID!) { node(id: $id) { ... on UserContentEditable { userContentEdits { nodes { diff } } } } }`
+	graphql(editsQuery, map[string]interface{}{"id": "PRRC_21", "first": 1}, &edits)
+	assert.Equal(t, "Why lock here?", edits.Data.Node.UserContentEdits.Nodes[0].Diff, "newest first")
+	require.True(t, edits.Data.Node.UserContentEdits.PageInfo.HasNextPage)
+	graphql(editsQuery, map[string]interface{}{"id": "PRRC_21", "first": 1, "cursor": edits.Data.Node.UserContentEdits.PageInfo.EndCursor}, &edits)
+	assert.Equal(t, "Why?", edits.Data.Node.UserContentEdits.Nodes[0].Diff)
+	assert.False(t, edits.Data.Node.UserContentEdits.PageInfo.HasNextPage)
+
+	var failure struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	graphql(`query { viewer { login } }`, nil, &failure)
+	require.Len(t, failure.Errors, 1)
+	assert.Contains(t, failure.Errors[0].Message, "does not support this query")
+
+	graphql(`mutation { resolveReviewThread }`, map[string]interface{}{"threadId": "PRRT_22"}, &failure)
+	assert.Contains(t, failure.Errors[0].Message, "Could not resolve to a node")
+}
+
+func TestEditRecordsHistory(t *testing.T) {
+	s := newTestServer(t)
+
+	var comment map[string]interface{}
+	call(t, s, "PATCH", "/repos/octo/app/issues/comments/11", `{"body":"Nice work"}`, &comment)
+	assert.Equal(t, "Nice work", comment["body"])
+	assert.Equal(t, "2024-03-02T12:00:00Z", comment["updated_at"])
+
+	c := s.comments[11]
+	require.Len(t, c.edits, 2)
+	assert.Equal(t, "Nice", c.edits[0].body)
+	assert.Equal(t, "bob", c.edits[0].editor)
+	assert.Equal(t, "me", c.edits[1].editor)
+
+	resp := call(t, s, "PATCH", "/repos/octo/app/pulls/comments/11", `{"body":"wrong kind"}`, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRequestsAndRateLimit(t *testing.T) {
+	s := newTestServer(t)
+
+	call(t, s, "GET", "/user", "", nil)
+	var limit struct {
+		Resources struct {
+			Core struct {
+				Limit     int `json:"limit"`
+				Remaining int `json:"remaining"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	call(t, s, "GET", "/rate_limit", "", &limit)
+	assert.Equal(t, 5000, limit.Resources.Core.Limit)
+	assert.Equal(t, 4998, limit.Resources.Core.Remaining)
+	assert.Equal(t, []string{"GET /user", "GET /rate_limit"}, s.Requests())
+}
+
+func jsonNumber(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,544 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RecordCassetteEnv names a file to record every HTTP exchange of the real client
+// into. The file is written by CloseRecordings. Cassettes hold no credentials;
+// replay them in tests with newReplayTransport.
+const RecordCassetteEnv = "GH_COMMENT_RECORD_CASSETTE"
+
+// redactedHeaders never reach a cassette
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Github-Otp":  true,
+}
+
+// volatileHeaders vary between recordings or follow from the body, and are left
+// out so re-recording is stable
+var volatileHeaders = map[string]bool{
+	"Content-Length":        true,
+	"Date":                  true,
+	"Time-Zone":             true,
+	"User-Agent":            true,
+	"X-Github-Request-Id":   true,
+	"X-Ratelimit-Remaining": true,
+	"X-Ratelimit-Reset":     true,
+	"X-Ratelimit-Used":      true,
+}
+
+// urlTokenParams are query parameters GitHub puts credentials in, such as the
+// token on a private repository's diff redirect
+var urlTokenParams = []string{"token", "jwt"}
+
+// emailPattern matches email addresses in bodies
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// Cassette is a recorded sequence of HTTP exchanges
+type Cassette struct {
+	// Note says where the cassette came from when it was not recorded
+	Note string `json:"note,omitempty"`
+	// Repo and PR are what the recording ran against, so a test replays the same requests
+	Repo string `json:"repo,omitempty"`
+	PR   int    `json:"pr,omitempty"`
+
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and the response it got
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request replay matches on, plus its headers for reference
+type RecordedRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	JSON    json.RawMessage   `json:"json,omitempty"`
+}
+
+// RecordedResponse is a response as GitHub sent it
+type RecordedResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	JSON    json.RawMessage   `json:"json,omitempty"`
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to path
+func (c *Cassette) Save(path string) error {
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false) // keep & in URLs readable
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(c); err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(path, data.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// recordings holds the cassette being recorded per file, so every client in the
+// process appends to the same one
+var recordings = struct {
+	sync.Mutex
+	byPath map[string]*recording
+}{byPath: make(map[string]*recording)}
+
+type recording struct {
+	mu       sync.Mutex
+	path     string
+	cassette Cassette
+}
+
+// recordingTransport passes requests on to base and keeps each exchange for the
+// cassette, which is written once the recording is closed
+type recordingTransport struct {
+	base      http.RoundTripper
+	recording *recording
+}
+
+// newRecordingTransport records into path. The first transport for a path starts
+// the recording afresh; later ones append to it until it is closed.
+func newRecordingTransport(path string, base http.RoundTripper) *recordingTransport {
+	recordings.Lock()
+	defer recordings.Unlock()
+
+	r := recordings.byPath[path]
+	if r == nil {
+		r = &recording{path: path}
+		recordings.byPath[path] = r
+	}
+	return &recordingTransport{base: base, recording: r}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body for recording: %w", err)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to read response body for recording: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: recordHeaders(req.Header),
+		},
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: recordHeaders(resp.Header),
+		},
+	}
+	interaction.Request.Body, interaction.Request.JSON = recordBody(requestBody)
+	interaction.Response.Body, interaction.Response.JSON = recordBody(responseBody)
+
+	r := t.recording
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Close redacts the recording and writes its cassette
+func (t *recordingTransport) Close() error {
+	recordings.Lock()
+	if recordings.byPath[t.recording.path] == t.recording {
+		delete(recordings.byPath, t.recording.path)
+	}
+	recordings.Unlock()
+	return t.recording.save()
+}
+
+// CloseRecordings writes every cassette recorded through RecordCassetteEnv.
+// Call it once the process has made its last request.
+func CloseRecordings() error {
+	recordings.Lock()
+	open := recordings.byPath
+	recordings.byPath = make(map[string]*recording)
+	recordings.Unlock()
+
+	var errs []error
+	for _, r := range open {
+		if err := r.save(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *recording) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := redactCassette(&r.cassette); err != nil {
+		return err
+	}
+	return r.cassette.Save(r.path)
+}
+
+// readBody reads a body and puts back a fresh reader over the same bytes
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+// recordHeaders flattens headers for a cassette, redacting credentials
+func recordHeaders(header http.Header) map[string]string {
+	recorded := make(map[string]string)
+	for name, values := range header {
+		name = http.CanonicalHeaderKey(name)
+		switch {
+		case redactedHeaders[name]:
+			recorded[name] = "REDACTED"
+		case !volatileHeaders[name]:
+			recorded[name] = strings.Join(values, ", ")
+		}
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	return recorded
+}
+
+// redactCassette removes personal data and credentials from recorded bodies:
+// email addresses everywhere, and for a private repository every *_url field but
+// diff_url, which FetchPRDiff follows. Node IDs are kept; they identify objects,
+// not people, and replayed mutations send them back.
+func redactCassette(c *Cassette) error {
+	private := false
+	for _, interaction := range c.Interactions {
+		if bytes.Contains(interaction.Response.JSON, []byte(`"private": true`)) {
+			private = true
+			break
+		}
+	}
+
+	for i := range c.Interactions {
+		request, response := &c.Interactions[i].Request, &c.Interactions[i].Response
+		var err error
+		if request.JSON, err = redactJSON(request.JSON, private); err != nil {
+			return fmt.Errorf("failed to redact request to %s: %w", request.URL, err)
+		}
+		if response.JSON, err = redactJSON(response.JSON, private); err != nil {
+			return fmt.Errorf("failed to redact response from %s: %w", request.URL, err)
+		}
+		request.Body = emailPattern.ReplaceAllString(request.Body, "REDACTED")
+		response.Body = emailPattern.ReplaceAllString(response.Body, "REDACTED")
+		if location, ok := response.Headers["Location"]; ok {
+			response.Headers["Location"] = redactURL(location)
+		}
+	}
+	return nil
+}
+
+// redactJSON redacts a JSON body, leaving it byte for byte alone when nothing changes
+func redactJSON(data json.RawMessage, private bool) (json.RawMessage, error) {
+	if data == nil {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	redacted, changed := redactValue("", value, private)
+	if !changed {
+		return data, nil
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(redacted); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(bytes.TrimRight(out.Bytes(), "\n")), nil
+}
+
+// redactValue redacts one JSON value found under key, reporting whether it changed
+func redactValue(key string, value interface{}, private bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		changed := false
+		for k, item := range v {
+			var itemChanged bool
+			if v[k], itemChanged = redactValue(k, item, private); itemChanged {
+				changed = true
+			}
+		}
+		return v, changed
+	case []interface{}:
+		changed := false
+		for i, item := range v {
+			var itemChanged bool
+			if v[i], itemChanged = redactValue(key, item, private); itemChanged {
+				changed = true
+			}
+		}
+		return v, changed
+	case string:
+		redacted := v
+		switch {
+		case key == "email" && v != "":
+			redacted = "REDACTED"
+		case private && strings.HasSuffix(key, "_url") && key != "diff_url":
+			redacted = "REDACTED"
+		case key == "url" || strings.HasSuffix(key, "_url"):
+			redacted = redactURL(v)
+		default:
+			redacted = emailPattern.ReplaceAllString(v, "REDACTED")
+		}
+		return redacted, redacted != v
+	default:
+		return value, false
+	}
+}
+
+// redactURL blanks credentials carried in a URL's query
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	query := u.Query()
+	changed := false
+	for _, param := range urlTokenParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// recordBody keeps JSON bodies as JSON so cassettes stay readable
+func recordBody(data []byte) (string, json.RawMessage) {
+	if len(data) == 0 {
+		return "", nil
+	}
+	var indented bytes.Buffer
+	if json.Valid(data) && json.Indent(&indented, data, "", "  ") == nil {
+		return "", json.RawMessage(indented.Bytes())
+	}
+	return string(data), nil
+}
+
+// replayTransport answers requests from a cassette. Each request is served by the
+// first unplayed interaction with the same method, URL, body and If-None-Match,
+// so a listing repeated after a write gets the later response.
+type replayTransport struct {
+	name     string
+	cassette *Cassette
+
+	mu     sync.Mutex
+	played []bool
+}
+
+func newReplayTransport(name string, cassette *Cassette) *replayTransport {
+	return &replayTransport{
+		name:     name,
+		cassette: cassette,
+		played:   make([]bool, len(cassette.Interactions)),
+	}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.cassette.Interactions {
+		if !t.played[i] && requestMatches(interaction.Request, req, body) {
+			t.played[i] = true
+			return replayResponse(interaction.Response, req), nil
+		}
+	}
+	return nil, t.mismatch(req, body)
+}
+
+// Unplayed lists the recorded requests that were never replayed
+func (t *replayTransport) Unplayed() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var unplayed []string
+	for i, interaction := range t.cassette.Interactions {
+		if !t.played[i] {
+			unplayed = append(unplayed, interaction.Request.Method+" "+interaction.Request.URL)
+		}
+	}
+	return unplayed
+}
+
+// mismatch explains why no recorded interaction fits a request
+func (t *replayTransport) mismatch(req *http.Request, body []byte) error {
+	request := req.Method + " " + req.URL.String()
+
+	var sameURL, unplayed []string
+	replayed := 0
+	for i, interaction := range t.cassette.Interactions {
+		recorded := interaction.Request
+		if recorded.Method == req.Method && sameURLs(recorded.URL, req.URL) {
+			if t.played[i] {
+				replayed++
+			} else {
+				sameURL = append(sameURL, describeRecordedBody(recorded))
+			}
+		}
+		if !t.played[i] && len(unplayed) < 3 {
+			unplayed = append(unplayed, recorded.Method+" "+recorded.URL)
+		}
+	}
+
+	switch {
+	case len(sameURL) > 0:
+		return fmt.Errorf("cassette %s: %s was recorded with a different body or If-None-Match\n  sent:     %s\n  recorded: %s",
+			t.name, request, compactBody(body), strings.Join(sameURL, "\n            "))
+	case replayed > 0:
+		return fmt.Errorf("cassette %s: %s was recorded %d time(s) and has already been replayed", t.name, request, replayed)
+	case len(unplayed) > 0:
+		return fmt.Errorf("cassette %s: no recorded interaction for %s; next unplayed: %s",
+			t.name, request, strings.Join(unplayed, ", "))
+	default:
+		return fmt.Errorf("cassette %s: no recorded interaction for %s; every interaction has been replayed", t.name, request)
+	}
+}
+
+// requestMatches reports whether a recorded request is the same as req
+func requestMatches(recorded RecordedRequest, req *http.Request, body []byte) bool {
+	return recorded.Method == req.Method &&
+		sameURLs(recorded.URL, req.URL) &&
+		recorded.Headers["If-None-Match"] == req.Header.Get("If-None-Match") &&
+		sameBodies(recorded, body)
+}
+
+// sameURLs compares URLs ignoring query parameter order
+func sameURLs(recorded string, actual *url.URL) bool {
+	u, err := url.Parse(recorded)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == actual.Scheme && u.Host == actual.Host && u.Path == actual.Path &&
+		u.Query().Encode() == actual.Query().Encode()
+}
+
+// sameBodies compares JSON bodies by value and others byte for byte
+func sameBodies(recorded RecordedRequest, body []byte) bool {
+	if recorded.JSON == nil {
+		return recorded.Body == string(body)
+	}
+	var want, got interface{}
+	if json.Unmarshal(recorded.JSON, &want) != nil || json.Unmarshal(body, &got) != nil {
+		return false
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+func describeRecordedBody(recorded RecordedRequest) string {
+	if recorded.JSON != nil {
+		return compactBody(recorded.JSON)
+	}
+	return compactBody([]byte(recorded.Body))
+}
+
+// compactBody renders a body on one line for error messages
+func compactBody(body []byte) string {
+	if len(body) == 0 {
+		return "(empty)"
+	}
+	var compacted bytes.Buffer
+	if json.Compact(&compacted, body) == nil {
+		body = compacted.Bytes()
+	}
+	if len(body) > 200 {
+		return string(body[:200]) + "..."
+	}
+	return string(body)
+}
+
+// replayResponse builds the response a recorded interaction describes
+func replayResponse(recorded RecordedResponse, req *http.Request) *http.Response {
+	header := make(http.Header)
+	for name, value := range recorded.Headers {
+		header.Set(name, value)
+	}
+
+	body := []byte(recorded.Body)
+	if recorded.JSON != nil {
+		var compacted bytes.Buffer
+		if json.Compact(&compacted, recorded.JSON) == nil {
+			body = compacted.Bytes()
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,247 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTrip sends a request through transport and returns the status and body
+func roundTrip(t *testing.T, transport http.RoundTripper, method, url, body string, header http.Header) (int, string, error) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data), nil
+}
+
+func TestRecordingTransportRedactsAndReplays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("ETag", `"abc"`)
+		if strings.HasSuffix(r.URL.Path, ".diff") {
+			_, _ = io.WriteString(w, "diff --git a/x b/x\n")
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"id":1,"body":"hi"}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	recorder := newRecordingTransport(path, http.DefaultTransport)
+	auth := http.Header{"Authorization": {"token ghp_supersecret"}}
+
+	status, body, err := roundTrip(t, recorder, "POST", server.URL+"/repos/o/r/issues/1/comments", `{"body":"hi"}`, auth)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, `{"id":1,"body":"hi"}`, body, "the caller still gets the whole body")
+	_, _, err = roundTrip(t, recorder, "GET", server.URL+"/o/r/pull/1.diff", "", auth)
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, path, "nothing is written until the recording is closed")
+	require.NoError(t, recorder.Close())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "ghp_supersecret")
+	assert.NotContains(t, string(data), "session=secret")
+
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, cassette.Interactions, 2)
+	assert.Equal(t, "REDACTED", cassette.Interactions[0].Request.Headers["Authorization"])
+	assert.JSONEq(t, `{"body":"hi"}`, string(cassette.Interactions[0].Request.JSON))
+	assert.Equal(t, "diff --git a/x b/x\n", cassette.Interactions[1].Response.Body)
+
+	// Replaying gives back the same responses without the server
+	replay := newReplayTransport("session", cassette)
+	status, body, err = roundTrip(t, replay, "POST", server.URL+"/repos/o/r/issues/1/comments", `{ "body": "hi" }`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.JSONEq(t, `{"id":1,"body":"hi"}`, body)
+	_, body, err = roundTrip(t, replay, "GET", server.URL+"/o/r/pull/1.diff", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "diff --git a/x b/x\n", body)
+	assert.Empty(t, replay.Unplayed())
+}
+
+func TestReplayTransportMatching(t *testing.T) {
+	cassette := &Cassette{Interactions: []Interaction{
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "https://api.github.com/repos/o/r/issues/1/comments?per_page=100&page=1"},
+			Response: RecordedResponse{Status: 200, JSON: []byte(`[]`)},
+		},
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "https://api.github.com/repos/o/r/issues/1/comments?per_page=100&page=1"},
+			Response: RecordedResponse{Status: 200, JSON: []byte(`[{"id":1}]`)},
+		},
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "https://api.github.com/repos/o/r/issues/1/comments?per_page=100&page=1", Headers: map[string]string{"If-None-Match": `"abc"`}},
+			Response: RecordedResponse{Status: 304},
+		},
+		{
+			Request:  RecordedRequest{Method: "POST", URL: "https://api.github.com/graphql", JSON: []byte(`{"query":"query { viewer { login } }","variables":{"n":1}}`)},
+			Response: RecordedResponse{Status: 200, JSON: []byte(`{"data":{"viewer":{"login":"octo"}}}`), Headers: map[string]string{"Content-Type": "application/json"}},
+		},
+	}}
+	replay := newReplayTransport("matching", cassette)
+	listURL := "https://api.github.com/repos/o/r/issues/1/comments?page=1&per_page=100"
+
+	// Query order does not matter, and a repeated request gets the next recording
+	_, body, err := roundTrip(t, replay, "GET", listURL, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", body)
+	_, body, err = roundTrip(t, replay, "GET", listURL, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"id":1}]`, body)
+
+	// If-None-Match picks the conditional recording
+	status, _, err := roundTrip(t, replay, "GET", listURL, "", http.Header{"If-None-Match": {`"abc"`}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, status)
+
+	_, _, err = roundTrip(t, replay, "GET", listURL, "", nil)
+	assert.EqualError(t, err, "cassette matching: GET "+listURL+" was recorded 3 time(s) and has already been replayed")
+
+	_, _, err = roundTrip(t, replay, "POST", "https://api.github.com/graphql", `{"query":"query { viewer { login } }","variables":{"n":2}}`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cassette matching: POST https://api.github.com/graphql was recorded with a different body")
+	assert.Contains(t, err.Error(), `sent:     {"query":"query { viewer { login } }","variables":{"n":2}}`)
+	assert.Contains(t, err.Error(), `"variables":{"n":1}`)
+
+	_, _, err = roundTrip(t, replay, "DELETE", "https://api.github.com/repos/o/r/issues/comments/1", "", nil)
+	assert.EqualError(t, err, "cassette matching: no recorded interaction for DELETE https://api.github.com/repos/o/r/issues/comments/1; next unplayed: POST https://api.github.com/graphql")
+	assert.Equal(t, []string{"POST https://api.github.com/graphql"}, replay.Unplayed())
+
+	// JSON bodies match by value
+	_, body, err = roundTrip(t, replay, "POST", "https://api.github.com/graphql", `{"variables":{"n":1},"query":"query { viewer { login } }"}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":{"viewer":{"login":"octo"}}}`, body)
+
+	_, _, err = roundTrip(t, replay, "DELETE", "https://api.github.com/repos/o/r/issues/comments/1", "", nil)
+	assert.EqualError(t, err, "cassette matching: no recorded interaction for DELETE https://api.github.com/repos/o/r/issues/comments/1; every interaction has been replayed")
+}
+
+func TestReplayMismatchSurfacesThroughClient(t *testing.T) {
+	client, err := newRealClient(api.ClientOptions{Host: "github.com", AuthToken: "replay-token"}, newReplayTransport("empty", &Cassette{}))
+	require.NoError(t, err)
+
+	_, err = client.ListIssueComments("o", "r", 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cassette empty: no recorded interaction for GET https://api.github.com/repos/o/r/issues/1/comments?per_page=100")
+}
+
+func TestLoadCassetteErrors(t *testing.T) {
+	_, err := LoadCassette(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read cassette")
+
+	path := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err = LoadCassette(path)
+	assert.ErrorContains(t, err, "failed to parse cassette")
+}
+
+func TestRecordCassetteEnvRecordsClientTraffic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `[]`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cli.json")
+	t.Setenv(RecordCassetteEnv, path)
+	t.Setenv("GH_TOKEN", "ghp_supersecret")
+
+	client, err := NewRealClientForServer(server.URL)
+	require.NoError(t, err)
+	_, err = client.ListIssueComments("o", "r", 1)
+	require.NoError(t, err)
+	_, err = client.ListReviewComments("o", "r", 1)
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, path)
+	require.NoError(t, CloseRecordings())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "ghp_supersecret")
+
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, cassette.Interactions, 2, "both clients append to the same cassette")
+	assert.Equal(t, "https://api.github.com/repos/o/r/issues/1/comments?per_page=100", cassette.Interactions[0].Request.URL)
+	assert.Equal(t, "REDACTED", cassette.Interactions[0].Request.Headers["Authorization"])
+	assert.Equal(t, "https://api.github.com/repos/o/r/pulls/1/comments?per_page=100", cassette.Interactions[1].Request.URL)
+}
+
+func TestRedactCassette(t *testing.T) {
+	public := `{
+  "body": "Ping jane.doe@example.com about this",
+  "user": {"login": "octo", "email": "octo@example.com", "avatar_url": "https://avatars.githubusercontent.com/u/1?v=4"}
+}`
+	untouched := `{"login":   "octo",  "url": "https://api.github.com/users/octo"}`
+	cassette := &Cassette{Interactions: []Interaction{
+		{
+			Request:  RecordedRequest{Method: "POST", URL: "https://api.github.com/repos/o/r/issues/1/comments", JSON: []byte(`{"body": "cc admin@example.org"}`)},
+			Response: RecordedResponse{Status: 201, JSON: []byte(public)},
+		},
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "https://api.github.com/user"},
+			Response: RecordedResponse{Status: 200, JSON: []byte(untouched)},
+		},
+	}}
+
+	require.NoError(t, redactCassette(cassette))
+	assert.JSONEq(t, `{"body": "cc REDACTED"}`, string(cassette.Interactions[0].Request.JSON))
+	assert.JSONEq(t, `{
+  "body": "Ping REDACTED about this",
+  "user": {"login": "octo", "email": "REDACTED", "avatar_url": "https://avatars.githubusercontent.com/u/1?v=4"}
+}`, string(cassette.Interactions[0].Response.JSON))
+	assert.Equal(t, untouched, string(cassette.Interactions[1].Response.JSON), "bodies with nothing to redact keep their layout")
+
+	// A private repository's URLs are redacted, except the diff_url the client follows
+	private := &Cassette{Interactions: []Interaction{
+		{
+			Request: RecordedRequest{Method: "GET", URL: "https://api.github.com/repos/o/secret/pulls/1"},
+			Response: RecordedResponse{Status: 200, JSON: []byte(`{
+  "url": "https://api.github.com/repos/o/secret/pulls/1",
+  "html_url": "https://github.com/o/secret/pull/1",
+  "diff_url": "https://github.com/o/secret/pull/1.diff",
+  "head": {"repo": {"private": true, "clone_url": "https://github.com/o/secret.git"}}
+}`)},
+		},
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "https://github.com/o/secret/pull/1.diff"},
+			Response: RecordedResponse{Status: 302, Headers: map[string]string{"Location": "https://patch-diff.githubusercontent.com/raw/o/secret/pull/1.diff?token=ABCDEF"}},
+		},
+	}}
+	require.NoError(t, redactCassette(private))
+	assert.JSONEq(t, `{
+  "url": "https://api.github.com/repos/o/secret/pulls/1",
+  "html_url": "REDACTED",
+  "diff_url": "https://github.com/o/secret/pull/1.diff",
+  "head": {"repo": {"private": true, "clone_url": "REDACTED"}}
+}`, string(private.Interactions[0].Response.JSON))
+	assert.Equal(t, "https://patch-diff.githubusercontent.com/raw/o/secret/pull/1.diff?token=REDACTED", private.Interactions[1].Response.Headers["Location"])
+}
@@ -1,6 +1,10 @@
 package github
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
 	"time"
 )
 
@@ -12,12 +16,29 @@ type GitHubAPI interface {
 	CreateIssueComment(owner, repo string, prNumber int, body string) (*Comment, error)
 	CreateReviewCommentReply(owner, repo string, commentID int, body string) (*Comment, error)
 
+	// Conditional requests (If-None-Match) for cheap polling
+	ListIssueCommentsIfChanged(owner, repo string, prNumber int, etag string) (*ConditionalComments, error)
+	ListReviewCommentsIfChanged(owner, repo string, prNumber int, etag string) (*ConditionalComments, error)
+
 	// Reaction operations
 	AddReaction(owner, repo string, commentID int, prNumber int, reaction string) error
 	RemoveReaction(owner, repo string, commentID int, prNumber int, reaction string) error
+	ListReactions(owner, repo string, commentID int, prNumber int) ([]Reaction, error)
+
+	// Reactions on a comment whose type ("issue" or "review") is already known,
+	// skipping the comment listing the calls above make to detect it
+	AddCommentReaction(owner, repo string, commentID int, commentType, reaction string) error
+	RemoveCommentReaction(owner, repo string, commentID int, commentType, reaction string) error
+	ListCommentReactions(owner, repo string, commentID int, commentType string) ([]Reaction, error)
+
+	// Reactions on the PR description itself
+	AddPRReaction(owner, repo string, prNumber int, reaction string) error
+	RemovePRReaction(owner, repo string, prNumber int, reaction string) error
+	ListPRReactions(owner, repo string, prNumber int) ([]Reaction, error)
 
 	// Comment operations
 	EditComment(owner, repo string, commentID int, prNumber int, body string) error
+	ListCommentEdits(owner, repo string, commentID int, prNumber int) ([]CommentEdit, error)
 	AddReviewComment(owner, repo string, pr int, comment ReviewCommentInput) error
 
 	// PR operations
@@ -26,17 +47,38 @@ type GitHubAPI interface {
 
 	// Review operations
 	CreateReview(owner, repo string, pr int, review ReviewInput) error
+	ListReviews(owner, repo string, pr int) ([]Review, error)
 	FindPendingReview(owner, repo string, pr int) (int, error)
+	ListReviewCommentsForReview(owner, repo string, pr, reviewID int) ([]Comment, error)
 	SubmitReview(owner, repo string, pr, reviewID int, body, event string) error
 
 	// GraphQL operations
 	ResolveReviewThread(threadID string) error
 	FindReviewThreadForComment(owner, repo string, prNumber, commentID int) (string, error)
+	ListReviewThreads(owner, repo string, prNumber int) ([]ReviewThread, error)
+
+	// Repository-wide operations
+	SearchPullRequests(query string, limit int) ([]PullRequest, error)
+	// ListRepoReviewComments pages through comments newest first, stopping before the
+	// first comment keep rejects; a nil keep lists them all
+	ListRepoReviewComments(owner, repo string, since time.Time, keep func(Comment) bool) ([]Comment, error)
+
+	// Rate limit status (checking it does not count against the limit)
+	GetRateLimit() (*RateLimit, error)
+}
+
+// ConditionalComments is the result of a comment listing made with an ETag.
+// When NotModified is true, Comments is nil and the caller's cached copy is current.
+type ConditionalComments struct {
+	Comments    []Comment
+	ETag        string
+	NotModified bool
 }
 
 // Comment represents a GitHub comment (issue or review)
 type Comment struct {
 	ID        int       `json:"id"`
+	NodeID    string    `json:"node_id,omitempty"`
 	Body      string    `json:"body"`
 	User      User      `json:"user"`
 	CreatedAt time.Time `json:"created_at"`
@@ -45,10 +87,16 @@ type Comment struct {
 	// Review comment specific fields
 	Path           string `json:"path,omitempty"`
 	Line           int    `json:"line,omitempty"`
+	StartLine      int    `json:"start_line,omitempty"`
 	Position       int    `json:"position,omitempty"`
 	CommitID       string `json:"commit_id,omitempty"`
 	PullRequestURL string `json:"pull_request_url,omitempty"`
 	InReplyToID    int    `json:"in_reply_to_id,omitempty"`
+	HTMLURL        string `json:"html_url,omitempty"`
+	DiffHunk       string `json:"diff_hunk,omitempty"`
+
+	// Reaction counts returned inline by the comments endpoints
+	Reactions ReactionSummary `json:"reactions"`
 
 	// Computed fields
 	Type string `json:"-"` // "issue" or "review"
@@ -61,6 +109,102 @@ type User struct {
 	AvatarURL string `json:"avatar_url"`
 }
 
+// ReactionContents lists the reaction types GitHub supports, in display order
+var ReactionContents = []string{"+1", "-1", "laugh", "hooray", "confused", "heart", "rocket", "eyes"}
+
+// ReactionSummary holds per-emoji reaction counts for a comment
+type ReactionSummary struct {
+	TotalCount int `json:"total_count"`
+	PlusOne    int `json:"+1"`
+	MinusOne   int `json:"-1"`
+	Laugh      int `json:"laugh"`
+	Hooray     int `json:"hooray"`
+	Confused   int `json:"confused"`
+	Heart      int `json:"heart"`
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
+}
+
+// Count returns the number of reactions of the given type (+1, -1, laugh, ...)
+func (r ReactionSummary) Count(reaction string) int {
+	switch reaction {
+	case "+1":
+		return r.PlusOne
+	case "-1":
+		return r.MinusOne
+	case "laugh":
+		return r.Laugh
+	case "hooray":
+		return r.Hooray
+	case "confused":
+		return r.Confused
+	case "heart":
+		return r.Heart
+	case "rocket":
+		return r.Rocket
+	case "eyes":
+		return r.Eyes
+	}
+	return 0
+}
+
+// Reaction is a single user's reaction to a comment or PR
+type Reaction struct {
+	ID        int       `json:"id"`
+	Content   string    `json:"content"`
+	User      User      `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommentEdit is one revision of a comment body from its edit history
+type CommentEdit struct {
+	EditedAt time.Time `json:"edited_at"`
+	Editor   string    `json:"editor"`
+	Body     string    `json:"body"`
+	Deleted  bool      `json:"deleted,omitempty"` // removed from the history; Body is empty
+}
+
+// RateLimit is the REST API request budget for the current hour
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// PullRequest represents a pull request returned by search
+type PullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"` // "open", "closed" or "merged"
+	User      User       `json:"user"`
+	Labels    []string   `json:"labels,omitempty"`
+	HTMLURL   string     `json:"html_url"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	MergedAt  *time.Time `json:"merged_at,omitempty"`
+}
+
+// Review represents a submitted pull request review
+type Review struct {
+	ID          int       `json:"id"`
+	User        User      `json:"user"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED or PENDING
+	SubmittedAt time.Time `json:"submitted_at"`
+	HTMLURL     string    `json:"html_url,omitempty"`
+}
+
+// ReviewThread represents a review conversation thread and its resolution state
+type ReviewThread struct {
+	ID         string `json:"id"`
+	IsResolved bool   `json:"is_resolved"`
+	IsOutdated bool   `json:"is_outdated"`
+	Path       string `json:"path"`
+	Line       int    `json:"line,omitempty"`
+	ResolvedBy string `json:"resolved_by,omitempty"`
+	CommentIDs []int  `json:"comment_ids"`
+}
+
 // ReviewCommentInput represents input for creating a review comment
 type ReviewCommentInput struct {
 	Body      string `json:"body"`
@@ -68,7 +212,11 @@ type ReviewCommentInput struct {
 	Line      int    `json:"line,omitempty"`
 	StartLine int    `json:"start_line,omitempty"`
 	Side      string `json:"side,omitempty"`
-	// Note: commit_id is NOT included here - GitHub uses the review-level commit automatically
+	// Note: commit_id is NOT needed inside a review - GitHub uses the review-level commit automatically.
+	// Standalone comments (AddReviewComment) must set it.
+	CommitID string `json:"commit_id,omitempty"`
+	// SubjectType "file" comments on the whole file instead of a line (standalone comments only)
+	SubjectType string `json:"subject_type,omitempty"`
 }
 
 // ReviewInput represents input for creating a review
@@ -98,17 +246,50 @@ type MockClient struct {
 	PendingReviewID   int
 	SubmittedReviewID int
 
+	// Repository-wide data
+	PullRequests       []PullRequest
+	ReviewThreads      []ReviewThread
+	RepoReviewComments []Comment
+	Reviews            []Review
+	PendingComments    []Comment        // line comments of the pending review
+	PRDiff             *PullRequestDiff // overrides the default test.go diff when set
+
+	// Reactions by comment ID, and on the PR description
+	Reactions   map[int][]Reaction
+	PRReactions []Reaction
+	RateLimit   *RateLimit // defaults to a full budget when nil
+
+	// Edit history by comment ID, oldest first
+	CommentEdits map[int][]CommentEdit
+
 	// Call tracking for regression tests
-	CreateReviewCalls []ReviewInput
+	mu                    sync.Mutex
+	CreateReviewCalls     []ReviewInput
+	AddReviewCommentCalls []ReviewCommentInput
+	IssueCommentBodies    []string
+	SearchQueries         []string
+	ListedThreadPRs       []int
+	NotModifiedCount      int
+	AddedPRReactions      []string
+	RemovedPRReactions    []string
+	EditedBodies          map[int]string
 
 	// Error simulation
-	ListIssueCommentsError  error
-	ListReviewCommentsError error
-	CreateCommentError      error
-	ResolveThreadError      error
-	FindReviewThreadError   error
-	FindPendingReviewError  error
-	SubmitReviewError       error
+	ListIssueCommentsError           error
+	ListReviewCommentsError          error
+	CreateCommentError               error
+	ResolveThreadError               error
+	FindReviewThreadError            error
+	FindPendingReviewError           error
+	ListReviewCommentsForReviewError error
+	SubmitReviewError                error
+	SearchError                      error
+	ListThreadsError                 error
+	ListReviewsError                 error
+	ListReactionsError               error
+	AddReactionError                 error
+	RateLimitError                   error
+	ListCommentEditsError            error
 }
 
 // NewMockClient creates a new mock client for testing
@@ -159,6 +340,34 @@ func (m *MockClient) ListReviewComments(owner, repo string, prNumber int) ([]Com
 	return m.ReviewComments, nil
 }
 
+func (m *MockClient) ListIssueCommentsIfChanged(owner, repo string, prNumber int, etag string) (*ConditionalComments, error) {
+	if m.ListIssueCommentsError != nil {
+		return nil, m.ListIssueCommentsError
+	}
+	return m.conditional(m.IssueComments, etag), nil
+}
+
+func (m *MockClient) ListReviewCommentsIfChanged(owner, repo string, prNumber int, etag string) (*ConditionalComments, error) {
+	if m.ListReviewCommentsError != nil {
+		return nil, m.ListReviewCommentsError
+	}
+	return m.conditional(m.ReviewComments, etag), nil
+}
+
+// conditional mimics GitHub's ETag handling with a hash of the comments' content
+func (m *MockClient) conditional(comments []Comment, etag string) *ConditionalComments {
+	data, _ := json.Marshal(comments)
+	current := fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+
+	if etag == current {
+		m.mu.Lock()
+		m.NotModifiedCount++
+		m.mu.Unlock()
+		return &ConditionalComments{ETag: current, NotModified: true}
+	}
+	return &ConditionalComments{Comments: comments, ETag: current}
+}
+
 func (m *MockClient) CreateIssueComment(owner, repo string, prNumber int, body string) (*Comment, error) {
 	if m.CreateCommentError != nil {
 		return nil, m.CreateCommentError
@@ -172,6 +381,7 @@ func (m *MockClient) CreateIssueComment(owner, repo string, prNumber int, body s
 		CreatedAt: time.Now(),
 	}
 	m.CreatedComment = comment
+	m.IssueCommentBodies = append(m.IssueCommentBodies, body)
 	return comment, nil
 }
 
@@ -207,22 +417,82 @@ func (m *MockClient) ResolveReviewThread(threadID string) error {
 }
 
 func (m *MockClient) AddReaction(owner, repo string, commentID int, prNumber int, reaction string) error {
-	return nil
+	return m.AddReactionError
 }
 
 func (m *MockClient) RemoveReaction(owner, repo string, commentID int, prNumber int, reaction string) error {
 	return nil
 }
 
+func (m *MockClient) AddCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	return m.AddReactionError
+}
+
+func (m *MockClient) RemoveCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	return nil
+}
+
+func (m *MockClient) ListCommentReactions(owner, repo string, commentID int, commentType string) ([]Reaction, error) {
+	return m.ListReactions(owner, repo, commentID, 0)
+}
+
+func (m *MockClient) ListCommentEdits(owner, repo string, commentID int, prNumber int) ([]CommentEdit, error) {
+	if m.ListCommentEditsError != nil {
+		return nil, m.ListCommentEditsError
+	}
+	return m.CommentEdits[commentID], nil
+}
+
+func (m *MockClient) ListReactions(owner, repo string, commentID int, prNumber int) ([]Reaction, error) {
+	if m.ListReactionsError != nil {
+		return nil, m.ListReactionsError
+	}
+	return m.Reactions[commentID], nil
+}
+
+func (m *MockClient) AddPRReaction(owner, repo string, prNumber int, reaction string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.AddedPRReactions = append(m.AddedPRReactions, reaction)
+	return nil
+}
+
+func (m *MockClient) RemovePRReaction(owner, repo string, prNumber int, reaction string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.RemovedPRReactions = append(m.RemovedPRReactions, reaction)
+	return nil
+}
+
+func (m *MockClient) ListPRReactions(owner, repo string, prNumber int) ([]Reaction, error) {
+	if m.ListReactionsError != nil {
+		return nil, m.ListReactionsError
+	}
+	return m.PRReactions, nil
+}
+
 func (m *MockClient) EditComment(owner, repo string, commentID int, prNumber int, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.EditedBodies == nil {
+		m.EditedBodies = make(map[int]string)
+	}
+	m.EditedBodies[commentID] = body
 	return nil
 }
 
 func (m *MockClient) AddReviewComment(owner, repo string, pr int, comment ReviewCommentInput) error {
+	if m.CreateCommentError != nil {
+		return m.CreateCommentError
+	}
+	m.AddReviewCommentCalls = append(m.AddReviewCommentCalls, comment)
 	return nil
 }
 
 func (m *MockClient) FetchPRDiff(owner, repo string, pr int) (*PullRequestDiff, error) {
+	if m.PRDiff != nil {
+		return m.PRDiff, nil
+	}
 	return &PullRequestDiff{
 		Files: []DiffFile{
 			{
@@ -250,6 +520,13 @@ func (m *MockClient) CreateReview(owner, repo string, pr int, review ReviewInput
 	return nil
 }
 
+func (m *MockClient) ListReviews(owner, repo string, pr int) ([]Review, error) {
+	if m.ListReviewsError != nil {
+		return nil, m.ListReviewsError
+	}
+	return m.Reviews, nil
+}
+
 func (m *MockClient) FindPendingReview(owner, repo string, pr int) (int, error) {
 	if m.FindPendingReviewError != nil {
 		return 0, m.FindPendingReviewError
@@ -257,6 +534,13 @@ func (m *MockClient) FindPendingReview(owner, repo string, pr int) (int, error)
 	return m.PendingReviewID, nil
 }
 
+func (m *MockClient) ListReviewCommentsForReview(owner, repo string, pr, reviewID int) ([]Comment, error) {
+	if m.ListReviewCommentsForReviewError != nil {
+		return nil, m.ListReviewCommentsForReviewError
+	}
+	return m.PendingComments, nil
+}
+
 func (m *MockClient) SubmitReview(owner, repo string, pr, reviewID int, body, event string) error {
 	if m.SubmitReviewError != nil {
 		return m.SubmitReviewError
@@ -264,3 +548,53 @@ func (m *MockClient) SubmitReview(owner, repo string, pr, reviewID int, body, ev
 	m.SubmittedReviewID = reviewID
 	return nil
 }
+
+func (m *MockClient) ListReviewThreads(owner, repo string, prNumber int) ([]ReviewThread, error) {
+	if m.ListThreadsError != nil {
+		return nil, m.ListThreadsError
+	}
+	m.mu.Lock()
+	m.ListedThreadPRs = append(m.ListedThreadPRs, prNumber)
+	m.mu.Unlock()
+	return m.ReviewThreads, nil
+}
+
+func (m *MockClient) SearchPullRequests(query string, limit int) ([]PullRequest, error) {
+	if m.SearchError != nil {
+		return nil, m.SearchError
+	}
+	m.mu.Lock()
+	m.SearchQueries = append(m.SearchQueries, query)
+	m.mu.Unlock()
+	if limit > 0 && len(m.PullRequests) > limit {
+		return m.PullRequests[:limit], nil
+	}
+	return m.PullRequests, nil
+}
+
+func (m *MockClient) ListRepoReviewComments(owner, repo string, since time.Time, keep func(Comment) bool) ([]Comment, error) {
+	if m.ListReviewCommentsError != nil {
+		return nil, m.ListReviewCommentsError
+	}
+	var comments []Comment
+	for _, comment := range m.RepoReviewComments {
+		if !since.IsZero() && comment.UpdatedAt.Before(since) {
+			continue
+		}
+		if keep != nil && !keep(comment) {
+			break
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+func (m *MockClient) GetRateLimit() (*RateLimit, error) {
+	if m.RateLimitError != nil {
+		return nil, m.RateLimitError
+	}
+	if m.RateLimit != nil {
+		return m.RateLimit, nil
+	}
+	return &RateLimit{Limit: 5000, Remaining: 5000, Reset: time.Now().Add(time.Hour)}, nil
+}
@@ -1,10 +1,15 @@
 package github
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewMockClient(t *testing.T) {
@@ -149,6 +154,24 @@ func TestMockClientRemoveReaction(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestMockClientReactions(t *testing.T) {
+	client := NewMockClient()
+	client.Reactions = map[int][]Reaction{123456: {{Content: "+1", User: User{Login: "alice"}}}}
+
+	reactions, err := client.ListReactions("owner", "repo", 123456, 123)
+	assert.NoError(t, err)
+	assert.Len(t, reactions, 1)
+
+	assert.NoError(t, client.AddPRReaction("owner", "repo", 123, "rocket"))
+	assert.NoError(t, client.RemovePRReaction("owner", "repo", 123, "eyes"))
+	assert.Equal(t, []string{"rocket"}, client.AddedPRReactions)
+	assert.Equal(t, []string{"eyes"}, client.RemovedPRReactions)
+
+	client.ListReactionsError = assert.AnError
+	_, err = client.ListPRReactions("owner", "repo", 123)
+	assert.Error(t, err)
+}
+
 func TestMockClientEditComment(t *testing.T) {
 	client := NewMockClient()
 
@@ -329,3 +352,64 @@ func TestPullRequestDiff(t *testing.T) {
 	assert.True(t, diff.Files[1].Lines[10])
 	assert.True(t, diff.Files[1].Lines[11])
 }
+
+func TestReactionSummaryCount(t *testing.T) {
+	var summary ReactionSummary
+	err := json.Unmarshal([]byte(`{"total_count":4,"+1":2,"-1":1,"rocket":1}`), &summary)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, summary.TotalCount)
+	assert.Equal(t, 2, summary.Count("+1"))
+	assert.Equal(t, 1, summary.Count("-1"))
+	assert.Equal(t, 1, summary.Count("rocket"))
+	assert.Equal(t, 0, summary.Count("heart"))
+	assert.Equal(t, 0, summary.Count("unknown"))
+}
+
+func TestMockClientConditionalListing(t *testing.T) {
+	client := NewMockClient()
+
+	first, err := client.ListIssueCommentsIfChanged("owner", "repo", 1, "")
+	require.NoError(t, err)
+	assert.False(t, first.NotModified)
+	assert.Len(t, first.Comments, 1)
+	require.NotEmpty(t, first.ETag)
+
+	second, err := client.ListIssueCommentsIfChanged("owner", "repo", 1, first.ETag)
+	require.NoError(t, err)
+	assert.True(t, second.NotModified)
+	assert.Nil(t, second.Comments)
+	assert.Equal(t, 1, client.NotModifiedCount)
+
+	client.IssueComments[0].Body = "edited"
+	third, err := client.ListIssueCommentsIfChanged("owner", "repo", 1, first.ETag)
+	require.NoError(t, err)
+	assert.False(t, third.NotModified)
+	assert.NotEqual(t, first.ETag, third.ETag)
+}
+
+func TestConditionalTransportSetsIfNoneMatch(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("If-None-Match"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &conditionalTransport{base: http.DefaultTransport}}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	ctx := context.WithValue(context.Background(), etagContextKey{}, `"abc"`)
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"", `"abc"`}, received)
+	assert.Empty(t, req.Header.Get("If-None-Match"), "the caller's request should not be modified")
+}
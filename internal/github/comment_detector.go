@@ -12,44 +12,61 @@ type CommentInfo struct {
 	FilePath string // empty for issue comments
 	Line     int    // 0 for issue comments
 	Found    bool
+	Comment  *Comment // the comment itself, when found
 }
 
 // DetectCommentType intelligently detects comment type by looking it up
 func (c *RealClient) DetectCommentType(owner, repo string, commentID int, prNumber int) (*CommentInfo, error) {
+	return FindComment(c, owner, repo, commentID, prNumber)
+}
+
+// FindComment looks a comment up among the PR's issue and review comments.
+// A comment that isn't found is not an error; failing to list both kinds is.
+func FindComment(client GitHubAPI, owner, repo string, commentID int, prNumber int) (*CommentInfo, error) {
 	if prNumber <= 0 {
 		return nil, fmt.Errorf("PR number required for comment type detection")
 	}
 
 	// Get all comments to find the specific one
-	issueComments, err := c.ListIssueComments(owner, repo, prNumber)
-	if err == nil {
-		for _, comment := range issueComments {
+	issueComments, issueErr := client.ListIssueComments(owner, repo, prNumber)
+	if issueErr == nil {
+		for i, comment := range issueComments {
 			if comment.ID == commentID {
+				found := issueComments[i]
+				found.Type = "issue"
 				return &CommentInfo{
-					ID:    commentID,
-					Type:  "issue",
-					Found: true,
+					ID:      commentID,
+					Type:    "issue",
+					Found:   true,
+					Comment: &found,
 				}, nil
 			}
 		}
 	}
 
 	// Check review comments
-	reviewComments, err := c.ListReviewComments(owner, repo, prNumber)
-	if err == nil {
-		for _, comment := range reviewComments {
+	reviewComments, reviewErr := client.ListReviewComments(owner, repo, prNumber)
+	if reviewErr == nil {
+		for i, comment := range reviewComments {
 			if comment.ID == commentID {
+				found := reviewComments[i]
+				found.Type = "review"
 				return &CommentInfo{
 					ID:       commentID,
 					Type:     "review",
 					FilePath: comment.Path,
 					Line:     comment.Line,
 					Found:    true,
+					Comment:  &found,
 				}, nil
 			}
 		}
 	}
 
+	if issueErr != nil && reviewErr != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", issueErr)
+	}
+
 	return &CommentInfo{
 		ID:    commentID,
 		Found: false,
@@ -0,0 +1,32 @@
+package github
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindComment(t *testing.T) {
+	mock := NewMockClient()
+
+	info, err := FindComment(mock, "owner", "repo", 654321, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "review", info.Type)
+	assert.Equal(t, "main.go", info.FilePath)
+	assert.Equal(t, "reviewer2", info.Comment.User.Login)
+
+	// Only one listing failing still finds comments of the other kind
+	mock.ListReviewCommentsError = errors.New("boom")
+	info, err = FindComment(mock, "owner", "repo", 123456, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "issue", info.Type)
+
+	info, err = FindComment(mock, "owner", "repo", 42, 1)
+	require.NoError(t, err)
+	assert.False(t, info.Found)
+
+	_, err = FindComment(mock, "owner", "repo", 42, 0)
+	assert.ErrorContains(t, err, "PR number required")
+}
@@ -2,10 +2,23 @@ package github
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
 )
 
+// newHTTPError builds the error go-gh returns for a failed response, with header pairs
+func newHTTPError(status int, message string, headers ...string) *api.HTTPError {
+	header := http.Header{}
+	for i := 0; i+1 < len(headers); i += 2 {
+		header.Set(headers[i], headers[i+1])
+	}
+	return &api.HTTPError{StatusCode: status, Message: message, Headers: header}
+}
+
 func TestValidateRepoParams(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -111,46 +124,60 @@ func TestWrapAPIError(t *testing.T) {
 	}{
 		{
 			name:      "rate limit error",
-			err:       errors.New("rate limit exceeded"),
+			err:       newHTTPError(403, "API rate limit exceeded for user ID 1.", "X-RateLimit-Remaining", "0"),
 			operation: "fetch comments for PR #%d in %s/%s",
 			args:      []interface{}{123, "owner", "repo"},
 			wantMsg:   "rate limit exceeded while trying to fetch comments for PR #123 in owner/repo",
 		},
 		{
-			name:      "403 error (likely rate limit)",
-			err:       errors.New("HTTP 403 Forbidden"),
+			name:      "429 error",
+			err:       newHTTPError(429, "Too Many Requests"),
 			operation: "create comment on PR #%d",
 			args:      []interface{}{456},
 			wantMsg:   "rate limit exceeded while trying to create comment on PR #456",
 		},
+		{
+			name:      "403 error without rate limiting",
+			err:       newHTTPError(403, "Resource not accessible by integration", "X-RateLimit-Remaining", "4999"),
+			operation: "create comment on PR #%d",
+			args:      []interface{}{456},
+			wantMsg:   "permission denied while trying to create comment on PR #456",
+		},
 		{
 			name:      "404 error",
-			err:       errors.New("HTTP 404 Not Found"),
+			err:       newHTTPError(404, "Not Found"),
 			operation: "fetch PR #%d details",
 			args:      []interface{}{789},
 			wantMsg:   "resource not found while trying to fetch PR #789 details",
 		},
 		{
 			name:      "401 error",
-			err:       errors.New("HTTP 401 Unauthorized"),
+			err:       newHTTPError(401, "Bad credentials"),
 			operation: "list comments",
 			args:      []interface{}{},
 			wantMsg:   "authentication failed while trying to list comments",
 		},
 		{
 			name:      "422 error",
-			err:       errors.New("HTTP 422 Unprocessable Entity"),
+			err:       newHTTPError(422, "Validation Failed"),
 			operation: "create review comment",
 			args:      []interface{}{},
 			wantMsg:   "validation error while trying to create review comment",
 		},
 		{
 			name:      "secondary rate limit",
-			err:       errors.New("abuse detection triggered"),
+			err:       newHTTPError(403, "You have exceeded a secondary rate limit. Please wait a few minutes before you try again."),
 			operation: "create multiple comments",
 			args:      []interface{}{},
 			wantMsg:   "secondary rate limit triggered while trying to create multiple comments",
 		},
+		{
+			name:      "rate limit text outside an HTTP error",
+			err:       errors.New("rate limit exceeded"),
+			operation: "perform operation",
+			args:      []interface{}{},
+			wantMsg:   "GitHub API error while trying to perform operation",
+		},
 		{
 			name:      "generic error",
 			err:       errors.New("network error"),
@@ -174,7 +201,7 @@ func TestWrapAPIError(t *testing.T) {
 			}
 
 			// Check for helpful tips in specific error types
-			if strings.Contains(tt.err.Error(), "rate limit") {
+			if strings.HasPrefix(tt.wantMsg, "rate limit") {
 				if !strings.Contains(result.Error(), "gh api rate_limit") {
 					t.Errorf("rate limit error should include tip about checking rate limit status")
 				}
@@ -299,3 +326,20 @@ func TestErrorHandlingInMethods(t *testing.T) {
 		}
 	})
 }
+
+func TestIsRateLimited(t *testing.T) {
+	assert := func(want bool, err error) {
+		t.Helper()
+		if got := IsRateLimited(err); got != want {
+			t.Errorf("IsRateLimited(%v) = %v, want %v", err, got, want)
+		}
+	}
+
+	assert(true, newHTTPError(http.StatusTooManyRequests, ""))
+	assert(true, newHTTPError(http.StatusForbidden, "", "X-RateLimit-Remaining", "0"))
+	assert(true, newHTTPError(http.StatusForbidden, "", "Retry-After", "60"))
+	assert(true, fmt.Errorf("listing: %w", newHTTPError(http.StatusForbidden, "API rate limit exceeded for user ID 1.")))
+	assert(false, newHTTPError(http.StatusForbidden, "Resource not accessible by integration", "X-RateLimit-Remaining", "4999"))
+	assert(false, newHTTPError(http.StatusNotFound, "rate limit"))
+	assert(false, errors.New("HTTP 403: API rate limit exceeded"))
+}
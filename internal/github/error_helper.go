@@ -1,10 +1,44 @@
 package github
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
 )
 
+// IsRateLimited reports whether GitHub refused a request for exceeding a rate limit:
+// a 429, or a 403 with no requests remaining, a Retry-After header or a message
+// saying so. A 403 for missing permissions is not rate limiting.
+func IsRateLimited(err error) bool {
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	switch httpErr.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		message := strings.ToLower(httpErr.Message)
+		return httpErr.Headers.Get("X-RateLimit-Remaining") == "0" || httpErr.Headers.Get("Retry-After") != "" ||
+			strings.Contains(message, "rate limit") || strings.Contains(message, "abuse detection")
+	}
+	return false
+}
+
+// isSecondaryRateLimit reports whether a rate-limited request hit one of GitHub's
+// secondary limits on bursts of requests rather than the hourly quota
+func isSecondaryRateLimit(err error) bool {
+	var httpErr *api.HTTPError
+	if !IsRateLimited(err) || !errors.As(err, &httpErr) {
+		return false
+	}
+	message := strings.ToLower(httpErr.Message)
+	return strings.Contains(message, "secondary") || strings.Contains(message, "abuse")
+}
+
 // EnhancedAPIError provides intelligent error handling with suggestions
 type EnhancedAPIError struct {
 	OriginalError error
@@ -2,12 +2,17 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
 )
@@ -16,16 +21,48 @@ import (
 type RealClient struct {
 	restClient    *api.RESTClient
 	graphqlClient *api.GraphQLClient
+	diffClient    *http.Client // fetches diffs from github.com, which is not an API host
 }
 
 // NewRealClient creates a new GitHub API client
 func NewRealClient() (*RealClient, error) {
-	restClient, err := api.DefaultRESTClient()
+	return newRealClient(api.ClientOptions{}, http.DefaultTransport)
+}
+
+// NewRealClientForServer creates a client that sends every request to baseURL
+// instead of GitHub, such as the in-process fake from internal/fakegithub. It
+// never reads the gh configuration; the token comes from GH_TOKEN if set.
+func NewRealClientForServer(baseURL string) (*RealClient, error) {
+	target, err := url.Parse(baseURL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q: expected something like http://127.0.0.1:8080", baseURL)
+	}
+
+	token := os.Getenv("GH_TOKEN")
+	if token == "" {
+		token = "fake-token"
+	}
+
+	return newRealClient(api.ClientOptions{Host: "github.com", AuthToken: token},
+		&redirectTransport{target: target, base: http.DefaultTransport})
+}
+
+// newRealClient creates the REST and GraphQL clients on top of a transport
+func newRealClient(opts api.ClientOptions, transport http.RoundTripper) (*RealClient, error) {
+	if path := os.Getenv(RecordCassetteEnv); path != "" {
+		transport = newRecordingTransport(path, transport)
+	}
+
+	restOpts := opts
+	restOpts.Transport = &conditionalTransport{base: transport}
+	restClient, err := api.NewRESTClient(restOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create REST client: %w", err)
 	}
 
-	graphqlClient, err := api.DefaultGraphQLClient()
+	graphqlOpts := opts
+	graphqlOpts.Transport = transport
+	graphqlClient, err := api.NewGraphQLClient(graphqlOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GraphQL client: %w", err)
 	}
@@ -33,9 +70,24 @@ func NewRealClient() (*RealClient, error) {
 	return &RealClient{
 		restClient:    restClient,
 		graphqlClient: graphqlClient,
+		diffClient:    &http.Client{Transport: transport},
 	}, nil
 }
 
+// redirectTransport sends requests meant for GitHub to another server, keeping the path
+type redirectTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.base.RoundTrip(req)
+}
+
 // ListIssueComments fetches all issue comments for a PR
 func (c *RealClient) ListIssueComments(owner, repo string, prNumber int) ([]Comment, error) {
 	if err := validateRepoParams(owner, repo); err != nil {
@@ -86,6 +138,143 @@ func (c *RealClient) ListReviewComments(owner, repo string, prNumber int) ([]Com
 	return comments, nil
 }
 
+// ListIssueCommentsIfChanged fetches issue comments unless they are unchanged since etag.
+// GitHub does not count 304 Not Modified responses against the rate limit.
+func (c *RealClient) ListIssueCommentsIfChanged(owner, repo string, prNumber int, etag string) (*ConditionalComments, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
+	}
+	if prNumber <= 0 {
+		return nil, fmt.Errorf("invalid PR number %d: must be positive", prNumber)
+	}
+
+	endpoint := fmt.Sprintf("repos/%s/%s/issues/%d/comments?per_page=100", owner, repo, prNumber)
+
+	result, err := c.getIfNoneMatch(endpoint, etag)
+	if err != nil {
+		return nil, c.wrapAPIError(err, "fetch issue comments for PR #%d in %s/%s", prNumber, owner, repo)
+	}
+
+	for i := range result.Comments {
+		result.Comments[i].Type = "issue"
+	}
+
+	return result, nil
+}
+
+// ListReviewCommentsIfChanged fetches review comments unless they are unchanged since etag
+func (c *RealClient) ListReviewCommentsIfChanged(owner, repo string, prNumber int, etag string) (*ConditionalComments, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
+	}
+	if prNumber <= 0 {
+		return nil, fmt.Errorf("invalid PR number %d: must be positive", prNumber)
+	}
+
+	endpoint := fmt.Sprintf("repos/%s/%s/pulls/%d/comments?per_page=100", owner, repo, prNumber)
+
+	result, err := c.getIfNoneMatch(endpoint, etag)
+	if err != nil {
+		return nil, c.wrapAPIError(err, "fetch review comments for PR #%d in %s/%s", prNumber, owner, repo)
+	}
+
+	for i := range result.Comments {
+		result.Comments[i].Type = "review"
+	}
+
+	return result, nil
+}
+
+// getIfNoneMatch fetches every page of a comment listing unless none has changed since
+// etag, which holds the ETag of each page separated by spaces. Every page is checked,
+// not just the first, so comments added or edited past the first 100 are noticed.
+func (c *RealClient) getIfNoneMatch(endpoint, etag string) (*ConditionalComments, error) {
+	// The first page is reused when it is the one that changed
+	var changed *http.Response
+	if pageETags := strings.Fields(etag); len(pageETags) > 0 {
+		modified := false
+		for i, pageETag := range pageETags {
+			resp, err := c.getCommentPage(endpoint, i+1, pageETag)
+			if err != nil {
+				var httpErr *api.HTTPError
+				if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotModified {
+					continue
+				}
+				return nil, err
+			}
+			modified = true
+			if i == 0 {
+				changed = resp
+			} else {
+				resp.Body.Close()
+			}
+			break
+		}
+		if !modified {
+			return &ConditionalComments{ETag: etag, NotModified: true}, nil
+		}
+	}
+
+	var comments []Comment
+	var pageETags []string
+	for page := 1; ; page++ {
+		resp := changed
+		changed = nil
+		if resp == nil {
+			var err error
+			if resp, err = c.getCommentPage(endpoint, page, ""); err != nil {
+				return nil, err
+			}
+		}
+
+		var pageComments []Comment
+		err := json.NewDecoder(resp.Body).Decode(&pageComments)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode comments: %w", err)
+		}
+		comments = append(comments, pageComments...)
+		pageETags = append(pageETags, resp.Header.Get("ETag"))
+
+		if len(pageComments) < 100 {
+			break
+		}
+	}
+
+	// A page without an ETag can't be checked later, so the listing can't be either
+	for _, pageETag := range pageETags {
+		if pageETag == "" {
+			return &ConditionalComments{Comments: comments}, nil
+		}
+	}
+	return &ConditionalComments{Comments: comments, ETag: strings.Join(pageETags, " ")}, nil
+}
+
+// getCommentPage requests one page of 100 comments, conditionally when etag is set
+func (c *RealClient) getCommentPage(endpoint string, page int, etag string) (*http.Response, error) {
+	if page > 1 {
+		endpoint = fmt.Sprintf("%s&page=%d", endpoint, page)
+	}
+	ctx := context.WithValue(context.Background(), etagContextKey{}, etag)
+	return c.restClient.RequestWithContext(ctx, http.MethodGet, endpoint, nil)
+}
+
+// etagContextKey carries the ETag for a conditional request through the request context
+type etagContextKey struct{}
+
+// conditionalTransport sets If-None-Match on requests whose context carries an ETag
+type conditionalTransport struct {
+	base http.RoundTripper
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if etag, ok := req.Context().Value(etagContextKey{}).(string); ok && etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", etag)
+	}
+	return t.base.RoundTrip(req)
+}
+
 // CreateIssueComment adds a general comment to a PR
 func (c *RealClient) CreateIssueComment(owner, repo string, prNumber int, body string) (*Comment, error) {
 	if err := validateRepoParams(owner, repo); err != nil {
@@ -279,94 +468,237 @@ func (c *RealClient) ResolveReviewThread(threadID string) error {
 
 // AddReaction adds a reaction to a comment
 func (c *RealClient) AddReaction(owner, repo string, commentID int, prNumber int, reaction string) error {
-	if err := validateRepoParams(owner, repo); err != nil {
+	if err := validateReactionParams(owner, repo, commentID, prNumber, reaction); err != nil {
 		return err
 	}
+
+	endpoint, err := c.commentReactionsEndpoint(owner, repo, commentID, prNumber, "add_reaction")
+	if err != nil {
+		return err
+	}
+
+	if err := c.postReaction(endpoint, reaction); err != nil {
+		return CreateSmartError(c, "add_reaction", "reply", commentID, prNumber, err)
+	}
+
+	return nil
+}
+
+// RemoveReaction removes a reaction from a comment
+func (c *RealClient) RemoveReaction(owner, repo string, commentID int, prNumber int, reaction string) error {
+	if err := validateReactionParams(owner, repo, commentID, prNumber, reaction); err != nil {
+		return err
+	}
+
+	endpoint, err := c.commentReactionsEndpoint(owner, repo, commentID, prNumber, "remove_reaction")
+	if err != nil {
+		return err
+	}
+
+	found, err := c.deleteOwnReaction(endpoint, reaction)
+	if err != nil {
+		return CreateSmartError(c, "remove_reaction", "reply", commentID, prNumber, err)
+	}
+	if !found {
+		return fmt.Errorf("'%s' reaction not found on comment #%d (you may not have reacted with this emoji)", reaction, commentID)
+	}
+
+	return nil
+}
+
+// ListReactions fetches every reaction on a comment along with who left it
+func (c *RealClient) ListReactions(owner, repo string, commentID int, prNumber int) ([]Reaction, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
+	}
 	if commentID <= 0 {
-		return fmt.Errorf("invalid comment ID %d: must be positive", commentID)
+		return nil, fmt.Errorf("invalid comment ID %d: must be positive", commentID)
 	}
 	if prNumber <= 0 {
-		return fmt.Errorf("invalid PR number %d: must be positive", prNumber)
+		return nil, fmt.Errorf("invalid PR number %d: must be positive", prNumber)
+	}
+
+	endpoint, err := c.commentReactionsEndpoint(owner, repo, commentID, prNumber, "list_reactions")
+	if err != nil {
+		return nil, err
+	}
+
+	reactions, err := c.getReactions(endpoint)
+	if err != nil {
+		return nil, c.wrapAPIError(err, "list reactions on comment #%d", commentID)
 	}
+	return reactions, nil
+}
+
+// AddCommentReaction adds a reaction to a comment of a known type with a single request
+func (c *RealClient) AddCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
 	if !isValidReaction(reaction) {
 		return fmt.Errorf("invalid reaction '%s': must be one of +1, -1, laugh, hooray, confused, heart, rocket, eyes", reaction)
 	}
-
-	// Detect comment type first to use the correct endpoint
-	commentInfo, err := c.DetectCommentType(owner, repo, commentID, prNumber)
+	endpoint, err := typedCommentReactionsEndpoint(owner, repo, commentID, commentType)
 	if err != nil {
-		return CreateSmartError(c, "add_reaction", "reply", commentID, prNumber, err)
+		return err
 	}
 
-	if !commentInfo.Found {
-		return CreateSmartError(c, "add_reaction", "reply", commentID, prNumber, fmt.Errorf("comment #%d not found", commentID))
+	if err := c.postReaction(endpoint, reaction); err != nil {
+		return c.wrapAPIError(err, "add reaction to comment #%d", commentID)
 	}
+	return nil
+}
 
-	// Use the correct endpoint based on detected comment type
-	var endpoint string
-	if commentInfo.Type == "review" {
-		endpoint = fmt.Sprintf("repos/%s/%s/pulls/comments/%d/reactions", owner, repo, commentID)
-	} else {
-		endpoint = fmt.Sprintf("repos/%s/%s/issues/comments/%d/reactions", owner, repo, commentID)
+// RemoveCommentReaction removes the current user's reaction from a comment of a known type
+func (c *RealClient) RemoveCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	if !isValidReaction(reaction) {
+		return fmt.Errorf("invalid reaction '%s': must be one of +1, -1, laugh, hooray, confused, heart, rocket, eyes", reaction)
+	}
+	endpoint, err := typedCommentReactionsEndpoint(owner, repo, commentID, commentType)
+	if err != nil {
+		return err
 	}
 
-	payload := map[string]string{"content": reaction}
-	body, err := json.Marshal(payload)
+	found, err := c.deleteOwnReaction(endpoint, reaction)
 	if err != nil {
-		return fmt.Errorf("failed to marshal reaction payload: %w", err)
+		return c.wrapAPIError(err, "remove reaction from comment #%d", commentID)
 	}
+	if !found {
+		return fmt.Errorf("'%s' reaction not found on comment #%d (you may not have reacted with this emoji)", reaction, commentID)
+	}
+	return nil
+}
 
-	err = c.restClient.Post(endpoint, bytes.NewReader(body), nil)
+// ListCommentReactions fetches every reaction on a comment of a known type without looking the type up
+func (c *RealClient) ListCommentReactions(owner, repo string, commentID int, commentType string) ([]Reaction, error) {
+	endpoint, err := typedCommentReactionsEndpoint(owner, repo, commentID, commentType)
 	if err != nil {
-		return CreateSmartError(c, "add_reaction", "reply", commentID, prNumber, err)
+		return nil, err
 	}
 
+	reactions, err := c.getReactions(endpoint)
+	if err != nil {
+		return nil, c.wrapAPIError(err, "list reactions on comment #%d", commentID)
+	}
+	return reactions, nil
+}
+
+// AddPRReaction adds a reaction to the PR description
+func (c *RealClient) AddPRReaction(owner, repo string, prNumber int, reaction string) error {
+	if err := validatePRReactionParams(owner, repo, prNumber, reaction); err != nil {
+		return err
+	}
+
+	if err := c.postReaction(prReactionsEndpoint(owner, repo, prNumber), reaction); err != nil {
+		return c.wrapAPIError(err, "add reaction to PR #%d", prNumber)
+	}
 	return nil
 }
 
-// RemoveReaction removes a reaction from a comment
-func (c *RealClient) RemoveReaction(owner, repo string, commentID int, prNumber int, reaction string) error {
-	if err := validateRepoParams(owner, repo); err != nil {
+// RemovePRReaction removes the current user's reaction from the PR description
+func (c *RealClient) RemovePRReaction(owner, repo string, prNumber int, reaction string) error {
+	if err := validatePRReactionParams(owner, repo, prNumber, reaction); err != nil {
 		return err
 	}
-	if commentID <= 0 {
-		return fmt.Errorf("invalid comment ID %d: must be positive", commentID)
+
+	found, err := c.deleteOwnReaction(prReactionsEndpoint(owner, repo, prNumber), reaction)
+	if err != nil {
+		return c.wrapAPIError(err, "remove reaction from PR #%d", prNumber)
+	}
+	if !found {
+		return fmt.Errorf("'%s' reaction not found on PR #%d (you may not have reacted with this emoji)", reaction, prNumber)
+	}
+	return nil
+}
+
+// ListPRReactions fetches every reaction on the PR description along with who left it
+func (c *RealClient) ListPRReactions(owner, repo string, prNumber int) ([]Reaction, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
 	}
 	if prNumber <= 0 {
-		return fmt.Errorf("invalid PR number %d: must be positive", prNumber)
+		return nil, fmt.Errorf("invalid PR number %d: must be positive", prNumber)
 	}
-	if !isValidReaction(reaction) {
-		return fmt.Errorf("invalid reaction '%s': must be one of +1, -1, laugh, hooray, confused, heart, rocket, eyes", reaction)
+
+	reactions, err := c.getReactions(prReactionsEndpoint(owner, repo, prNumber))
+	if err != nil {
+		return nil, c.wrapAPIError(err, "list reactions on PR #%d", prNumber)
 	}
+	return reactions, nil
+}
 
-	// Detect comment type first to use the correct endpoint
+// commentReactionsEndpoint detects the comment type and returns its reactions endpoint
+func (c *RealClient) commentReactionsEndpoint(owner, repo string, commentID, prNumber int, operation string) (string, error) {
 	commentInfo, err := c.DetectCommentType(owner, repo, commentID, prNumber)
 	if err != nil {
-		return CreateSmartError(c, "remove_reaction", "reply", commentID, prNumber, err)
+		return "", CreateSmartError(c, operation, "reply", commentID, prNumber, err)
 	}
 
 	if !commentInfo.Found {
-		return CreateSmartError(c, "remove_reaction", "reply", commentID, prNumber, fmt.Errorf("comment #%d not found", commentID))
+		return "", CreateSmartError(c, operation, "reply", commentID, prNumber, fmt.Errorf("comment #%d not found", commentID))
 	}
 
-	// Use the correct endpoint based on detected comment type
-	var endpoint string
-	if commentInfo.Type == "review" {
-		endpoint = fmt.Sprintf("repos/%s/%s/pulls/comments/%d/reactions", owner, repo, commentID)
-	} else {
-		endpoint = fmt.Sprintf("repos/%s/%s/issues/comments/%d/reactions", owner, repo, commentID)
+	return reactionsEndpointForType(owner, repo, commentID, commentInfo.Type), nil
+}
+
+// typedCommentReactionsEndpoint validates a comment of a known type and returns its reactions endpoint
+func typedCommentReactionsEndpoint(owner, repo string, commentID int, commentType string) (string, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return "", err
 	}
+	if commentID <= 0 {
+		return "", fmt.Errorf("invalid comment ID %d: must be positive", commentID)
+	}
+	if commentType != "issue" && commentType != "review" {
+		return "", fmt.Errorf("invalid comment type %q: must be issue or review", commentType)
+	}
+	return reactionsEndpointForType(owner, repo, commentID, commentType), nil
+}
 
-	// Get reactions to find the ID to delete
-	var reactions []struct {
-		ID      int    `json:"id"`
-		Content string `json:"content"`
-		User    User   `json:"user"`
+// reactionsEndpointForType returns the reactions endpoint of an issue or review comment
+func reactionsEndpointForType(owner, repo string, commentID int, commentType string) string {
+	if commentType == "review" {
+		return fmt.Sprintf("repos/%s/%s/pulls/comments/%d/reactions", owner, repo, commentID)
 	}
+	return fmt.Sprintf("repos/%s/%s/issues/comments/%d/reactions", owner, repo, commentID)
+}
 
-	err = c.restClient.Get(endpoint, &reactions)
+// prReactionsEndpoint returns the reactions endpoint for a PR description (PRs are issues here)
+func prReactionsEndpoint(owner, repo string, prNumber int) string {
+	return fmt.Sprintf("repos/%s/%s/issues/%d/reactions", owner, repo, prNumber)
+}
+
+// postReaction creates a reaction at a reactions endpoint
+func (c *RealClient) postReaction(endpoint, reaction string) error {
+	payload := map[string]string{"content": reaction}
+	body, err := json.Marshal(payload)
 	if err != nil {
-		return CreateSmartError(c, "remove_reaction", "reply", commentID, prNumber, err)
+		return fmt.Errorf("failed to marshal reaction payload: %w", err)
+	}
+
+	return c.restClient.Post(endpoint, bytes.NewReader(body), nil)
+}
+
+// getReactions fetches all pages of a reactions endpoint
+func (c *RealClient) getReactions(endpoint string) ([]Reaction, error) {
+	var reactions []Reaction
+	for page := 1; ; page++ {
+		var pageReactions []Reaction
+		if err := c.restClient.Get(fmt.Sprintf("%s?per_page=100&page=%d", endpoint, page), &pageReactions); err != nil {
+			return nil, err
+		}
+
+		reactions = append(reactions, pageReactions...)
+		if len(pageReactions) < 100 {
+			break
+		}
+	}
+	return reactions, nil
+}
+
+// deleteOwnReaction deletes the current user's reaction of the given type, reporting whether one existed
+func (c *RealClient) deleteOwnReaction(endpoint, reaction string) (bool, error) {
+	// Get reactions to find the ID to delete
+	reactions, err := c.getReactions(endpoint)
+	if err != nil {
+		return false, err
 	}
 
 	// Find current user's reaction
@@ -375,29 +707,44 @@ func (c *RealClient) RemoveReaction(owner, repo string, commentID int, prNumber
 	}
 	err = c.restClient.Get("user", &currentUser)
 	if err != nil {
-		return c.wrapAPIError(err, "get current user info")
+		return false, c.wrapAPIError(err, "get current user info")
 	}
 
-	// Find and delete the reaction
 	for _, r := range reactions {
 		if r.Content == reaction && r.User.Login == currentUser.Login {
-			// Use the correct delete endpoint based on comment type
-			var deleteEndpoint string
-			if commentInfo.Type == "review" {
-				deleteEndpoint = fmt.Sprintf("repos/%s/%s/pulls/comments/%d/reactions/%d", owner, repo, commentID, r.ID)
-			} else {
-				deleteEndpoint = fmt.Sprintf("repos/%s/%s/issues/comments/%d/reactions/%d", owner, repo, commentID, r.ID)
+			if err := c.restClient.Delete(fmt.Sprintf("%s/%d", endpoint, r.ID), nil); err != nil {
+				return false, err
 			}
-
-			err = c.restClient.Delete(deleteEndpoint, nil)
-			if err != nil {
-				return CreateSmartError(c, "remove_reaction", "reply", commentID, prNumber, err)
-			}
-			return nil
+			return true, nil
 		}
 	}
 
-	return fmt.Errorf("'%s' reaction not found on comment #%d (you may not have reacted with this emoji)", reaction, commentID)
+	return false, nil
+}
+
+// validateReactionParams checks the arguments shared by the comment reaction calls
+func validateReactionParams(owner, repo string, commentID, prNumber int, reaction string) error {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return err
+	}
+	if commentID <= 0 {
+		return fmt.Errorf("invalid comment ID %d: must be positive", commentID)
+	}
+	return validatePRReactionParams(owner, repo, prNumber, reaction)
+}
+
+// validatePRReactionParams checks the arguments shared by the PR reaction calls
+func validatePRReactionParams(owner, repo string, prNumber int, reaction string) error {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return err
+	}
+	if prNumber <= 0 {
+		return fmt.Errorf("invalid PR number %d: must be positive", prNumber)
+	}
+	if !isValidReaction(reaction) {
+		return fmt.Errorf("invalid reaction '%s': must be one of +1, -1, laugh, hooray, confused, heart, rocket, eyes", reaction)
+	}
+	return nil
 }
 
 // EditComment edits an existing comment
@@ -447,6 +794,109 @@ func (c *RealClient) EditComment(owner, repo string, commentID int, prNumber int
 	return nil
 }
 
+// ListCommentEdits fetches a comment's edit history over GraphQL, oldest revision first.
+// Once a comment has been edited GitHub keeps every version, including the original.
+func (c *RealClient) ListCommentEdits(owner, repo string, commentID int, prNumber int) ([]CommentEdit, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
+	}
+	if commentID <= 0 {
+		return nil, fmt.Errorf("invalid comment ID %d: must be positive", commentID)
+	}
+	if prNumber <= 0 {
+		return nil, fmt.Errorf("invalid PR number %d: must be positive", prNumber)
+	}
+
+	// The GraphQL node ID comes with the REST comment
+	commentInfo, err := c.DetectCommentType(owner, repo, commentID, prNumber)
+	if err != nil {
+		return nil, CreateSmartError(c, "history", "history", commentID, prNumber, err)
+	}
+	if !commentInfo.Found {
+		return nil, CreateSmartError(c, "history", "history", commentID, prNumber, fmt.Errorf("comment #%d not found", commentID))
+	}
+	if commentInfo.Comment.NodeID == "" {
+		return nil, fmt.Errorf("comment #%d has no GraphQL node ID", commentID)
+	}
+
+	query := `
+		query($id: ID!, $cursor: String) {
+			node(id: $id) {
+				... on UserContentEditable {
+					userContentEdits(first: 100, after: $cursor) {
+						nodes {
+							editedAt
+							deletedAt
+							diff
+							editor {
+								login
+							}
+						}
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+					}
+				}
+			}
+		}`
+
+	var edits []CommentEdit
+	var cursor *string
+	for {
+		variables := map[string]interface{}{
+			"id":     commentInfo.Comment.NodeID,
+			"cursor": cursor,
+		}
+
+		var result struct {
+			Node struct {
+				UserContentEdits struct {
+					Nodes []struct {
+						EditedAt  time.Time  `json:"editedAt"`
+						DeletedAt *time.Time `json:"deletedAt"`
+						Diff      *string    `json:"diff"`
+						Editor    *struct {
+							Login string `json:"login"`
+						} `json:"editor"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"userContentEdits"`
+			} `json:"node"`
+		}
+
+		if err := c.graphqlClient.Do(query, variables, &result); err != nil {
+			return nil, c.wrapAPIError(err, "fetch edit history of comment #%d", commentID)
+		}
+
+		for _, node := range result.Node.UserContentEdits.Nodes {
+			edit := CommentEdit{EditedAt: node.EditedAt, Deleted: node.DeletedAt != nil}
+			if node.Diff != nil {
+				edit.Body = *node.Diff
+			}
+			if node.Editor != nil {
+				edit.Editor = node.Editor.Login
+			}
+			edits = append(edits, edit)
+		}
+
+		pageInfo := result.Node.UserContentEdits.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		cursor = &pageInfo.EndCursor
+	}
+
+	// GitHub lists the newest edit first
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits, nil
+}
+
 // AddReviewComment adds a line-specific comment to a PR
 func (c *RealClient) AddReviewComment(owner, repo string, pr int, comment ReviewCommentInput) error {
 	if err := validateRepoParams(owner, repo); err != nil {
@@ -514,7 +964,11 @@ func (c *RealClient) FetchPRDiff(owner, repo string, pr int) (*PullRequestDiff,
 	}
 
 	// Fetch the actual diff
-	diffResp, err := http.Get(prData.DiffURL)
+	diffClient := c.diffClient
+	if diffClient == nil {
+		diffClient = http.DefaultClient
+	}
+	diffResp, err := diffClient.Get(prData.DiffURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch diff from GitHub: %w", err)
 	}
@@ -581,29 +1035,27 @@ func isValidReaction(reaction string) bool {
 func (c *RealClient) wrapAPIError(err error, operation string, args ...interface{}) error {
 	context := fmt.Sprintf(operation, args...)
 
-	// Check if this is a rate limit error
-	if strings.Contains(err.Error(), "rate limit") || strings.Contains(err.Error(), "403") {
-		return fmt.Errorf("rate limit exceeded while trying to %s: %w\n\n💡 Tips:\n   • Wait a few minutes before retrying\n   • Check your rate limit status: gh api rate_limit\n   • Consider reducing API calls if this happens frequently", context, err)
+	// Classify by the response status; errors that aren't HTTP responses stay generic
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) {
+		return fmt.Errorf("GitHub API error while trying to %s: %w", context, err)
 	}
 
-	// Check for common API errors and provide helpful messages
-	if strings.Contains(err.Error(), "404") {
+	switch {
+	case isSecondaryRateLimit(err):
+		return fmt.Errorf("secondary rate limit triggered while trying to %s: %w\n\n💡 Tips:\n   • This is a temporary protective measure by GitHub\n   • Wait 60 seconds before retrying\n   • Reduce the frequency of API calls", context, err)
+	case IsRateLimited(err):
+		return fmt.Errorf("rate limit exceeded while trying to %s: %w\n\n💡 Tips:\n   • Wait a few minutes before retrying\n   • Check your rate limit status: gh api rate_limit\n   • Consider reducing API calls if this happens frequently", context, err)
+	case httpErr.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("permission denied while trying to %s: %w\n\n💡 Tips:\n   • Check that your token has access to this repository: gh auth status\n   • Some actions need write access or a token with more scopes", context, err)
+	case httpErr.StatusCode == http.StatusNotFound:
 		return fmt.Errorf("resource not found while trying to %s: %w\n\n💡 Tips:\n   • Verify the repository exists and you have access to it\n   • Check the PR/comment ID is correct\n   • Ensure you have the right permissions", context, err)
-	}
-
-	if strings.Contains(err.Error(), "401") {
+	case httpErr.StatusCode == http.StatusUnauthorized:
 		return fmt.Errorf("authentication failed while trying to %s: %w\n\n💡 Tips:\n   • Check your GitHub CLI authentication: gh auth status\n   • Re-authenticate if needed: gh auth login\n   • Verify you have access to this repository", context, err)
-	}
-
-	if strings.Contains(err.Error(), "422") {
+	case httpErr.StatusCode == http.StatusUnprocessableEntity:
 		return fmt.Errorf("validation error while trying to %s: %w\n\n💡 Tips:\n   • Check that your input parameters are valid\n   • Verify line numbers exist in the diff\n   • Ensure comment body is not empty", context, err)
 	}
 
-	// Check for secondary rate limits (GitHub doesn't always send proper headers)
-	if strings.Contains(err.Error(), "abuse") || strings.Contains(err.Error(), "secondary") {
-		return fmt.Errorf("secondary rate limit triggered while trying to %s: %w\n\n💡 Tips:\n   • This is a temporary protective measure by GitHub\n   • Wait 60 seconds before retrying\n   • Reduce the frequency of API calls", context, err)
-	}
-
 	// Generic API error
 	return fmt.Errorf("GitHub API error while trying to %s: %w", context, err)
 }
@@ -724,6 +1176,25 @@ func (c *RealClient) GetPRDetails(owner, repo string, pr int) (map[string]interf
 	return result, nil
 }
 
+// ListReviews fetches the reviews submitted on a PR
+func (c *RealClient) ListReviews(owner, repo string, pr int) ([]Review, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
+	}
+	if pr <= 0 {
+		return nil, fmt.Errorf("invalid PR number %d: must be positive", pr)
+	}
+
+	endpoint := fmt.Sprintf("repos/%s/%s/pulls/%d/reviews?per_page=100", owner, repo, pr)
+
+	var reviews []Review
+	if err := c.restClient.Get(endpoint, &reviews); err != nil {
+		return nil, c.wrapAPIError(err, "list reviews for PR #%d in %s/%s", pr, owner, repo)
+	}
+
+	return reviews, nil
+}
+
 // FindPendingReview finds a pending review for the current user on a PR
 func (c *RealClient) FindPendingReview(owner, repo string, pr int) (int, error) {
 	if err := validateRepoParams(owner, repo); err != nil {
@@ -754,6 +1225,40 @@ func (c *RealClient) FindPendingReview(owner, repo string, pr int) (int, error)
 	return 0, nil // No pending review found
 }
 
+// ListReviewCommentsForReview fetches the line comments of one review. For a
+// pending review only its author sees them.
+func (c *RealClient) ListReviewCommentsForReview(owner, repo string, pr, reviewID int) ([]Comment, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
+	}
+	if pr <= 0 {
+		return nil, fmt.Errorf("invalid PR number %d: must be positive", pr)
+	}
+	if reviewID <= 0 {
+		return nil, fmt.Errorf("invalid review ID %d: must be positive", reviewID)
+	}
+
+	var comments []Comment
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf("repos/%s/%s/pulls/%d/reviews/%d/comments?per_page=100&page=%d", owner, repo, pr, reviewID, page)
+
+		var pageComments []Comment
+		if err := c.restClient.Get(endpoint, &pageComments); err != nil {
+			return nil, c.wrapAPIError(err, "list comments of review %d on PR #%d in %s/%s", reviewID, pr, owner, repo)
+		}
+
+		for _, comment := range pageComments {
+			comment.Type = "review"
+			comments = append(comments, comment)
+		}
+		if len(pageComments) < 100 {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
 // SubmitReview submits a pending review with a body and event
 func (c *RealClient) SubmitReview(owner, repo string, pr, reviewID int, body, event string) error {
 	if err := validateRepoParams(owner, repo); err != nil {
@@ -811,3 +1316,241 @@ func (c *RealClient) getCommitIDFromExistingComments(owner, repo string, pr int)
 
 	return "", nil // No commit ID found
 }
+
+// ListReviewThreads fetches the review threads of a PR with their resolution state
+func (c *RealClient) ListReviewThreads(owner, repo string, prNumber int) ([]ReviewThread, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
+	}
+	if prNumber <= 0 {
+		return nil, fmt.Errorf("invalid PR number %d: must be positive", prNumber)
+	}
+
+	query := `
+		query($owner: String!, $name: String!, $number: Int!) {
+			repository(owner: $owner, name: $name) {
+				pullRequest(number: $number) {
+					reviewThreads(first: 100) {
+						nodes {
+							id
+							isResolved
+							isOutdated
+							path
+							line
+							resolvedBy {
+								login
+							}
+							comments(first: 100) {
+								nodes {
+									databaseId
+								}
+							}
+						}
+					}
+				}
+			}
+		}`
+
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"name":   repo,
+		"number": prNumber,
+	}
+
+	var result struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						ID         string `json:"id"`
+						IsResolved bool   `json:"isResolved"`
+						IsOutdated bool   `json:"isOutdated"`
+						Path       string `json:"path"`
+						Line       int    `json:"line"`
+						ResolvedBy *struct {
+							Login string `json:"login"`
+						} `json:"resolvedBy"`
+						Comments struct {
+							Nodes []struct {
+								DatabaseID int `json:"databaseId"`
+							} `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+
+	err := c.graphqlClient.Do(query, variables, &result)
+	if err != nil {
+		return nil, c.wrapAPIError(err, "list review threads for PR #%d in %s/%s", prNumber, owner, repo)
+	}
+
+	var threads []ReviewThread
+	for _, node := range result.Repository.PullRequest.ReviewThreads.Nodes {
+		thread := ReviewThread{
+			ID:         node.ID,
+			IsResolved: node.IsResolved,
+			IsOutdated: node.IsOutdated,
+			Path:       node.Path,
+			Line:       node.Line,
+		}
+		if node.ResolvedBy != nil {
+			thread.ResolvedBy = node.ResolvedBy.Login
+		}
+		for _, comment := range node.Comments.Nodes {
+			thread.CommentIDs = append(thread.CommentIDs, comment.DatabaseID)
+		}
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
+
+// SearchPullRequests runs a GitHub issue search query restricted to pull requests
+func (c *RealClient) SearchPullRequests(query string, limit int) ([]PullRequest, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+	if !strings.Contains(query, "is:pr") && !strings.Contains(query, "type:pr") {
+		query = "is:pr " + query
+	}
+
+	var pullRequests []PullRequest
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf("search/issues?q=%s&per_page=100&page=%d", url.QueryEscape(query), page)
+
+		var result struct {
+			TotalCount int `json:"total_count"`
+			Items      []struct {
+				Number    int       `json:"number"`
+				Title     string    `json:"title"`
+				State     string    `json:"state"`
+				User      User      `json:"user"`
+				HTMLURL   string    `json:"html_url"`
+				CreatedAt time.Time `json:"created_at"`
+				UpdatedAt time.Time `json:"updated_at"`
+				Labels    []struct {
+					Name string `json:"name"`
+				} `json:"labels"`
+				PullRequest struct {
+					MergedAt *time.Time `json:"merged_at"`
+				} `json:"pull_request"`
+			} `json:"items"`
+		}
+
+		err := c.restClient.Get(endpoint, &result)
+		if err != nil {
+			return nil, c.wrapAPIError(err, "search pull requests with query %q", query)
+		}
+
+		for _, item := range result.Items {
+			pr := PullRequest{
+				Number:    item.Number,
+				Title:     item.Title,
+				State:     item.State,
+				User:      item.User,
+				HTMLURL:   item.HTMLURL,
+				CreatedAt: item.CreatedAt,
+				UpdatedAt: item.UpdatedAt,
+				MergedAt:  item.PullRequest.MergedAt,
+			}
+			if pr.MergedAt != nil {
+				pr.State = "merged"
+			}
+			for _, label := range item.Labels {
+				pr.Labels = append(pr.Labels, label.Name)
+			}
+			pullRequests = append(pullRequests, pr)
+
+			if limit > 0 && len(pullRequests) >= limit {
+				return pullRequests, nil
+			}
+		}
+
+		if len(result.Items) < 100 || len(pullRequests) >= result.TotalCount {
+			break
+		}
+	}
+
+	return pullRequests, nil
+}
+
+// ListRepoReviewComments fetches review comments across every PR in a repository, newest first.
+// Pages are requested one at a time, so no more are fetched once keep rejects a comment.
+func (c *RealClient) ListRepoReviewComments(owner, repo string, since time.Time, keep func(Comment) bool) ([]Comment, error) {
+	if err := validateRepoParams(owner, repo); err != nil {
+		return nil, err
+	}
+
+	var comments []Comment
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf("repos/%s/%s/pulls/comments?sort=created&direction=desc&per_page=100&page=%d", owner, repo, page)
+		if !since.IsZero() {
+			endpoint += "&since=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+		}
+
+		var pageComments []Comment
+		err := c.restClient.Get(endpoint, &pageComments)
+		if err != nil {
+			return nil, c.wrapAPIError(err, "list review comments in %s/%s", owner, repo)
+		}
+
+		for _, comment := range pageComments {
+			comment.Type = "review"
+			if keep != nil && !keep(comment) {
+				return comments, nil
+			}
+			comments = append(comments, comment)
+		}
+
+		if len(pageComments) < 100 {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
+// GetRateLimit fetches the core REST API budget; this endpoint does not count against it
+func (c *RealClient) GetRateLimit() (*RateLimit, error) {
+	var response struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+
+	if err := c.restClient.Get("rate_limit", &response); err != nil {
+		return nil, c.wrapAPIError(err, "check rate limit")
+	}
+
+	core := response.Resources.Core
+	return &RateLimit{
+		Limit:     core.Limit,
+		Remaining: core.Remaining,
+		Reset:     time.Unix(core.Reset, 0),
+	}, nil
+}
+
+// PRNumberFromURL extracts the PR number from a pull_request_url or html_url
+func PRNumberFromURL(prURL string) (int, error) {
+	trimmed := strings.TrimRight(prURL, "/")
+	if idx := strings.IndexAny(trimmed, "#?"); idx != -1 {
+		trimmed = trimmed[:idx]
+	}
+
+	parts := strings.Split(trimmed, "/")
+	if len(parts) < 2 || (parts[len(parts)-2] != "pulls" && parts[len(parts)-2] != "pull") {
+		return 0, fmt.Errorf("invalid pull request URL: %s", prURL)
+	}
+
+	number, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse PR number from URL %s: %w", prURL, err)
+	}
+	return number, nil
+}
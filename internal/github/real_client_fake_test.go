@@ -0,0 +1,221 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/fakegithub"
+)
+
+// newFakeGitHubClient returns a RealClient talking to a fake GitHub seeded with the shared test fixture
+func newFakeGitHubClient(t *testing.T) (*RealClient, *fakegithub.Server) {
+	t.Helper()
+	server, err := fakegithub.NewFromFixture("../../testdata/fixtures/github.yaml")
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+	server.SetClock(func() time.Time { return time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC) })
+
+	client, err := NewRealClientForServer(server.URL())
+	require.NoError(t, err)
+	return client, server
+}
+
+func TestNewRealClientForServerRejectsBadURL(t *testing.T) {
+	_, err := NewRealClientForServer("localhost:8080")
+	assert.ErrorContains(t, err, "invalid server URL")
+}
+
+func TestRealClientAgainstFakeComments(t *testing.T) {
+	client, _ := newFakeGitHubClient(t)
+
+	issueComments, err := client.ListIssueComments("test-owner", "test-repo", 123)
+	require.NoError(t, err)
+	require.Len(t, issueComments, 1)
+	assert.Equal(t, "This looks good to me!", issueComments[0].Body)
+	assert.Equal(t, "issue", issueComments[0].Type)
+
+	reviewComments, err := client.ListReviewComments("test-owner", "test-repo", 123)
+	require.NoError(t, err)
+	require.Len(t, reviewComments, 1)
+	assert.Equal(t, "src/main.go", reviewComments[0].Path)
+	assert.Equal(t, 42, reviewComments[0].Line)
+	assert.Equal(t, 1, reviewComments[0].Reactions.PlusOne)
+
+	created, err := client.CreateIssueComment("test-owner", "test-repo", 789, "Looks solid")
+	require.NoError(t, err)
+	assert.Equal(t, "test-user", created.User.Login)
+
+	reply, err := client.CreateReviewCommentReply("test-owner", "test-repo", 2001, "Fixed")
+	require.NoError(t, err)
+	assert.Equal(t, 2001, reply.InReplyToID)
+	assert.Equal(t, "src/main.go", reply.Path)
+
+	require.NoError(t, client.EditComment("test-owner", "test-repo", created.ID, 789, "Looks solid!"))
+	edits, err := client.ListCommentEdits("test-owner", "test-repo", created.ID, 789)
+	require.NoError(t, err)
+	require.Len(t, edits, 2)
+	assert.Equal(t, "Looks solid", edits[0].Body)
+	assert.Equal(t, "Looks solid!", edits[1].Body)
+	assert.Equal(t, time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC), edits[1].EditedAt)
+
+	err = client.AddReviewComment("test-owner", "test-repo", 789, ReviewCommentInput{Body: "Out of diff", Path: "ratelimit.go", Line: 40, CommitID: "abc"})
+	assert.ErrorContains(t, err, "validation error")
+
+	_, err = client.ListIssueComments("test-owner", "test-repo", 999)
+	assert.ErrorContains(t, err, "resource not found")
+}
+
+func TestRealClientAgainstFakeConditionalListing(t *testing.T) {
+	client, server := newFakeGitHubClient(t)
+
+	first, err := client.ListIssueCommentsIfChanged("test-owner", "test-repo", 123, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, first.ETag)
+
+	unchanged, err := client.ListIssueCommentsIfChanged("test-owner", "test-repo", 123, first.ETag)
+	require.NoError(t, err)
+	assert.True(t, unchanged.NotModified)
+
+	_, err = client.CreateIssueComment("test-owner", "test-repo", 123, "New")
+	require.NoError(t, err)
+	changed, err := client.ListIssueCommentsIfChanged("test-owner", "test-repo", 123, first.ETag)
+	require.NoError(t, err)
+	assert.False(t, changed.NotModified)
+	assert.Len(t, changed.Comments, 2)
+
+	assert.Contains(t, server.Requests(), "POST /repos/test-owner/test-repo/issues/123/comments")
+}
+
+func TestRealClientAgainstFakeReactions(t *testing.T) {
+	client, server := newFakeGitHubClient(t)
+
+	require.NoError(t, client.AddReaction("test-owner", "test-repo", 3002, 456, "rocket"))
+	reactions, err := client.ListReactions("test-owner", "test-repo", 3002, 456)
+	require.NoError(t, err)
+	require.Len(t, reactions, 1)
+	assert.Equal(t, "test-user", reactions[0].User.Login)
+
+	require.NoError(t, client.RemoveReaction("test-owner", "test-repo", 3002, 456, "rocket"))
+	err = client.RemoveReaction("test-owner", "test-repo", 3002, 456, "rocket")
+	assert.ErrorContains(t, err, "reaction not found")
+
+	// With the type known, a reaction is a single request without listing the PR's comments
+	before := len(server.Requests())
+	require.NoError(t, client.AddCommentReaction("test-owner", "test-repo", 3002, "review", "eyes"))
+	assert.Equal(t, []string{"POST /repos/test-owner/test-repo/pulls/comments/3002/reactions"}, server.Requests()[before:])
+	require.NoError(t, client.RemoveCommentReaction("test-owner", "test-repo", 3002, "review", "eyes"))
+	assert.ErrorContains(t, client.AddCommentReaction("test-owner", "test-repo", 3002, "commit", "eyes"), "invalid comment type")
+
+	require.NoError(t, client.AddPRReaction("test-owner", "test-repo", 456, "heart"))
+	prReactions, err := client.ListPRReactions("test-owner", "test-repo", 456)
+	require.NoError(t, err)
+	assert.Len(t, prReactions, 1)
+}
+
+func TestRealClientAgainstFakeReviews(t *testing.T) {
+	client, _ := newFakeGitHubClient(t)
+
+	threads, err := client.ListReviewThreads("test-owner", "test-repo", 456)
+	require.NoError(t, err)
+	require.Len(t, threads, 2, "the pending review's comment is not a thread yet")
+	assert.Equal(t, []int{3002, 3003}, threads[1].CommentIDs)
+
+	threadID, err := client.FindReviewThreadForComment("test-owner", "test-repo", 456, 3003)
+	require.NoError(t, err)
+	require.NoError(t, client.ResolveReviewThread(threadID))
+	threads, err = client.ListReviewThreads("test-owner", "test-repo", 456)
+	require.NoError(t, err)
+	assert.True(t, threads[1].IsResolved)
+	assert.Equal(t, "test-user", threads[1].ResolvedBy)
+
+	reviewID, err := client.FindPendingReview("test-owner", "test-repo", 456)
+	require.NoError(t, err)
+	assert.Equal(t, 5002, reviewID)
+	pending, err := client.ListReviewCommentsForReview("test-owner", "test-repo", 456, reviewID)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "Use a parameterized query here", pending[0].Body)
+	assert.Equal(t, "database.py", pending[0].Path)
+	require.NoError(t, client.SubmitReview("test-owner", "test-repo", 456, reviewID, "Please fix", "REQUEST_CHANGES"))
+
+	reviews, err := client.ListReviews("test-owner", "test-repo", 456)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "CHANGES_REQUESTED", reviews[0].State)
+
+	require.NoError(t, client.CreateReview("test-owner", "test-repo", 789, ReviewInput{
+		Body:     "LGTM",
+		Event:    "APPROVE",
+		Comments: []ReviewCommentInput{{Body: "Nice constant", Path: "ratelimit.go", Line: 4}},
+	}))
+	comments, err := client.ListReviewComments("test-owner", "test-repo", 789)
+	require.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	diff, err := client.FetchPRDiff("test-owner", "test-repo", 789)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	assert.Equal(t, "ratelimit.go", diff.Files[0].Filename)
+	assert.True(t, diff.Files[0].Lines[4])
+}
+
+func TestRealClientAgainstFakeRepositoryQueries(t *testing.T) {
+	client, _ := newFakeGitHubClient(t)
+
+	pullRequests, err := client.SearchPullRequests("repo:test-owner/test-repo label:security", 0)
+	require.NoError(t, err)
+	require.Len(t, pullRequests, 1)
+	assert.Equal(t, 456, pullRequests[0].Number)
+	assert.Equal(t, []string{"security"}, pullRequests[0].Labels)
+
+	comments, err := client.ListRepoReviewComments("test-owner", "test-repo", time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, 3003, comments[0].ID, "newest first")
+
+	details, err := client.GetPRDetails("test-owner", "test-repo", 123)
+	require.NoError(t, err)
+	assert.Equal(t, "Add login flow", details["title"])
+
+	limit, err := client.GetRateLimit()
+	require.NoError(t, err)
+	assert.Equal(t, 5000, limit.Limit)
+	assert.Less(t, limit.Remaining, 5000)
+}
+
+func TestRealClientAgainstFakeReactionPages(t *testing.T) {
+	reactions := make([]fakegithub.ReactionFixture, 0, 101)
+	for i := 1; i <= 101; i++ {
+		reactions = append(reactions, fakegithub.ReactionFixture{Content: "+1", User: fmt.Sprintf("fan-%03d", i)})
+	}
+	server := fakegithub.New()
+	defer server.Close()
+	require.NoError(t, server.Seed(&fakegithub.Fixture{
+		Repos: map[string]fakegithub.RepoFixture{
+			"octo/app": {Pulls: []fakegithub.PullFixture{{
+				Number:   7,
+				Title:    "Speed up startup",
+				Author:   "octo",
+				Comments: []fakegithub.CommentFixture{{ID: 100, Author: "octo", Body: "Shipping this today", Reactions: reactions}},
+			}}},
+		},
+	}))
+	client, err := NewRealClientForServer(server.URL())
+	require.NoError(t, err)
+
+	// 101 reactions take a full page of 100 and a second page of one
+	got, err := client.ListReactions("octo", "app", 100, 7)
+	require.NoError(t, err)
+	require.Len(t, got, 101)
+	assert.Equal(t, "fan-001", got[0].User.Login)
+	assert.Equal(t, "fan-101", got[100].User.Login)
+
+	comments, err := client.ListIssueComments("octo", "app", 7)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, 101, comments[0].Reactions.PlusOne)
+}
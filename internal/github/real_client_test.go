@@ -2,9 +2,20 @@ package github
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silouanwright/gh-comment/internal/fakegithub"
 )
 
 func TestNewRealClient(t *testing.T) {
@@ -147,6 +158,48 @@ func TestRealClientValidation(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid reaction 'invalid'")
 	})
 
+	t.Run("ListReactions validation", func(t *testing.T) {
+		_, err := client.ListReactions("owner", "", 123, 123)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "repository name cannot be empty")
+
+		_, err = client.ListReactions("owner", "repo", 0, 123)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid comment ID 0: must be positive")
+
+		_, err = client.ListReactions("owner", "repo", 123, 0)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid PR number 0: must be positive")
+	})
+
+	t.Run("ListCommentEdits validation", func(t *testing.T) {
+		_, err := client.ListCommentEdits("", "repo", 123, 123)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "repository owner cannot be empty")
+
+		_, err = client.ListCommentEdits("owner", "repo", -5, 123)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid comment ID -5: must be positive")
+
+		_, err = client.ListCommentEdits("owner", "repo", 123, 0)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid PR number 0: must be positive")
+	})
+
+	t.Run("PR reaction validation", func(t *testing.T) {
+		err := client.AddPRReaction("owner", "repo", 0, "+1")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid PR number 0: must be positive")
+
+		err = client.RemovePRReaction("owner", "repo", 123, "thumbsup")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid reaction 'thumbsup'")
+
+		_, err = client.ListPRReactions("", "repo", 123)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "repository owner cannot be empty")
+	})
+
 	t.Run("EditComment validation", func(t *testing.T) {
 		err := client.EditComment("", "repo", 123, 123, "test")
 		assert.Error(t, err)
@@ -304,7 +357,7 @@ func TestWrapAPIErrorInRealClient(t *testing.T) {
 	}{
 		{
 			name:           "rate limit error",
-			err:            errors.New("rate limit exceeded"),
+			err:            newHTTPError(http.StatusForbidden, "API rate limit exceeded", "X-RateLimit-Remaining", "0"),
 			operation:      "test operation on %s",
 			args:           []interface{}{"repo"},
 			wantContains:   []string{"rate limit exceeded", "test operation on repo"},
@@ -312,23 +365,31 @@ func TestWrapAPIErrorInRealClient(t *testing.T) {
 		},
 		{
 			name:           "403 error",
-			err:            errors.New("HTTP 403 Forbidden"),
+			err:            newHTTPError(http.StatusForbidden, "Must have admin rights to Repository."),
 			operation:      "access resource %d",
 			args:           []interface{}{123},
-			wantContains:   []string{"rate limit exceeded", "access resource 123"},
-			wantTipKeyword: "gh api rate_limit",
+			wantContains:   []string{"permission denied", "access resource 123"},
+			wantTipKeyword: "gh auth status",
 		},
 		{
 			name:           "404 error",
-			err:            errors.New("HTTP 404 Not Found"),
+			err:            newHTTPError(http.StatusNotFound, "Not Found"),
 			operation:      "find resource %s/%s",
 			args:           []interface{}{"owner", "repo"},
 			wantContains:   []string{"resource not found", "find resource owner/repo"},
 			wantTipKeyword: "Verify the repository exists",
 		},
+		{
+			name:           "404 error from a server on a port containing 403",
+			err:            &api.HTTPError{StatusCode: http.StatusNotFound, Message: "Not Found", RequestURL: &url.URL{Scheme: "http", Host: "127.0.0.1:40389", Path: "/repos/o/r/issues/9/comments"}},
+			operation:      "fetch comments",
+			args:           []interface{}{},
+			wantContains:   []string{"resource not found", "fetch comments"},
+			wantTipKeyword: "Verify the repository exists",
+		},
 		{
 			name:           "401 error",
-			err:            errors.New("HTTP 401 Unauthorized"),
+			err:            newHTTPError(http.StatusUnauthorized, "Bad credentials"),
 			operation:      "authenticate user",
 			args:           []interface{}{},
 			wantContains:   []string{"authentication failed", "authenticate user"},
@@ -336,7 +397,7 @@ func TestWrapAPIErrorInRealClient(t *testing.T) {
 		},
 		{
 			name:           "422 error",
-			err:            errors.New("HTTP 422 Unprocessable Entity"),
+			err:            newHTTPError(http.StatusUnprocessableEntity, "Validation Failed"),
 			operation:      "validate input %s",
 			args:           []interface{}{"data"},
 			wantContains:   []string{"validation error", "validate input data"},
@@ -344,7 +405,7 @@ func TestWrapAPIErrorInRealClient(t *testing.T) {
 		},
 		{
 			name:           "secondary rate limit",
-			err:            errors.New("abuse detection triggered"),
+			err:            newHTTPError(http.StatusForbidden, "You have triggered an abuse detection mechanism."),
 			operation:      "create multiple items",
 			args:           []interface{}{},
 			wantContains:   []string{"secondary rate limit", "create multiple items"},
@@ -452,3 +513,247 @@ index 7654321..gfedcba 100644
 		})
 	}
 }
+
+// cassetteTarget is the repository and PR a cassette test makes its requests against
+type cassetteTarget struct {
+	Owner, Repo string
+	PR          int
+}
+
+// newCassetteClient returns a RealClient replaying testdata/cassettes/<name>.json
+// against the repository and PR the cassette was recorded on, and fails the test
+// if any recorded request goes unused.
+//
+// With UPDATE_CASSETTES=1 it records the cassette from GitHub instead. GH_TOKEN
+// authenticates, and GH_COMMENT_CASSETTE_REPO (owner/repo) and GH_COMMENT_CASSETTE_PR
+// name a throwaway public repository and an open PR the test may comment on.
+// seed, if given, prepares the PR first through a client that is not recorded.
+func newCassetteClient(t *testing.T, name string, seed func(client *RealClient, target cassetteTarget)) (*RealClient, cassetteTarget) {
+	t.Helper()
+	path := filepath.Join("testdata", "cassettes", name+".json")
+	t.Setenv(RecordCassetteEnv, "")
+
+	if os.Getenv("UPDATE_CASSETTES") == "1" {
+		token, repo := os.Getenv("GH_TOKEN"), os.Getenv("GH_COMMENT_CASSETTE_REPO")
+		pr, _ := strconv.Atoi(os.Getenv("GH_COMMENT_CASSETTE_PR"))
+		owner, repoName, ok := strings.Cut(repo, "/")
+		if token == "" || !ok || pr <= 0 {
+			t.Fatal("recording needs GH_TOKEN, GH_COMMENT_CASSETTE_REPO=owner/repo and GH_COMMENT_CASSETTE_PR; see docs/testing/TESTING.md")
+		}
+		target := cassetteTarget{Owner: owner, Repo: repoName, PR: pr}
+		opts := api.ClientOptions{Host: "github.com", AuthToken: token}
+
+		if seed != nil {
+			seeder, err := newRealClient(opts, http.DefaultTransport)
+			require.NoError(t, err)
+			seed(seeder, target)
+		}
+
+		recorder := newRecordingTransport(path, http.DefaultTransport)
+		recorder.recording.cassette.Repo, recorder.recording.cassette.PR = repo, pr
+		t.Cleanup(func() { assert.NoError(t, recorder.Close()) })
+
+		client, err := newRealClient(opts, recorder)
+		require.NoError(t, err)
+		return client, target
+	}
+
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err, "record it with UPDATE_CASSETTES=1 go test ./internal/github/")
+	owner, repoName, ok := strings.Cut(cassette.Repo, "/")
+	require.True(t, ok && cassette.PR > 0, "cassette %s does not say which repository and PR it was recorded on", path)
+
+	transport := newReplayTransport(name, cassette)
+	client, err := newRealClient(api.ClientOptions{Host: "github.com", AuthToken: "replay-token"}, transport)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.Empty(t, transport.Unplayed(), "recorded requests the test no longer makes; re-record with UPDATE_CASSETTES=1")
+	})
+	return client, cassetteTarget{Owner: owner, Repo: repoName, PR: cassette.PR}
+}
+
+func TestRealClientPaginationFromCassette(t *testing.T) {
+	client, target := newCassetteClient(t, "pagination", func(seeder *RealClient, target cassetteTarget) {
+		listing, err := seeder.ListIssueCommentsIfChanged(target.Owner, target.Repo, target.PR, "")
+		require.NoError(t, err)
+		for i := len(listing.Comments); i <= 100; i++ {
+			_, err := seeder.CreateIssueComment(target.Owner, target.Repo, target.PR, fmt.Sprintf("Pagination filler %d", i+1))
+			require.NoError(t, err)
+		}
+	})
+
+	// More than 100 comments take a full first page and a second one
+	first, err := client.ListIssueCommentsIfChanged(target.Owner, target.Repo, target.PR, "")
+	require.NoError(t, err)
+	require.Greater(t, len(first.Comments), 100)
+	assert.NotEmpty(t, first.ETag)
+	seen := make(map[int]bool)
+	for _, comment := range first.Comments {
+		assert.Equal(t, "issue", comment.Type)
+		assert.False(t, seen[comment.ID], "comment %d is on two pages", comment.ID)
+		seen[comment.ID] = true
+	}
+
+	// Asking again with the ETag gets 304 Not Modified for both pages
+	again, err := client.ListIssueCommentsIfChanged(target.Owner, target.Repo, target.PR, first.ETag)
+	require.NoError(t, err)
+	assert.True(t, again.NotModified)
+	assert.Empty(t, again.Comments)
+}
+
+func TestListCommentsIfChangedFetchesEveryPage(t *testing.T) {
+	comments := make([]fakegithub.CommentFixture, 0, 101)
+	reviewComments := make([]fakegithub.CommentFixture, 0, 101)
+	for i := 1; i <= 101; i++ {
+		comments = append(comments, fakegithub.CommentFixture{ID: i, Author: "octo", Body: fmt.Sprintf("comment %d", i)})
+		reviewComments = append(reviewComments, fakegithub.CommentFixture{ID: 1000 + i, Author: "octo", Body: fmt.Sprintf("note %d", i), Path: "main.go", Line: 1})
+	}
+	server := fakegithub.New()
+	defer server.Close()
+	require.NoError(t, server.Seed(&fakegithub.Fixture{
+		Repos: map[string]fakegithub.RepoFixture{
+			"octo/app": {Pulls: []fakegithub.PullFixture{{
+				Number:         7,
+				Title:          "Speed up startup",
+				Author:         "octo",
+				Comments:       comments,
+				ReviewComments: reviewComments,
+			}}},
+		},
+	}))
+	t.Setenv(RecordCassetteEnv, "")
+	client, err := NewRealClientForServer(server.URL())
+	require.NoError(t, err)
+
+	issue, err := client.ListIssueCommentsIfChanged("octo", "app", 7, "")
+	require.NoError(t, err)
+	require.Len(t, issue.Comments, 101)
+	assert.Equal(t, "comment 101", issue.Comments[100].Body)
+	assert.Equal(t, "issue", issue.Comments[100].Type)
+
+	again, err := client.ListIssueCommentsIfChanged("octo", "app", 7, issue.ETag)
+	require.NoError(t, err)
+	assert.True(t, again.NotModified)
+
+	// A comment landing on the second page changes only that page
+	_, err = client.CreateIssueComment("octo", "app", 7, "comment 102")
+	require.NoError(t, err)
+	changed, err := client.ListIssueCommentsIfChanged("octo", "app", 7, issue.ETag)
+	require.NoError(t, err)
+	assert.False(t, changed.NotModified)
+	require.Len(t, changed.Comments, 102)
+	assert.Equal(t, "comment 102", changed.Comments[101].Body)
+	assert.NotEqual(t, issue.ETag, changed.ETag)
+
+	review, err := client.ListReviewCommentsIfChanged("octo", "app", 7, "")
+	require.NoError(t, err)
+	require.Len(t, review.Comments, 101)
+	assert.Equal(t, "review", review.Comments[100].Type)
+}
+
+func TestRealClientErrorsFromCassette(t *testing.T) {
+	client, target := newCassetteClient(t, "errors", nil)
+	owner, repo, pr := target.Owner, target.Repo, target.PR
+
+	_, err := client.ListIssueComments(owner, repo, 999999)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resource not found while trying to fetch issue comments for PR #999999")
+	assert.Contains(t, err.Error(), "HTTP 404")
+
+	diff, err := client.FetchPRDiff(owner, repo, pr)
+	require.NoError(t, err)
+	require.NotEmpty(t, diff.Files)
+	headSHA := prHeadSHA(t, client, target)
+
+	err = client.AddReviewComment(owner, repo, pr, ReviewCommentInput{Body: "Far outside the diff", Path: diff.Files[0].Filename, Line: 99999, Side: "RIGHT", CommitID: headSHA})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "validation error")
+	assert.Contains(t, err.Error(), "HTTP 422")
+
+	err = client.ResolveReviewThread("PRRT_kwDOAAAAAAAAAAAA")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Could not resolve to a node with the global id of 'PRRT_kwDOAAAAAAAAAAAA'")
+}
+
+func TestRealClientGraphQLFromCassette(t *testing.T) {
+	client, target := newCassetteClient(t, "graphql", nil)
+	owner, repo, pr := target.Owner, target.Repo, target.PR
+
+	// Start a thread on the first line the PR adds
+	diff, err := client.FetchPRDiff(owner, repo, pr)
+	require.NoError(t, err)
+	require.NotEmpty(t, diff.Files)
+	file := diff.Files[0]
+	line := 0
+	for l := range file.Lines {
+		if line == 0 || l < line {
+			line = l
+		}
+	}
+	require.NotZero(t, line, "the PR must add lines to %s", file.Filename)
+	require.NoError(t, client.AddReviewComment(owner, repo, pr, ReviewCommentInput{Body: "Cassette thread", Path: file.Filename, Line: line, Side: "RIGHT", CommitID: prHeadSHA(t, client, target)}))
+
+	comments, err := client.ListReviewComments(owner, repo, pr)
+	require.NoError(t, err)
+	var root *Comment
+	for i := range comments {
+		if comments[i].Body == "Cassette thread" {
+			root = &comments[i]
+		}
+	}
+	require.NotNil(t, root)
+	reply, err := client.CreateReviewCommentReply(owner, repo, root.ID, "Cassette reply")
+	require.NoError(t, err)
+	assert.Equal(t, root.ID, reply.InReplyToID)
+
+	threads, err := client.ListReviewThreads(owner, repo, pr)
+	require.NoError(t, err)
+	thread := findThread(threads, root.ID)
+	require.NotNil(t, thread)
+	assert.Equal(t, []int{root.ID, reply.ID}, thread.CommentIDs)
+	assert.Equal(t, file.Filename, thread.Path)
+	assert.Equal(t, line, thread.Line)
+	assert.False(t, thread.IsResolved)
+
+	threadID, err := client.FindReviewThreadForComment(owner, repo, pr, reply.ID)
+	require.NoError(t, err)
+	assert.Equal(t, thread.ID, threadID)
+	require.NoError(t, client.ResolveReviewThread(threadID))
+	threads, err = client.ListReviewThreads(owner, repo, pr)
+	require.NoError(t, err)
+	thread = findThread(threads, root.ID)
+	require.NotNil(t, thread)
+	assert.True(t, thread.IsResolved)
+	assert.Equal(t, root.User.Login, thread.ResolvedBy)
+
+	require.NoError(t, client.EditComment(owner, repo, reply.ID, pr, "Cassette reply, edited"))
+	edits, err := client.ListCommentEdits(owner, repo, reply.ID, pr)
+	require.NoError(t, err)
+	require.Len(t, edits, 2)
+	assert.Equal(t, "Cassette reply", edits[0].Body)
+	assert.Equal(t, "Cassette reply, edited", edits[1].Body)
+	assert.Equal(t, root.User.Login, edits[1].Editor)
+}
+
+// prHeadSHA returns the commit a PR's head points at
+func prHeadSHA(t *testing.T, client *RealClient, target cassetteTarget) string {
+	t.Helper()
+	details, err := client.GetPRDetails(target.Owner, target.Repo, target.PR)
+	require.NoError(t, err)
+	head, _ := details["head"].(map[string]interface{})
+	sha, _ := head["sha"].(string)
+	require.NotEmpty(t, sha)
+	return sha
+}
+
+// findThread returns the thread containing a comment
+func findThread(threads []ReviewThread, commentID int) *ReviewThread {
+	for i := range threads {
+		for _, id := range threads[i].CommentIDs {
+			if id == commentID {
+				return &threads[i]
+			}
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 )
 
 // TestClient implements GitHubAPI for testing with a configurable HTTP client
@@ -245,6 +246,38 @@ func (c *TestClient) RemoveReaction(owner, repo string, commentID int, prNumber
 	return fmt.Errorf("not implemented in test client")
 }
 
+func (c *TestClient) ListReactions(owner, repo string, commentID int, prNumber int) ([]Reaction, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) AddCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	return fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) RemoveCommentReaction(owner, repo string, commentID int, commentType, reaction string) error {
+	return fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) ListCommentReactions(owner, repo string, commentID int, commentType string) ([]Reaction, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) AddPRReaction(owner, repo string, prNumber int, reaction string) error {
+	return fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) RemovePRReaction(owner, repo string, prNumber int, reaction string) error {
+	return fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) ListPRReactions(owner, repo string, prNumber int) ([]Reaction, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) ListCommentEdits(owner, repo string, commentID int, prNumber int) ([]CommentEdit, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
 func (c *TestClient) EditComment(owner, repo string, commentID int, prNumber int, body string) error {
 	return fmt.Errorf("not implemented in test client")
 }
@@ -253,10 +286,50 @@ func (c *TestClient) FetchPRDiff(owner, repo string, pr int) (*PullRequestDiff,
 	return nil, fmt.Errorf("not implemented in test client")
 }
 
+func (c *TestClient) ListReviews(owner, repo string, pr int) ([]Review, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
 func (c *TestClient) FindPendingReview(owner, repo string, pr int) (int, error) {
 	return 0, fmt.Errorf("not implemented in test client")
 }
 
+func (c *TestClient) ListReviewCommentsForReview(owner, repo string, pr, reviewID int) ([]Comment, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
 func (c *TestClient) SubmitReview(owner, repo string, pr, reviewID int, body, event string) error {
 	return fmt.Errorf("not implemented in test client")
 }
+
+func (c *TestClient) ListReviewThreads(owner, repo string, prNumber int) ([]ReviewThread, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) SearchPullRequests(query string, limit int) ([]PullRequest, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) ListRepoReviewComments(owner, repo string, since time.Time, keep func(Comment) bool) ([]Comment, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
+
+func (c *TestClient) ListIssueCommentsIfChanged(owner, repo string, prNumber int, etag string) (*ConditionalComments, error) {
+	comments, err := c.ListIssueComments(owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &ConditionalComments{Comments: comments}, nil
+}
+
+func (c *TestClient) ListReviewCommentsIfChanged(owner, repo string, prNumber int, etag string) (*ConditionalComments, error) {
+	comments, err := c.ListReviewComments(owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &ConditionalComments{Comments: comments}, nil
+}
+
+func (c *TestClient) GetRateLimit() (*RateLimit, error) {
+	return nil, fmt.Errorf("not implemented in test client")
+}
@@ -3,15 +3,16 @@ package test
 import (
 	"fmt"
 	"os"
-	"strings"
 	"testing"
 
 	"github.com/rogpeppe/go-internal/testscript"
 
 	"github.com/silouanwright/gh-comment/cmd"
+	"github.com/silouanwright/gh-comment/internal/fakegithub"
 )
 
-var mockServer *cmd.MockGitHubServer
+// fixturePath seeds the fake GitHub each script runs against
+const fixturePath = "../testdata/fixtures/github.yaml"
 
 func TestMain(m *testing.M) {
 	testscript.Main(m, map[string]func(){
@@ -25,15 +26,27 @@ func TestMain(m *testing.M) {
 	})
 }
 
+// setupFakeGitHub starts a freshly seeded fake GitHub for one script, so writes
+// made by one script never leak into another, and points gh-comment at it
+func setupFakeGitHub(env *testscript.Env) error {
+	server, err := fakegithub.NewFromFixture(fixturePath)
+	if err != nil {
+		return err
+	}
+	env.Defer(server.Close)
+
+	env.Setenv("GH_TOKEN", "test-token")
+	env.Setenv("GH_HOST", "github.com")
+	env.Setenv("MOCK_SERVER_URL", server.URL())
+	env.Setenv("GH_REPO", "test-owner/test-repo")
+	env.Setenv("NO_COLOR", "1")
+	return nil
+}
+
 func TestIntegration(t *testing.T) {
 	testscript.Run(t, testscript.Params{
-		Dir: "../testdata/scripts",
-		Setup: func(env *testscript.Env) error {
-			// Set up test environment
-			env.Setenv("GH_TOKEN", "test-token")
-			env.Setenv("GH_HOST", "github.com")
-			return nil
-		},
+		Dir:   "../testdata/scripts",
+		Setup: setupFakeGitHub,
 		Condition: func(cond string) (bool, error) {
 			switch cond {
 			case "has-gh":
@@ -49,32 +62,12 @@ func TestIntegration(t *testing.T) {
 
 func TestEnhancedIntegration(t *testing.T) {
 	testscript.Run(t, testscript.Params{
-		Dir: "../testdata/enhanced-scripts",
-		Setup: func(env *testscript.Env) error {
-			// Start mock GitHub API server
-			mockServer = cmd.NewMockGitHubServer()
-
-			// DETERMINISTIC SETUP: Always set up all scenarios
-			// This ensures consistent test data regardless of condition checks
-			mockServer.SetupTestScenario("basic")
-			mockServer.SetupTestScenario("security-review")
-
-			// Set up test environment to use mock server
-			env.Setenv("GH_TOKEN", "test-token")
-			env.Setenv("GH_HOST", strings.TrimPrefix(mockServer.URL(), "http://"))
-			env.Setenv("MOCK_SERVER_URL", mockServer.URL())
-
-			// Set up test repository context
-			env.Setenv("GH_REPO", "test-owner/test-repo")
-
-			return nil
-		},
+		Dir:   "../testdata/enhanced-scripts",
+		Setup: setupFakeGitHub,
 		Condition: func(cond string) (bool, error) {
 			switch cond {
-			case "mock-server":
-				return mockServer != nil, nil
-			case "scenario:basic", "scenario:security-review":
-				// Scenarios are always available now
+			case "mock-server", "scenario:basic", "scenario:security-review":
+				// Every script gets the fake GitHub seeded with all scenarios
 				return true, nil
 			default:
 				return false, nil
@@ -82,5 +75,3 @@ func TestEnhancedIntegration(t *testing.T) {
 		},
 	})
 }
-
-// No longer needed - main1 function removed as we're using cmd.Execute() directly